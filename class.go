@@ -43,10 +43,14 @@ type GnetStatsQueue struct {
 
 // ClassStatistics representation based on generic networking statistics for netlink.
 // See Documentation/networking/gen_stats.txt in Linux source code for more details.
+// RateEst64 and BasicHw are only reported by the kernel when relevant and are
+// left nil otherwise.
 type ClassStatistics struct {
-	Basic   *GnetStatsBasic
-	Queue   *GnetStatsQueue
-	RateEst *GnetStatsRateEst
+	Basic     *GnetStatsBasic
+	Queue     *GnetStatsQueue
+	RateEst   *GnetStatsRateEst
+	RateEst64 *GnetStatsRateEst64
+	BasicHw   *GnetStatsBasic
 }
 
 // NewClassStatistics Construct a ClassStatistics struct which fields are all initialized by 0.
@@ -115,6 +119,47 @@ func (q *HtbClass) Type() string {
 	return "htb"
 }
 
+// DrrClass represents a class of a Drr qdisc
+type DrrClass struct {
+	ClassAttrs
+	Quantum uint32 // in bytes, 0 means the MTU of the device
+}
+
+func (q DrrClass) String() string {
+	return fmt.Sprintf("{Quantum: %d}", q.Quantum)
+}
+
+// Attrs returns the class attributes
+func (q *DrrClass) Attrs() *ClassAttrs {
+	return &q.ClassAttrs
+}
+
+// Type return the class type
+func (q *DrrClass) Type() string {
+	return "drr"
+}
+
+// QfqClass represents a class of a Qfq qdisc
+type QfqClass struct {
+	ClassAttrs
+	Weight uint32 // 0 means the kernel default of 1
+	Lmax   uint32 // in bytes, 0 means the MTU of the device
+}
+
+func (q QfqClass) String() string {
+	return fmt.Sprintf("{Weight: %d, Lmax: %d}", q.Weight, q.Lmax)
+}
+
+// Attrs returns the class attributes
+func (q *QfqClass) Attrs() *ClassAttrs {
+	return &q.ClassAttrs
+}
+
+// Type return the class type
+func (q *QfqClass) Type() string {
+	return "qfq"
+}
+
 // GenericClass classes represent types that are not currently understood
 // by this netlink library.
 type GenericClass struct {
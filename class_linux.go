@@ -194,6 +194,19 @@ func classPayload(req *nl.NetlinkRequest, class Class) error {
 		nl.NewRtAttrChild(options, nl.TCA_HFSC_RSC, nl.SerializeHfscCurve(&opt.Rsc))
 		nl.NewRtAttrChild(options, nl.TCA_HFSC_FSC, nl.SerializeHfscCurve(&opt.Fsc))
 		nl.NewRtAttrChild(options, nl.TCA_HFSC_USC, nl.SerializeHfscCurve(&opt.Usc))
+	case "drr":
+		drr := class.(*DrrClass)
+		if drr.Quantum > 0 {
+			options.AddRtAttr(nl.TCA_DRR_QUANTUM, nl.Uint32Attr(drr.Quantum))
+		}
+	case "qfq":
+		qfq := class.(*QfqClass)
+		if qfq.Weight > 0 {
+			options.AddRtAttr(nl.TCA_QFQ_WEIGHT, nl.Uint32Attr(qfq.Weight))
+		}
+		if qfq.Lmax > 0 {
+			options.AddRtAttr(nl.TCA_QFQ_LMAX, nl.Uint32Attr(qfq.Lmax))
+		}
 	}
 	req.AddData(options)
 	return nil
@@ -254,6 +267,10 @@ func (h *Handle) ClassList(link Link, parent uint32) ([]Class, error) {
 					class = &HtbClass{}
 				case "hfsc":
 					class = &HfscClass{}
+				case "drr":
+					class = &DrrClass{}
+				case "qfq":
+					class = &QfqClass{}
 				default:
 					class = &GenericClass{ClassType: classType}
 				}
@@ -277,6 +294,18 @@ func (h *Handle) ClassList(link Link, parent uint32) ([]Class, error) {
 					if err != nil {
 						return nil, err
 					}
+				case "drr":
+					data, err := nl.ParseRouteAttr(attr.Value)
+					if err != nil {
+						return nil, err
+					}
+					parseDrrClassData(class, data)
+				case "qfq":
+					data, err := nl.ParseRouteAttr(attr.Value)
+					if err != nil {
+						return nil, err
+					}
+					parseQfqClassData(class, data)
 				}
 			// For backward compatibility.
 			case nl.TCA_STATS:
@@ -338,6 +367,28 @@ func parseHfscClassData(class Class, data []syscall.NetlinkRouteAttr) (bool, err
 	return detailed, nil
 }
 
+func parseDrrClassData(class Class, data []syscall.NetlinkRouteAttr) {
+	drr := class.(*DrrClass)
+	for _, datum := range data {
+		switch datum.Attr.Type {
+		case nl.TCA_DRR_QUANTUM:
+			drr.Quantum = native.Uint32(datum.Value[0:4])
+		}
+	}
+}
+
+func parseQfqClassData(class Class, data []syscall.NetlinkRouteAttr) {
+	qfq := class.(*QfqClass)
+	for _, datum := range data {
+		switch datum.Attr.Type {
+		case nl.TCA_QFQ_WEIGHT:
+			qfq.Weight = native.Uint32(datum.Value[0:4])
+		case nl.TCA_QFQ_LMAX:
+			qfq.Lmax = native.Uint32(datum.Value[0:4])
+		}
+	}
+}
+
 func parseTcStats(data []byte) (*ClassStatistics, error) {
 	buf := &bytes.Buffer{}
 	buf.Write(data)
@@ -390,6 +441,18 @@ func parseTcStats2(data []byte) (*ClassStatistics, error) {
 				return nil, fmt.Errorf("Failed to parse ClassStatistics.RateEst with: %v\n%s",
 					err, hex.Dump(datum.Value))
 			}
+		case nl.TCA_STATS_RATE_EST64:
+			stats.RateEst64 = &GnetStatsRateEst64{}
+			if err := parseGnetStats(datum.Value, stats.RateEst64); err != nil {
+				return nil, fmt.Errorf("Failed to parse ClassStatistics.RateEst64 with: %v\n%s",
+					err, hex.Dump(datum.Value))
+			}
+		case nl.TCA_STATS_BASIC_HW:
+			stats.BasicHw = &GnetStatsBasic{}
+			if err := parseGnetStats(datum.Value, stats.BasicHw); err != nil {
+				return nil, fmt.Errorf("Failed to parse ClassStatistics.BasicHw with: %v\n%s",
+					err, hex.Dump(datum.Value))
+			}
 		}
 	}
 
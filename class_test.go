@@ -674,3 +674,77 @@ func TestClassHfsc(t *testing.T) {
 	}
 
 }
+
+func TestDrrQfqClassAddDel(t *testing.T) {
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+	if err := LinkAdd(&Ifb{LinkAttrs{Name: "foo"}}); err != nil {
+		t.Fatal(err)
+	}
+	link, err := LinkByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	qdiscAttrs := QdiscAttrs{
+		LinkIndex: link.Attrs().Index,
+		Handle:    MakeHandle(1, 0),
+		Parent:    HANDLE_ROOT,
+	}
+	classAttrs := ClassAttrs{
+		LinkIndex: link.Attrs().Index,
+		Parent:    MakeHandle(1, 0),
+		Handle:    MakeHandle(1, 1),
+	}
+
+	drr := &Drr{QdiscAttrs: qdiscAttrs}
+	if err := QdiscAdd(drr); err != nil {
+		t.Fatal(err)
+	}
+	drrClass := &DrrClass{ClassAttrs: classAttrs, Quantum: 3000}
+	if err := ClassAdd(drrClass); err != nil {
+		t.Fatal(err)
+	}
+	classes, err := SafeClassList(link, MakeHandle(1, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(classes) != 1 {
+		t.Fatal("Failed to add class")
+	}
+	c, ok := classes[0].(*DrrClass)
+	if !ok {
+		t.Fatal("Class is the wrong type")
+	}
+	if c.Quantum != drrClass.Quantum {
+		t.Fatal("Quantum does not match")
+	}
+	if err := QdiscDel(drr); err != nil {
+		t.Fatal(err)
+	}
+
+	qfq := &Qfq{QdiscAttrs: qdiscAttrs}
+	if err := QdiscAdd(qfq); err != nil {
+		t.Fatal(err)
+	}
+	qfqClass := &QfqClass{ClassAttrs: classAttrs, Weight: 10, Lmax: 2048}
+	if err := ClassAdd(qfqClass); err != nil {
+		t.Fatal(err)
+	}
+	classes, err = SafeClassList(link, MakeHandle(1, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(classes) != 1 {
+		t.Fatal("Failed to add class")
+	}
+	q, ok := classes[0].(*QfqClass)
+	if !ok {
+		t.Fatal("Class is the wrong type")
+	}
+	if q.Weight != qfqClass.Weight || q.Lmax != qfqClass.Lmax {
+		t.Fatal("Parameters do not match")
+	}
+	if err := QdiscDel(qfq); err != nil {
+		t.Fatal(err)
+	}
+}
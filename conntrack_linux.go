@@ -6,8 +6,11 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"strings"
+	"syscall"
 
 	"github.com/ndupreez/netlink/nl"
+	"github.com/vishvananda/netns"
 	"golang.org/x/sys/unix"
 )
 
@@ -41,7 +44,8 @@ type InetFamily uint8
 //  -C [table]                    Show counter
 //  -S                            Show statistics
 
-// ConntrackTableList returns the flow list of a table of a specific family
+// ConntrackTableList returns the flow list of a table of a specific family,
+// the expectations are listed with ConntrackExpectList
 // conntrack -L [table] [options]          List conntrack or expectation table
 func ConntrackTableList(table ConntrackTableType, family InetFamily) ([]*ConntrackFlow, error) {
 	return pkgHandle.ConntrackTableList(table, family)
@@ -60,6 +64,28 @@ func ConntrackDeleteFilter(table ConntrackTableType, family InetFamily, filter C
 	return pkgHandle.ConntrackDeleteFilter(table, family, filter)
 }
 
+// ConntrackCreate creates a new conntrack entry from the flow, it fails if
+// the entry already exists. The flow needs both tuples and a timeout.
+// conntrack -I [table] parameters         Create a conntrack or expectation
+func ConntrackCreate(table ConntrackTableType, family InetFamily, flow *ConntrackFlow) error {
+	return pkgHandle.ConntrackCreate(table, family, flow)
+}
+
+// ConntrackUpdate updates the existing conntrack entry matching the tuples
+// of the flow. The tuples and the counters can not be changed.
+// conntrack -U [table] parameters         Update a conntrack
+func ConntrackUpdate(table ConntrackTableType, family InetFamily, flow *ConntrackFlow) error {
+	return pkgHandle.ConntrackUpdate(table, family, flow)
+}
+
+// ConntrackGet returns the conntrack entry matching the forward tuple of the
+// flow, or its reverse tuple if no forward addresses are set, in the zone of
+// the flow.
+// conntrack -G [table] parameters         Get conntrack or expectation
+func ConntrackGet(table ConntrackTableType, family InetFamily, flow *ConntrackFlow) (*ConntrackFlow, error) {
+	return pkgHandle.ConntrackGet(table, family, flow)
+}
+
 // ConntrackTableList returns the flow list of a table of a specific family using the netlink handle passed
 // conntrack -L [table] [options]          List conntrack or expectation table
 func (h *Handle) ConntrackTableList(table ConntrackTableType, family InetFamily) ([]*ConntrackFlow, error) {
@@ -109,6 +135,251 @@ func (h *Handle) ConntrackDeleteFilter(table ConntrackTableType, family InetFami
 	return matched, nil
 }
 
+// ConntrackEventType is the type of a conntrack event. The values are bits
+// so that they can be combined into the EventMask of
+// ConntrackSubscribeOptions.
+type ConntrackEventType uint8
+
+const (
+	ConntrackEventNew ConntrackEventType = 1 << iota
+	ConntrackEventUpdate
+	ConntrackEventDestroy
+	ConntrackEventAll = ConntrackEventNew | ConntrackEventUpdate | ConntrackEventDestroy
+)
+
+func (t ConntrackEventType) String() string {
+	switch t {
+	case ConntrackEventNew:
+		return "NEW"
+	case ConntrackEventUpdate:
+		return "UPDATE"
+	case ConntrackEventDestroy:
+		return "DESTROY"
+	}
+	return fmt.Sprintf("%d", uint8(t))
+}
+
+// ConntrackEvent is sent down the channel of ConntrackSubscribe for each
+// conntrack event. The Flow of a DESTROY event carries the final counters of
+// the connection when accounting is enabled (nf_conntrack_acct).
+type ConntrackEvent struct {
+	Type ConntrackEventType
+	Flow *ConntrackFlow
+}
+
+// ConntrackSubscribe takes a chan down which notifications will be sent
+// when conntrack entries are created, updated or destroyed. Close the
+// 'done' chan to stop subscription.
+// Equivalent to: `conntrack -E`
+func ConntrackSubscribe(ch chan<- ConntrackEvent, done <-chan struct{}) error {
+	return conntrackSubscribeAt(netns.None(), netns.None(), ch, done, nil, ConntrackEventAll, 0)
+}
+
+// ConntrackSubscribeAt works like ConntrackSubscribe plus it allows the caller
+// to choose the network namespace in which to subscribe (ns).
+func ConntrackSubscribeAt(ns netns.NsHandle, ch chan<- ConntrackEvent, done <-chan struct{}) error {
+	return conntrackSubscribeAt(ns, netns.None(), ch, done, nil, ConntrackEventAll, 0)
+}
+
+// ConntrackSubscribeOptions contains a set of options to use with
+// ConntrackSubscribeWithOptions.
+type ConntrackSubscribeOptions struct {
+	Namespace     *netns.NsHandle
+	ErrorCallback func(error)
+	// EventMask selects the events to receive, all of them if zero.
+	// Equivalent to: `conntrack -E -e NEW,DESTROY`
+	EventMask         ConntrackEventType
+	ReceiveBufferSize int
+}
+
+// ConntrackSubscribeWithOptions work like ConntrackSubscribe but enable to
+// provide additional options to modify the behavior. Currently, the
+// namespace, an error callback, the event mask and the socket receive
+// buffer size can be provided.
+func ConntrackSubscribeWithOptions(ch chan<- ConntrackEvent, done <-chan struct{}, options ConntrackSubscribeOptions) error {
+	if options.Namespace == nil {
+		none := netns.None()
+		options.Namespace = &none
+	}
+	if options.EventMask == 0 {
+		options.EventMask = ConntrackEventAll
+	}
+	return conntrackSubscribeAt(*options.Namespace, netns.None(), ch, done, options.ErrorCallback, options.EventMask, options.ReceiveBufferSize)
+}
+
+func conntrackSubscribeAt(newNs, curNs netns.NsHandle, ch chan<- ConntrackEvent, done <-chan struct{}, cberr func(error), mask ConntrackEventType, rcvbuf int) error {
+	return conntrackEventsSubscribeAt(newNs, curNs, ConntrackTable, done, cberr, mask, rcvbuf,
+		func(t ConntrackEventType, data []byte) {
+			ch <- ConntrackEvent{Type: t, Flow: parseRawData(data)}
+		},
+		func() { close(ch) })
+}
+
+// conntrackEventsSubscribeAt subscribes to the events of the table selected
+// by mask and calls send with the type and the raw data of each of them.
+// finish is called once the subscription ends.
+func conntrackEventsSubscribeAt(newNs, curNs netns.NsHandle, table ConntrackTableType, done <-chan struct{}, cberr func(error),
+	mask ConntrackEventType, rcvbuf int, send func(ConntrackEventType, []byte), finish func()) error {
+	// the NEW, UPDATE and DESTROY groups of a table are consecutive
+	first := uint(nl.NFNLGRP_CONNTRACK_NEW)
+	if table == ConntrackExpectTable {
+		first = nl.NFNLGRP_CONNTRACK_EXP_NEW
+	}
+	var groups []uint
+	if mask&ConntrackEventNew != 0 {
+		groups = append(groups, first)
+	}
+	if mask&ConntrackEventUpdate != 0 {
+		groups = append(groups, first+1)
+	}
+	if mask&ConntrackEventDestroy != 0 {
+		groups = append(groups, first+2)
+	}
+	if len(groups) == 0 {
+		return fmt.Errorf("invalid conntrack event mask: %d", mask)
+	}
+	s, err := nl.SubscribeAt(newNs, curNs, unix.NETLINK_NETFILTER, groups...)
+	if err != nil {
+		return err
+	}
+	if rcvbuf != 0 {
+		// a busy table easily overruns the default buffer, the socket
+		// then fails with ENOBUFS which is reported to the error callback
+		if err := s.SetReceiveBufferSize(rcvbuf, false); err != nil {
+			s.Close()
+			return err
+		}
+	}
+	if done != nil {
+		go func() {
+			<-done
+			s.Close()
+		}()
+	}
+	go func() {
+		defer finish()
+		for {
+			msgs, from, err := s.Receive()
+			if err != nil {
+				if cberr != nil {
+					cberr(err)
+				}
+				return
+			}
+			if from.Pid != nl.PidKernel {
+				if cberr != nil {
+					cberr(fmt.Errorf("Wrong sender portid %d, expected %d", from.Pid, nl.PidKernel))
+				}
+				continue
+			}
+			for _, m := range msgs {
+				if m.Header.Type == unix.NLMSG_DONE {
+					continue
+				}
+				if m.Header.Type == unix.NLMSG_ERROR {
+					native := nl.NativeEndian()
+					error := int32(native.Uint32(m.Data[0:4]))
+					if error == 0 {
+						continue
+					}
+					if cberr != nil {
+						cberr(fmt.Errorf("error message: %v",
+							syscall.Errno(-error)))
+					}
+					continue
+				}
+				t, err := conntrackEventType(table, m.Header)
+				if err != nil {
+					if cberr != nil {
+						cberr(err)
+					}
+					continue
+				}
+				send(t, m.Data)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// conntrackEventType maps the header of a conntrack notification of the table
+// to its event type. The kernel sends IPCTNL_MSG_CT_NEW for both new and
+// updated entries, only new ones are flagged with NLM_F_CREATE. The
+// IPCTNL_MSG_EXP_* messages of the expectations have the same values.
+func conntrackEventType(table ConntrackTableType, h syscall.NlMsghdr) (ConntrackEventType, error) {
+	if h.Type>>8 != uint16(table) {
+		return 0, fmt.Errorf("bad message type: %d", h.Type)
+	}
+	switch h.Type & 0xff {
+	case nl.IPCTNL_MSG_CT_NEW:
+		if h.Flags&unix.NLM_F_CREATE != 0 {
+			return ConntrackEventNew, nil
+		}
+		return ConntrackEventUpdate, nil
+	case nl.IPCTNL_MSG_CT_DELETE:
+		return ConntrackEventDestroy, nil
+	}
+	return 0, fmt.Errorf("bad message type: %d", h.Type)
+}
+
+// ConntrackCreate creates a new conntrack entry from the flow using the netlink handle passed,
+// it fails if the entry already exists. The flow needs both tuples and a timeout.
+// conntrack -I [table] parameters         Create a conntrack or expectation
+func (h *Handle) ConntrackCreate(table ConntrackTableType, family InetFamily, flow *ConntrackFlow) error {
+	return h.conntrackNew(table, family, flow, unix.NLM_F_ACK|unix.NLM_F_CREATE|unix.NLM_F_EXCL)
+}
+
+// ConntrackUpdate updates the existing conntrack entry matching the tuples of the flow
+// using the netlink handle passed. The tuples and the counters can not be changed.
+// conntrack -U [table] parameters         Update a conntrack
+func (h *Handle) ConntrackUpdate(table ConntrackTableType, family InetFamily, flow *ConntrackFlow) error {
+	return h.conntrackNew(table, family, flow, unix.NLM_F_ACK)
+}
+
+func (h *Handle) conntrackNew(table ConntrackTableType, family InetFamily, flow *ConntrackFlow, flags int) error {
+	attrs, err := flow.toNlData(family)
+	if err != nil {
+		return err
+	}
+	req := h.newConntrackRequest(table, family, nl.IPCTNL_MSG_CT_NEW, flags)
+	for _, attr := range attrs {
+		req.AddData(attr)
+	}
+	_, err = req.Execute(unix.NETLINK_NETFILTER, 0)
+	return err
+}
+
+// ConntrackGet returns the conntrack entry matching the forward tuple of the flow, or its
+// reverse tuple if no forward addresses are set, in the zone of the flow using the netlink
+// handle passed.
+// conntrack -G [table] parameters         Get conntrack or expectation
+func (h *Handle) ConntrackGet(table ConntrackTableType, family InetFamily, flow *ConntrackFlow) (*ConntrackFlow, error) {
+	var tuple *nl.RtAttr
+	var err error
+	if flow.Forward.SrcIP != nil || flow.Forward.DstIP != nil {
+		tuple, err = flow.Forward.toNlData(nl.CTA_TUPLE_ORIG, family)
+	} else {
+		tuple, err = flow.Reverse.toNlData(nl.CTA_TUPLE_REPLY, family)
+	}
+	if err != nil {
+		return nil, err
+	}
+	req := h.newConntrackRequest(table, family, nl.IPCTNL_MSG_CT_GET, unix.NLM_F_ACK)
+	req.AddData(tuple)
+	if flow.Zone != 0 {
+		req.AddData(nl.NewRtAttr(nl.CTA_ZONE, htons(flow.Zone)))
+	}
+	res, err := req.Execute(unix.NETLINK_NETFILTER, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(res) == 0 {
+		return nil, fmt.Errorf("no conntrack entry found")
+	}
+	return parseRawData(res[0]), nil
+}
+
 func (h *Handle) newConntrackRequest(table ConntrackTableType, family InetFamily, operation, flags int) *nl.NetlinkRequest {
 	// Create the Netlink request object
 	req := h.newNetlinkRequest((int(table)<<8)|operation, flags)
@@ -129,7 +400,7 @@ func (h *Handle) dumpConntrackTable(table ConntrackTableType, family InetFamily)
 
 // The full conntrack flow structure is very complicated and can be found in the file:
 // http://git.netfilter.org/libnetfilter_conntrack/tree/include/internal/object.h
-// For the time being, the structure below allows to parse and extract the base information of a flow
+// The structure below holds the information shown by `conntrack -L -o extended`
 type ipTuple struct {
 	Bytes    uint64
 	DstIP    net.IP
@@ -138,6 +409,231 @@ type ipTuple struct {
 	Protocol uint8
 	SrcIP    net.IP
 	SrcPort  uint16
+	ICMPID   uint16
+	ICMPType uint8
+	ICMPCode uint8
+}
+
+func (t *ipTuple) String() string {
+	var l4 string
+	switch t.Protocol {
+	case unix.IPPROTO_ICMP, unix.IPPROTO_ICMPV6:
+		l4 = fmt.Sprintf("type=%d code=%d id=%d", t.ICMPType, t.ICMPCode, t.ICMPID)
+	default:
+		l4 = fmt.Sprintf("sport=%d dport=%d", t.SrcPort, t.DstPort)
+	}
+	return fmt.Sprintf("src=%s dst=%s %s packets=%d bytes=%d", t.SrcIP.String(), t.DstIP.String(), l4, t.Packets, t.Bytes)
+}
+
+// toNlData serializes the tuple as the nested attrType attribute, the
+// reverse of parseIpTuple
+func (t *ipTuple) toNlData(attrType int, family InetFamily) (*nl.RtAttr, error) {
+	srcType, dstType, size := nl.CTA_IP_V4_SRC, nl.CTA_IP_V4_DST, net.IPv4len
+	if family == unix.AF_INET6 {
+		srcType, dstType, size = nl.CTA_IP_V6_SRC, nl.CTA_IP_V6_DST, net.IPv6len
+	}
+	src, dst := t.SrcIP.To16(), t.DstIP.To16()
+	if size == net.IPv4len {
+		src, dst = t.SrcIP.To4(), t.DstIP.To4()
+	}
+	if src == nil || dst == nil {
+		return nil, fmt.Errorf("invalid tuple addresses for family %d: src=%s dst=%s", family, t.SrcIP, t.DstIP)
+	}
+	tuple := nl.NewRtAttr(attrType|int(nl.NLA_F_NESTED), nil)
+	ip := tuple.AddRtAttr(nl.CTA_TUPLE_IP|int(nl.NLA_F_NESTED), nil)
+	ip.AddRtAttr(srcType, src)
+	ip.AddRtAttr(dstType, dst)
+	proto := tuple.AddRtAttr(nl.CTA_TUPLE_PROTO|int(nl.NLA_F_NESTED), nil)
+	proto.AddRtAttr(nl.CTA_PROTO_NUM, nl.Uint8Attr(t.Protocol))
+	switch t.Protocol {
+	case unix.IPPROTO_ICMP:
+		proto.AddRtAttr(nl.CTA_PROTO_ICMP_ID, htons(t.ICMPID))
+		proto.AddRtAttr(nl.CTA_PROTO_ICMP_TYPE, nl.Uint8Attr(t.ICMPType))
+		proto.AddRtAttr(nl.CTA_PROTO_ICMP_CODE, nl.Uint8Attr(t.ICMPCode))
+	case unix.IPPROTO_ICMPV6:
+		proto.AddRtAttr(nl.CTA_PROTO_ICMPV6_ID, htons(t.ICMPID))
+		proto.AddRtAttr(nl.CTA_PROTO_ICMPV6_TYPE, nl.Uint8Attr(t.ICMPType))
+		proto.AddRtAttr(nl.CTA_PROTO_ICMPV6_CODE, nl.Uint8Attr(t.ICMPCode))
+	default:
+		proto.AddRtAttr(nl.CTA_PROTO_SRC_PORT, htons(t.SrcPort))
+		proto.AddRtAttr(nl.CTA_PROTO_DST_PORT, htons(t.DstPort))
+	}
+	return tuple, nil
+}
+
+// ConntrackStatus holds the IPS_* status bits of a conntrack entry
+type ConntrackStatus uint32
+
+const (
+	IPS_EXPECTED ConntrackStatus = 1 << iota
+	IPS_SEEN_REPLY
+	IPS_ASSURED
+	IPS_CONFIRMED
+	IPS_SRC_NAT
+	IPS_DST_NAT
+	IPS_SEQ_ADJUST
+	IPS_SRC_NAT_DONE
+	IPS_DST_NAT_DONE
+	IPS_DYING
+	IPS_FIXED_TIMEOUT
+	IPS_TEMPLATE
+	IPS_UNTRACKED
+	IPS_HELPER
+	IPS_OFFLOAD
+	IPS_HW_OFFLOAD
+)
+
+var conntrackStatusNames = []string{
+	"EXPECTED",
+	"SEEN_REPLY",
+	"ASSURED",
+	"CONFIRMED",
+	"SRC_NAT",
+	"DST_NAT",
+	"SEQ_ADJUST",
+	"SRC_NAT_DONE",
+	"DST_NAT_DONE",
+	"DYING",
+	"FIXED_TIMEOUT",
+	"TEMPLATE",
+	"UNTRACKED",
+	"HELPER",
+	"OFFLOAD",
+	"HW_OFFLOAD",
+}
+
+func (s ConntrackStatus) String() string {
+	var names []string
+	for i, name := range conntrackStatusNames {
+		if s&(1<<uint(i)) != 0 {
+			names = append(names, name)
+		}
+	}
+	if rest := s &^ (1<<uint(len(conntrackStatusNames)) - 1); rest != 0 {
+		names = append(names, fmt.Sprintf("0x%x", uint32(rest)))
+	}
+	return strings.Join(names, "|")
+}
+
+// ConntrackTCPState is the state of a tracked TCP connection
+type ConntrackTCPState uint8
+
+const (
+	TCP_CONNTRACK_NONE ConntrackTCPState = iota
+	TCP_CONNTRACK_SYN_SENT
+	TCP_CONNTRACK_SYN_RECV
+	TCP_CONNTRACK_ESTABLISHED
+	TCP_CONNTRACK_FIN_WAIT
+	TCP_CONNTRACK_CLOSE_WAIT
+	TCP_CONNTRACK_LAST_ACK
+	TCP_CONNTRACK_TIME_WAIT
+	TCP_CONNTRACK_CLOSE
+	TCP_CONNTRACK_SYN_SENT2
+)
+
+func (s ConntrackTCPState) String() string {
+	switch s {
+	case TCP_CONNTRACK_NONE:
+		return "NONE"
+	case TCP_CONNTRACK_SYN_SENT:
+		return "SYN_SENT"
+	case TCP_CONNTRACK_SYN_RECV:
+		return "SYN_RECV"
+	case TCP_CONNTRACK_ESTABLISHED:
+		return "ESTABLISHED"
+	case TCP_CONNTRACK_FIN_WAIT:
+		return "FIN_WAIT"
+	case TCP_CONNTRACK_CLOSE_WAIT:
+		return "CLOSE_WAIT"
+	case TCP_CONNTRACK_LAST_ACK:
+		return "LAST_ACK"
+	case TCP_CONNTRACK_TIME_WAIT:
+		return "TIME_WAIT"
+	case TCP_CONNTRACK_CLOSE:
+		return "CLOSE"
+	case TCP_CONNTRACK_SYN_SENT2:
+		return "SYN_SENT2"
+	}
+	return fmt.Sprintf("UNKNOWN(%d)", uint8(s))
+}
+
+// ProtoInfo is the layer 4 protocol specific state of a conntrack entry,
+// one of *ProtoInfoTCP, *ProtoInfoDCCP or *ProtoInfoSCTP
+type ProtoInfo interface {
+	Protocol() string
+	toNlData() *nl.RtAttr
+}
+
+// ProtoInfoTCP is the CTA_PROTOINFO_TCP state
+type ProtoInfoTCP struct {
+	State       ConntrackTCPState
+	WScaleOrig  uint8
+	WScaleReply uint8
+	FlagsOrig   uint8
+	FlagsReply  uint8
+}
+
+func (p *ProtoInfoTCP) Protocol() string { return "tcp" }
+
+func (p *ProtoInfoTCP) toNlData() *nl.RtAttr {
+	info := nl.NewRtAttr(nl.CTA_PROTOINFO_TCP|int(nl.NLA_F_NESTED), nil)
+	info.AddRtAttr(nl.CTA_PROTOINFO_TCP_STATE, nl.Uint8Attr(uint8(p.State)))
+	if p.WScaleOrig != 0 || p.WScaleReply != 0 {
+		info.AddRtAttr(nl.CTA_PROTOINFO_TCP_WSCALE_ORIGINAL, nl.Uint8Attr(p.WScaleOrig))
+		info.AddRtAttr(nl.CTA_PROTOINFO_TCP_WSCALE_REPLY, nl.Uint8Attr(p.WScaleReply))
+	}
+	if p.FlagsOrig != 0 || p.FlagsReply != 0 {
+		// struct nf_ct_tcp_flags { __u8 flags; __u8 mask; }
+		info.AddRtAttr(nl.CTA_PROTOINFO_TCP_FLAGS_ORIGINAL, []byte{p.FlagsOrig, p.FlagsOrig})
+		info.AddRtAttr(nl.CTA_PROTOINFO_TCP_FLAGS_REPLY, []byte{p.FlagsReply, p.FlagsReply})
+	}
+	return info
+}
+
+// ProtoInfoDCCP is the CTA_PROTOINFO_DCCP state
+type ProtoInfoDCCP struct {
+	State uint8
+}
+
+func (p *ProtoInfoDCCP) Protocol() string { return "dccp" }
+
+func (p *ProtoInfoDCCP) toNlData() *nl.RtAttr {
+	info := nl.NewRtAttr(nl.CTA_PROTOINFO_DCCP|int(nl.NLA_F_NESTED), nil)
+	info.AddRtAttr(nl.CTA_PROTOINFO_DCCP_STATE, nl.Uint8Attr(p.State))
+	return info
+}
+
+// ProtoInfoSCTP is the CTA_PROTOINFO_SCTP state
+type ProtoInfoSCTP struct {
+	State     uint8
+	VTagOrig  uint32
+	VTagReply uint32
+}
+
+func (p *ProtoInfoSCTP) Protocol() string { return "sctp" }
+
+func (p *ProtoInfoSCTP) toNlData() *nl.RtAttr {
+	info := nl.NewRtAttr(nl.CTA_PROTOINFO_SCTP|int(nl.NLA_F_NESTED), nil)
+	info.AddRtAttr(nl.CTA_PROTOINFO_SCTP_STATE, nl.Uint8Attr(p.State))
+	info.AddRtAttr(nl.CTA_PROTOINFO_SCTP_VTAG_ORIGINAL, htonl(p.VTagOrig))
+	info.AddRtAttr(nl.CTA_PROTOINFO_SCTP_VTAG_REPLY, htonl(p.VTagReply))
+	return info
+}
+
+// ConntrackSeqAdj is the TCP sequence number adjustment of one direction,
+// applied by NAT helpers that change the payload length
+type ConntrackSeqAdj struct {
+	CorrectionPos uint32
+	OffsetBefore  uint32
+	OffsetAfter   uint32
+}
+
+func (s *ConntrackSeqAdj) toNlData(attrType int) *nl.RtAttr {
+	seqAdj := nl.NewRtAttr(attrType|int(nl.NLA_F_NESTED), nil)
+	seqAdj.AddRtAttr(nl.CTA_SEQADJ_CORRECTION_POS, htonl(s.CorrectionPos))
+	seqAdj.AddRtAttr(nl.CTA_SEQADJ_OFFSET_BEFORE, htonl(s.OffsetBefore))
+	seqAdj.AddRtAttr(nl.CTA_SEQADJ_OFFSET_AFTER, htonl(s.OffsetAfter))
+	return seqAdj
 }
 
 type ConntrackFlow struct {
@@ -145,55 +641,167 @@ type ConntrackFlow struct {
 	Forward    ipTuple
 	Reverse    ipTuple
 	Mark       uint32
+	Status     ConntrackStatus
+	// TimeOut is the remaining lifetime of the entry in seconds
+	TimeOut   uint32
+	ProtoInfo ProtoInfo
+	Use       uint32
+	ID        uint32
+	Zone      uint16
+	// Labels is the connlabel bitmap, bit n is label n of connlabel.conf
+	Labels []byte
+	// TimeStart and TimeStop are in nanoseconds since the epoch, they
+	// are only set with nf_conntrack_timestamp enabled
+	TimeStart   uint64
+	TimeStop    uint64
+	SeqAdjOrig  *ConntrackSeqAdj
+	SeqAdjReply *ConntrackSeqAdj
+	Helper      string
+	SecCtx      string
+}
+
+// toNlData returns the attributes of the flow that can be set with
+// IPCTNL_MSG_CT_NEW, the counters, id, use and timestamps are read only
+func (s *ConntrackFlow) toNlData(family InetFamily) ([]*nl.RtAttr, error) {
+	forward, err := s.Forward.toNlData(nl.CTA_TUPLE_ORIG, family)
+	if err != nil {
+		return nil, err
+	}
+	reverse, err := s.Reverse.toNlData(nl.CTA_TUPLE_REPLY, family)
+	if err != nil {
+		return nil, err
+	}
+	attrs := []*nl.RtAttr{forward, reverse}
+	if s.TimeOut != 0 {
+		attrs = append(attrs, nl.NewRtAttr(nl.CTA_TIMEOUT, htonl(s.TimeOut)))
+	}
+	if s.Status != 0 {
+		attrs = append(attrs, nl.NewRtAttr(nl.CTA_STATUS, htonl(uint32(s.Status))))
+	}
+	if s.Mark != 0 {
+		attrs = append(attrs, nl.NewRtAttr(nl.CTA_MARK, htonl(s.Mark)))
+	}
+	if s.Zone != 0 {
+		attrs = append(attrs, nl.NewRtAttr(nl.CTA_ZONE, htons(s.Zone)))
+	}
+	if s.ProtoInfo != nil {
+		protoinfo := nl.NewRtAttr(nl.CTA_PROTOINFO|int(nl.NLA_F_NESTED), nil)
+		protoinfo.AddChild(s.ProtoInfo.toNlData())
+		attrs = append(attrs, protoinfo)
+	}
+	if s.Helper != "" {
+		help := nl.NewRtAttr(nl.CTA_HELP|int(nl.NLA_F_NESTED), nil)
+		help.AddRtAttr(nl.CTA_HELP_NAME, nl.ZeroTerminated(s.Helper))
+		attrs = append(attrs, help)
+	}
+	if len(s.Labels) > 0 {
+		attrs = append(attrs, nl.NewRtAttr(nl.CTA_LABELS, s.Labels))
+	}
+	if s.SeqAdjOrig != nil {
+		attrs = append(attrs, s.SeqAdjOrig.toNlData(nl.CTA_SEQ_ADJ_ORIG))
+	}
+	if s.SeqAdjReply != nil {
+		attrs = append(attrs, s.SeqAdjReply.toNlData(nl.CTA_SEQ_ADJ_REPLY))
+	}
+	return attrs, nil
+}
+
+// HasLabel returns true if the connlabel bit is set on the flow
+func (s *ConntrackFlow) HasLabel(bit uint) bool {
+	i := int(bit / 8)
+	return i < len(s.Labels) && s.Labels[i]&(1<<(bit%8)) != 0
 }
 
 func (s *ConntrackFlow) String() string {
 	// conntrack cmd output:
 	// udp      17 src=127.0.0.1 dst=127.0.0.1 sport=4001 dport=1234 packets=5 bytes=532 [UNREPLIED] src=127.0.0.1 dst=127.0.0.1 sport=1234 dport=4001 packets=10 bytes=1078 mark=0
-	return fmt.Sprintf("%s\t%d src=%s dst=%s sport=%d dport=%d packets=%d bytes=%d\tsrc=%s dst=%s sport=%d dport=%d packets=%d bytes=%d mark=%d",
-		nl.L4ProtoMap[s.Forward.Protocol], s.Forward.Protocol,
-		s.Forward.SrcIP.String(), s.Forward.DstIP.String(), s.Forward.SrcPort, s.Forward.DstPort, s.Forward.Packets, s.Forward.Bytes,
-		s.Reverse.SrcIP.String(), s.Reverse.DstIP.String(), s.Reverse.SrcPort, s.Reverse.DstPort, s.Reverse.Packets, s.Reverse.Bytes,
-		s.Mark)
+	// tcp      6 431999 ESTABLISHED src=127.0.0.1 dst=127.0.0.1 sport=4001 dport=80 packets=3 bytes=164 src=127.0.0.1 dst=127.0.0.1 sport=80 dport=4001 packets=2 bytes=112 [ASSURED] mark=0 zone=1 use=1
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\t%d", nl.L4ProtoMap[s.Forward.Protocol], s.Forward.Protocol)
+	if s.TimeOut != 0 {
+		fmt.Fprintf(&b, " %d", s.TimeOut)
+	}
+	if tcp, ok := s.ProtoInfo.(*ProtoInfoTCP); ok {
+		fmt.Fprintf(&b, " %s", tcp.State)
+	}
+	fmt.Fprintf(&b, " %s", s.Forward.String())
+	if s.Status != 0 && s.Status&IPS_SEEN_REPLY == 0 {
+		b.WriteString(" [UNREPLIED]")
+	}
+	fmt.Fprintf(&b, "\t%s", s.Reverse.String())
+	if s.Status&IPS_ASSURED != 0 {
+		b.WriteString(" [ASSURED]")
+	}
+	fmt.Fprintf(&b, " mark=%d", s.Mark)
+	if s.SecCtx != "" {
+		fmt.Fprintf(&b, " secctx=%s", s.SecCtx)
+	}
+	if s.Zone != 0 {
+		fmt.Fprintf(&b, " zone=%d", s.Zone)
+	}
+	if s.Helper != "" {
+		fmt.Fprintf(&b, " helper=%s", s.Helper)
+	}
+	if s.Use != 0 {
+		fmt.Fprintf(&b, " use=%d", s.Use)
+	}
+	return b.String()
 }
 
 // This method parse the ip tuple structure
 // The message structure is the following:
-// <len, [CTA_IP_V4_SRC|CTA_IP_V6_SRC], 16 bytes for the IP>
-// <len, [CTA_IP_V4_DST|CTA_IP_V6_DST], 16 bytes for the IP>
-// <len, NLA_F_NESTED|nl.CTA_TUPLE_PROTO, 1 byte for the protocol, 3 bytes of padding>
+// <len, NLA_F_NESTED|CTA_TUPLE_IP> containing
+// <len, [CTA_IP_V4_SRC|CTA_IP_V6_SRC], 4 or 16 bytes for the IP>
+// <len, [CTA_IP_V4_DST|CTA_IP_V6_DST], 4 or 16 bytes for the IP>
+// <len, NLA_F_NESTED|CTA_TUPLE_PROTO> containing
+// <len, CTA_PROTO_NUM, 1 byte for the protocol, 3 bytes of padding>
 // <len, CTA_PROTO_SRC_PORT, 2 bytes for the source port, 2 bytes of padding>
-// <len, CTA_PROTO_DST_PORT, 2 bytes for the source port, 2 bytes of padding>
-func parseIpTuple(reader *bytes.Reader, tpl *ipTuple) uint8 {
-	for i := 0; i < 2; i++ {
-		_, t, _, v := parseNfAttrTLV(reader)
-		switch t {
-		case nl.CTA_IP_V4_SRC, nl.CTA_IP_V6_SRC:
-			tpl.SrcIP = v
-		case nl.CTA_IP_V4_DST, nl.CTA_IP_V6_DST:
-			tpl.DstIP = v
-		}
+// <len, CTA_PROTO_DST_PORT, 2 bytes for the destination port, 2 bytes of padding>
+// or the CTA_PROTO_ICMP[V6]_ID, _TYPE and _CODE for ICMP
+func parseIpTuple(data []byte, tpl *ipTuple) error {
+	attrs, err := nl.ParseRouteAttr(data)
+	if err != nil {
+		return err
 	}
-	// Skip the next 4 bytes  nl.NLA_F_NESTED|nl.CTA_TUPLE_PROTO
-	reader.Seek(4, seekCurrent)
-	_, t, _, v := parseNfAttrTLV(reader)
-	if t == nl.CTA_PROTO_NUM {
-		tpl.Protocol = uint8(v[0])
-	}
-	// Skip some padding 3 bytes
-	reader.Seek(3, seekCurrent)
-	for i := 0; i < 2; i++ {
-		_, t, _ := parseNfAttrTL(reader)
-		switch t {
-		case nl.CTA_PROTO_SRC_PORT:
-			parseBERaw16(reader, &tpl.SrcPort)
-		case nl.CTA_PROTO_DST_PORT:
-			parseBERaw16(reader, &tpl.DstPort)
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.CTA_TUPLE_IP:
+			ips, err := nl.ParseRouteAttr(attr.Value)
+			if err != nil {
+				return err
+			}
+			for _, ip := range ips {
+				switch ip.Attr.Type & nl.NLA_TYPE_MASK {
+				case nl.CTA_IP_V4_SRC, nl.CTA_IP_V6_SRC:
+					tpl.SrcIP = net.IP(ip.Value)
+				case nl.CTA_IP_V4_DST, nl.CTA_IP_V6_DST:
+					tpl.DstIP = net.IP(ip.Value)
+				}
+			}
+		case nl.CTA_TUPLE_PROTO:
+			protos, err := nl.ParseRouteAttr(attr.Value)
+			if err != nil {
+				return err
+			}
+			for _, proto := range protos {
+				switch proto.Attr.Type & nl.NLA_TYPE_MASK {
+				case nl.CTA_PROTO_NUM:
+					tpl.Protocol = proto.Value[0]
+				case nl.CTA_PROTO_SRC_PORT:
+					tpl.SrcPort = binary.BigEndian.Uint16(proto.Value)
+				case nl.CTA_PROTO_DST_PORT:
+					tpl.DstPort = binary.BigEndian.Uint16(proto.Value)
+				case nl.CTA_PROTO_ICMP_ID, nl.CTA_PROTO_ICMPV6_ID:
+					tpl.ICMPID = binary.BigEndian.Uint16(proto.Value)
+				case nl.CTA_PROTO_ICMP_TYPE, nl.CTA_PROTO_ICMPV6_TYPE:
+					tpl.ICMPType = proto.Value[0]
+				case nl.CTA_PROTO_ICMP_CODE, nl.CTA_PROTO_ICMPV6_CODE:
+					tpl.ICMPCode = proto.Value[0]
+				}
+			}
 		}
-		// Skip some padding 2 byte
-		reader.Seek(2, seekCurrent)
 	}
-	return tpl.Protocol
+	return nil
 }
 
 func parseNfAttrTLV(r *bytes.Reader) (isNested bool, attrType, len uint16, value []byte) {
@@ -215,82 +823,478 @@ func parseNfAttrTL(r *bytes.Reader) (isNested bool, attrType, len uint16) {
 	return isNested, attrType, len
 }
 
-func parseBERaw16(r *bytes.Reader, v *uint16) {
-	binary.Read(r, binary.BigEndian, v)
-}
-
-func parseBERaw32(r *bytes.Reader, v *uint32) {
-	binary.Read(r, binary.BigEndian, v)
+func parseByteAndPacketCounters(data []byte) (bytes, packets uint64, err error) {
+	attrs, err := nl.ParseRouteAttr(data)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.CTA_COUNTERS_BYTES:
+			bytes = binary.BigEndian.Uint64(attr.Value)
+		case nl.CTA_COUNTERS_PACKETS:
+			packets = binary.BigEndian.Uint64(attr.Value)
+		}
+	}
+	return bytes, packets, nil
 }
 
-func parseBERaw64(r *bytes.Reader, v *uint64) {
-	binary.Read(r, binary.BigEndian, v)
+func parseProtoInfo(data []byte) (ProtoInfo, error) {
+	attrs, err := nl.ParseRouteAttr(data)
+	if err != nil {
+		return nil, err
+	}
+	for _, attr := range attrs {
+		infos, err := nl.ParseRouteAttr(attr.Value)
+		if err != nil {
+			return nil, err
+		}
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.CTA_PROTOINFO_TCP:
+			p := &ProtoInfoTCP{}
+			for _, info := range infos {
+				switch info.Attr.Type & nl.NLA_TYPE_MASK {
+				case nl.CTA_PROTOINFO_TCP_STATE:
+					p.State = ConntrackTCPState(info.Value[0])
+				case nl.CTA_PROTOINFO_TCP_WSCALE_ORIGINAL:
+					p.WScaleOrig = info.Value[0]
+				case nl.CTA_PROTOINFO_TCP_WSCALE_REPLY:
+					p.WScaleReply = info.Value[0]
+				// struct nf_ct_tcp_flags { __u8 flags; __u8 mask; }
+				case nl.CTA_PROTOINFO_TCP_FLAGS_ORIGINAL:
+					p.FlagsOrig = info.Value[0]
+				case nl.CTA_PROTOINFO_TCP_FLAGS_REPLY:
+					p.FlagsReply = info.Value[0]
+				}
+			}
+			return p, nil
+		case nl.CTA_PROTOINFO_DCCP:
+			p := &ProtoInfoDCCP{}
+			for _, info := range infos {
+				if info.Attr.Type&nl.NLA_TYPE_MASK == nl.CTA_PROTOINFO_DCCP_STATE {
+					p.State = info.Value[0]
+				}
+			}
+			return p, nil
+		case nl.CTA_PROTOINFO_SCTP:
+			p := &ProtoInfoSCTP{}
+			for _, info := range infos {
+				switch info.Attr.Type & nl.NLA_TYPE_MASK {
+				case nl.CTA_PROTOINFO_SCTP_STATE:
+					p.State = info.Value[0]
+				case nl.CTA_PROTOINFO_SCTP_VTAG_ORIGINAL:
+					p.VTagOrig = binary.BigEndian.Uint32(info.Value)
+				case nl.CTA_PROTOINFO_SCTP_VTAG_REPLY:
+					p.VTagReply = binary.BigEndian.Uint32(info.Value)
+				}
+			}
+			return p, nil
+		}
+	}
+	return nil, nil
 }
 
-func parseByteAndPacketCounters(r *bytes.Reader) (bytes, packets uint64) {
-	for i := 0; i < 2; i++ {
-		switch _, t, _ := parseNfAttrTL(r); t {
-		case nl.CTA_COUNTERS_BYTES:
-			parseBERaw64(r, &bytes)
-		case nl.CTA_COUNTERS_PACKETS:
-			parseBERaw64(r, &packets)
-		default:
-			return
+func parseSeqAdj(data []byte) (*ConntrackSeqAdj, error) {
+	attrs, err := nl.ParseRouteAttr(data)
+	if err != nil {
+		return nil, err
+	}
+	seqAdj := &ConntrackSeqAdj{}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.CTA_SEQADJ_CORRECTION_POS:
+			seqAdj.CorrectionPos = binary.BigEndian.Uint32(attr.Value)
+		case nl.CTA_SEQADJ_OFFSET_BEFORE:
+			seqAdj.OffsetBefore = binary.BigEndian.Uint32(attr.Value)
+		case nl.CTA_SEQADJ_OFFSET_AFTER:
+			seqAdj.OffsetAfter = binary.BigEndian.Uint32(attr.Value)
 		}
 	}
-	return
+	return seqAdj, nil
 }
 
-func parseConnectionMark(r *bytes.Reader) (mark uint32) {
-	parseBERaw32(r, &mark)
-	return
+// parseNestedString returns the string of the attribute of the given type
+// nested in data, as used by CTA_HELP and CTA_SECCTX
+func parseNestedString(data []byte, attrType uint16) (string, error) {
+	attrs, err := nl.ParseRouteAttr(data)
+	if err != nil {
+		return "", err
+	}
+	for _, attr := range attrs {
+		if attr.Attr.Type&nl.NLA_TYPE_MASK == attrType {
+			return strings.TrimRight(string(attr.Value), "\x00"), nil
+		}
+	}
+	return "", nil
 }
 
 func parseRawData(data []byte) *ConntrackFlow {
 	s := &ConntrackFlow{}
 	// First there is the Nfgenmsg header
 	// consume only the family field
-	reader := bytes.NewReader(data)
-	binary.Read(reader, nl.NativeEndian(), &s.FamilyType)
+	s.FamilyType = nl.DeserializeNfgenmsg(data).NfgenFamily
 
-	// skip rest of the Netfilter header
-	reader.Seek(3, seekCurrent)
 	// The message structure is the following:
 	// <len, NLA_F_NESTED|CTA_TUPLE_ORIG> 4 bytes
-	// <len, NLA_F_NESTED|CTA_TUPLE_IP> 4 bytes
 	// flow information of the forward flow
 	// <len, NLA_F_NESTED|CTA_TUPLE_REPLY> 4 bytes
-	// <len, NLA_F_NESTED|CTA_TUPLE_IP> 4 bytes
 	// flow information of the reverse flow
-	for reader.Len() > 0 {
-		if nested, t, l := parseNfAttrTL(reader); nested {
-			switch t {
-			case nl.CTA_TUPLE_ORIG:
-				if nested, t, _ = parseNfAttrTL(reader); nested && t == nl.CTA_TUPLE_IP {
-					parseIpTuple(reader, &s.Forward)
-				}
-			case nl.CTA_TUPLE_REPLY:
-				if nested, t, _ = parseNfAttrTL(reader); nested && t == nl.CTA_TUPLE_IP {
-					parseIpTuple(reader, &s.Reverse)
-				} else {
-					// Header not recognized skip it
-					reader.Seek(int64(l), seekCurrent)
+	// followed by the optional attributes of the entry
+	attrs, err := nl.ParseRouteAttr(data[nl.SizeofNfgenmsg:])
+	if err != nil {
+		return s
+	}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.CTA_TUPLE_ORIG:
+			err = parseIpTuple(attr.Value, &s.Forward)
+		case nl.CTA_TUPLE_REPLY:
+			err = parseIpTuple(attr.Value, &s.Reverse)
+		case nl.CTA_COUNTERS_ORIG:
+			s.Forward.Bytes, s.Forward.Packets, err = parseByteAndPacketCounters(attr.Value)
+		case nl.CTA_COUNTERS_REPLY:
+			s.Reverse.Bytes, s.Reverse.Packets, err = parseByteAndPacketCounters(attr.Value)
+		case nl.CTA_MARK:
+			s.Mark = binary.BigEndian.Uint32(attr.Value)
+		case nl.CTA_STATUS:
+			s.Status = ConntrackStatus(binary.BigEndian.Uint32(attr.Value))
+		case nl.CTA_TIMEOUT:
+			s.TimeOut = binary.BigEndian.Uint32(attr.Value)
+		case nl.CTA_PROTOINFO:
+			s.ProtoInfo, err = parseProtoInfo(attr.Value)
+		case nl.CTA_USE:
+			s.Use = binary.BigEndian.Uint32(attr.Value)
+		case nl.CTA_ID:
+			s.ID = binary.BigEndian.Uint32(attr.Value)
+		case nl.CTA_ZONE:
+			s.Zone = binary.BigEndian.Uint16(attr.Value)
+		case nl.CTA_LABELS:
+			s.Labels = make([]byte, len(attr.Value))
+			copy(s.Labels, attr.Value)
+		case nl.CTA_TIMESTAMP:
+			var ts []syscall.NetlinkRouteAttr
+			ts, err = nl.ParseRouteAttr(attr.Value)
+			for _, t := range ts {
+				switch t.Attr.Type & nl.NLA_TYPE_MASK {
+				case nl.CTA_TIMESTAMP_START:
+					s.TimeStart = binary.BigEndian.Uint64(t.Value)
+				case nl.CTA_TIMESTAMP_STOP:
+					s.TimeStop = binary.BigEndian.Uint64(t.Value)
 				}
-			case nl.CTA_COUNTERS_ORIG:
-				s.Forward.Bytes, s.Forward.Packets = parseByteAndPacketCounters(reader)
-			case nl.CTA_COUNTERS_REPLY:
-				s.Reverse.Bytes, s.Reverse.Packets = parseByteAndPacketCounters(reader)
-			}
-		} else {
-			switch t {
-			case nl.CTA_MARK:
-				s.Mark = parseConnectionMark(reader)
 			}
+		case nl.CTA_SEQ_ADJ_ORIG:
+			s.SeqAdjOrig, err = parseSeqAdj(attr.Value)
+		case nl.CTA_SEQ_ADJ_REPLY:
+			s.SeqAdjReply, err = parseSeqAdj(attr.Value)
+		case nl.CTA_HELP:
+			s.Helper, err = parseNestedString(attr.Value, nl.CTA_HELP_NAME)
+		case nl.CTA_SECCTX:
+			s.SecCtx, err = parseNestedString(attr.Value, nl.CTA_SECCTX_NAME)
+		}
+		if err != nil {
+			// keep what could be parsed so far
+			break
 		}
 	}
 	return s
 }
 
+// ConntrackExpectFlags holds the NF_CT_EXPECT_* flags of an expectation
+type ConntrackExpectFlags uint32
+
+const (
+	NF_CT_EXPECT_PERMANENT ConntrackExpectFlags = 1 << iota
+	NF_CT_EXPECT_INACTIVE
+	NF_CT_EXPECT_USERSPACE
+)
+
+func (f ConntrackExpectFlags) String() string {
+	var names []string
+	if f&NF_CT_EXPECT_PERMANENT != 0 {
+		names = append(names, "PERMANENT")
+	}
+	if f&NF_CT_EXPECT_INACTIVE != 0 {
+		names = append(names, "INACTIVE")
+	}
+	if f&NF_CT_EXPECT_USERSPACE != 0 {
+		names = append(names, "USERSPACE")
+	}
+	if rest := f &^ (NF_CT_EXPECT_PERMANENT | NF_CT_EXPECT_INACTIVE | NF_CT_EXPECT_USERSPACE); rest != 0 {
+		names = append(names, fmt.Sprintf("0x%x", uint32(rest)))
+	}
+	return strings.Join(names, ",")
+}
+
+// ConntrackExpectNAT is the NAT applied to the expected connection, the
+// Tuple holds the address and port the expectation is mangled to in the
+// Dir direction (0 original, 1 reply)
+type ConntrackExpectNAT struct {
+	Dir   uint32
+	Tuple ipTuple
+}
+
+// ConntrackExpect is an entry of the expectation table: a connection the
+// helper of the Master connection expects, such as the data connection of
+// FTP or the RTP streams of SIP. Only the bits set in the Mask addresses and
+// ports of the expected Tuple are compared to the new connections.
+type ConntrackExpect struct {
+	FamilyType uint8
+	Master     ipTuple
+	Tuple      ipTuple
+	Mask       ipTuple
+	// TimeOut is the remaining lifetime of the expectation in seconds
+	TimeOut uint32
+	ID      uint32
+	Helper  string
+	Zone    uint16
+	Flags   ConntrackExpectFlags
+	Class   uint32
+	NAT     *ConntrackExpectNAT
+}
+
+func (e *ConntrackExpect) String() string {
+	// conntrack cmd output:
+	// 297 proto=6 src=10.0.0.1 dst=10.0.0.2 sport=0 dport=40000 mask-src=255.255.255.255 mask-dst=255.255.255.255 sport=0 dport=65535 master-src=10.0.0.1 master-dst=10.0.0.2 sport=40001 dport=21 class=0 helper=ftp
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d proto=%d src=%s dst=%s sport=%d dport=%d", e.TimeOut, e.Tuple.Protocol,
+		e.Tuple.SrcIP, e.Tuple.DstIP, e.Tuple.SrcPort, e.Tuple.DstPort)
+	fmt.Fprintf(&b, " mask-src=%s mask-dst=%s sport=%d dport=%d", e.Mask.SrcIP, e.Mask.DstIP, e.Mask.SrcPort, e.Mask.DstPort)
+	fmt.Fprintf(&b, " master-src=%s master-dst=%s sport=%d dport=%d", e.Master.SrcIP, e.Master.DstIP, e.Master.SrcPort, e.Master.DstPort)
+	if e.Flags != 0 {
+		fmt.Fprintf(&b, " %s", e.Flags)
+	}
+	fmt.Fprintf(&b, " class=%d", e.Class)
+	if e.Zone != 0 {
+		fmt.Fprintf(&b, " zone=%d", e.Zone)
+	}
+	if e.Helper != "" {
+		fmt.Fprintf(&b, " helper=%s", e.Helper)
+	}
+	if e.NAT != nil {
+		fmt.Fprintf(&b, " nat-dir=%d nat-src=%s sport=%d", e.NAT.Dir, e.NAT.Tuple.SrcIP, e.NAT.Tuple.SrcPort)
+	}
+	return b.String()
+}
+
+// ConntrackExpectEvent is sent down the channel of ConntrackExpectSubscribe
+// for each expectation event. The kernel sends NEW and DESTROY events only.
+type ConntrackExpectEvent struct {
+	Type   ConntrackEventType
+	Expect *ConntrackExpect
+}
+
+// ConntrackExpectList returns the expectations of a specific family
+// conntrack -L expect [options]          List expectation table
+func ConntrackExpectList(family InetFamily) ([]*ConntrackExpect, error) {
+	return pkgHandle.ConntrackExpectList(family)
+}
+
+// ConntrackExpectCreate creates a new expectation, it fails if the
+// expectation already exists. The master connection must exist and the Mask
+// addresses must be set. Older kernels accept a master without a helper if
+// the TimeOut is set and flag the expectation NF_CT_EXPECT_USERSPACE, recent
+// ones return EOPNOTSUPP and need the helper assigned to the master, e.g. with
+// the Helper of ConntrackCreate.
+// conntrack -I expect parameters         Create an expectation
+func ConntrackExpectCreate(family InetFamily, expect *ConntrackExpect) error {
+	return pkgHandle.ConntrackExpectCreate(family, expect)
+}
+
+// ConntrackExpectDelete deletes the expectation matching the expected Tuple
+// and the Zone, and the ID if it is set.
+// conntrack -D expect parameters         Delete an expectation
+func ConntrackExpectDelete(family InetFamily, expect *ConntrackExpect) error {
+	return pkgHandle.ConntrackExpectDelete(family, expect)
+}
+
+// ConntrackExpectList returns the expectations of a specific family using the netlink handle passed
+// conntrack -L expect [options]          List expectation table
+func (h *Handle) ConntrackExpectList(family InetFamily) ([]*ConntrackExpect, error) {
+	req := h.newConntrackRequest(ConntrackExpectTable, family, nl.IPCTNL_MSG_EXP_GET, unix.NLM_F_DUMP)
+	res, err := req.Execute(unix.NETLINK_NETFILTER, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*ConntrackExpect
+	for _, dataRaw := range res {
+		result = append(result, parseExpectRawData(dataRaw))
+	}
+	return result, nil
+}
+
+// ConntrackExpectCreate creates a new expectation using the netlink handle passed, it fails if
+// the expectation already exists. The master connection must exist and the Mask addresses must
+// be set. Older kernels accept a master without a helper if the TimeOut is set and flag the
+// expectation NF_CT_EXPECT_USERSPACE, recent ones return EOPNOTSUPP and need the helper assigned
+// to the master, e.g. with the Helper of ConntrackCreate.
+// conntrack -I expect parameters         Create an expectation
+func (h *Handle) ConntrackExpectCreate(family InetFamily, expect *ConntrackExpect) error {
+	attrs, err := expect.toNlData(family)
+	if err != nil {
+		return err
+	}
+	req := h.newConntrackRequest(ConntrackExpectTable, family, nl.IPCTNL_MSG_EXP_NEW, unix.NLM_F_ACK|unix.NLM_F_CREATE|unix.NLM_F_EXCL)
+	for _, attr := range attrs {
+		req.AddData(attr)
+	}
+	_, err = req.Execute(unix.NETLINK_NETFILTER, 0)
+	return err
+}
+
+// ConntrackExpectDelete deletes the expectation matching the expected Tuple and the Zone, and
+// the ID if it is set, using the netlink handle passed.
+// conntrack -D expect parameters         Delete an expectation
+func (h *Handle) ConntrackExpectDelete(family InetFamily, expect *ConntrackExpect) error {
+	tuple, err := expect.Tuple.toNlData(nl.CTA_EXPECT_TUPLE, family)
+	if err != nil {
+		return err
+	}
+	req := h.newConntrackRequest(ConntrackExpectTable, family, nl.IPCTNL_MSG_EXP_DELETE, unix.NLM_F_ACK)
+	req.AddData(tuple)
+	if expect.Zone != 0 {
+		req.AddData(nl.NewRtAttr(nl.CTA_EXPECT_ZONE, htons(expect.Zone)))
+	}
+	if expect.ID != 0 {
+		req.AddData(nl.NewRtAttr(nl.CTA_EXPECT_ID, htonl(expect.ID)))
+	}
+	_, err = req.Execute(unix.NETLINK_NETFILTER, 0)
+	return err
+}
+
+// ConntrackExpectSubscribe takes a chan down which notifications will be sent
+// when expectations are created or destroyed. Close the 'done' chan to stop
+// subscription.
+// Equivalent to: `conntrack -E expect`
+func ConntrackExpectSubscribe(ch chan<- ConntrackExpectEvent, done <-chan struct{}) error {
+	return conntrackExpectSubscribeAt(netns.None(), netns.None(), ch, done, nil, ConntrackEventAll, 0)
+}
+
+// ConntrackExpectSubscribeWithOptions work like ConntrackExpectSubscribe but
+// enable to provide the same options as ConntrackSubscribeWithOptions.
+func ConntrackExpectSubscribeWithOptions(ch chan<- ConntrackExpectEvent, done <-chan struct{}, options ConntrackSubscribeOptions) error {
+	if options.Namespace == nil {
+		none := netns.None()
+		options.Namespace = &none
+	}
+	if options.EventMask == 0 {
+		options.EventMask = ConntrackEventAll
+	}
+	return conntrackExpectSubscribeAt(*options.Namespace, netns.None(), ch, done, options.ErrorCallback, options.EventMask, options.ReceiveBufferSize)
+}
+
+func conntrackExpectSubscribeAt(newNs, curNs netns.NsHandle, ch chan<- ConntrackExpectEvent, done <-chan struct{}, cberr func(error), mask ConntrackEventType, rcvbuf int) error {
+	return conntrackEventsSubscribeAt(newNs, curNs, ConntrackExpectTable, done, cberr, mask, rcvbuf,
+		func(t ConntrackEventType, data []byte) {
+			ch <- ConntrackExpectEvent{Type: t, Expect: parseExpectRawData(data)}
+		},
+		func() { close(ch) })
+}
+
+// toNlData returns the attributes of the expectation that can be set with
+// IPCTNL_MSG_EXP_NEW
+func (e *ConntrackExpect) toNlData(family InetFamily) ([]*nl.RtAttr, error) {
+	var attrs []*nl.RtAttr
+	for _, t := range []struct {
+		attrType int
+		tuple    *ipTuple
+	}{
+		{nl.CTA_EXPECT_MASTER, &e.Master},
+		{nl.CTA_EXPECT_TUPLE, &e.Tuple},
+		{nl.CTA_EXPECT_MASK, &e.Mask},
+	} {
+		attr, err := t.tuple.toNlData(t.attrType, family)
+		if err != nil {
+			return nil, err
+		}
+		attrs = append(attrs, attr)
+	}
+	if e.TimeOut != 0 {
+		attrs = append(attrs, nl.NewRtAttr(nl.CTA_EXPECT_TIMEOUT, htonl(e.TimeOut)))
+	}
+	if e.Helper != "" {
+		attrs = append(attrs, nl.NewRtAttr(nl.CTA_EXPECT_HELP_NAME, nl.ZeroTerminated(e.Helper)))
+	}
+	if e.Zone != 0 {
+		attrs = append(attrs, nl.NewRtAttr(nl.CTA_EXPECT_ZONE, htons(e.Zone)))
+	}
+	if e.Flags != 0 {
+		attrs = append(attrs, nl.NewRtAttr(nl.CTA_EXPECT_FLAGS, htonl(uint32(e.Flags))))
+	}
+	if e.Class != 0 {
+		attrs = append(attrs, nl.NewRtAttr(nl.CTA_EXPECT_CLASS, htonl(e.Class)))
+	}
+	if e.NAT != nil {
+		tuple, err := e.NAT.Tuple.toNlData(nl.CTA_EXPECT_NAT_TUPLE, family)
+		if err != nil {
+			return nil, err
+		}
+		nat := nl.NewRtAttr(nl.CTA_EXPECT_NAT|int(nl.NLA_F_NESTED), nil)
+		nat.AddRtAttr(nl.CTA_EXPECT_NAT_DIR, htonl(e.NAT.Dir))
+		nat.AddChild(tuple)
+		attrs = append(attrs, nat)
+	}
+	return attrs, nil
+}
+
+func parseExpectNAT(data []byte) (*ConntrackExpectNAT, error) {
+	attrs, err := nl.ParseRouteAttr(data)
+	if err != nil {
+		return nil, err
+	}
+	nat := &ConntrackExpectNAT{}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.CTA_EXPECT_NAT_DIR:
+			nat.Dir = binary.BigEndian.Uint32(attr.Value)
+		case nl.CTA_EXPECT_NAT_TUPLE:
+			if err := parseIpTuple(attr.Value, &nat.Tuple); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return nat, nil
+}
+
+func parseExpectRawData(data []byte) *ConntrackExpect {
+	e := &ConntrackExpect{}
+	e.FamilyType = nl.DeserializeNfgenmsg(data).NfgenFamily
+
+	attrs, err := nl.ParseRouteAttr(data[nl.SizeofNfgenmsg:])
+	if err != nil {
+		return e
+	}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.CTA_EXPECT_MASTER:
+			err = parseIpTuple(attr.Value, &e.Master)
+		case nl.CTA_EXPECT_TUPLE:
+			err = parseIpTuple(attr.Value, &e.Tuple)
+		case nl.CTA_EXPECT_MASK:
+			err = parseIpTuple(attr.Value, &e.Mask)
+		case nl.CTA_EXPECT_TIMEOUT:
+			e.TimeOut = binary.BigEndian.Uint32(attr.Value)
+		case nl.CTA_EXPECT_ID:
+			e.ID = binary.BigEndian.Uint32(attr.Value)
+		case nl.CTA_EXPECT_HELP_NAME:
+			e.Helper = strings.TrimRight(string(attr.Value), "\x00")
+		case nl.CTA_EXPECT_ZONE:
+			e.Zone = binary.BigEndian.Uint16(attr.Value)
+		case nl.CTA_EXPECT_FLAGS:
+			e.Flags = ConntrackExpectFlags(binary.BigEndian.Uint32(attr.Value))
+		case nl.CTA_EXPECT_CLASS:
+			e.Class = binary.BigEndian.Uint32(attr.Value)
+		case nl.CTA_EXPECT_NAT:
+			e.NAT, err = parseExpectNAT(attr.Value)
+		}
+		if err != nil {
+			// keep what could be parsed so far
+			break
+		}
+	}
+	return e
+}
+
 // Conntrack parameters and options:
 //   -n, --src-nat ip                      source NAT ip
 //   -g, --dst-nat ip                      destination NAT ip
@@ -346,9 +1350,15 @@ type CustomConntrackFilter interface {
 }
 
 type ConntrackFilter struct {
-	ipFilter    map[ConntrackFilterType]net.IP
-	portFilter  map[ConntrackFilterType]uint16
-	protoFilter uint8
+	ipFilter       map[ConntrackFilterType]net.IP
+	portFilter     map[ConntrackFilterType]uint16
+	protoFilter    uint8
+	zoneFilter     *uint16
+	markFilter     *uint32
+	markMask       uint32
+	statusFilter   ConntrackStatus
+	tcpStateFilter *ConntrackTCPState
+	labelFilter    []byte
 }
 
 // AddIP adds an IP to the conntrack filter
@@ -391,10 +1401,81 @@ func (f *ConntrackFilter) AddProtocol(proto uint8) error {
 	return nil
 }
 
+// AddZone adds the conntrack zone to the conntrack filter
+// -w, --zone value              Set conntrack zone
+func (f *ConntrackFilter) AddZone(zone uint16) error {
+	if f.zoneFilter != nil {
+		return errors.New("Filter attribute already present")
+	}
+	f.zoneFilter = &zone
+	return nil
+}
+
+// AddMark adds the connection mark to the conntrack filter. Only the bits
+// set in mask are compared, a zero mask compares the whole mark
+// -m, --mark mark[/mask]        Match mark
+func (f *ConntrackFilter) AddMark(mark, mask uint32) error {
+	if f.markFilter != nil {
+		return errors.New("Filter attribute already present")
+	}
+	if mask == 0 {
+		mask = ^uint32(0)
+	}
+	f.markFilter = &mark
+	f.markMask = mask
+	return nil
+}
+
+// AddStatus adds status bits to the conntrack filter, a flow matches
+// if all of them are set
+// -u, --status status           Match status, eg. ASSURED
+func (f *ConntrackFilter) AddStatus(status ConntrackStatus) error {
+	if status == 0 {
+		return errors.New("Filter attribute without any status bit")
+	}
+	if f.statusFilter != 0 {
+		return errors.New("Filter attribute already present")
+	}
+	f.statusFilter = status
+	return nil
+}
+
+// AddTCPState adds the TCP connection state to the conntrack filter if the
+// Layer 4 protocol is TCP
+// --state state                 Match TCP state, eg. ESTABLISHED
+func (f *ConntrackFilter) AddTCPState(state ConntrackTCPState) error {
+	if f.protoFilter != unix.IPPROTO_TCP {
+		return fmt.Errorf("Filter attribute not available without the TCP Layer 4 protocol: %d", f.protoFilter)
+	}
+	if f.tcpStateFilter != nil {
+		return errors.New("Filter attribute already present")
+	}
+	f.tcpStateFilter = &state
+	return nil
+}
+
+// AddLabel adds a connlabel bit to the conntrack filter, a flow matches
+// if all the labels added are set
+// -l, --label label             Match label
+func (f *ConntrackFilter) AddLabel(bit uint) error {
+	i := int(bit / 8)
+	if i >= len(f.labelFilter) {
+		labels := make([]byte, i+1)
+		copy(labels, f.labelFilter)
+		f.labelFilter = labels
+	}
+	if f.labelFilter[i]&(1<<(bit%8)) != 0 {
+		return errors.New("Filter attribute already present")
+	}
+	f.labelFilter[i] |= 1 << (bit % 8)
+	return nil
+}
+
 // MatchConntrackFlow applies the filter to the flow and returns true if the flow matches the filter
 // false otherwise
 func (f *ConntrackFilter) MatchConntrackFlow(flow *ConntrackFlow) bool {
-	if len(f.ipFilter) == 0 && len(f.portFilter) == 0 && f.protoFilter == 0 {
+	if len(f.ipFilter) == 0 && len(f.portFilter) == 0 && f.protoFilter == 0 &&
+		f.zoneFilter == nil && f.markFilter == nil && f.statusFilter == 0 && len(f.labelFilter) == 0 {
 		// empty filter always not match
 		return false
 	}
@@ -448,6 +1529,35 @@ func (f *ConntrackFilter) MatchConntrackFlow(flow *ConntrackFlow) bool {
 		}
 	}
 
+	// -w, --zone value	Conntrack zone
+	if f.zoneFilter != nil {
+		match = match && *f.zoneFilter == flow.Zone
+	}
+
+	// -m, --mark mark[/mask]	Connection mark
+	if f.markFilter != nil {
+		match = match && *f.markFilter&f.markMask == flow.Mark&f.markMask
+	}
+
+	// -u, --status status	Status bits
+	if f.statusFilter != 0 {
+		match = match && flow.Status&f.statusFilter == f.statusFilter
+	}
+
+	// --state state	TCP state
+	if f.tcpStateFilter != nil {
+		tcp, ok := flow.ProtoInfo.(*ProtoInfoTCP)
+		match = match && ok && tcp.State == *f.tcpStateFilter
+	}
+
+	// -l, --label label	Connection labels
+	for i, bits := range f.labelFilter {
+		if !match {
+			break
+		}
+		match = i < len(flow.Labels) && flow.Labels[i]&bits == bits
+	}
+
 	return match
 }
 
@@ -3,11 +3,16 @@
 package netlink
 
 import (
+	"encoding/binary"
 	"fmt"
 	"net"
+	"reflect"
 	"runtime"
+	"syscall"
 	"testing"
+	"time"
 
+	"github.com/ndupreez/netlink/nl"
 	"github.com/vishvananda/netns"
 	"golang.org/x/sys/unix"
 )
@@ -133,6 +138,9 @@ func TestConntrackTableList(t *testing.T) {
 			flow.Forward.DstPort == 3000 &&
 			(flow.Forward.SrcPort >= 2000 && flow.Forward.SrcPort <= 2005) {
 			found++
+			if flow.Status&IPS_CONFIRMED == 0 || flow.TimeOut == 0 || flow.ID == 0 {
+				t.Errorf("Missing status, timeout or id: %s", flow)
+			}
 		}
 
 		if flow.Forward.Bytes == 0 && flow.Forward.Packets == 0 && flow.Reverse.Bytes == 0 && flow.Reverse.Packets == 0 {
@@ -221,6 +229,118 @@ func TestConntrackTableFlush(t *testing.T) {
 	netns.Set(*origns)
 }
 
+// TestConntrackSubscribe tests the conntrack event subscription
+// Creates some flows, flushes them and checks the NEW and DESTROY events
+func TestConntrackSubscribe(t *testing.T) {
+	skipUnlessRoot(t)
+	setUpNetlinkTestWithKModule(t, "nf_conntrack")
+	setUpNetlinkTestWithKModule(t, "nf_conntrack_netlink")
+	k, m, err := KernelVersion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// conntrack l3proto was unified since 4.19
+	// https://github.com/torvalds/linux/commit/a0ae2562c6c4b2721d9fddba63b7286c13517d9f
+	if k < 4 || k == 4 && m < 19 {
+		setUpNetlinkTestWithKModule(t, "nf_conntrack_ipv4")
+	}
+	// Creates a new namespace and bring up the loopback interface
+	origns, ns, h := nsCreateAndEnter(t)
+	defer netns.Set(*origns)
+	defer origns.Close()
+	defer ns.Close()
+	defer runtime.UnlockOSThread()
+
+	setUpF(t, "/proc/sys/net/netfilter/nf_conntrack_acct", "1")
+
+	ch := make(chan ConntrackEvent)
+	done := make(chan struct{})
+	defer close(done)
+	var lastError error
+	defer func() {
+		if lastError != nil {
+			t.Fatalf("Fatal error received during subscription: %v", lastError)
+		}
+	}()
+	err = ConntrackSubscribeWithOptions(ch, done, ConntrackSubscribeOptions{
+		Namespace:         ns,
+		EventMask:         ConntrackEventNew | ConntrackEventDestroy,
+		ReceiveBufferSize: 1 << 20,
+		ErrorCallback: func(err error) {
+			lastError = err
+		},
+	})
+	CheckErrorFail(t, err)
+
+	// Create 5 udp flows
+	udpFlowCreateProg(t, 5, 5000, "127.0.0.10", 6000)
+
+	isTestFlow := func(flow *ConntrackFlow) bool {
+		return flow.Forward.Protocol == 17 &&
+			flow.Forward.DstIP.Equal(net.ParseIP("127.0.0.10")) &&
+			flow.Forward.DstPort == 6000 &&
+			(flow.Forward.SrcPort >= 5000 && flow.Forward.SrcPort <= 5005)
+	}
+	waitFor := func(typ ConntrackEventType) {
+		for found := 0; found < 5; {
+			select {
+			case update := <-ch:
+				if update.Type == ConntrackEventUpdate {
+					t.Fatal("Received an UPDATE event which is not in the mask")
+				}
+				if update.Type != typ || !isTestFlow(update.Flow) {
+					continue
+				}
+				if typ == ConntrackEventDestroy && update.Flow.Forward.Packets == 0 {
+					t.Errorf("No final counters in the DESTROY event: %s", update.Flow)
+				}
+				found++
+			case <-time.After(time.Second):
+				t.Fatalf("Timed out waiting for %s events, found %d over 5", typ, found)
+			}
+		}
+	}
+	waitFor(ConntrackEventNew)
+
+	// Flush the table
+	err = h.ConntrackTableFlush(ConntrackTable)
+	CheckErrorFail(t, err)
+	waitFor(ConntrackEventDestroy)
+
+	// Switch back to the original namespace
+	netns.Set(*origns)
+}
+
+func TestConntrackEventType(t *testing.T) {
+	for _, tc := range []struct {
+		msgType uint16
+		flags   uint16
+		want    ConntrackEventType
+	}{
+		{ConntrackTable<<8 | nl.IPCTNL_MSG_CT_NEW, unix.NLM_F_CREATE | unix.NLM_F_EXCL, ConntrackEventNew},
+		{ConntrackTable<<8 | nl.IPCTNL_MSG_CT_NEW, 0, ConntrackEventUpdate},
+		{ConntrackTable<<8 | nl.IPCTNL_MSG_CT_DELETE, 0, ConntrackEventDestroy},
+	} {
+		got, err := conntrackEventType(ConntrackTable, syscall.NlMsghdr{Type: tc.msgType, Flags: tc.flags})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tc.want {
+			t.Errorf("Message type %d flags %d: expected %s, got %s", tc.msgType, tc.flags, tc.want, got)
+		}
+	}
+	if _, err := conntrackEventType(ConntrackTable, syscall.NlMsghdr{Type: ConntrackTable<<8 | nl.IPCTNL_MSG_CT_GET}); err == nil {
+		t.Error("Expected an error for a GET message")
+	}
+	if _, err := conntrackEventType(ConntrackTable, syscall.NlMsghdr{Type: ConntrackExpectTable<<8 | nl.IPCTNL_MSG_EXP_NEW}); err == nil {
+		t.Error("Expected an error for an expectation message")
+	}
+	got, err := conntrackEventType(ConntrackExpectTable, syscall.NlMsghdr{Type: ConntrackExpectTable<<8 | nl.IPCTNL_MSG_EXP_DELETE})
+	if err != nil || got != ConntrackEventDestroy {
+		t.Errorf("Expected a DESTROY expectation event, got %s %v", got, err)
+	}
+}
+
 // TestConntrackTableDelete tests the deletion with filter
 // Creates 2 group of flows then deletes only one group and validates the result
 func TestConntrackTableDelete(t *testing.T) {
@@ -507,3 +627,600 @@ func TestConntrackFilter(t *testing.T) {
 		t.Fatalf("Error, there should be only 1 match, v4:%d, v6:%d", v4Match, v6Match)
 	}
 }
+
+func TestParseRawDataExtended(t *testing.T) {
+	be16 := func(v uint16) []byte {
+		b := make([]byte, 2)
+		binary.BigEndian.PutUint16(b, v)
+		return b
+	}
+	be32 := func(v uint32) []byte {
+		b := make([]byte, 4)
+		binary.BigEndian.PutUint32(b, v)
+		return b
+	}
+	be64 := func(v uint64) []byte {
+		b := make([]byte, 8)
+		binary.BigEndian.PutUint64(b, v)
+		return b
+	}
+	nested := func(attrType int) *nl.RtAttr {
+		return nl.NewRtAttr(int(nl.NLA_F_NESTED)|attrType, nil)
+	}
+	tuple := func(attrType int, src, dst string, sport, dport uint16) *nl.RtAttr {
+		tpl := nested(attrType)
+		ip := tpl.AddRtAttr(int(nl.NLA_F_NESTED)|nl.CTA_TUPLE_IP, nil)
+		ip.AddRtAttr(nl.CTA_IP_V4_SRC, net.ParseIP(src).To4())
+		ip.AddRtAttr(nl.CTA_IP_V4_DST, net.ParseIP(dst).To4())
+		proto := tpl.AddRtAttr(int(nl.NLA_F_NESTED)|nl.CTA_TUPLE_PROTO, nil)
+		proto.AddRtAttr(nl.CTA_PROTO_NUM, nl.Uint8Attr(unix.IPPROTO_TCP))
+		proto.AddRtAttr(nl.CTA_PROTO_SRC_PORT, be16(sport))
+		proto.AddRtAttr(nl.CTA_PROTO_DST_PORT, be16(dport))
+		return tpl
+	}
+	counters := func(attrType int, packets, bytes uint64) *nl.RtAttr {
+		c := nested(attrType)
+		c.AddRtAttr(nl.CTA_COUNTERS_PACKETS, be64(packets))
+		c.AddRtAttr(nl.CTA_COUNTERS_BYTES, be64(bytes))
+		return c
+	}
+
+	protoinfo := nested(nl.CTA_PROTOINFO)
+	tcp := protoinfo.AddRtAttr(int(nl.NLA_F_NESTED)|nl.CTA_PROTOINFO_TCP, nil)
+	tcp.AddRtAttr(nl.CTA_PROTOINFO_TCP_STATE, nl.Uint8Attr(uint8(TCP_CONNTRACK_ESTABLISHED)))
+	tcp.AddRtAttr(nl.CTA_PROTOINFO_TCP_WSCALE_ORIGINAL, nl.Uint8Attr(7))
+	tcp.AddRtAttr(nl.CTA_PROTOINFO_TCP_WSCALE_REPLY, nl.Uint8Attr(9))
+	tcp.AddRtAttr(nl.CTA_PROTOINFO_TCP_FLAGS_ORIGINAL, []byte{0x23, 0})
+	tcp.AddRtAttr(nl.CTA_PROTOINFO_TCP_FLAGS_REPLY, []byte{0x22, 0})
+
+	timestamp := nested(nl.CTA_TIMESTAMP)
+	timestamp.AddRtAttr(nl.CTA_TIMESTAMP_START, be64(1000))
+	timestamp.AddRtAttr(nl.CTA_TIMESTAMP_STOP, be64(2000))
+
+	seqAdj := nested(nl.CTA_SEQ_ADJ_ORIG)
+	seqAdj.AddRtAttr(nl.CTA_SEQADJ_CORRECTION_POS, be32(100))
+	seqAdj.AddRtAttr(nl.CTA_SEQADJ_OFFSET_BEFORE, be32(10))
+	seqAdj.AddRtAttr(nl.CTA_SEQADJ_OFFSET_AFTER, be32(20))
+
+	help := nested(nl.CTA_HELP)
+	help.AddRtAttr(nl.CTA_HELP_NAME, nl.ZeroTerminated("ftp"))
+
+	labels := make([]byte, 16)
+	labels[1] = 0x04
+
+	msg := &nl.Nfgenmsg{NfgenFamily: unix.AF_INET, Version: nl.NFNETLINK_V0}
+	data := msg.Serialize()
+	for _, attr := range []*nl.RtAttr{
+		tuple(nl.CTA_TUPLE_ORIG, "10.0.0.1", "10.0.0.2", 40000, 21),
+		tuple(nl.CTA_TUPLE_REPLY, "10.0.0.2", "192.168.0.1", 21, 40000),
+		nl.NewRtAttr(nl.CTA_ID, be32(0xdeadbeef)),
+		nl.NewRtAttr(nl.CTA_STATUS, be32(uint32(IPS_SEEN_REPLY|IPS_ASSURED|IPS_CONFIRMED|IPS_DST_NAT))),
+		counters(nl.CTA_COUNTERS_ORIG, 3, 180),
+		counters(nl.CTA_COUNTERS_REPLY, 2, 120),
+		nl.NewRtAttr(nl.CTA_TIMEOUT, be32(431999)),
+		nl.NewRtAttr(nl.CTA_MARK, be32(0x10)),
+		protoinfo,
+		help,
+		nl.NewRtAttr(nl.CTA_ZONE, be16(7)),
+		nl.NewRtAttr(nl.CTA_USE, be32(1)),
+		nl.NewRtAttr(nl.CTA_LABELS, labels),
+		timestamp,
+		seqAdj,
+	} {
+		data = append(data, attr.Serialize()...)
+	}
+
+	flow := parseRawData(data)
+	if flow.FamilyType != unix.AF_INET {
+		t.Errorf("Wrong family: %d", flow.FamilyType)
+	}
+	if !flow.Forward.SrcIP.Equal(net.ParseIP("10.0.0.1")) || flow.Forward.SrcPort != 40000 || flow.Forward.DstPort != 21 ||
+		flow.Forward.Protocol != unix.IPPROTO_TCP || flow.Forward.Packets != 3 || flow.Forward.Bytes != 180 {
+		t.Errorf("Wrong forward tuple: %+v", flow.Forward)
+	}
+	if !flow.Reverse.DstIP.Equal(net.ParseIP("192.168.0.1")) || flow.Reverse.Packets != 2 || flow.Reverse.Bytes != 120 {
+		t.Errorf("Wrong reverse tuple: %+v", flow.Reverse)
+	}
+	if flow.ID != 0xdeadbeef || flow.TimeOut != 431999 || flow.Mark != 0x10 || flow.Zone != 7 || flow.Use != 1 {
+		t.Errorf("Wrong id/timeout/mark/zone/use: %d/%d/%d/%d/%d", flow.ID, flow.TimeOut, flow.Mark, flow.Zone, flow.Use)
+	}
+	if flow.Status != IPS_SEEN_REPLY|IPS_ASSURED|IPS_CONFIRMED|IPS_DST_NAT {
+		t.Errorf("Wrong status: %s", flow.Status)
+	}
+	if flow.Status.String() != "SEEN_REPLY|ASSURED|CONFIRMED|DST_NAT" {
+		t.Errorf("Wrong status string: %s", flow.Status)
+	}
+	expectedTCP := &ProtoInfoTCP{State: TCP_CONNTRACK_ESTABLISHED, WScaleOrig: 7, WScaleReply: 9, FlagsOrig: 0x23, FlagsReply: 0x22}
+	if tcp, ok := flow.ProtoInfo.(*ProtoInfoTCP); !ok || *tcp != *expectedTCP {
+		t.Errorf("Wrong protoinfo: %+v", flow.ProtoInfo)
+	}
+	if flow.Helper != "ftp" {
+		t.Errorf("Wrong helper: %q", flow.Helper)
+	}
+	if !flow.HasLabel(10) || flow.HasLabel(11) || flow.HasLabel(200) {
+		t.Errorf("Wrong labels: %v", flow.Labels)
+	}
+	if flow.TimeStart != 1000 || flow.TimeStop != 2000 {
+		t.Errorf("Wrong timestamps: %d %d", flow.TimeStart, flow.TimeStop)
+	}
+	if flow.SeqAdjOrig == nil || *flow.SeqAdjOrig != (ConntrackSeqAdj{CorrectionPos: 100, OffsetBefore: 10, OffsetAfter: 20}) || flow.SeqAdjReply != nil {
+		t.Errorf("Wrong sequence adjustment: %+v %+v", flow.SeqAdjOrig, flow.SeqAdjReply)
+	}
+	expected := "tcp\t6 431999 ESTABLISHED src=10.0.0.1 dst=10.0.0.2 sport=40000 dport=21 packets=3 bytes=180" +
+		"\tsrc=10.0.0.2 dst=192.168.0.1 sport=21 dport=40000 packets=2 bytes=120 [ASSURED] mark=16 zone=7 helper=ftp use=1"
+	if flow.String() != expected {
+		t.Errorf("Wrong string:\n%s\nexpected:\n%s", flow.String(), expected)
+	}
+}
+
+func TestConntrackFilterExtended(t *testing.T) {
+	labels := make([]byte, 16)
+	labels[0] = 0x03
+	flows := []ConntrackFlow{
+		{
+			FamilyType: unix.AF_INET,
+			Forward:    ipTuple{Protocol: unix.IPPROTO_TCP},
+			Mark:       0x1234,
+			Status:     IPS_SEEN_REPLY | IPS_ASSURED | IPS_CONFIRMED,
+			ProtoInfo:  &ProtoInfoTCP{State: TCP_CONNTRACK_ESTABLISHED},
+			Zone:       1,
+			Labels:     labels,
+		},
+		{
+			FamilyType: unix.AF_INET,
+			Forward:    ipTuple{Protocol: unix.IPPROTO_TCP},
+			Mark:       0x1200,
+			Status:     IPS_CONFIRMED,
+			ProtoInfo:  &ProtoInfoTCP{State: TCP_CONNTRACK_SYN_SENT},
+		},
+		{
+			FamilyType: unix.AF_INET,
+			Forward:    ipTuple{Protocol: unix.IPPROTO_UDP},
+			Status:     IPS_SEEN_REPLY | IPS_CONFIRMED,
+			Zone:       1,
+		},
+	}
+	count := func(filter *ConntrackFilter) int {
+		var n int
+		for i := range flows {
+			if filter.MatchConntrackFlow(&flows[i]) {
+				n++
+			}
+		}
+		return n
+	}
+
+	filter := &ConntrackFilter{}
+	CheckErrorFail(t, filter.AddZone(1))
+	if err := filter.AddZone(2); err == nil {
+		t.Fatal("Error, it should fail adding same attribute to the filter")
+	}
+	if n := count(filter); n != 2 {
+		t.Fatalf("Error, zone filter matched %d flows instead of 2", n)
+	}
+
+	filter = &ConntrackFilter{}
+	CheckErrorFail(t, filter.AddMark(0x1200, 0xff00))
+	if n := count(filter); n != 2 {
+		t.Fatalf("Error, mark/mask filter matched %d flows instead of 2", n)
+	}
+	filter = &ConntrackFilter{}
+	CheckErrorFail(t, filter.AddMark(0x1200, 0))
+	if n := count(filter); n != 1 {
+		t.Fatalf("Error, mark filter matched %d flows instead of 1", n)
+	}
+
+	filter = &ConntrackFilter{}
+	if err := filter.AddStatus(0); err == nil {
+		t.Fatal("Error, it should fail adding an empty status")
+	}
+	CheckErrorFail(t, filter.AddStatus(IPS_SEEN_REPLY|IPS_CONFIRMED))
+	if n := count(filter); n != 2 {
+		t.Fatalf("Error, status filter matched %d flows instead of 2", n)
+	}
+
+	filter = &ConntrackFilter{}
+	if err := filter.AddTCPState(TCP_CONNTRACK_ESTABLISHED); err == nil {
+		t.Fatal("Error, it should fail adding a TCP state filter without the TCP protocol")
+	}
+	CheckErrorFail(t, filter.AddProtocol(unix.IPPROTO_TCP))
+	CheckErrorFail(t, filter.AddTCPState(TCP_CONNTRACK_ESTABLISHED))
+	if n := count(filter); n != 1 {
+		t.Fatalf("Error, TCP state filter matched %d flows instead of 1", n)
+	}
+
+	filter = &ConntrackFilter{}
+	CheckErrorFail(t, filter.AddLabel(0))
+	CheckErrorFail(t, filter.AddLabel(1))
+	if err := filter.AddLabel(1); err == nil {
+		t.Fatal("Error, it should fail adding same attribute to the filter")
+	}
+	if n := count(filter); n != 1 {
+		t.Fatalf("Error, label filter matched %d flows instead of 1", n)
+	}
+	CheckErrorFail(t, filter.AddLabel(100))
+	if n := count(filter); n != 0 {
+		t.Fatalf("Error, label filter matched %d flows instead of 0", n)
+	}
+}
+
+func TestConntrackFlowSerialize(t *testing.T) {
+	labels := make([]byte, 16)
+	labels[2] = 0x80
+	flow := &ConntrackFlow{
+		FamilyType: unix.AF_INET6,
+		Forward: ipTuple{
+			SrcIP:    net.ParseIP("2001:db8::1"),
+			DstIP:    net.ParseIP("2001:db8::2"),
+			SrcPort:  40000,
+			DstPort:  443,
+			Protocol: unix.IPPROTO_TCP,
+		},
+		Reverse: ipTuple{
+			SrcIP:    net.ParseIP("2001:db8::2"),
+			DstIP:    net.ParseIP("2001:db8::1"),
+			SrcPort:  443,
+			DstPort:  40000,
+			Protocol: unix.IPPROTO_TCP,
+		},
+		Mark:        0x42,
+		Status:      IPS_SEEN_REPLY | IPS_ASSURED,
+		TimeOut:     300,
+		ProtoInfo:   &ProtoInfoTCP{State: TCP_CONNTRACK_ESTABLISHED, WScaleOrig: 7, WScaleReply: 7},
+		Zone:        3,
+		Labels:      labels,
+		SeqAdjReply: &ConntrackSeqAdj{CorrectionPos: 1, OffsetBefore: 2, OffsetAfter: 3},
+		Helper:      "ftp",
+	}
+	attrs, err := flow.toNlData(unix.AF_INET6)
+	CheckErrorFail(t, err)
+	msg := &nl.Nfgenmsg{NfgenFamily: unix.AF_INET6, Version: nl.NFNETLINK_V0}
+	data := msg.Serialize()
+	for _, attr := range attrs {
+		data = append(data, attr.Serialize()...)
+	}
+	parsed := parseRawData(data)
+	if !reflect.DeepEqual(parsed, flow) {
+		t.Fatalf("Flow does not survive serialization:\n%+v\n%+v", parsed, flow)
+	}
+
+	icmp := &ConntrackFlow{
+		Forward: ipTuple{
+			SrcIP:    net.ParseIP("10.0.0.1").To4(),
+			DstIP:    net.ParseIP("10.0.0.2").To4(),
+			Protocol: unix.IPPROTO_ICMP,
+			ICMPType: 8,
+			ICMPID:   1234,
+		},
+		Reverse: ipTuple{
+			SrcIP:    net.ParseIP("10.0.0.2").To4(),
+			DstIP:    net.ParseIP("10.0.0.1").To4(),
+			Protocol: unix.IPPROTO_ICMP,
+			ICMPID:   1234,
+		},
+		TimeOut: 30,
+	}
+	attrs, err = icmp.toNlData(unix.AF_INET)
+	CheckErrorFail(t, err)
+	data = msg.Serialize()
+	for _, attr := range attrs {
+		data = append(data, attr.Serialize()...)
+	}
+	parsed = parseRawData(data)
+	parsed.FamilyType = 0
+	if !reflect.DeepEqual(parsed, icmp) {
+		t.Fatalf("ICMP flow does not survive serialization:\n%+v\n%+v", parsed, icmp)
+	}
+
+	// An IPv6 flow can not be serialized for IPv4
+	if _, err := flow.toNlData(unix.AF_INET); err == nil {
+		t.Fatal("Error, it should fail serializing IPv6 addresses for IPv4")
+	}
+}
+
+// TestConntrackCreateUpdateGet creates a flow, fetches it, updates its mark
+// and timeout and checks the result
+func TestConntrackCreateUpdateGet(t *testing.T) {
+	skipUnlessRoot(t)
+	setUpNetlinkTestWithKModule(t, "nf_conntrack")
+	setUpNetlinkTestWithKModule(t, "nf_conntrack_netlink")
+
+	// Creates a new namespace and bring up the loopback interface
+	origns, ns, h := nsCreateAndEnter(t)
+	defer netns.Set(*origns)
+	defer origns.Close()
+	defer ns.Close()
+	defer runtime.UnlockOSThread()
+
+	flow := &ConntrackFlow{
+		Forward: ipTuple{
+			SrcIP:    net.ParseIP("10.0.0.1"),
+			DstIP:    net.ParseIP("10.0.0.2"),
+			SrcPort:  1000,
+			DstPort:  80,
+			Protocol: unix.IPPROTO_TCP,
+		},
+		Reverse: ipTuple{
+			SrcIP:    net.ParseIP("10.0.0.2"),
+			DstIP:    net.ParseIP("10.0.0.1"),
+			SrcPort:  80,
+			DstPort:  1000,
+			Protocol: unix.IPPROTO_TCP,
+		},
+		Mark:      10,
+		TimeOut:   100,
+		ProtoInfo: &ProtoInfoTCP{State: TCP_CONNTRACK_ESTABLISHED},
+	}
+	CheckErrorFail(t, h.ConntrackCreate(ConntrackTable, unix.AF_INET, flow))
+	if err := h.ConntrackCreate(ConntrackTable, unix.AF_INET, flow); err == nil {
+		t.Fatal("Error, it should fail creating an existing flow")
+	}
+
+	check := func(mark uint32, timeout uint32) {
+		got, err := h.ConntrackGet(ConntrackTable, unix.AF_INET, flow)
+		CheckErrorFail(t, err)
+		if !got.Forward.SrcIP.Equal(flow.Forward.SrcIP) || got.Forward.SrcPort != flow.Forward.SrcPort ||
+			!got.Reverse.SrcIP.Equal(flow.Reverse.SrcIP) || got.Reverse.SrcPort != flow.Reverse.SrcPort {
+			t.Fatalf("Wrong tuples: %s", got)
+		}
+		if got.Mark != mark {
+			t.Fatalf("Wrong mark %d, expected %d", got.Mark, mark)
+		}
+		// the timeout counts down from the value set
+		if got.TimeOut > timeout || got.TimeOut < timeout-5 {
+			t.Fatalf("Wrong timeout %d, expected %d", got.TimeOut, timeout)
+		}
+		if tcp, ok := got.ProtoInfo.(*ProtoInfoTCP); !ok || tcp.State != TCP_CONNTRACK_ESTABLISHED {
+			t.Fatalf("Wrong protoinfo: %+v", got.ProtoInfo)
+		}
+	}
+	check(10, 100)
+
+	flow.Mark = 20
+	flow.TimeOut = 200
+	CheckErrorFail(t, h.ConntrackUpdate(ConntrackTable, unix.AF_INET, flow))
+	check(20, 200)
+
+	// Get by the reverse tuple
+	var reply ConntrackFlow
+	reply.Reverse = flow.Reverse
+	got, err := h.ConntrackGet(ConntrackTable, unix.AF_INET, &reply)
+	CheckErrorFail(t, err)
+	if got.Mark != 20 {
+		t.Fatalf("Wrong flow found by the reverse tuple: %s", got)
+	}
+
+	CheckErrorFail(t, h.ConntrackTableFlush(ConntrackTable))
+	if _, err := h.ConntrackGet(ConntrackTable, unix.AF_INET, flow); err == nil {
+		t.Fatal("Error, the flow should have been flushed")
+	}
+
+	// Updating a missing flow fails
+	if err := h.ConntrackUpdate(ConntrackTable, unix.AF_INET, flow); err == nil {
+		t.Fatal("Error, it should fail updating a missing flow")
+	}
+}
+
+func TestConntrackExpectSerialize(t *testing.T) {
+	expect := &ConntrackExpect{
+		FamilyType: unix.AF_INET,
+		Master: ipTuple{
+			SrcIP:    net.ParseIP("10.0.0.1").To4(),
+			DstIP:    net.ParseIP("10.0.0.2").To4(),
+			SrcPort:  5060,
+			DstPort:  5060,
+			Protocol: unix.IPPROTO_UDP,
+		},
+		Tuple: ipTuple{
+			SrcIP:    net.ParseIP("10.0.0.2").To4(),
+			DstIP:    net.ParseIP("10.0.0.1").To4(),
+			DstPort:  16384,
+			Protocol: unix.IPPROTO_UDP,
+		},
+		Mask: ipTuple{
+			SrcIP:    net.ParseIP("255.255.255.255").To4(),
+			DstIP:    net.ParseIP("255.255.255.255").To4(),
+			DstPort:  0xffff,
+			Protocol: unix.IPPROTO_UDP,
+		},
+		TimeOut: 180,
+		Helper:  "sip",
+		Zone:    2,
+		Flags:   NF_CT_EXPECT_PERMANENT | NF_CT_EXPECT_INACTIVE,
+		Class:   1,
+		NAT: &ConntrackExpectNAT{
+			Dir: 1,
+			Tuple: ipTuple{
+				SrcIP:    net.ParseIP("192.168.0.1").To4(),
+				DstIP:    net.ParseIP("0.0.0.0").To4(),
+				SrcPort:  20000,
+				Protocol: unix.IPPROTO_UDP,
+			},
+		},
+	}
+	attrs, err := expect.toNlData(unix.AF_INET)
+	CheckErrorFail(t, err)
+	msg := &nl.Nfgenmsg{NfgenFamily: unix.AF_INET, Version: nl.NFNETLINK_V0}
+	data := msg.Serialize()
+	for _, attr := range attrs {
+		data = append(data, attr.Serialize()...)
+	}
+	data = append(data, nl.NewRtAttr(nl.CTA_EXPECT_ID, htonl(42)).Serialize()...)
+	parsed := parseExpectRawData(data)
+	expect.ID = 42
+	if !reflect.DeepEqual(parsed, expect) {
+		t.Fatalf("Expectation does not survive serialization:\n%+v\n%+v", parsed, expect)
+	}
+	if expect.Flags.String() != "PERMANENT,INACTIVE" {
+		t.Errorf("Wrong flags string: %s", expect.Flags)
+	}
+
+	// The mask addresses are mandatory
+	expect.Mask = ipTuple{Protocol: unix.IPPROTO_UDP}
+	if _, err := expect.toNlData(unix.AF_INET); err == nil {
+		t.Fatal("Error, it should fail serializing an expectation without mask")
+	}
+}
+
+// TestConntrackExpect creates an FTP connection and an expectation for its
+// data connection, lists and deletes it and checks the events
+func TestConntrackExpect(t *testing.T) {
+	skipUnlessRoot(t)
+	setUpNetlinkTestWithKModule(t, "nf_conntrack")
+	setUpNetlinkTestWithKModule(t, "nf_conntrack_netlink")
+	setUpNetlinkTestWithKModule(t, "nf_conntrack_ftp")
+
+	// Creates a new namespace and bring up the loopback interface
+	origns, ns, h := nsCreateAndEnter(t)
+	defer netns.Set(*origns)
+	defer origns.Close()
+	defer ns.Close()
+	defer runtime.UnlockOSThread()
+
+	ch := make(chan ConntrackExpectEvent)
+	done := make(chan struct{})
+	defer close(done)
+	err := ConntrackExpectSubscribeWithOptions(ch, done, ConntrackSubscribeOptions{Namespace: ns})
+	CheckErrorFail(t, err)
+
+	// The master connection needs a helper to hold expectations
+	master := &ConntrackFlow{
+		Forward: ipTuple{
+			SrcIP:    net.ParseIP("10.0.0.1"),
+			DstIP:    net.ParseIP("10.0.0.2"),
+			SrcPort:  40001,
+			DstPort:  21,
+			Protocol: unix.IPPROTO_TCP,
+		},
+		Reverse: ipTuple{
+			SrcIP:    net.ParseIP("10.0.0.2"),
+			DstIP:    net.ParseIP("10.0.0.1"),
+			SrcPort:  21,
+			DstPort:  40001,
+			Protocol: unix.IPPROTO_TCP,
+		},
+		TimeOut:   100,
+		ProtoInfo: &ProtoInfoTCP{State: TCP_CONNTRACK_ESTABLISHED},
+		Helper:    "ftp",
+	}
+	CheckErrorFail(t, h.ConntrackCreate(ConntrackTable, unix.AF_INET, master))
+
+	expect := &ConntrackExpect{
+		Master: master.Forward,
+		Tuple: ipTuple{
+			SrcIP:    net.ParseIP("10.0.0.1"),
+			DstIP:    net.ParseIP("10.0.0.2"),
+			DstPort:  40000,
+			Protocol: unix.IPPROTO_TCP,
+		},
+		Mask: ipTuple{
+			SrcIP:    net.ParseIP("255.255.255.255"),
+			DstIP:    net.ParseIP("255.255.255.255"),
+			DstPort:  0xffff,
+			Protocol: unix.IPPROTO_TCP,
+		},
+		TimeOut: 300,
+	}
+	CheckErrorFail(t, h.ConntrackExpectCreate(unix.AF_INET, expect))
+	if err := h.ConntrackExpectCreate(unix.AF_INET, expect); err == nil {
+		t.Fatal("Error, it should fail creating an existing expectation")
+	}
+
+	expects, err := h.ConntrackExpectList(unix.AF_INET)
+	CheckErrorFail(t, err)
+	if len(expects) != 1 {
+		t.Fatalf("Found %d expectations instead of 1", len(expects))
+	}
+	got := expects[0]
+	if !got.Tuple.DstIP.Equal(expect.Tuple.DstIP) || got.Tuple.DstPort != 40000 || got.Mask.DstPort != 0xffff ||
+		!got.Master.SrcIP.Equal(master.Forward.SrcIP) || got.Master.SrcPort != 40001 || got.Master.DstPort != 21 {
+		t.Fatalf("Wrong expectation: %s", got)
+	}
+	if got.Helper != "ftp" || got.TimeOut == 0 || got.TimeOut > 300 {
+		t.Fatalf("Wrong expectation helper or timeout: %s", got)
+	}
+
+	CheckErrorFail(t, h.ConntrackExpectDelete(unix.AF_INET, got))
+	expects, err = h.ConntrackExpectList(unix.AF_INET)
+	CheckErrorFail(t, err)
+	if len(expects) != 0 {
+		t.Fatalf("Found %d expectations, they should had been deleted", len(expects))
+	}
+
+	for _, typ := range []ConntrackEventType{ConntrackEventNew, ConntrackEventDestroy} {
+		select {
+		case update := <-ch:
+			if update.Type != typ || update.Expect.Tuple.DstPort != 40000 {
+				t.Fatalf("Expected a %s event, got %s %s", typ, update.Type, update.Expect)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for the %s event", typ)
+		}
+	}
+}
+
+// TestConntrackExpectUserspace creates an expectation on a master connection
+// without a helper, as a userspace ALG does
+func TestConntrackExpectUserspace(t *testing.T) {
+	skipUnlessRoot(t)
+	setUpNetlinkTestWithKModule(t, "nf_conntrack")
+	setUpNetlinkTestWithKModule(t, "nf_conntrack_netlink")
+
+	// Creates a new namespace and bring up the loopback interface
+	origns, ns, h := nsCreateAndEnter(t)
+	defer netns.Set(*origns)
+	defer origns.Close()
+	defer ns.Close()
+	defer runtime.UnlockOSThread()
+
+	master := &ConntrackFlow{
+		Forward: ipTuple{
+			SrcIP:    net.ParseIP("10.0.0.1"),
+			DstIP:    net.ParseIP("10.0.0.2"),
+			SrcPort:  5060,
+			DstPort:  5060,
+			Protocol: unix.IPPROTO_UDP,
+		},
+		Reverse: ipTuple{
+			SrcIP:    net.ParseIP("10.0.0.2"),
+			DstIP:    net.ParseIP("10.0.0.1"),
+			SrcPort:  5060,
+			DstPort:  5060,
+			Protocol: unix.IPPROTO_UDP,
+		},
+		TimeOut: 100,
+	}
+	CheckErrorFail(t, h.ConntrackCreate(ConntrackTable, unix.AF_INET, master))
+
+	expect := &ConntrackExpect{
+		Master: master.Forward,
+		Tuple: ipTuple{
+			SrcIP:    net.ParseIP("10.0.0.2"),
+			DstIP:    net.ParseIP("10.0.0.1"),
+			DstPort:  16384,
+			Protocol: unix.IPPROTO_UDP,
+		},
+		Mask: ipTuple{
+			SrcIP:    net.ParseIP("255.255.255.255"),
+			DstIP:    net.ParseIP("255.255.255.255"),
+			DstPort:  0xffff,
+			Protocol: unix.IPPROTO_UDP,
+		},
+		TimeOut: 300,
+	}
+	err := h.ConntrackExpectCreate(unix.AF_INET, expect)
+	if err == unix.EOPNOTSUPP {
+		t.Skip("Kernel requires a helper on the master connection")
+	}
+	CheckErrorFail(t, err)
+
+	expects, err := h.ConntrackExpectList(unix.AF_INET)
+	CheckErrorFail(t, err)
+	if len(expects) != 1 {
+		t.Fatalf("Found %d expectations instead of 1", len(expects))
+	}
+	if expects[0].Flags&NF_CT_EXPECT_USERSPACE == 0 || expects[0].Tuple.DstPort != 16384 {
+		t.Fatalf("Wrong userspace expectation: %s", expects[0])
+	}
+	CheckErrorFail(t, h.ConntrackExpectDelete(unix.AF_INET, expects[0]))
+}
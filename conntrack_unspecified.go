@@ -51,3 +51,118 @@ func (h *Handle) ConntrackTableFlush(table ConntrackTableType) error {
 func (h *Handle) ConntrackDeleteFilter(table ConntrackTableType, family InetFamily, filter *ConntrackFilter) (uint, error) {
 	return 0, ErrNotImplemented
 }
+
+// ConntrackEventType placeholder
+type ConntrackEventType uint8
+
+// ConntrackEvent placeholder
+type ConntrackEvent struct{}
+
+// ConntrackSubscribeOptions placeholder
+type ConntrackSubscribeOptions struct{}
+
+// ConntrackSubscribe takes a chan down which notifications will be sent
+// when conntrack entries are created, updated or destroyed. Close the
+// 'done' chan to stop subscription.
+// Equivalent to: `conntrack -E`
+func ConntrackSubscribe(ch chan<- ConntrackEvent, done <-chan struct{}) error {
+	return ErrNotImplemented
+}
+
+// ConntrackSubscribeWithOptions work like ConntrackSubscribe but enable to
+// provide additional options to modify the behavior.
+func ConntrackSubscribeWithOptions(ch chan<- ConntrackEvent, done <-chan struct{}, options ConntrackSubscribeOptions) error {
+	return ErrNotImplemented
+}
+
+// ConntrackCreate creates a new conntrack entry from the flow
+// conntrack -I [table] parameters         Create a conntrack or expectation
+func ConntrackCreate(table ConntrackTableType, family InetFamily, flow *ConntrackFlow) error {
+	return ErrNotImplemented
+}
+
+// ConntrackUpdate updates the existing conntrack entry matching the tuples of the flow
+// conntrack -U [table] parameters         Update a conntrack
+func ConntrackUpdate(table ConntrackTableType, family InetFamily, flow *ConntrackFlow) error {
+	return ErrNotImplemented
+}
+
+// ConntrackGet returns the conntrack entry matching the tuple of the flow
+// conntrack -G [table] parameters         Get conntrack or expectation
+func ConntrackGet(table ConntrackTableType, family InetFamily, flow *ConntrackFlow) (*ConntrackFlow, error) {
+	return nil, ErrNotImplemented
+}
+
+// ConntrackCreate creates a new conntrack entry from the flow using the netlink handle passed
+// conntrack -I [table] parameters         Create a conntrack or expectation
+func (h *Handle) ConntrackCreate(table ConntrackTableType, family InetFamily, flow *ConntrackFlow) error {
+	return ErrNotImplemented
+}
+
+// ConntrackUpdate updates the existing conntrack entry matching the tuples of the flow using the netlink handle passed
+// conntrack -U [table] parameters         Update a conntrack
+func (h *Handle) ConntrackUpdate(table ConntrackTableType, family InetFamily, flow *ConntrackFlow) error {
+	return ErrNotImplemented
+}
+
+// ConntrackGet returns the conntrack entry matching the tuple of the flow using the netlink handle passed
+// conntrack -G [table] parameters         Get conntrack or expectation
+func (h *Handle) ConntrackGet(table ConntrackTableType, family InetFamily, flow *ConntrackFlow) (*ConntrackFlow, error) {
+	return nil, ErrNotImplemented
+}
+
+// ConntrackExpect placeholder
+type ConntrackExpect struct{}
+
+// ConntrackExpectEvent placeholder
+type ConntrackExpectEvent struct{}
+
+// ConntrackExpectList returns the expectations of a specific family
+// conntrack -L expect [options]          List expectation table
+func ConntrackExpectList(family InetFamily) ([]*ConntrackExpect, error) {
+	return nil, ErrNotImplemented
+}
+
+// ConntrackExpectCreate creates a new expectation
+// conntrack -I expect parameters         Create an expectation
+func ConntrackExpectCreate(family InetFamily, expect *ConntrackExpect) error {
+	return ErrNotImplemented
+}
+
+// ConntrackExpectDelete deletes the expectation matching the expected tuple
+// conntrack -D expect parameters         Delete an expectation
+func ConntrackExpectDelete(family InetFamily, expect *ConntrackExpect) error {
+	return ErrNotImplemented
+}
+
+// ConntrackExpectSubscribe takes a chan down which notifications will be sent
+// when expectations are created or destroyed. Close the 'done' chan to stop
+// subscription.
+// Equivalent to: `conntrack -E expect`
+func ConntrackExpectSubscribe(ch chan<- ConntrackExpectEvent, done <-chan struct{}) error {
+	return ErrNotImplemented
+}
+
+// ConntrackExpectSubscribeWithOptions work like ConntrackExpectSubscribe but
+// enable to provide additional options to modify the behavior.
+func ConntrackExpectSubscribeWithOptions(ch chan<- ConntrackExpectEvent, done <-chan struct{}, options ConntrackSubscribeOptions) error {
+	return ErrNotImplemented
+}
+
+// ConntrackExpectList returns the expectations of a specific family using the netlink handle passed
+// conntrack -L expect [options]          List expectation table
+func (h *Handle) ConntrackExpectList(family InetFamily) ([]*ConntrackExpect, error) {
+	return nil, ErrNotImplemented
+}
+
+// ConntrackExpectCreate creates a new expectation using the netlink handle passed
+// conntrack -I expect parameters         Create an expectation
+func (h *Handle) ConntrackExpectCreate(family InetFamily, expect *ConntrackExpect) error {
+	return ErrNotImplemented
+}
+
+// ConntrackExpectDelete deletes the expectation matching the expected tuple using the netlink handle passed
+// conntrack -D expect parameters         Delete an expectation
+func (h *Handle) ConntrackExpectDelete(family InetFamily, expect *ConntrackExpect) error {
+	return ErrNotImplemented
+}
@@ -0,0 +1,177 @@
+package netlink
+
+import "fmt"
+
+// Speed and duplex of a link which is down or not negotiated yet
+const (
+	SPEED_UNKNOWN  = 0xffffffff
+	DUPLEX_HALF    = 0x00
+	DUPLEX_FULL    = 0x01
+	DUPLEX_UNKNOWN = 0xff
+)
+
+// EthtoolLinkModes holds the link modes, speed and duplex of a device.
+// Optional settings are pointers, they are left unchanged when nil on set.
+type EthtoolLinkModes struct {
+	Autoneg        *bool
+	Speed          *uint32 // Mb/s, SPEED_UNKNOWN when the link is down
+	Duplex         *uint8  // DUPLEX_*
+	Lanes          *uint32
+	Supported      []string // read only
+	Advertised     []string // replaces the advertised modes when non-nil
+	PeerAdvertised []string // read only
+}
+
+// EthtoolLinkExtState tells why a link is down
+type EthtoolLinkExtState uint8
+
+const (
+	ETHTOOL_LINK_EXT_STATE_AUTONEG EthtoolLinkExtState = iota
+	ETHTOOL_LINK_EXT_STATE_LINK_TRAINING_FAILURE
+	ETHTOOL_LINK_EXT_STATE_LINK_LOGICAL_MISMATCH
+	ETHTOOL_LINK_EXT_STATE_BAD_SIGNAL_INTEGRITY
+	ETHTOOL_LINK_EXT_STATE_NO_CABLE
+	ETHTOOL_LINK_EXT_STATE_CABLE_ISSUE
+	ETHTOOL_LINK_EXT_STATE_EEPROM_ISSUE
+	ETHTOOL_LINK_EXT_STATE_CALIBRATION_FAILURE
+	ETHTOOL_LINK_EXT_STATE_POWER_BUDGET_EXCEEDED
+	ETHTOOL_LINK_EXT_STATE_OVERHEAT
+)
+
+func (s EthtoolLinkExtState) String() string {
+	switch s {
+	case ETHTOOL_LINK_EXT_STATE_AUTONEG:
+		return "Autoneg"
+	case ETHTOOL_LINK_EXT_STATE_LINK_TRAINING_FAILURE:
+		return "Link training failure"
+	case ETHTOOL_LINK_EXT_STATE_LINK_LOGICAL_MISMATCH:
+		return "Logical mismatch"
+	case ETHTOOL_LINK_EXT_STATE_BAD_SIGNAL_INTEGRITY:
+		return "Bad signal integrity"
+	case ETHTOOL_LINK_EXT_STATE_NO_CABLE:
+		return "No cable"
+	case ETHTOOL_LINK_EXT_STATE_CABLE_ISSUE:
+		return "Cable issue"
+	case ETHTOOL_LINK_EXT_STATE_EEPROM_ISSUE:
+		return "EEPROM issue"
+	case ETHTOOL_LINK_EXT_STATE_CALIBRATION_FAILURE:
+		return "Calibration failure"
+	case ETHTOOL_LINK_EXT_STATE_POWER_BUDGET_EXCEEDED:
+		return "Power budget exceeded"
+	case ETHTOOL_LINK_EXT_STATE_OVERHEAT:
+		return "Overheat"
+	default:
+		return fmt.Sprintf("unknown(%d)", s)
+	}
+}
+
+// EthtoolLinkState holds the link state of a device. ExtState and
+// ExtSubstate are only reported by some drivers when the link is down, the
+// meaning of ExtSubstate depends on ExtState.
+type EthtoolLinkState struct {
+	Link        bool
+	SQI         *uint32 // signal quality index
+	SQIMax      *uint32
+	ExtState    *EthtoolLinkExtState
+	ExtSubstate uint8
+}
+
+// EthtoolFeatures holds the sets of feature names of a device, such as
+// "rx-gro" or "tx-tcp-segmentation".
+type EthtoolFeatures struct {
+	Hardware map[string]bool // features that can be changed
+	Wanted   map[string]bool // features requested
+	Active   map[string]bool // features enabled
+	NoChange map[string]bool // features that can't be changed
+}
+
+// EthtoolRings holds the ring sizes of a device. The current sizes are
+// left unchanged when nil on set.
+type EthtoolRings struct {
+	RxMax      uint32
+	RxMiniMax  uint32
+	RxJumboMax uint32
+	TxMax      uint32
+	Rx         *uint32
+	RxMini     *uint32
+	RxJumbo    *uint32
+	Tx         *uint32
+}
+
+// EthtoolChannels holds the channel counts of a device. The current counts
+// are left unchanged when nil on set.
+type EthtoolChannels struct {
+	RxMax       uint32
+	TxMax       uint32
+	OtherMax    uint32
+	CombinedMax uint32
+	Rx          *uint32
+	Tx          *uint32
+	Other       *uint32
+	Combined    *uint32
+}
+
+// EthtoolCoalesce holds the interrupt coalescing parameters of a device.
+// Only the parameters supported by the driver are reported, the
+// parameters are left unchanged when nil on set.
+type EthtoolCoalesce struct {
+	RxUsecs            *uint32
+	RxMaxFrames        *uint32
+	RxUsecsIrq         *uint32
+	RxMaxFramesIrq     *uint32
+	TxUsecs            *uint32
+	TxMaxFrames        *uint32
+	TxUsecsIrq         *uint32
+	TxMaxFramesIrq     *uint32
+	StatsBlockUsecs    *uint32
+	UseAdaptiveRx      *bool
+	UseAdaptiveTx      *bool
+	PktRateLow         *uint32
+	RxUsecsLow         *uint32
+	RxMaxFramesLow     *uint32
+	TxUsecsLow         *uint32
+	TxMaxFramesLow     *uint32
+	PktRateHigh        *uint32
+	RxUsecsHigh        *uint32
+	RxMaxFramesHigh    *uint32
+	TxUsecsHigh        *uint32
+	TxMaxFramesHigh    *uint32
+	RateSampleInterval *uint32
+}
+
+// EthtoolPause holds the pause frame settings of a device, left unchanged
+// when nil on set.
+type EthtoolPause struct {
+	Autoneg *bool
+	Rx      *bool
+	Tx      *bool
+}
+
+// EthtoolEEE holds the Energy Efficient Ethernet settings of a device.
+// Optional settings are pointers, they are left unchanged when nil on set.
+type EthtoolEEE struct {
+	Supported    []string // read only
+	Advertised   []string // replaces the advertised modes when non-nil
+	Peer         []string // read only
+	Active       bool     // read only
+	Enabled      *bool
+	TxLpiEnabled *bool
+	TxLpiTimer   *uint32 // usecs
+}
+
+// EthtoolUpdate is used to pass information back from EthtoolSubscribe.
+// Cmd is the ETHTOOL_MSG_*_NTF notification type, the field matching it
+// is set for the link modes, features, rings, channels, coalesce, pause
+// and EEE notifications.
+type EthtoolUpdate struct {
+	Cmd       uint8
+	LinkIndex int
+	LinkName  string
+	LinkModes *EthtoolLinkModes
+	Features  *EthtoolFeatures
+	Rings     *EthtoolRings
+	Channels  *EthtoolChannels
+	Coalesce  *EthtoolCoalesce
+	Pause     *EthtoolPause
+	EEE       *EthtoolEEE
+}
@@ -0,0 +1,896 @@
+package netlink
+
+import (
+	"fmt"
+	"syscall"
+
+	"github.com/ndupreez/netlink/nl"
+	"github.com/vishvananda/netns"
+	"golang.org/x/sys/unix"
+)
+
+// ethtoolHeader is the type of the request header attribute, the first
+// attribute of every ethtool message
+const ethtoolHeader = 1
+
+// EthtoolLinkModesGet returns the link modes, speed and duplex of a device.
+// Equivalent to: `ethtool $link`
+func EthtoolLinkModesGet(link Link) (*EthtoolLinkModes, error) {
+	return pkgHandle.EthtoolLinkModesGet(link)
+}
+
+// EthtoolLinkModesGet returns the link modes, speed and duplex of a device.
+// Equivalent to: `ethtool $link`
+func (h *Handle) EthtoolLinkModesGet(link Link) (*EthtoolLinkModes, error) {
+	attrs, err := h.ethtoolGet(link, nl.ETHTOOL_MSG_LINKMODES_GET)
+	if err != nil {
+		return nil, err
+	}
+	return parseEthtoolLinkModes(attrs, nil)
+}
+
+// EthtoolLinkModesSet changes the non-nil settings of modes.
+// Equivalent to: `ethtool -s $link speed $speed duplex $duplex autoneg $autoneg advertise $modes`
+func EthtoolLinkModesSet(link Link, modes *EthtoolLinkModes) error {
+	return pkgHandle.EthtoolLinkModesSet(link, modes)
+}
+
+// EthtoolLinkModesSet changes the non-nil settings of modes.
+// Equivalent to: `ethtool -s $link speed $speed duplex $duplex autoneg $autoneg advertise $modes`
+func (h *Handle) EthtoolLinkModesSet(link Link, modes *EthtoolLinkModes) error {
+	var attrs []*nl.RtAttr
+	if modes.Autoneg != nil {
+		attrs = append(attrs, nl.NewRtAttr(nl.ETHTOOL_A_LINKMODES_AUTONEG, boolToByte(*modes.Autoneg)))
+	}
+	if modes.Advertised != nil {
+		attrs = append(attrs, ethtoolBitsetList(nl.ETHTOOL_A_LINKMODES_OURS, modes.Advertised))
+	}
+	if modes.Speed != nil {
+		attrs = append(attrs, nl.NewRtAttr(nl.ETHTOOL_A_LINKMODES_SPEED, nl.Uint32Attr(*modes.Speed)))
+	}
+	if modes.Duplex != nil {
+		attrs = append(attrs, nl.NewRtAttr(nl.ETHTOOL_A_LINKMODES_DUPLEX, nl.Uint8Attr(*modes.Duplex)))
+	}
+	if modes.Lanes != nil {
+		attrs = append(attrs, nl.NewRtAttr(nl.ETHTOOL_A_LINKMODES_LANES, nl.Uint32Attr(*modes.Lanes)))
+	}
+	_, err := h.ethtoolExecute(link, nl.ETHTOOL_MSG_LINKMODES_SET, 0, attrs...)
+	return err
+}
+
+// EthtoolLinkStateGet returns the link state of a device, including the
+// reason why the link is down when the driver reports it.
+// Equivalent to: `ethtool $link`
+func EthtoolLinkStateGet(link Link) (*EthtoolLinkState, error) {
+	return pkgHandle.EthtoolLinkStateGet(link)
+}
+
+// EthtoolLinkStateGet returns the link state of a device, including the
+// reason why the link is down when the driver reports it.
+// Equivalent to: `ethtool $link`
+func (h *Handle) EthtoolLinkStateGet(link Link) (*EthtoolLinkState, error) {
+	attrs, err := h.ethtoolGet(link, nl.ETHTOOL_MSG_LINKSTATE_GET)
+	if err != nil {
+		return nil, err
+	}
+	state := &EthtoolLinkState{}
+	for _, attr := range attrs {
+		switch attr.Attr.Type {
+		case nl.ETHTOOL_A_LINKSTATE_LINK:
+			state.Link = attr.Value[0] != 0
+		case nl.ETHTOOL_A_LINKSTATE_SQI:
+			sqi := native.Uint32(attr.Value[0:4])
+			state.SQI = &sqi
+		case nl.ETHTOOL_A_LINKSTATE_SQI_MAX:
+			sqiMax := native.Uint32(attr.Value[0:4])
+			state.SQIMax = &sqiMax
+		case nl.ETHTOOL_A_LINKSTATE_EXT_STATE:
+			extState := EthtoolLinkExtState(attr.Value[0])
+			state.ExtState = &extState
+		case nl.ETHTOOL_A_LINKSTATE_EXT_SUBSTATE:
+			state.ExtSubstate = attr.Value[0]
+		}
+	}
+	return state, nil
+}
+
+// EthtoolFeaturesGet returns the features of a device.
+// Equivalent to: `ethtool -k $link`
+func EthtoolFeaturesGet(link Link) (*EthtoolFeatures, error) {
+	return pkgHandle.EthtoolFeaturesGet(link)
+}
+
+// EthtoolFeaturesGet returns the features of a device.
+// Equivalent to: `ethtool -k $link`
+func (h *Handle) EthtoolFeaturesGet(link Link) (*EthtoolFeatures, error) {
+	attrs, err := h.ethtoolGet(link, nl.ETHTOOL_MSG_FEATURES_GET)
+	if err != nil {
+		return nil, err
+	}
+	return parseEthtoolFeatures(attrs, nil)
+}
+
+// EthtoolFeaturesSet enables or disables the features of a device by name,
+// the features missing from the map are left unchanged. The kernel may not
+// apply every requested feature, check the active ones with
+// EthtoolFeaturesGet.
+// Equivalent to: `ethtool -K $link $feature on|off`
+func EthtoolFeaturesSet(link Link, features map[string]bool) error {
+	return pkgHandle.EthtoolFeaturesSet(link, features)
+}
+
+// EthtoolFeaturesSet enables or disables the features of a device by name,
+// the features missing from the map are left unchanged. The kernel may not
+// apply every requested feature, check the active ones with
+// EthtoolFeaturesGet.
+// Equivalent to: `ethtool -K $link $feature on|off`
+func (h *Handle) EthtoolFeaturesSet(link Link, features map[string]bool) error {
+	bitset := nl.NewRtAttr(nl.ETHTOOL_A_FEATURES_WANTED|unix.NLA_F_NESTED, nil)
+	bits := bitset.AddRtAttr(nl.ETHTOOL_A_BITSET_BITS|unix.NLA_F_NESTED, nil)
+	for name, enabled := range features {
+		bit := bits.AddRtAttr(nl.ETHTOOL_A_BITSET_BITS_BIT|unix.NLA_F_NESTED, nil)
+		bit.AddRtAttr(nl.ETHTOOL_A_BITSET_BIT_NAME, nl.ZeroTerminated(name))
+		if enabled {
+			bit.AddRtAttr(nl.ETHTOOL_A_BITSET_BIT_VALUE, nil)
+		}
+	}
+	_, err := h.ethtoolExecute(link, nl.ETHTOOL_MSG_FEATURES_SET, nl.ETHTOOL_FLAG_OMIT_REPLY, bitset)
+	return err
+}
+
+// EthtoolRingsGet returns the ring sizes of a device.
+// Equivalent to: `ethtool -g $link`
+func EthtoolRingsGet(link Link) (*EthtoolRings, error) {
+	return pkgHandle.EthtoolRingsGet(link)
+}
+
+// EthtoolRingsGet returns the ring sizes of a device.
+// Equivalent to: `ethtool -g $link`
+func (h *Handle) EthtoolRingsGet(link Link) (*EthtoolRings, error) {
+	attrs, err := h.ethtoolGet(link, nl.ETHTOOL_MSG_RINGS_GET)
+	if err != nil {
+		return nil, err
+	}
+	return parseEthtoolRings(attrs), nil
+}
+
+// EthtoolRingsSet changes the non-nil ring sizes of rings.
+// Equivalent to: `ethtool -G $link rx $rx tx $tx`
+func EthtoolRingsSet(link Link, rings *EthtoolRings) error {
+	return pkgHandle.EthtoolRingsSet(link, rings)
+}
+
+// EthtoolRingsSet changes the non-nil ring sizes of rings.
+// Equivalent to: `ethtool -G $link rx $rx tx $tx`
+func (h *Handle) EthtoolRingsSet(link Link, rings *EthtoolRings) error {
+	_, err := h.ethtoolExecute(link, nl.ETHTOOL_MSG_RINGS_SET, 0, ethtoolFieldAttrs(rings.fields())...)
+	return err
+}
+
+// EthtoolChannelsGet returns the channel counts of a device.
+// Equivalent to: `ethtool -l $link`
+func EthtoolChannelsGet(link Link) (*EthtoolChannels, error) {
+	return pkgHandle.EthtoolChannelsGet(link)
+}
+
+// EthtoolChannelsGet returns the channel counts of a device.
+// Equivalent to: `ethtool -l $link`
+func (h *Handle) EthtoolChannelsGet(link Link) (*EthtoolChannels, error) {
+	attrs, err := h.ethtoolGet(link, nl.ETHTOOL_MSG_CHANNELS_GET)
+	if err != nil {
+		return nil, err
+	}
+	return parseEthtoolChannels(attrs), nil
+}
+
+// EthtoolChannelsSet changes the non-nil channel counts of channels.
+// Equivalent to: `ethtool -L $link combined $combined`
+func EthtoolChannelsSet(link Link, channels *EthtoolChannels) error {
+	return pkgHandle.EthtoolChannelsSet(link, channels)
+}
+
+// EthtoolChannelsSet changes the non-nil channel counts of channels.
+// Equivalent to: `ethtool -L $link combined $combined`
+func (h *Handle) EthtoolChannelsSet(link Link, channels *EthtoolChannels) error {
+	_, err := h.ethtoolExecute(link, nl.ETHTOOL_MSG_CHANNELS_SET, 0, ethtoolFieldAttrs(channels.fields())...)
+	return err
+}
+
+// EthtoolCoalesceGet returns the interrupt coalescing parameters of a
+// device.
+// Equivalent to: `ethtool -c $link`
+func EthtoolCoalesceGet(link Link) (*EthtoolCoalesce, error) {
+	return pkgHandle.EthtoolCoalesceGet(link)
+}
+
+// EthtoolCoalesceGet returns the interrupt coalescing parameters of a
+// device.
+// Equivalent to: `ethtool -c $link`
+func (h *Handle) EthtoolCoalesceGet(link Link) (*EthtoolCoalesce, error) {
+	attrs, err := h.ethtoolGet(link, nl.ETHTOOL_MSG_COALESCE_GET)
+	if err != nil {
+		return nil, err
+	}
+	return parseEthtoolCoalesce(attrs), nil
+}
+
+// EthtoolCoalesceSet changes the non-nil parameters of coalesce.
+// Equivalent to: `ethtool -C $link rx-usecs $usecs ...`
+func EthtoolCoalesceSet(link Link, coalesce *EthtoolCoalesce) error {
+	return pkgHandle.EthtoolCoalesceSet(link, coalesce)
+}
+
+// EthtoolCoalesceSet changes the non-nil parameters of coalesce.
+// Equivalent to: `ethtool -C $link rx-usecs $usecs ...`
+func (h *Handle) EthtoolCoalesceSet(link Link, coalesce *EthtoolCoalesce) error {
+	_, err := h.ethtoolExecute(link, nl.ETHTOOL_MSG_COALESCE_SET, 0, ethtoolFieldAttrs(coalesce.fields())...)
+	return err
+}
+
+// EthtoolPauseGet returns the pause frame settings of a device.
+// Equivalent to: `ethtool -a $link`
+func EthtoolPauseGet(link Link) (*EthtoolPause, error) {
+	return pkgHandle.EthtoolPauseGet(link)
+}
+
+// EthtoolPauseGet returns the pause frame settings of a device.
+// Equivalent to: `ethtool -a $link`
+func (h *Handle) EthtoolPauseGet(link Link) (*EthtoolPause, error) {
+	attrs, err := h.ethtoolGet(link, nl.ETHTOOL_MSG_PAUSE_GET)
+	if err != nil {
+		return nil, err
+	}
+	return parseEthtoolPause(attrs), nil
+}
+
+// EthtoolPauseSet changes the non-nil settings of pause.
+// Equivalent to: `ethtool -A $link autoneg $autoneg rx $rx tx $tx`
+func EthtoolPauseSet(link Link, pause *EthtoolPause) error {
+	return pkgHandle.EthtoolPauseSet(link, pause)
+}
+
+// EthtoolPauseSet changes the non-nil settings of pause.
+// Equivalent to: `ethtool -A $link autoneg $autoneg rx $rx tx $tx`
+func (h *Handle) EthtoolPauseSet(link Link, pause *EthtoolPause) error {
+	_, err := h.ethtoolExecute(link, nl.ETHTOOL_MSG_PAUSE_SET, 0, ethtoolFieldAttrs(pause.fields())...)
+	return err
+}
+
+// EthtoolEEEGet returns the Energy Efficient Ethernet settings of a device.
+// Equivalent to: `ethtool --show-eee $link`
+func EthtoolEEEGet(link Link) (*EthtoolEEE, error) {
+	return pkgHandle.EthtoolEEEGet(link)
+}
+
+// EthtoolEEEGet returns the Energy Efficient Ethernet settings of a device.
+// Equivalent to: `ethtool --show-eee $link`
+func (h *Handle) EthtoolEEEGet(link Link) (*EthtoolEEE, error) {
+	attrs, err := h.ethtoolGet(link, nl.ETHTOOL_MSG_EEE_GET)
+	if err != nil {
+		return nil, err
+	}
+	return parseEthtoolEEE(attrs, nil)
+}
+
+// EthtoolEEESet changes the non-nil settings of eee.
+// Equivalent to: `ethtool --set-eee $link eee $enabled tx-lpi $enabled tx-timer $timer advertise $modes`
+func EthtoolEEESet(link Link, eee *EthtoolEEE) error {
+	return pkgHandle.EthtoolEEESet(link, eee)
+}
+
+// EthtoolEEESet changes the non-nil settings of eee.
+// Equivalent to: `ethtool --set-eee $link eee $enabled tx-lpi $enabled tx-timer $timer advertise $modes`
+func (h *Handle) EthtoolEEESet(link Link, eee *EthtoolEEE) error {
+	attrs := ethtoolFieldAttrs(eee.fields())
+	if eee.Advertised != nil {
+		attrs = append(attrs, ethtoolBitsetList(nl.ETHTOOL_A_EEE_MODES_OURS, eee.Advertised))
+	}
+	_, err := h.ethtoolExecute(link, nl.ETHTOOL_MSG_EEE_SET, 0, attrs...)
+	return err
+}
+
+// EthtoolSubscribeOptions contains a set of options to use with
+// EthtoolSubscribeWithOptions.
+type EthtoolSubscribeOptions struct {
+	Namespace     *netns.NsHandle
+	ErrorCallback func(error)
+}
+
+// EthtoolSubscribe takes a chan down which notifications will be sent
+// when the ethtool settings of a device change. Close the 'done' chan to
+// stop subscription.
+// Equivalent to: `ethtool --monitor`
+func EthtoolSubscribe(ch chan<- EthtoolUpdate, done <-chan struct{}) error {
+	return ethtoolSubscribeAt(netns.None(), netns.None(), ch, done, nil)
+}
+
+// EthtoolSubscribeWithOptions work like EthtoolSubscribe but enable to
+// provide additional options to modify the behavior. Currently, the
+// namespace can be provided as well as an error callback.
+func EthtoolSubscribeWithOptions(ch chan<- EthtoolUpdate, done <-chan struct{}, options EthtoolSubscribeOptions) error {
+	if options.Namespace == nil {
+		none := netns.None()
+		options.Namespace = &none
+	}
+	return ethtoolSubscribeAt(*options.Namespace, netns.None(), ch, done, options.ErrorCallback)
+}
+
+func ethtoolSubscribeAt(newNs, curNs netns.NsHandle, ch chan<- EthtoolUpdate, done <-chan struct{}, cberr func(error)) error {
+	// generic netlink families and their string sets are not namespaced
+	f, err := pkgHandle.GenlFamilyGet(nl.ETHTOOL_GENL_NAME)
+	if err != nil {
+		return err
+	}
+	var group *GenlMulticastGroup
+	for i := range f.Groups {
+		if f.Groups[i].Name == nl.ETHTOOL_MCGRP_MONITOR_NAME {
+			group = &f.Groups[i]
+		}
+	}
+	if group == nil {
+		return fmt.Errorf("ethtool multicast group %s not found", nl.ETHTOOL_MCGRP_MONITOR_NAME)
+	}
+	// notifications carry compact bitsets, without the bit names
+	linkModes, err := pkgHandle.ethtoolStringSet(f, ETH_SS_LINK_MODES)
+	if err != nil {
+		return err
+	}
+	features, err := pkgHandle.ethtoolStringSet(f, ETH_SS_FEATURES)
+	if err != nil {
+		return err
+	}
+
+	s, err := nl.SubscribeAt(newNs, curNs, unix.NETLINK_GENERIC)
+	if err != nil {
+		return err
+	}
+	if err := s.JoinGroup(group.ID); err != nil {
+		s.Close()
+		return err
+	}
+	if done != nil {
+		go func() {
+			<-done
+			s.Close()
+		}()
+	}
+	go func() {
+		defer close(ch)
+		for {
+			msgs, from, err := s.Receive()
+			if err != nil {
+				if cberr != nil {
+					cberr(err)
+				}
+				return
+			}
+			if from.Pid != nl.PidKernel {
+				if cberr != nil {
+					cberr(fmt.Errorf("Wrong sender portid %d, expected %d", from.Pid, nl.PidKernel))
+				}
+				continue
+			}
+			for _, m := range msgs {
+				if m.Header.Type != f.ID {
+					continue
+				}
+				update, err := parseEthtoolUpdate(m.Data, linkModes, features)
+				if err != nil {
+					if cberr != nil {
+						cberr(err)
+					}
+					return
+				}
+				ch <- *update
+			}
+		}
+	}()
+
+	return nil
+}
+
+func parseEthtoolUpdate(b []byte, linkModes, features []string) (*EthtoolUpdate, error) {
+	msg := nl.DeserializeGenlmsg(b)
+	attrs, err := nl.ParseRouteAttr(b[nl.SizeofGenlmsg:])
+	if err != nil {
+		return nil, err
+	}
+	update := &EthtoolUpdate{Cmd: msg.Command}
+	for _, attr := range attrs {
+		if attr.Attr.Type&^unix.NLA_F_NESTED != ethtoolHeader {
+			continue
+		}
+		header, err := nl.ParseRouteAttr(attr.Value)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range header {
+			switch a.Attr.Type {
+			case nl.ETHTOOL_A_HEADER_DEV_INDEX:
+				update.LinkIndex = int(native.Uint32(a.Value[0:4]))
+			case nl.ETHTOOL_A_HEADER_DEV_NAME:
+				update.LinkName = string(a.Value[:len(a.Value)-1])
+			}
+		}
+	}
+	switch msg.Command {
+	case nl.ETHTOOL_MSG_LINKMODES_NTF:
+		update.LinkModes, err = parseEthtoolLinkModes(attrs, linkModes)
+	case nl.ETHTOOL_MSG_FEATURES_NTF:
+		update.Features, err = parseEthtoolFeatures(attrs, features)
+	case nl.ETHTOOL_MSG_RINGS_NTF:
+		update.Rings = parseEthtoolRings(attrs)
+	case nl.ETHTOOL_MSG_CHANNELS_NTF:
+		update.Channels = parseEthtoolChannels(attrs)
+	case nl.ETHTOOL_MSG_COALESCE_NTF:
+		update.Coalesce = parseEthtoolCoalesce(attrs)
+	case nl.ETHTOOL_MSG_PAUSE_NTF:
+		update.Pause = parseEthtoolPause(attrs)
+	case nl.ETHTOOL_MSG_EEE_NTF:
+		update.EEE, err = parseEthtoolEEE(attrs, linkModes)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return update, nil
+}
+
+// ethtoolExecute sends the ethtool command cmd about link, the header
+// flags are ETHTOOL_FLAG_*.
+func (h *Handle) ethtoolExecute(link Link, cmd uint8, flags uint32, attrs ...*nl.RtAttr) ([][]byte, error) {
+	f, err := h.GenlFamilyGet(nl.ETHTOOL_GENL_NAME)
+	if err != nil {
+		return nil, err
+	}
+	base := link.Attrs()
+	h.ensureIndex(base)
+	msg := &nl.Genlmsg{
+		Command: cmd,
+		Version: nl.ETHTOOL_GENL_VERSION,
+	}
+	req := h.newNetlinkRequest(int(f.ID), unix.NLM_F_ACK)
+	req.AddData(msg)
+	header := nl.NewRtAttr(ethtoolHeader|unix.NLA_F_NESTED, nil)
+	header.AddRtAttr(nl.ETHTOOL_A_HEADER_DEV_INDEX, nl.Uint32Attr(uint32(base.Index)))
+	if flags != 0 {
+		header.AddRtAttr(nl.ETHTOOL_A_HEADER_FLAGS, nl.Uint32Attr(flags))
+	}
+	req.AddData(header)
+	for _, attr := range attrs {
+		req.AddData(attr)
+	}
+	return req.Execute(unix.NETLINK_GENERIC, 0)
+}
+
+// ethtoolGet sends the ethtool get command cmd about link and returns the
+// attributes of the reply, with bit names in the bitsets.
+func (h *Handle) ethtoolGet(link Link, cmd uint8) ([]syscall.NetlinkRouteAttr, error) {
+	msgs, err := h.ethtoolExecute(link, cmd, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(msgs) != 1 {
+		return nil, fmt.Errorf("expected 1 ethtool reply, got %d", len(msgs))
+	}
+	return nl.ParseRouteAttr(msgs[0][nl.SizeofGenlmsg:])
+}
+
+// ethtoolStringSet returns the strings of a global string set, ETH_SS_*,
+// indexed by bit.
+func (h *Handle) ethtoolStringSet(f *GenlFamily, id uint32) ([]string, error) {
+	msg := &nl.Genlmsg{
+		Command: nl.ETHTOOL_MSG_STRSET_GET,
+		Version: nl.ETHTOOL_GENL_VERSION,
+	}
+	req := h.newNetlinkRequest(int(f.ID), unix.NLM_F_ACK)
+	req.AddData(msg)
+	req.AddData(nl.NewRtAttr(ethtoolHeader|unix.NLA_F_NESTED, nil))
+	sets := nl.NewRtAttr(nl.ETHTOOL_A_STRSET_STRINGSETS|unix.NLA_F_NESTED, nil)
+	set := sets.AddRtAttr(nl.ETHTOOL_A_STRINGSETS_STRINGSET|unix.NLA_F_NESTED, nil)
+	set.AddRtAttr(nl.ETHTOOL_A_STRINGSET_ID, nl.Uint32Attr(id))
+	req.AddData(sets)
+	msgs, err := req.Execute(unix.NETLINK_GENERIC, 0)
+	if err != nil {
+		return nil, err
+	}
+	var strings []string
+	for _, m := range msgs {
+		attrs, err := nl.ParseRouteAttr(m[nl.SizeofGenlmsg:])
+		if err != nil {
+			return nil, err
+		}
+		for _, attr := range attrs {
+			if attr.Attr.Type&^unix.NLA_F_NESTED != nl.ETHTOOL_A_STRSET_STRINGSETS {
+				continue
+			}
+			if strings, err = parseEthtoolStringSets(attr.Value, id); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return strings, nil
+}
+
+func parseEthtoolStringSets(b []byte, id uint32) ([]string, error) {
+	sets, err := nl.ParseRouteAttr(b)
+	if err != nil {
+		return nil, err
+	}
+	for _, set := range sets {
+		attrs, err := nl.ParseRouteAttr(set.Value)
+		if err != nil {
+			return nil, err
+		}
+		var (
+			setID   uint32
+			strings []string
+		)
+		for _, attr := range attrs {
+			switch attr.Attr.Type &^ unix.NLA_F_NESTED {
+			case nl.ETHTOOL_A_STRINGSET_ID:
+				setID = native.Uint32(attr.Value[0:4])
+			case nl.ETHTOOL_A_STRINGSET_STRINGS:
+				list, err := nl.ParseRouteAttr(attr.Value)
+				if err != nil {
+					return nil, err
+				}
+				for _, s := range list {
+					str, err := nl.ParseRouteAttr(s.Value)
+					if err != nil {
+						return nil, err
+					}
+					var (
+						index uint32
+						value string
+					)
+					for _, a := range str {
+						switch a.Attr.Type {
+						case nl.ETHTOOL_A_STRING_INDEX:
+							index = native.Uint32(a.Value[0:4])
+						case nl.ETHTOOL_A_STRING_VALUE:
+							value = string(a.Value[:len(a.Value)-1])
+						}
+					}
+					for uint32(len(strings)) <= index {
+						strings = append(strings, "")
+					}
+					strings[index] = value
+				}
+			}
+		}
+		if setID == id {
+			return strings, nil
+		}
+	}
+	return nil, nil
+}
+
+// ethtoolBitsetList encodes a bitset setting exactly the named bits
+func ethtoolBitsetList(attrType int, names []string) *nl.RtAttr {
+	bitset := nl.NewRtAttr(attrType|unix.NLA_F_NESTED, nil)
+	bitset.AddRtAttr(nl.ETHTOOL_A_BITSET_NOMASK, nil)
+	bits := bitset.AddRtAttr(nl.ETHTOOL_A_BITSET_BITS|unix.NLA_F_NESTED, nil)
+	for _, name := range names {
+		bit := bits.AddRtAttr(nl.ETHTOOL_A_BITSET_BITS_BIT|unix.NLA_F_NESTED, nil)
+		bit.AddRtAttr(nl.ETHTOOL_A_BITSET_BIT_NAME, nl.ZeroTerminated(name))
+	}
+	return bitset
+}
+
+// parseEthtoolBitset returns the names of the bits set in the value and in
+// the mask of a bitset, a list having no mask. The bits of a compact
+// bitset have no names, they are looked up in names.
+func parseEthtoolBitset(b []byte, names []string) (value, mask []string, err error) {
+	attrs, err := nl.ParseRouteAttr(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	bitName := func(i uint32) string {
+		if int(i) < len(names) && names[i] != "" {
+			return names[i]
+		}
+		return fmt.Sprintf("bit%d", i)
+	}
+	compact := func(words []byte, size uint32) []string {
+		var res []string
+		for i := uint32(0); i < size && int(i/32)*4+4 <= len(words); i++ {
+			if native.Uint32(words[i/32*4:])&(1<<(i%32)) != 0 {
+				res = append(res, bitName(i))
+			}
+		}
+		return res
+	}
+	var (
+		nomask bool
+		size   uint32
+	)
+	for _, attr := range attrs {
+		switch attr.Attr.Type &^ unix.NLA_F_NESTED {
+		case nl.ETHTOOL_A_BITSET_NOMASK:
+			nomask = true
+		case nl.ETHTOOL_A_BITSET_SIZE:
+			size = native.Uint32(attr.Value[0:4])
+		}
+	}
+	for _, attr := range attrs {
+		switch attr.Attr.Type &^ unix.NLA_F_NESTED {
+		case nl.ETHTOOL_A_BITSET_VALUE:
+			value = compact(attr.Value, size)
+		case nl.ETHTOOL_A_BITSET_MASK:
+			mask = compact(attr.Value, size)
+		case nl.ETHTOOL_A_BITSET_BITS:
+			bits, err := nl.ParseRouteAttr(attr.Value)
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, bit := range bits {
+				data, err := nl.ParseRouteAttr(bit.Value)
+				if err != nil {
+					return nil, nil, err
+				}
+				var (
+					name string
+					set  bool
+				)
+				for _, datum := range data {
+					switch datum.Attr.Type {
+					case nl.ETHTOOL_A_BITSET_BIT_INDEX:
+						if name == "" {
+							name = bitName(native.Uint32(datum.Value[0:4]))
+						}
+					case nl.ETHTOOL_A_BITSET_BIT_NAME:
+						name = string(datum.Value[:len(datum.Value)-1])
+					case nl.ETHTOOL_A_BITSET_BIT_VALUE:
+						set = true
+					}
+				}
+				if nomask || set {
+					value = append(value, name)
+				}
+				if !nomask {
+					mask = append(mask, name)
+				}
+			}
+		}
+	}
+	if nomask {
+		mask = nil
+	}
+	return value, mask, nil
+}
+
+// ethtoolField pairs the attribute type of an optional ethtool setting
+// with its field, a **uint32 or a **bool sent as u8.
+type ethtoolField struct {
+	attr  uint16
+	value interface{}
+}
+
+func ethtoolFieldAttrs(fields []ethtoolField) []*nl.RtAttr {
+	var attrs []*nl.RtAttr
+	for _, field := range fields {
+		switch v := field.value.(type) {
+		case **uint32:
+			if *v != nil {
+				attrs = append(attrs, nl.NewRtAttr(int(field.attr), nl.Uint32Attr(**v)))
+			}
+		case **bool:
+			if *v != nil {
+				attrs = append(attrs, nl.NewRtAttr(int(field.attr), boolToByte(**v)))
+			}
+		}
+	}
+	return attrs
+}
+
+func parseEthtoolFields(attrs []syscall.NetlinkRouteAttr, fields []ethtoolField) {
+	for _, attr := range attrs {
+		for _, field := range fields {
+			if field.attr != attr.Attr.Type {
+				continue
+			}
+			switch v := field.value.(type) {
+			case **uint32:
+				val := native.Uint32(attr.Value[0:4])
+				*v = &val
+			case **bool:
+				val := attr.Value[0] != 0
+				*v = &val
+			}
+		}
+	}
+}
+
+func (r *EthtoolRings) fields() []ethtoolField {
+	return []ethtoolField{
+		{nl.ETHTOOL_A_RINGS_RX, &r.Rx},
+		{nl.ETHTOOL_A_RINGS_RX_MINI, &r.RxMini},
+		{nl.ETHTOOL_A_RINGS_RX_JUMBO, &r.RxJumbo},
+		{nl.ETHTOOL_A_RINGS_TX, &r.Tx},
+	}
+}
+
+func (c *EthtoolChannels) fields() []ethtoolField {
+	return []ethtoolField{
+		{nl.ETHTOOL_A_CHANNELS_RX_COUNT, &c.Rx},
+		{nl.ETHTOOL_A_CHANNELS_TX_COUNT, &c.Tx},
+		{nl.ETHTOOL_A_CHANNELS_OTHER_COUNT, &c.Other},
+		{nl.ETHTOOL_A_CHANNELS_COMBINED_COUNT, &c.Combined},
+	}
+}
+
+func (c *EthtoolCoalesce) fields() []ethtoolField {
+	return []ethtoolField{
+		{nl.ETHTOOL_A_COALESCE_RX_USECS, &c.RxUsecs},
+		{nl.ETHTOOL_A_COALESCE_RX_MAX_FRAMES, &c.RxMaxFrames},
+		{nl.ETHTOOL_A_COALESCE_RX_USECS_IRQ, &c.RxUsecsIrq},
+		{nl.ETHTOOL_A_COALESCE_RX_MAX_FRAMES_IRQ, &c.RxMaxFramesIrq},
+		{nl.ETHTOOL_A_COALESCE_TX_USECS, &c.TxUsecs},
+		{nl.ETHTOOL_A_COALESCE_TX_MAX_FRAMES, &c.TxMaxFrames},
+		{nl.ETHTOOL_A_COALESCE_TX_USECS_IRQ, &c.TxUsecsIrq},
+		{nl.ETHTOOL_A_COALESCE_TX_MAX_FRAMES_IRQ, &c.TxMaxFramesIrq},
+		{nl.ETHTOOL_A_COALESCE_STATS_BLOCK_USECS, &c.StatsBlockUsecs},
+		{nl.ETHTOOL_A_COALESCE_USE_ADAPTIVE_RX, &c.UseAdaptiveRx},
+		{nl.ETHTOOL_A_COALESCE_USE_ADAPTIVE_TX, &c.UseAdaptiveTx},
+		{nl.ETHTOOL_A_COALESCE_PKT_RATE_LOW, &c.PktRateLow},
+		{nl.ETHTOOL_A_COALESCE_RX_USECS_LOW, &c.RxUsecsLow},
+		{nl.ETHTOOL_A_COALESCE_RX_MAX_FRAMES_LOW, &c.RxMaxFramesLow},
+		{nl.ETHTOOL_A_COALESCE_TX_USECS_LOW, &c.TxUsecsLow},
+		{nl.ETHTOOL_A_COALESCE_TX_MAX_FRAMES_LOW, &c.TxMaxFramesLow},
+		{nl.ETHTOOL_A_COALESCE_PKT_RATE_HIGH, &c.PktRateHigh},
+		{nl.ETHTOOL_A_COALESCE_RX_USECS_HIGH, &c.RxUsecsHigh},
+		{nl.ETHTOOL_A_COALESCE_RX_MAX_FRAMES_HIGH, &c.RxMaxFramesHigh},
+		{nl.ETHTOOL_A_COALESCE_TX_USECS_HIGH, &c.TxUsecsHigh},
+		{nl.ETHTOOL_A_COALESCE_TX_MAX_FRAMES_HIGH, &c.TxMaxFramesHigh},
+		{nl.ETHTOOL_A_COALESCE_RATE_SAMPLE_INTERVAL, &c.RateSampleInterval},
+	}
+}
+
+func (p *EthtoolPause) fields() []ethtoolField {
+	return []ethtoolField{
+		{nl.ETHTOOL_A_PAUSE_AUTONEG, &p.Autoneg},
+		{nl.ETHTOOL_A_PAUSE_RX, &p.Rx},
+		{nl.ETHTOOL_A_PAUSE_TX, &p.Tx},
+	}
+}
+
+func (e *EthtoolEEE) fields() []ethtoolField {
+	return []ethtoolField{
+		{nl.ETHTOOL_A_EEE_ENABLED, &e.Enabled},
+		{nl.ETHTOOL_A_EEE_TX_LPI_ENABLED, &e.TxLpiEnabled},
+		{nl.ETHTOOL_A_EEE_TX_LPI_TIMER, &e.TxLpiTimer},
+	}
+}
+
+func parseEthtoolLinkModes(attrs []syscall.NetlinkRouteAttr, names []string) (*EthtoolLinkModes, error) {
+	modes := &EthtoolLinkModes{}
+	for _, attr := range attrs {
+		switch attr.Attr.Type &^ unix.NLA_F_NESTED {
+		case nl.ETHTOOL_A_LINKMODES_AUTONEG:
+			autoneg := attr.Value[0] != 0
+			modes.Autoneg = &autoneg
+		case nl.ETHTOOL_A_LINKMODES_OURS:
+			advertised, supported, err := parseEthtoolBitset(attr.Value, names)
+			if err != nil {
+				return nil, err
+			}
+			modes.Advertised, modes.Supported = advertised, supported
+		case nl.ETHTOOL_A_LINKMODES_PEER:
+			peer, _, err := parseEthtoolBitset(attr.Value, names)
+			if err != nil {
+				return nil, err
+			}
+			modes.PeerAdvertised = peer
+		case nl.ETHTOOL_A_LINKMODES_SPEED:
+			speed := native.Uint32(attr.Value[0:4])
+			modes.Speed = &speed
+		case nl.ETHTOOL_A_LINKMODES_DUPLEX:
+			duplex := attr.Value[0]
+			modes.Duplex = &duplex
+		case nl.ETHTOOL_A_LINKMODES_LANES:
+			lanes := native.Uint32(attr.Value[0:4])
+			modes.Lanes = &lanes
+		}
+	}
+	return modes, nil
+}
+
+func parseEthtoolFeatures(attrs []syscall.NetlinkRouteAttr, names []string) (*EthtoolFeatures, error) {
+	features := &EthtoolFeatures{}
+	for _, attr := range attrs {
+		var set *map[string]bool
+		switch attr.Attr.Type &^ unix.NLA_F_NESTED {
+		case nl.ETHTOOL_A_FEATURES_HW:
+			set = &features.Hardware
+		case nl.ETHTOOL_A_FEATURES_WANTED:
+			set = &features.Wanted
+		case nl.ETHTOOL_A_FEATURES_ACTIVE:
+			set = &features.Active
+		case nl.ETHTOOL_A_FEATURES_NOCHANGE:
+			set = &features.NoChange
+		default:
+			continue
+		}
+		value, _, err := parseEthtoolBitset(attr.Value, names)
+		if err != nil {
+			return nil, err
+		}
+		*set = make(map[string]bool, len(value))
+		for _, name := range value {
+			(*set)[name] = true
+		}
+	}
+	return features, nil
+}
+
+func parseEthtoolRings(attrs []syscall.NetlinkRouteAttr) *EthtoolRings {
+	rings := &EthtoolRings{}
+	for _, attr := range attrs {
+		switch attr.Attr.Type {
+		case nl.ETHTOOL_A_RINGS_RX_MAX:
+			rings.RxMax = native.Uint32(attr.Value[0:4])
+		case nl.ETHTOOL_A_RINGS_RX_MINI_MAX:
+			rings.RxMiniMax = native.Uint32(attr.Value[0:4])
+		case nl.ETHTOOL_A_RINGS_RX_JUMBO_MAX:
+			rings.RxJumboMax = native.Uint32(attr.Value[0:4])
+		case nl.ETHTOOL_A_RINGS_TX_MAX:
+			rings.TxMax = native.Uint32(attr.Value[0:4])
+		}
+	}
+	parseEthtoolFields(attrs, rings.fields())
+	return rings
+}
+
+func parseEthtoolChannels(attrs []syscall.NetlinkRouteAttr) *EthtoolChannels {
+	channels := &EthtoolChannels{}
+	for _, attr := range attrs {
+		switch attr.Attr.Type {
+		case nl.ETHTOOL_A_CHANNELS_RX_MAX:
+			channels.RxMax = native.Uint32(attr.Value[0:4])
+		case nl.ETHTOOL_A_CHANNELS_TX_MAX:
+			channels.TxMax = native.Uint32(attr.Value[0:4])
+		case nl.ETHTOOL_A_CHANNELS_OTHER_MAX:
+			channels.OtherMax = native.Uint32(attr.Value[0:4])
+		case nl.ETHTOOL_A_CHANNELS_COMBINED_MAX:
+			channels.CombinedMax = native.Uint32(attr.Value[0:4])
+		}
+	}
+	parseEthtoolFields(attrs, channels.fields())
+	return channels
+}
+
+func parseEthtoolCoalesce(attrs []syscall.NetlinkRouteAttr) *EthtoolCoalesce {
+	coalesce := &EthtoolCoalesce{}
+	parseEthtoolFields(attrs, coalesce.fields())
+	return coalesce
+}
+
+func parseEthtoolPause(attrs []syscall.NetlinkRouteAttr) *EthtoolPause {
+	pause := &EthtoolPause{}
+	parseEthtoolFields(attrs, pause.fields())
+	return pause
+}
+
+func parseEthtoolEEE(attrs []syscall.NetlinkRouteAttr, names []string) (*EthtoolEEE, error) {
+	eee := &EthtoolEEE{}
+	for _, attr := range attrs {
+		switch attr.Attr.Type &^ unix.NLA_F_NESTED {
+		case nl.ETHTOOL_A_EEE_MODES_OURS:
+			advertised, supported, err := parseEthtoolBitset(attr.Value, names)
+			if err != nil {
+				return nil, err
+			}
+			eee.Advertised, eee.Supported = advertised, supported
+		case nl.ETHTOOL_A_EEE_MODES_PEER:
+			peer, _, err := parseEthtoolBitset(attr.Value, names)
+			if err != nil {
+				return nil, err
+			}
+			eee.Peer = peer
+		case nl.ETHTOOL_A_EEE_ACTIVE:
+			eee.Active = attr.Value[0] != 0
+		}
+	}
+	parseEthtoolFields(attrs, eee.fields())
+	return eee, nil
+}
@@ -0,0 +1,120 @@
+// +build linux
+
+package netlink
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ndupreez/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+func TestParseEthtoolBitset(t *testing.T) {
+	// verbose bitset with a mask: supported and advertised link modes
+	verbose := nl.NewRtAttr(nl.ETHTOOL_A_LINKMODES_OURS|unix.NLA_F_NESTED, nil)
+	verbose.AddRtAttr(nl.ETHTOOL_A_BITSET_SIZE, nl.Uint32Attr(3))
+	bits := verbose.AddRtAttr(nl.ETHTOOL_A_BITSET_BITS|unix.NLA_F_NESTED, nil)
+	for i, name := range []string{"10baseT/Half", "10baseT/Full", "100baseT/Full"} {
+		bit := bits.AddRtAttr(nl.ETHTOOL_A_BITSET_BITS_BIT|unix.NLA_F_NESTED, nil)
+		bit.AddRtAttr(nl.ETHTOOL_A_BITSET_BIT_INDEX, nl.Uint32Attr(uint32(i)))
+		bit.AddRtAttr(nl.ETHTOOL_A_BITSET_BIT_NAME, nl.ZeroTerminated(name))
+		if i > 0 {
+			bit.AddRtAttr(nl.ETHTOOL_A_BITSET_BIT_VALUE, nil)
+		}
+	}
+	value, mask, err := parseEthtoolBitset(verbose.Serialize()[unix.SizeofRtAttr:], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(value, []string{"10baseT/Full", "100baseT/Full"}) {
+		t.Fatalf("unexpected verbose value: %v", value)
+	}
+	if !reflect.DeepEqual(mask, []string{"10baseT/Half", "10baseT/Full", "100baseT/Full"}) {
+		t.Fatalf("unexpected verbose mask: %v", mask)
+	}
+
+	// compact list, as sent in notifications
+	compact := nl.NewRtAttr(nl.ETHTOOL_A_FEATURES_ACTIVE|unix.NLA_F_NESTED, nil)
+	compact.AddRtAttr(nl.ETHTOOL_A_BITSET_NOMASK, nil)
+	compact.AddRtAttr(nl.ETHTOOL_A_BITSET_SIZE, nl.Uint32Attr(40))
+	words := make([]byte, 8)
+	native.PutUint32(words[0:], 1<<1|1<<3)
+	native.PutUint32(words[4:], 1<<(35-32))
+	compact.AddRtAttr(nl.ETHTOOL_A_BITSET_VALUE, words)
+	names := []string{"tx-scatter-gather", "tx-checksum-ipv4", "tx-checksum-ip-generic", "rx-gro"}
+	value, mask, err = parseEthtoolBitset(compact.Serialize()[unix.SizeofRtAttr:], names)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(value, []string{"tx-checksum-ipv4", "rx-gro", "bit35"}) {
+		t.Fatalf("unexpected compact value: %v", value)
+	}
+	if mask != nil {
+		t.Fatalf("unexpected mask for a list: %v", mask)
+	}
+}
+
+func TestParseEthtoolUpdate(t *testing.T) {
+	b := (&nl.Genlmsg{Command: nl.ETHTOOL_MSG_RINGS_NTF, Version: nl.ETHTOOL_GENL_VERSION}).Serialize()
+	header := nl.NewRtAttr(ethtoolHeader|unix.NLA_F_NESTED, nil)
+	header.AddRtAttr(nl.ETHTOOL_A_HEADER_DEV_INDEX, nl.Uint32Attr(4))
+	header.AddRtAttr(nl.ETHTOOL_A_HEADER_DEV_NAME, nl.ZeroTerminated("eth0"))
+	b = append(b, header.Serialize()...)
+
+	rx, tx := uint32(1024), uint32(512)
+	rings := &EthtoolRings{Rx: &rx, Tx: &tx}
+	b = append(b, nl.NewRtAttr(nl.ETHTOOL_A_RINGS_RX_MAX, nl.Uint32Attr(4096)).Serialize()...)
+	for _, attr := range ethtoolFieldAttrs(rings.fields()) {
+		b = append(b, attr.Serialize()...)
+	}
+
+	update, err := parseEthtoolUpdate(b, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if update.Cmd != nl.ETHTOOL_MSG_RINGS_NTF || update.LinkIndex != 4 || update.LinkName != "eth0" {
+		t.Fatalf("unexpected update: %+v", update)
+	}
+	if update.Rings == nil {
+		t.Fatal("rings not parsed")
+	}
+	rings.RxMax = 4096
+	if !reflect.DeepEqual(update.Rings, rings) {
+		t.Fatalf("expected rings %+v, got %+v", rings, update.Rings)
+	}
+}
+
+func TestEthtoolFeaturesGetSet(t *testing.T) {
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+	minKernelRequired(t, 5, 6)
+
+	if err := LinkAdd(&Dummy{LinkAttrs{Name: "foo"}}); err != nil {
+		t.Fatal(err)
+	}
+	link, err := LinkByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	features, err := EthtoolFeaturesGet(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !features.Hardware["tx-checksum-ip-generic"] {
+		t.Fatalf("tx-checksum-ip-generic not changeable on dummy: %v", features.Hardware)
+	}
+
+	enabled := features.Active["tx-checksum-ip-generic"]
+	if err := EthtoolFeaturesSet(link, map[string]bool{"tx-checksum-ip-generic": !enabled}); err != nil {
+		t.Fatal(err)
+	}
+	features, err = EthtoolFeaturesGet(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if features.Active["tx-checksum-ip-generic"] == enabled {
+		t.Fatal("tx-checksum-ip-generic not toggled")
+	}
+}
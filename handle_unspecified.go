@@ -61,6 +61,14 @@ func (h *Handle) LinkSetAlias(link Link, name string) error {
 	return ErrNotImplemented
 }
 
+func (h *Handle) LinkAddAltName(link Link, name string) error {
+	return ErrNotImplemented
+}
+
+func (h *Handle) LinkDelAltName(link Link, name string) error {
+	return ErrNotImplemented
+}
+
 func (h *Handle) LinkSetHardwareAddr(link Link, hwaddr net.HardwareAddr) error {
 	return ErrNotImplemented
 }
@@ -85,6 +93,10 @@ func (h *Handle) LinkSetVfRate(link Link, vf, minRate, maxRate int) error {
 	return ErrNotImplemented
 }
 
+func (h *Handle) LinkSetVf(link Link, vf int, config VfConfig) error {
+	return ErrNotImplemented
+}
+
 func (h *Handle) LinkSetMaster(link Link, master Link) error {
 	return ErrNotImplemented
 }
@@ -109,6 +121,10 @@ func (h *Handle) LinkAdd(link Link) error {
 	return ErrNotImplemented
 }
 
+func (h *Handle) LinkModify(link Link) error {
+	return ErrNotImplemented
+}
+
 func (h *Handle) LinkDel(link Link) error {
 	return ErrNotImplemented
 }
@@ -129,6 +145,14 @@ func (h *Handle) LinkList() ([]Link, error) {
 	return nil, ErrNotImplemented
 }
 
+func (h *Handle) LinkStats(link Link, filterMask uint32) (*LinkStatsInfo, error) {
+	return nil, ErrNotImplemented
+}
+
+func (h *Handle) LinkStatsList(filterMask uint32) ([]LinkStatsInfo, error) {
+	return nil, ErrNotImplemented
+}
+
 func (h *Handle) LinkSetHairpin(link Link, mode bool) error {
 	return ErrNotImplemented
 }
@@ -153,6 +177,42 @@ func (h *Handle) LinkSetFlood(link Link, mode bool) error {
 	return ErrNotImplemented
 }
 
+func (h *Handle) LinkSetMcastFlood(link Link, mode bool) error {
+	return ErrNotImplemented
+}
+
+func (h *Handle) LinkSetBcastFlood(link Link, mode bool) error {
+	return ErrNotImplemented
+}
+
+func (h *Handle) LinkSetMcastToUcast(link Link, mode bool) error {
+	return ErrNotImplemented
+}
+
+func (h *Handle) LinkSetBrNeighSuppress(link Link, mode bool) error {
+	return ErrNotImplemented
+}
+
+func (h *Handle) LinkSetIsolated(link Link, mode bool) error {
+	return ErrNotImplemented
+}
+
+func (h *Handle) LinkSetBrPortState(link Link, state uint8) error {
+	return ErrNotImplemented
+}
+
+func (h *Handle) LinkSetBrPortPriority(link Link, prio uint16) error {
+	return ErrNotImplemented
+}
+
+func (h *Handle) LinkSetBrPortCost(link Link, cost uint32) error {
+	return ErrNotImplemented
+}
+
+func (h *Handle) LinkSetBrPortMcastRouter(link Link, router uint8) error {
+	return ErrNotImplemented
+}
+
 func (h *Handle) LinkSetTxQLen(link Link, qlen int) error {
 	return ErrNotImplemented
 }
@@ -209,6 +269,10 @@ func (h *Handle) FilterList(link Link, parent uint32) ([]Filter, error) {
 	return nil, ErrNotImplemented
 }
 
+func (h *Handle) TcApply(link Link, tree *TcTree) error {
+	return ErrNotImplemented
+}
+
 func (h *Handle) NeighAdd(neigh *Neigh) error {
 	return ErrNotImplemented
 }
@@ -33,6 +33,14 @@ const (
 	ETH_SS_FEATURES
 	// ETH_SS_RSS_HASH_FUNCS is RSS hush function names
 	ETH_SS_RSS_HASH_FUNCS
+	// ETH_SS_TUNABLES are tunable names
+	ETH_SS_TUNABLES
+	// ETH_SS_PHY_STATS are PHY statistic names
+	ETH_SS_PHY_STATS
+	// ETH_SS_PHY_TUNABLES are PHY tunable names
+	ETH_SS_PHY_TUNABLES
+	// ETH_SS_LINK_MODES are link mode names
+	ETH_SS_LINK_MODES
 )
 
 // IfreqSlave is a struct for ioctl bond manipulation syscalls.
@@ -47,6 +47,7 @@ type LinkAttrs struct {
 	Vfs          []VfInfo // virtual functions available on link
 	Group        uint32
 	Slave        LinkSlave
+	AltNames     []string // alternative names, see LinkAddAltName
 }
 
 // LinkSlave represents a slave device.
@@ -76,6 +77,34 @@ type VfInfo struct {
 
 	RssQuery uint32
 	Trust    uint32
+
+	VlanProto VlanProtocol     // IFLA_VF_VLAN_LIST protocol of Vlan
+	NodeGUID  net.HardwareAddr // IFLA_VF_IB_NODE_GUID
+	PortGUID  net.HardwareAddr // IFLA_VF_IB_PORT_GUID
+}
+
+// VfVlan is a VLAN of a virtual function, 802.1Q unless Proto is set.
+type VfVlan struct {
+	Vlan  int
+	Qos   int
+	Proto VlanProtocol
+}
+
+// VfConfig holds the settings of a virtual function applied by LinkSetVf.
+// Only the settings which are set are changed.
+type VfConfig struct {
+	Mac net.HardwareAddr
+	// Vlans replaces the VLAN configuration, use a single VLAN 0 to
+	// remove it. The kernel accepts a single 802.1Q or 802.1ad VLAN.
+	Vlans     []VfVlan
+	MinTxRate *uint32 // Mbps, the current rate is kept when only MaxTxRate is set
+	MaxTxRate *uint32 // Mbps, the current rate is kept when only MinTxRate is set
+	Spoofchk  *bool
+	Trust     *bool
+	LinkState *uint32 // IFLA_VF_LINK_STATE_*
+	RssQuery  *bool
+	NodeGUID  net.HardwareAddr
+	PortGUID  net.HardwareAddr
 }
 
 // LinkOperState represents the values of the IFLA_OPERSTATE link
@@ -255,13 +284,55 @@ func (ifb *Ifb) Type() string {
 	return "ifb"
 }
 
-// Bridge links are simple linux bridges
+// Nlmon links capture the netlink traffic of the namespace
+type Nlmon struct {
+	LinkAttrs
+}
+
+func (nlmon *Nlmon) Attrs() *LinkAttrs {
+	return &nlmon.LinkAttrs
+}
+
+func (nlmon *Nlmon) Type() string {
+	return "nlmon"
+}
+
+// Bridge links are simple linux bridges. The nil fields are left to the
+// kernel defaults. The times and intervals are in hundredths of a second.
 type Bridge struct {
 	LinkAttrs
-	MulticastSnooping *bool
-	AgeingTime        *uint32
-	HelloTime         *uint32
-	VlanFiltering     *bool
+	MulticastSnooping              *bool
+	AgeingTime                     *uint32
+	HelloTime                      *uint32
+	VlanFiltering                  *bool
+	ForwardDelay                   *uint32
+	MaxAge                         *uint32
+	StpState                       *uint32 // 0 disabled, 1 kernel STP, 2 user space STP
+	Priority                       *uint16
+	GroupFwdMask                   *uint16
+	GroupAddr                      net.HardwareAddr
+	VlanProtocol                   *VlanProtocol
+	VlanDefaultPVID                *uint16
+	VlanStatsEnabled               *bool
+	VlanStatsPerPort               *bool
+	MulticastRouter                *uint8 // nl.MDB_RTR_TYPE_*
+	MulticastQuerier               *bool
+	MulticastQueryUseIfaddr        *bool
+	MulticastHashMax               *uint32
+	MulticastLastMemberCount       *uint32
+	MulticastStartupQueryCount     *uint32
+	MulticastLastMemberInterval    *uint64
+	MulticastMembershipInterval    *uint64
+	MulticastQuerierInterval       *uint64
+	MulticastQueryInterval         *uint64
+	MulticastQueryResponseInterval *uint64
+	MulticastStartupQueryInterval  *uint64
+	MulticastStatsEnabled          *bool
+	MulticastIgmpVersion           *uint8
+	MulticastMldVersion            *uint8
+	NfCallIptables                 *bool
+	NfCallIp6tables                *bool
+	NfCallArptables                *bool
 }
 
 func (bridge *Bridge) Attrs() *LinkAttrs {
@@ -409,7 +480,9 @@ type Vxlan struct {
 	UDP6ZeroCSumRx bool
 	NoAge          bool
 	GBP            bool
+	GPE            bool
 	FlowBased      bool
+	VniFilter      bool // requires FlowBased
 	Age            int
 	Limit          int
 	Port           int
@@ -456,6 +529,15 @@ func (ipvlan *IPVlan) Type() string {
 	return "ipvlan"
 }
 
+// IPVtap - ipvtap is a virtual interfaces based on ipvlan
+type IPVtap struct {
+	IPVlan
+}
+
+func (ipvtap IPVtap) Type() string {
+	return "ipvtap"
+}
+
 // VlanProtocol type
 type VlanProtocol int
 
@@ -1001,6 +1083,24 @@ func (gretap *Gretap) Type() string {
 	return "gretap"
 }
 
+// Erspan devices are gretap devices carrying ERSPAN mirrored traffic. The
+// Index is used by version 1, Dir and HwID by version 2. A 0 Version is
+// left to the kernel default.
+type Erspan struct {
+	Gretap
+	Version uint8
+	Index   uint32
+	Dir     uint8
+	HwID    uint16
+}
+
+func (erspan *Erspan) Type() string {
+	if erspan.Local.To4() == nil {
+		return "ip6erspan"
+	}
+	return "erspan"
+}
+
 type Iptun struct {
 	LinkAttrs
 	Ttl        uint8
@@ -1202,12 +1302,32 @@ const (
 	CAN_STATE_SLEEPING
 )
 
+// CAN controller modes, for the Can Mask and Flags
+const (
+	CAN_CTRLMODE_LOOPBACK       = 0x01
+	CAN_CTRLMODE_LISTENONLY     = 0x02
+	CAN_CTRLMODE_3_SAMPLES      = 0x04
+	CAN_CTRLMODE_ONE_SHOT       = 0x08
+	CAN_CTRLMODE_BERR_REPORTING = 0x10
+	CAN_CTRLMODE_FD             = 0x20
+	CAN_CTRLMODE_PRESUME_ACK    = 0x40
+	CAN_CTRLMODE_FD_NON_ISO     = 0x80
+	CAN_CTRLMODE_CC_LEN8_DLC    = 0x100
+)
+
+// Can links are the SocketCAN controllers. They can't be created, their
+// configuration is changed with LinkModify while they are down.
+//
+// The bit timing is set either from BitRate, SamplePoint and SyncJumpWidth,
+// the kernel computing the segments, or from TimeQuanta and the segments
+// when BitRate is 0. The same goes for the CAN FD data phase bit timing.
+// The controller modes in Mask are set to Flags. A 0 RestartMs is not sent.
 type Can struct {
 	LinkAttrs
 
 	BitRate            uint32
-	SamplePoint        uint32
-	TimeQuanta         uint32
+	SamplePoint        uint32 // in tenths of percent
+	TimeQuanta         uint32 // in ns
 	PropagationSegment uint32
 	PhaseSegment1      uint32
 	PhaseSegment2      uint32
@@ -1224,6 +1344,24 @@ type Can struct {
 	BitRatePreScalerMax uint32
 	BitRatePreScalerInc uint32
 
+	DataBitRate            uint32
+	DataSamplePoint        uint32
+	DataTimeQuanta         uint32
+	DataPropagationSegment uint32
+	DataPhaseSegment1      uint32
+	DataPhaseSegment2      uint32
+	DataSyncJumpWidth      uint32
+	DataBitRatePreScaler   uint32
+
+	DataTimeSegment1Min     uint32
+	DataTimeSegment1Max     uint32
+	DataTimeSegment2Min     uint32
+	DataTimeSegment2Max     uint32
+	DataSyncJumpWidthMax    uint32
+	DataBitRatePreScalerMin uint32
+	DataBitRatePreScalerMax uint32
+	DataBitRatePreScalerInc uint32
+
 	ClockFrequency uint32
 
 	State uint32
@@ -1235,6 +1373,14 @@ type Can struct {
 	RxError uint16
 
 	RestartMs uint32
+
+	// Termination is the bus termination resistance in Ohm, nil to
+	// leave it unchanged, 0 to disable it.
+	Termination      *uint16
+	TerminationConst []uint16 // supported resistances, read only
+	BitRateConst     []uint32 // supported bit rates, read only
+	DataBitRateConst []uint32 // supported data bit rates, read only
+	BitRateMax       uint32   // read only
 }
 
 func (can *Can) Attrs() *LinkAttrs {
@@ -1245,6 +1391,179 @@ func (can *Can) Type() string {
 	return "can"
 }
 
+// BareUDP links encapsulate the packets of EtherType in UDP without any
+// tunnel header, Port is the UDP destination port.
+type BareUDP struct {
+	LinkAttrs
+	Port       uint16
+	EtherType  uint16
+	SrcPortMin uint16
+	MultiProto bool
+}
+
+func (bareudp *BareUDP) Attrs() *LinkAttrs {
+	return &bareudp.LinkAttrs
+}
+
+func (bareudp *BareUDP) Type() string {
+	return "bareudp"
+}
+
+// Vcan links are virtual CAN devices
+type Vcan struct {
+	LinkAttrs
+}
+
+func (vcan *Vcan) Attrs() *LinkAttrs {
+	return &vcan.LinkAttrs
+}
+
+func (vcan *Vcan) Type() string {
+	return "vcan"
+}
+
+// Vxcan links are virtual CAN tunnels, created in pairs like veth
+type Vxcan struct {
+	LinkAttrs
+	PeerName string // created only
+}
+
+func (vxcan *Vxcan) Attrs() *LinkAttrs {
+	return &vxcan.LinkAttrs
+}
+
+func (vxcan *Vxcan) Type() string {
+	return "vxcan"
+}
+
+// BatmanAdv links are B.A.T.M.A.N. advanced mesh interfaces. An empty
+// Algorithm is left to the kernel default.
+type BatmanAdv struct {
+	LinkAttrs
+	Algorithm string // created only
+}
+
+func (batadv *BatmanAdv) Attrs() *LinkAttrs {
+	return &batadv.LinkAttrs
+}
+
+func (batadv *BatmanAdv) Type() string {
+	return "batadv"
+}
+
+// HsrProtocol is the redundancy protocol of a hsr link
+type HsrProtocol uint8
+
+const (
+	HSR_PROTOCOL_HSR HsrProtocol = iota
+	HSR_PROTOCOL_PRP
+)
+
+func (p HsrProtocol) String() string {
+	switch p {
+	case HSR_PROTOCOL_HSR:
+		return "hsr"
+	case HSR_PROTOCOL_PRP:
+		return "prp"
+	}
+	return fmt.Sprintf("HsrProtocol(%d)", p)
+}
+
+// Hsr links are HSR or PRP redundant devices over two slave links. The
+// supervision frames are sent to the multicast address ending with
+// MulticastSpec. SupervisionAddr and SeqNr are read only.
+type Hsr struct {
+	LinkAttrs
+	Slave1Index     int
+	Slave2Index     int
+	InterlinkIndex  int
+	MulticastSpec   uint8
+	Version         uint8
+	Protocol        HsrProtocol
+	SupervisionAddr net.HardwareAddr
+	SeqNr           uint16
+}
+
+func (hsr *Hsr) Attrs() *LinkAttrs {
+	return &hsr.LinkAttrs
+}
+
+func (hsr *Hsr) Type() string {
+	return "hsr"
+}
+
+// Team links aggregate ports like bonds, their configuration is done by the
+// team generic netlink family, usually through teamd.
+type Team struct {
+	LinkAttrs
+}
+
+func (team *Team) Attrs() *LinkAttrs {
+	return &team.LinkAttrs
+}
+
+func (team *Team) Type() string {
+	return "team"
+}
+
+// NetkitMode is the mode of a netkit link
+type NetkitMode uint32
+
+const (
+	NETKIT_MODE_L2 NetkitMode = iota
+	NETKIT_MODE_L3
+)
+
+func (m NetkitMode) String() string {
+	switch m {
+	case NETKIT_MODE_L2:
+		return "l2"
+	case NETKIT_MODE_L3:
+		return "l3"
+	}
+	return fmt.Sprintf("NetkitMode(%d)", m)
+}
+
+// NetkitPolicy is the verdict of a netkit device without attached BPF
+// program
+type NetkitPolicy uint32
+
+const (
+	NETKIT_POLICY_FORWARD   NetkitPolicy = 0
+	NETKIT_POLICY_BLACKHOLE NetkitPolicy = 2
+)
+
+func (p NetkitPolicy) String() string {
+	switch p {
+	case NETKIT_POLICY_FORWARD:
+		return "forward"
+	case NETKIT_POLICY_BLACKHOLE:
+		return "blackhole"
+	}
+	return fmt.Sprintf("NetkitPolicy(%d)", p)
+}
+
+// Netkit links are created in pairs like veth, the packets being handled by
+// the BPF programs attached to the primary device. Policy and PeerPolicy
+// apply while no program is attached and are the only settings which can be
+// changed. Primary is read only.
+type Netkit struct {
+	LinkAttrs
+	Mode       NetkitMode
+	Policy     NetkitPolicy
+	PeerPolicy NetkitPolicy
+	PeerName   string // created only
+	Primary    bool
+}
+
+func (netkit *Netkit) Attrs() *LinkAttrs {
+	return &netkit.LinkAttrs
+}
+
+func (netkit *Netkit) Type() string {
+	return "netkit"
+}
+
 type IPoIB struct {
 	LinkAttrs
 	Pkey   uint16
@@ -1260,11 +1579,87 @@ func (ipoib *IPoIB) Type() string {
 	return "ipoib"
 }
 
+// Macsec cipher suites
+const (
+	MACSEC_CIPHER_ID_GCM_AES_128     uint64 = 0x0080C20001000001
+	MACSEC_CIPHER_ID_GCM_AES_256     uint64 = 0x0080C20001000002
+	MACSEC_CIPHER_ID_GCM_AES_XPN_128 uint64 = 0x0080C20001000003
+	MACSEC_CIPHER_ID_GCM_AES_XPN_256 uint64 = 0x0080C20001000004
+)
+
+type MacsecValidation uint8
+
+const (
+	MACSEC_VALIDATE_DISABLED MacsecValidation = iota
+	MACSEC_VALIDATE_CHECK
+	MACSEC_VALIDATE_STRICT
+)
+
+func (v MacsecValidation) String() string {
+	switch v {
+	case MACSEC_VALIDATE_DISABLED:
+		return "disabled"
+	case MACSEC_VALIDATE_CHECK:
+		return "check"
+	case MACSEC_VALIDATE_STRICT:
+		return "strict"
+	}
+	return fmt.Sprintf("validate(%d)", uint8(v))
+}
+
+type MacsecOffload uint8
+
+const (
+	MACSEC_OFFLOAD_OFF MacsecOffload = iota
+	MACSEC_OFFLOAD_PHY
+	MACSEC_OFFLOAD_MAC
+)
+
+func (o MacsecOffload) String() string {
+	switch o {
+	case MACSEC_OFFLOAD_OFF:
+		return "off"
+	case MACSEC_OFFLOAD_PHY:
+		return "phy"
+	case MACSEC_OFFLOAD_MAC:
+		return "mac"
+	}
+	return fmt.Sprintf("offload(%d)", uint8(o))
+}
+
+// Macsec links are IEEE 802.1AE secure entities (SecY) on top of the
+// ParentIndex link. The nil fields are left to the kernel defaults.
+type Macsec struct {
+	LinkAttrs
+	SCI           uint64 // MAC address and port, 0 to derive it from the MAC address and Port
+	Port          uint16 // only used when SCI is 0
+	CipherSuite   uint64 // MACSEC_CIPHER_ID_*, 0 for the default
+	IcvLen        uint8  // 0 for the default
+	EncodingSA    *uint8
+	Encrypt       *bool
+	Protect       *bool
+	IncludeSCI    *bool
+	EndStation    *bool
+	SCB           *bool
+	ReplayProtect *bool
+	Window        *uint32
+	Validation    *MacsecValidation
+	Offload       *MacsecOffload
+}
+
+func (macsec *Macsec) Attrs() *LinkAttrs {
+	return &macsec.LinkAttrs
+}
+
+func (macsec *Macsec) Type() string {
+	return "macsec"
+}
+
 // iproute2 supported devices;
 // vlan | veth | vcan | dummy | ifb | macvlan | macvtap |
 // bridge | bond | ipoib | ip6tnl | ipip | sit | vxlan |
 // gre | gretap | ip6gre | ip6gretap | vti | vti6 | nlmon |
-// bond_slave | ipvlan | xfrm
+// bond_slave | ipvlan | xfrm | macsec
 
 // LinkNotFoundError wraps the various not found errors when
 // getting/reading links. This is intended for better error
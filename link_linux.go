@@ -445,6 +445,49 @@ func (h *Handle) LinkSetAlias(link Link, name string) error {
 	return err
 }
 
+// LinkAddAltName adds an alternative name to the link device. Unlike the
+// name, an alternative name can be up to ALTIFNAMSIZ long.
+// Equivalent to: `ip link property add dev $link altname $name`
+func LinkAddAltName(link Link, name string) error {
+	return pkgHandle.LinkAddAltName(link, name)
+}
+
+// LinkAddAltName adds an alternative name to the link device. Unlike the
+// name, an alternative name can be up to ALTIFNAMSIZ long.
+// Equivalent to: `ip link property add dev $link altname $name`
+func (h *Handle) LinkAddAltName(link Link, name string) error {
+	return h.linkModifyProp(link, name, unix.RTM_NEWLINKPROP, unix.NLM_F_EXCL|unix.NLM_F_CREATE)
+}
+
+// LinkDelAltName removes an alternative name from the link device.
+// Equivalent to: `ip link property del dev $link altname $name`
+func LinkDelAltName(link Link, name string) error {
+	return pkgHandle.LinkDelAltName(link, name)
+}
+
+// LinkDelAltName removes an alternative name from the link device.
+// Equivalent to: `ip link property del dev $link altname $name`
+func (h *Handle) LinkDelAltName(link Link, name string) error {
+	return h.linkModifyProp(link, name, unix.RTM_DELLINKPROP, 0)
+}
+
+func (h *Handle) linkModifyProp(link Link, altName string, proto, flags int) error {
+	base := link.Attrs()
+	h.ensureIndex(base)
+	req := h.newNetlinkRequest(proto, flags|unix.NLM_F_ACK)
+
+	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
+	msg.Index = int32(base.Index)
+	req.AddData(msg)
+
+	props := nl.NewRtAttr(unix.IFLA_PROP_LIST|unix.NLA_F_NESTED, nil)
+	props.AddRtAttr(unix.IFLA_ALT_IFNAME, nl.ZeroTerminated(altName))
+	req.AddData(props)
+
+	_, err := req.Execute(unix.NETLINK_ROUTE, 0)
+	return err
+}
+
 // LinkSetHardwareAddr sets the hardware address of the link device.
 // Equivalent to: `ip link set $link address $hwaddr`
 func LinkSetHardwareAddr(link Link, hwaddr net.HardwareAddr) error {
@@ -763,6 +806,161 @@ func (h *Handle) LinkSetVfGUID(link Link, vf int, vfGuid net.HardwareAddr, guidT
 	return err
 }
 
+// LinkSetVf applies the settings of config to a vf of the link in a single
+// request.
+// Equivalent to: `ip link set $link vf $vf mac $mac vlan $vlan qos $qos proto $proto ...`
+func LinkSetVf(link Link, vf int, config VfConfig) error {
+	return pkgHandle.LinkSetVf(link, vf, config)
+}
+
+// LinkSetVf applies the settings of config to a vf of the link in a single
+// request.
+// Equivalent to: `ip link set $link vf $vf mac $mac vlan $vlan qos $qos proto $proto ...`
+func (h *Handle) LinkSetVf(link Link, vf int, config VfConfig) error {
+	base := link.Attrs()
+	h.ensureIndex(base)
+	req := h.newNetlinkRequest(unix.RTM_SETLINK, unix.NLM_F_ACK)
+
+	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
+	msg.Index = int32(base.Index)
+	req.AddData(msg)
+
+	if (config.MinTxRate == nil) != (config.MaxTxRate == nil) {
+		// both rates are set at once, keep the current value of the other one
+		current, err := h.LinkByIndex(base.Index)
+		if err != nil {
+			return err
+		}
+		if err := fillVfRates(&config, vf, current.Attrs().Vfs); err != nil {
+			return err
+		}
+	}
+
+	data := nl.NewRtAttr(unix.IFLA_VFINFO_LIST, nil)
+	info := data.AddRtAttr(nl.IFLA_VF_INFO, nil)
+	if err := addVfConfigAttrs(info, uint32(vf), &config); err != nil {
+		return err
+	}
+	req.AddData(data)
+
+	_, err := req.Execute(unix.NETLINK_ROUTE, 0)
+	return err
+}
+
+// fillVfRates sets the rate missing from config to the current rate of the vf.
+func fillVfRates(config *VfConfig, vf int, vfs []VfInfo) error {
+	for _, info := range vfs {
+		if info.ID != vf {
+			continue
+		}
+		if config.MinTxRate == nil {
+			rate := info.MinTxRate
+			config.MinTxRate = &rate
+		}
+		if config.MaxTxRate == nil {
+			rate := info.MaxTxRate
+			config.MaxTxRate = &rate
+		}
+		return nil
+	}
+	return fmt.Errorf("vf %d not found", vf)
+}
+
+func addVfConfigAttrs(info *nl.RtAttr, vf uint32, config *VfConfig) error {
+	if config.Mac != nil {
+		vfmsg := nl.VfMac{
+			Vf: vf,
+		}
+		copy(vfmsg.Mac[:], []byte(config.Mac))
+		info.AddRtAttr(nl.IFLA_VF_MAC, vfmsg.Serialize())
+	}
+	if config.Vlans != nil {
+		vlans := info.AddRtAttr(nl.IFLA_VF_VLAN_LIST, nil)
+		for _, vlan := range config.Vlans {
+			proto := vlan.Proto
+			if proto == 0 {
+				proto = VLAN_PROTOCOL_8021Q
+			}
+			vfmsg := nl.VfVlanInfo{
+				VfVlan: nl.VfVlan{
+					Vf:   vf,
+					Vlan: uint32(vlan.Vlan),
+					Qos:  uint32(vlan.Qos),
+				},
+				VlanProto: native.Uint16(htons(uint16(proto))),
+			}
+			vlans.AddRtAttr(nl.IFLA_VF_VLAN_INFO, vfmsg.Serialize())
+		}
+	}
+	if config.MinTxRate != nil || config.MaxTxRate != nil {
+		vfmsg := nl.VfRate{
+			Vf: vf,
+		}
+		if config.MinTxRate != nil {
+			vfmsg.MinTxRate = *config.MinTxRate
+		}
+		if config.MaxTxRate != nil {
+			vfmsg.MaxTxRate = *config.MaxTxRate
+		}
+		info.AddRtAttr(nl.IFLA_VF_RATE, vfmsg.Serialize())
+	}
+	if config.Spoofchk != nil {
+		vfmsg := nl.VfSpoofchk{
+			Vf: vf,
+		}
+		if *config.Spoofchk {
+			vfmsg.Setting = 1
+		}
+		info.AddRtAttr(nl.IFLA_VF_SPOOFCHK, vfmsg.Serialize())
+	}
+	if config.LinkState != nil {
+		vfmsg := nl.VfLinkState{
+			Vf:        vf,
+			LinkState: *config.LinkState,
+		}
+		info.AddRtAttr(nl.IFLA_VF_LINK_STATE, vfmsg.Serialize())
+	}
+	if config.RssQuery != nil {
+		vfmsg := nl.VfRssQueryEn{
+			Vf: vf,
+		}
+		if *config.RssQuery {
+			vfmsg.Setting = 1
+		}
+		info.AddRtAttr(nl.IFLA_VF_RSS_QUERY_EN, vfmsg.Serialize())
+	}
+	if config.Trust != nil {
+		vfmsg := nl.VfTrust{
+			Vf: vf,
+		}
+		if *config.Trust {
+			vfmsg.Setting = 1
+		}
+		info.AddRtAttr(nl.IFLA_VF_TRUST, vfmsg.Serialize())
+	}
+	for _, guid := range []struct {
+		guidType int
+		vfGuid   net.HardwareAddr
+	}{
+		{nl.IFLA_VF_IB_NODE_GUID, config.NodeGUID},
+		{nl.IFLA_VF_IB_PORT_GUID, config.PortGUID},
+	} {
+		guidType, vfGuid := guid.guidType, guid.vfGuid
+		if vfGuid == nil {
+			continue
+		}
+		if len(vfGuid) != 8 {
+			return fmt.Errorf("invalid vf GUID length %d, expected 8", len(vfGuid))
+		}
+		vfmsg := nl.VfGUID{
+			Vf:   vf,
+			GUID: networkOrder.Uint64(vfGuid),
+		}
+		info.AddRtAttr(guidType, vfmsg.Serialize())
+	}
+	return nil
+}
+
 // LinkSetMaster sets the master of the link device.
 // Equivalent to: `ip link set $link master $master`
 func LinkSetMaster(link Link, master Link) error {
@@ -916,7 +1114,9 @@ type vxlanPortRange struct {
 	Lo, Hi uint16
 }
 
-func addVxlanAttrs(vxlan *Vxlan, linkInfo *nl.RtAttr) {
+// addVxlanAttrs adds the vxlan attributes, only the ones the kernel can
+// change on an existing link when modify is set.
+func addVxlanAttrs(vxlan *Vxlan, linkInfo *nl.RtAttr, modify bool) {
 	data := linkInfo.AddRtAttr(nl.IFLA_INFO_DATA, nil)
 
 	if vxlan.FlowBased {
@@ -954,6 +1154,18 @@ func addVxlanAttrs(vxlan *Vxlan, linkInfo *nl.RtAttr) {
 	data.AddRtAttr(nl.IFLA_VXLAN_TTL, nl.Uint8Attr(uint8(vxlan.TTL)))
 	data.AddRtAttr(nl.IFLA_VXLAN_TOS, nl.Uint8Attr(uint8(vxlan.TOS)))
 	data.AddRtAttr(nl.IFLA_VXLAN_LEARNING, boolAttr(vxlan.Learning))
+	if vxlan.NoAge {
+		data.AddRtAttr(nl.IFLA_VXLAN_AGEING, nl.Uint32Attr(0))
+	} else if vxlan.Age > 0 {
+		data.AddRtAttr(nl.IFLA_VXLAN_AGEING, nl.Uint32Attr(uint32(vxlan.Age)))
+	}
+	if vxlan.Limit > 0 {
+		data.AddRtAttr(nl.IFLA_VXLAN_LIMIT, nl.Uint32Attr(uint32(vxlan.Limit)))
+	}
+	if modify {
+		return
+	}
+
 	data.AddRtAttr(nl.IFLA_VXLAN_PROXY, boolAttr(vxlan.Proxy))
 	data.AddRtAttr(nl.IFLA_VXLAN_RSC, boolAttr(vxlan.RSC))
 	data.AddRtAttr(nl.IFLA_VXLAN_L2MISS, boolAttr(vxlan.L2miss))
@@ -967,16 +1179,14 @@ func addVxlanAttrs(vxlan *Vxlan, linkInfo *nl.RtAttr) {
 	if vxlan.GBP {
 		data.AddRtAttr(nl.IFLA_VXLAN_GBP, []byte{})
 	}
+	if vxlan.GPE {
+		data.AddRtAttr(nl.IFLA_VXLAN_GPE, []byte{})
+	}
 	if vxlan.FlowBased {
 		data.AddRtAttr(nl.IFLA_VXLAN_FLOWBASED, boolAttr(vxlan.FlowBased))
 	}
-	if vxlan.NoAge {
-		data.AddRtAttr(nl.IFLA_VXLAN_AGEING, nl.Uint32Attr(0))
-	} else if vxlan.Age > 0 {
-		data.AddRtAttr(nl.IFLA_VXLAN_AGEING, nl.Uint32Attr(uint32(vxlan.Age)))
-	}
-	if vxlan.Limit > 0 {
-		data.AddRtAttr(nl.IFLA_VXLAN_LIMIT, nl.Uint32Attr(uint32(vxlan.Limit)))
+	if vxlan.VniFilter {
+		data.AddRtAttr(nl.IFLA_VXLAN_VNIFILTER, boolAttr(vxlan.VniFilter))
 	}
 	if vxlan.Port > 0 {
 		data.AddRtAttr(nl.IFLA_VXLAN_PORT, htons(uint16(vxlan.Port)))
@@ -1104,6 +1314,24 @@ func (h *Handle) LinkAdd(link Link) error {
 	return h.linkModify(link, unix.NLM_F_CREATE|unix.NLM_F_EXCL|unix.NLM_F_ACK)
 }
 
+// LinkModify changes an existing link device. The type specific
+// attributes are taken from the parameters in the link object, like
+// LinkAdd, the kernel refusing to change some of them (the bond Mode of an
+// up link, the Vxlan VxlanId...). The Vxlan and Geneve attributes the
+// kernel never changes, like the destination port, are not sent. Veth,
+// Vrf and GTP only get their generic attributes changed.
+// Equivalent to: `ip link set $link type $type ...`
+func LinkModify(link Link) error {
+	return pkgHandle.LinkModify(link)
+}
+
+// LinkModify changes an existing link device. The type specific
+// attributes are taken from the parameters in the link object, like
+// LinkAdd, the kernel refusing to change some of them (the bond Mode of an
+// up link, the Vxlan VxlanId...). The Vxlan and Geneve attributes the
+// kernel never changes, like the destination port, are not sent. Veth,
+// Vrf and GTP only get their generic attributes changed.
+// Equivalent to: `ip link set $link type $type ...`
 func (h *Handle) LinkModify(link Link) error {
 	return h.linkModify(link, unix.NLM_F_REQUEST|unix.NLM_F_ACK)
 }
@@ -1112,6 +1340,9 @@ func (h *Handle) linkModify(link Link, flags int) error {
 	// TODO: support extra data for macvlan
 	base := link.Attrs()
 
+	// without NLM_F_CREATE the kernel changes the existing link
+	modify := flags&unix.NLM_F_CREATE == 0
+
 	// if tuntap, then the name can be empty, OS will provide a name
 	tuntap, isTuntap := link.(*Tuntap)
 
@@ -1119,7 +1350,7 @@ func (h *Handle) linkModify(link Link, flags int) error {
 		return fmt.Errorf("LinkAttrs.Name cannot be empty")
 	}
 
-	if isTuntap {
+	if isTuntap && !modify {
 		if tuntap.Mode < unix.IFF_TUN || tuntap.Mode > unix.IFF_TAP {
 			return fmt.Errorf("Tuntap.Mode %v unknown", tuntap.Mode)
 		}
@@ -1288,7 +1519,7 @@ func (h *Handle) linkModify(link Link, flags int) error {
 		native.PutUint32(b, uint32(base.ParentIndex))
 		data := nl.NewRtAttr(unix.IFLA_LINK, b)
 		req.AddData(data)
-	} else if link.Type() == "ipvlan" || link.Type() == "ipoib" {
+	} else if !modify && (link.Type() == "ipvlan" || link.Type() == "ipvtap" || link.Type() == "ipoib" || link.Type() == "macsec") {
 		return fmt.Errorf("Can't create %s link without ParentIndex", link.Type())
 	}
 
@@ -1372,6 +1603,10 @@ func (h *Handle) linkModify(link Link, flags int) error {
 			data.AddRtAttr(nl.IFLA_VLAN_PROTOCOL, htons(uint16(link.VlanProtocol)))
 		}
 	case *Veth:
+		if modify {
+			// veth links have no attributes to change
+			break
+		}
 		data := linkInfo.AddRtAttr(nl.IFLA_INFO_DATA, nil)
 		peer := data.AddRtAttr(nl.VETH_INFO_PEER, nil)
 		nl.NewIfInfomsgChild(peer, unix.AF_UNSPEC)
@@ -1402,13 +1637,17 @@ func (h *Handle) linkModify(link Link, flags int) error {
 			}
 		}
 	case *Vxlan:
-		addVxlanAttrs(link, linkInfo)
+		addVxlanAttrs(link, linkInfo, modify)
 	case *Bond:
 		addBondAttrs(link, linkInfo)
 	case *IPVlan:
 		data := linkInfo.AddRtAttr(nl.IFLA_INFO_DATA, nil)
 		data.AddRtAttr(nl.IFLA_IPVLAN_MODE, nl.Uint16Attr(uint16(link.Mode)))
 		data.AddRtAttr(nl.IFLA_IPVLAN_FLAG, nl.Uint16Attr(uint16(link.Flag)))
+	case *IPVtap:
+		data := linkInfo.AddRtAttr(nl.IFLA_INFO_DATA, nil)
+		data.AddRtAttr(nl.IFLA_IPVLAN_MODE, nl.Uint16Attr(uint16(link.Mode)))
+		data.AddRtAttr(nl.IFLA_IPVLAN_FLAG, nl.Uint16Attr(uint16(link.Flag)))
 	case *Macvlan:
 		if link.Mode != MACVLAN_MODE_DEFAULT {
 			data := linkInfo.AddRtAttr(nl.IFLA_INFO_DATA, nil)
@@ -1420,9 +1659,11 @@ func (h *Handle) linkModify(link Link, flags int) error {
 			data.AddRtAttr(nl.IFLA_MACVLAN_MODE, nl.Uint32Attr(macvlanModes[link.Mode]))
 		}
 	case *Geneve:
-		addGeneveAttrs(link, linkInfo)
+		addGeneveAttrs(link, linkInfo, modify)
 	case *Gretap:
 		addGretapAttrs(link, linkInfo)
+	case *Erspan:
+		addErspanAttrs(link, linkInfo)
 	case *Iptun:
 		addIptunAttrs(link, linkInfo)
 	case *Ip6tnl:
@@ -1434,15 +1675,45 @@ func (h *Handle) linkModify(link Link, flags int) error {
 	case *Vti:
 		addVtiAttrs(link, linkInfo)
 	case *Vrf:
-		addVrfAttrs(link, linkInfo)
+		if !modify {
+			addVrfAttrs(link, linkInfo)
+		}
 	case *Bridge:
 		addBridgeAttrs(link, linkInfo)
 	case *GTP:
-		addGTPAttrs(link, linkInfo)
+		if !modify {
+			addGTPAttrs(link, linkInfo)
+		}
 	case *Xfrmi:
 		addXfrmiAttrs(link, linkInfo)
 	case *IPoIB:
 		addIPoIBAttrs(link, linkInfo)
+	case *Macsec:
+		addMacsecAttrs(link, linkInfo)
+	case *Can:
+		addCanAttrs(link, linkInfo)
+	case *BareUDP:
+		addBareUDPAttrs(link, linkInfo)
+	case *Vxcan:
+		if modify {
+			// vxcan links have no attributes to change
+			break
+		}
+		data := linkInfo.AddRtAttr(nl.IFLA_INFO_DATA, nil)
+		peer := data.AddRtAttr(nl.VXCAN_INFO_PEER, nil)
+		nl.NewIfInfomsgChild(peer, unix.AF_UNSPEC)
+		peer.AddRtAttr(unix.IFLA_IFNAME, nl.ZeroTerminated(link.PeerName))
+	case *BatmanAdv:
+		if !modify && link.Algorithm != "" {
+			data := linkInfo.AddRtAttr(nl.IFLA_INFO_DATA, nil)
+			data.AddRtAttr(nl.IFLA_BATADV_ALGO_NAME, nl.ZeroTerminated(link.Algorithm))
+		}
+	case *Hsr:
+		if !modify {
+			addHsrAttrs(link, linkInfo)
+		}
+	case *Netkit:
+		addNetkitAttrs(link, linkInfo, modify)
 	}
 
 	req.AddData(linkInfo)
@@ -1497,6 +1768,11 @@ func (h *Handle) linkByNameDump(name string) (Link, error) {
 		if link.Attrs().Name == name {
 			return link, nil
 		}
+		for _, altName := range link.Attrs().AltNames {
+			if altName == name {
+				return link, nil
+			}
+		}
 	}
 	return nil, LinkNotFoundError{fmt.Errorf("Link %s not found", name)}
 }
@@ -1515,12 +1791,14 @@ func (h *Handle) linkByAliasDump(alias string) (Link, error) {
 	return nil, LinkNotFoundError{fmt.Errorf("Link alias %s not found", alias)}
 }
 
-// LinkByName finds a link by name and returns a pointer to the object.
+// LinkByName finds a link by name or alternative name and returns a
+// pointer to the object.
 func LinkByName(name string) (Link, error) {
 	return pkgHandle.LinkByName(name)
 }
 
-// LinkByName finds a link by name and returns a pointer to the object.
+// LinkByName finds a link by name or alternative name and returns a
+// pointer to the object.
 func (h *Handle) LinkByName(name string) (Link, error) {
 	if h.lookupByDump {
 		return h.linkByNameDump(name)
@@ -1534,7 +1812,13 @@ func (h *Handle) LinkByName(name string) (Link, error) {
 	attr := nl.NewRtAttr(unix.IFLA_EXT_MASK, nl.Uint32Attr(nl.RTEXT_FILTER_VF))
 	req.AddData(attr)
 
-	nameData := nl.NewRtAttr(unix.IFLA_IFNAME, nl.ZeroTerminated(name))
+	// IFLA_IFNAME also matches alternative names, but longer names than
+	// IFNAMSIZ only fit in IFLA_ALT_IFNAME
+	nameType := unix.IFLA_IFNAME
+	if len(name) >= unix.IFNAMSIZ {
+		nameType = unix.IFLA_ALT_IFNAME
+	}
+	nameData := nl.NewRtAttr(nameType, nl.ZeroTerminated(name))
 	req.AddData(nameData)
 
 	link, err := execGetLink(req)
@@ -1684,12 +1968,16 @@ func LinkDeserialize(hdr *unix.NlMsghdr, m []byte) (Link, error) {
 						link = &Macvlan{}
 					case "macvtap":
 						link = &Macvtap{}
+					case "ipvtap":
+						link = &IPVtap{}
 					case "geneve":
 						link = &Geneve{}
 					case "gretap":
 						link = &Gretap{}
 					case "ip6gretap":
 						link = &Gretap{}
+					case "erspan", "ip6erspan":
+						link = &Erspan{}
 					case "ipip":
 						link = &Iptun{}
 					case "ip6tnl":
@@ -1712,8 +2000,26 @@ func LinkDeserialize(hdr *unix.NlMsghdr, m []byte) (Link, error) {
 						link = &Tuntap{}
 					case "ipoib":
 						link = &IPoIB{}
+					case "macsec":
+						link = &Macsec{}
 					case "can":
 						link = &Can{}
+					case "bareudp":
+						link = &BareUDP{}
+					case "nlmon":
+						link = &Nlmon{}
+					case "vcan":
+						link = &Vcan{}
+					case "vxcan":
+						link = &Vxcan{}
+					case "batadv":
+						link = &BatmanAdv{}
+					case "hsr":
+						link = &Hsr{}
+					case "team":
+						link = &Team{}
+					case "netkit":
+						link = &Netkit{}
 					default:
 						link = &GenericLink{LinkType: linkType}
 					}
@@ -1735,12 +2041,16 @@ func LinkDeserialize(hdr *unix.NlMsghdr, m []byte) (Link, error) {
 						parseMacvlanData(link, data)
 					case "macvtap":
 						parseMacvtapData(link, data)
+					case "ipvtap":
+						parseIPVtapData(link, data)
 					case "geneve":
 						parseGeneveData(link, data)
 					case "gretap":
 						parseGretapData(link, data)
 					case "ip6gretap":
 						parseGretapData(link, data)
+					case "erspan", "ip6erspan":
+						parseErspanData(link, data)
 					case "ipip":
 						parseIptunData(link, data)
 					case "ip6tnl":
@@ -1765,8 +2075,18 @@ func LinkDeserialize(hdr *unix.NlMsghdr, m []byte) (Link, error) {
 						parseTuntapData(link, data)
 					case "ipoib":
 						parseIPoIBData(link, data)
+					case "macsec":
+						parseMacsecData(link, data)
 					case "can":
 						parseCanData(link, data)
+					case "bareudp":
+						parseBareUDPData(link, data)
+					case "batadv":
+						parseBatmanAdvData(link, data)
+					case "hsr":
+						parseHsrData(link, data)
+					case "netkit":
+						parseNetkitData(link, data)
 					}
 				case nl.IFLA_INFO_SLAVE_KIND:
 					slaveType = string(info.Value[:len(info.Value)-1])
@@ -1857,6 +2177,16 @@ func LinkDeserialize(hdr *unix.NlMsghdr, m []byte) (Link, error) {
 			base.NumRxQueues = int(native.Uint32(attr.Value[0:4]))
 		case unix.IFLA_GROUP:
 			base.Group = native.Uint32(attr.Value[0:4])
+		case unix.IFLA_PROP_LIST | unix.NLA_F_NESTED:
+			props, err := nl.ParseRouteAttr(attr.Value)
+			if err != nil {
+				return nil, err
+			}
+			for _, prop := range props {
+				if prop.Attr.Type == unix.IFLA_ALT_IFNAME {
+					base.AltNames = append(base.AltNames, string(prop.Value[:len(prop.Value)-1]))
+				}
+			}
 		}
 	}
 
@@ -2129,7 +2459,127 @@ func (h *Handle) LinkSetBrProxyArpWiFi(link Link, mode bool) error {
 	return h.setProtinfoAttr(link, mode, nl.IFLA_BRPORT_PROXYARP_WIFI)
 }
 
+// LinkSetMcastFlood sets whether the bridge port floods unknown multicast.
+// Equivalent to: `bridge link set dev $link mcast_flood on|off`
+func LinkSetMcastFlood(link Link, mode bool) error {
+	return pkgHandle.LinkSetMcastFlood(link, mode)
+}
+
+// LinkSetMcastFlood sets whether the bridge port floods unknown multicast.
+// Equivalent to: `bridge link set dev $link mcast_flood on|off`
+func (h *Handle) LinkSetMcastFlood(link Link, mode bool) error {
+	return h.setProtinfoAttr(link, mode, nl.IFLA_BRPORT_MCAST_FLOOD)
+}
+
+// LinkSetBcastFlood sets whether the bridge port floods broadcast.
+// Equivalent to: `bridge link set dev $link bcast_flood on|off`
+func LinkSetBcastFlood(link Link, mode bool) error {
+	return pkgHandle.LinkSetBcastFlood(link, mode)
+}
+
+// LinkSetBcastFlood sets whether the bridge port floods broadcast.
+// Equivalent to: `bridge link set dev $link bcast_flood on|off`
+func (h *Handle) LinkSetBcastFlood(link Link, mode bool) error {
+	return h.setProtinfoAttr(link, mode, nl.IFLA_BRPORT_BCAST_FLOOD)
+}
+
+// LinkSetMcastToUcast sets whether the bridge port delivers multicast as
+// unicast to each group member.
+// Equivalent to: `bridge link set dev $link mcast_to_unicast on|off`
+func LinkSetMcastToUcast(link Link, mode bool) error {
+	return pkgHandle.LinkSetMcastToUcast(link, mode)
+}
+
+// LinkSetMcastToUcast sets whether the bridge port delivers multicast as
+// unicast to each group member.
+// Equivalent to: `bridge link set dev $link mcast_to_unicast on|off`
+func (h *Handle) LinkSetMcastToUcast(link Link, mode bool) error {
+	return h.setProtinfoAttr(link, mode, nl.IFLA_BRPORT_MCAST_TO_UCAST)
+}
+
+// LinkSetBrNeighSuppress sets whether the bridge port suppresses ARP and ND.
+// Equivalent to: `bridge link set dev $link neigh_suppress on|off`
+func LinkSetBrNeighSuppress(link Link, mode bool) error {
+	return pkgHandle.LinkSetBrNeighSuppress(link, mode)
+}
+
+// LinkSetBrNeighSuppress sets whether the bridge port suppresses ARP and ND.
+// Equivalent to: `bridge link set dev $link neigh_suppress on|off`
+func (h *Handle) LinkSetBrNeighSuppress(link Link, mode bool) error {
+	return h.setProtinfoAttr(link, mode, nl.IFLA_BRPORT_NEIGH_SUPPRESS)
+}
+
+// LinkSetIsolated sets whether the bridge port is isolated: isolated ports
+// only forward to the non isolated ones.
+// Equivalent to: `bridge link set dev $link isolated on|off`
+func LinkSetIsolated(link Link, mode bool) error {
+	return pkgHandle.LinkSetIsolated(link, mode)
+}
+
+// LinkSetIsolated sets whether the bridge port is isolated: isolated ports
+// only forward to the non isolated ones.
+// Equivalent to: `bridge link set dev $link isolated on|off`
+func (h *Handle) LinkSetIsolated(link Link, mode bool) error {
+	return h.setProtinfoAttr(link, mode, nl.IFLA_BRPORT_ISOLATED)
+}
+
+// LinkSetBrPortState sets the STP state of the bridge port, one of
+// nl.BR_STATE_*.
+// Equivalent to: `bridge link set dev $link state $state`
+func LinkSetBrPortState(link Link, state uint8) error {
+	return pkgHandle.LinkSetBrPortState(link, state)
+}
+
+// LinkSetBrPortState sets the STP state of the bridge port, one of
+// nl.BR_STATE_*.
+// Equivalent to: `bridge link set dev $link state $state`
+func (h *Handle) LinkSetBrPortState(link Link, state uint8) error {
+	return h.setBrPortAttr(link, nl.IFLA_BRPORT_STATE, nl.Uint8Attr(state))
+}
+
+// LinkSetBrPortPriority sets the STP priority of the bridge port.
+// Equivalent to: `bridge link set dev $link priority $prio`
+func LinkSetBrPortPriority(link Link, prio uint16) error {
+	return pkgHandle.LinkSetBrPortPriority(link, prio)
+}
+
+// LinkSetBrPortPriority sets the STP priority of the bridge port.
+// Equivalent to: `bridge link set dev $link priority $prio`
+func (h *Handle) LinkSetBrPortPriority(link Link, prio uint16) error {
+	return h.setBrPortAttr(link, nl.IFLA_BRPORT_PRIORITY, nl.Uint16Attr(prio))
+}
+
+// LinkSetBrPortCost sets the STP path cost of the bridge port.
+// Equivalent to: `bridge link set dev $link cost $cost`
+func LinkSetBrPortCost(link Link, cost uint32) error {
+	return pkgHandle.LinkSetBrPortCost(link, cost)
+}
+
+// LinkSetBrPortCost sets the STP path cost of the bridge port.
+// Equivalent to: `bridge link set dev $link cost $cost`
+func (h *Handle) LinkSetBrPortCost(link Link, cost uint32) error {
+	return h.setBrPortAttr(link, nl.IFLA_BRPORT_COST, nl.Uint32Attr(cost))
+}
+
+// LinkSetBrPortMcastRouter sets the multicast router type of the bridge
+// port, one of nl.MDB_RTR_TYPE_*.
+// Equivalent to: `bridge link set dev $link mcast_router $router`
+func LinkSetBrPortMcastRouter(link Link, router uint8) error {
+	return pkgHandle.LinkSetBrPortMcastRouter(link, router)
+}
+
+// LinkSetBrPortMcastRouter sets the multicast router type of the bridge
+// port, one of nl.MDB_RTR_TYPE_*.
+// Equivalent to: `bridge link set dev $link mcast_router $router`
+func (h *Handle) LinkSetBrPortMcastRouter(link Link, router uint8) error {
+	return h.setBrPortAttr(link, nl.IFLA_BRPORT_MULTICAST_ROUTER, nl.Uint8Attr(router))
+}
+
 func (h *Handle) setProtinfoAttr(link Link, mode bool, attr int) error {
+	return h.setBrPortAttr(link, attr, boolToByte(mode))
+}
+
+func (h *Handle) setBrPortAttr(link Link, attr int, value []byte) error {
 	base := link.Attrs()
 	h.ensureIndex(base)
 	req := h.newNetlinkRequest(unix.RTM_SETLINK, unix.NLM_F_ACK)
@@ -2139,7 +2589,7 @@ func (h *Handle) setProtinfoAttr(link Link, mode bool, attr int) error {
 	req.AddData(msg)
 
 	br := nl.NewRtAttr(unix.IFLA_PROTINFO|unix.NLA_F_NESTED, nil)
-	br.AddRtAttr(attr, boolToByte(mode))
+	br.AddRtAttr(attr, value)
 	req.AddData(br)
 	_, err := req.Execute(unix.NETLINK_ROUTE, 0)
 	if err != nil {
@@ -2222,8 +2672,8 @@ func parseVxlanData(link Link, data []syscall.NetlinkRouteAttr) {
 		// NOTE(vish): Apparently some messages can be sent with no value.
 		//             We special case GBP here to not change existing
 		//             functionality. It appears that GBP sends a datum.Value
-		//             of null.
-		if len(datum.Value) == 0 && datum.Attr.Type != nl.IFLA_VXLAN_GBP {
+		//             of null. GPE is a flag attribute without value too.
+		if len(datum.Value) == 0 && datum.Attr.Type != nl.IFLA_VXLAN_GBP && datum.Attr.Type != nl.IFLA_VXLAN_GPE {
 			continue
 		}
 		switch datum.Attr.Type {
@@ -2261,8 +2711,12 @@ func parseVxlanData(link Link, data []syscall.NetlinkRouteAttr) {
 			vxlan.UDP6ZeroCSumRx = int8(datum.Value[0]) != 0
 		case nl.IFLA_VXLAN_GBP:
 			vxlan.GBP = true
+		case nl.IFLA_VXLAN_GPE:
+			vxlan.GPE = true
 		case nl.IFLA_VXLAN_FLOWBASED:
 			vxlan.FlowBased = int8(datum.Value[0]) != 0
+		case nl.IFLA_VXLAN_VNIFILTER:
+			vxlan.VniFilter = int8(datum.Value[0]) != 0
 		case nl.IFLA_VXLAN_AGEING:
 			vxlan.Age = int(native.Uint32(datum.Value[0:4]))
 			vxlan.NoAge = vxlan.Age == 0
@@ -2416,6 +2870,11 @@ func parseIPVlanData(link Link, data []syscall.NetlinkRouteAttr) {
 	}
 }
 
+func parseIPVtapData(link Link, data []syscall.NetlinkRouteAttr) {
+	ipv := link.(*IPVtap)
+	parseIPVlanData(&ipv.IPVlan, data)
+}
+
 func parseMacvtapData(link Link, data []syscall.NetlinkRouteAttr) {
 	macv := link.(*Macvtap)
 	parseMacvlanData(&macv.Macvlan, data)
@@ -2473,12 +2932,16 @@ func linkFlags(rawFlags uint32) net.Flags {
 	return f
 }
 
-func addGeneveAttrs(geneve *Geneve, linkInfo *nl.RtAttr) {
+// addGeneveAttrs adds the geneve attributes, only the ones the kernel can
+// change on an existing link when modify is set.
+func addGeneveAttrs(geneve *Geneve, linkInfo *nl.RtAttr, modify bool) {
 	data := linkInfo.AddRtAttr(nl.IFLA_INFO_DATA, nil)
 
 	if geneve.FlowBased {
 		// In flow based mode, no other attributes need to be configured
-		linkInfo.AddRtAttr(nl.IFLA_GENEVE_COLLECT_METADATA, boolAttr(geneve.FlowBased))
+		if !modify {
+			linkInfo.AddRtAttr(nl.IFLA_GENEVE_COLLECT_METADATA, boolAttr(geneve.FlowBased))
+		}
 		return
 	}
 
@@ -2494,7 +2957,7 @@ func addGeneveAttrs(geneve *Geneve, linkInfo *nl.RtAttr) {
 		data.AddRtAttr(nl.IFLA_GENEVE_ID, nl.Uint32Attr(geneve.ID))
 	}
 
-	if geneve.Dport != 0 {
+	if geneve.Dport != 0 && !modify {
 		data.AddRtAttr(nl.IFLA_GENEVE_PORT, htons(geneve.Dport))
 	}
 
@@ -2525,13 +2988,13 @@ func parseGeneveData(link Link, data []syscall.NetlinkRouteAttr) {
 	}
 }
 
-func addGretapAttrs(gretap *Gretap, linkInfo *nl.RtAttr) {
+func addGretapAttrs(gretap *Gretap, linkInfo *nl.RtAttr) *nl.RtAttr {
 	data := linkInfo.AddRtAttr(nl.IFLA_INFO_DATA, nil)
 
 	if gretap.FlowBased {
 		// In flow based mode, no other attributes need to be configured
 		data.AddRtAttr(nl.IFLA_GRE_COLLECT_METADATA, boolAttr(gretap.FlowBased))
-		return
+		return data
 	}
 
 	if ip := gretap.Local; ip != nil {
@@ -2572,6 +3035,7 @@ func addGretapAttrs(gretap *Gretap, linkInfo *nl.RtAttr) {
 	data.AddRtAttr(nl.IFLA_GRE_ENCAP_FLAGS, nl.Uint16Attr(gretap.EncapFlags))
 	data.AddRtAttr(nl.IFLA_GRE_ENCAP_SPORT, htons(gretap.EncapSport))
 	data.AddRtAttr(nl.IFLA_GRE_ENCAP_DPORT, htons(gretap.EncapDport))
+	return data
 }
 
 func parseGretapData(link Link, data []syscall.NetlinkRouteAttr) {
@@ -2610,6 +3074,45 @@ func parseGretapData(link Link, data []syscall.NetlinkRouteAttr) {
 	}
 }
 
+func addErspanAttrs(erspan *Erspan, linkInfo *nl.RtAttr) {
+	// work on a copy so the flags of the caller are left untouched
+	gretap := erspan.Gretap
+	if !gretap.FlowBased {
+		// ERSPAN frames carry a GRE sequence number
+		gretap.IFlags |= uint16(nl.GRE_SEQ)
+		gretap.OFlags |= uint16(nl.GRE_SEQ)
+	}
+	data := addGretapAttrs(&gretap, linkInfo)
+
+	if erspan.Version != 0 {
+		data.AddRtAttr(nl.IFLA_GRE_ERSPAN_VER, nl.Uint8Attr(erspan.Version))
+	}
+	switch erspan.Version {
+	case 1:
+		data.AddRtAttr(nl.IFLA_GRE_ERSPAN_INDEX, nl.Uint32Attr(erspan.Index))
+	case 2:
+		data.AddRtAttr(nl.IFLA_GRE_ERSPAN_DIR, nl.Uint8Attr(erspan.Dir))
+		data.AddRtAttr(nl.IFLA_GRE_ERSPAN_HWID, nl.Uint16Attr(erspan.HwID))
+	}
+}
+
+func parseErspanData(link Link, data []syscall.NetlinkRouteAttr) {
+	erspan := link.(*Erspan)
+	parseGretapData(&erspan.Gretap, data)
+	for _, datum := range data {
+		switch datum.Attr.Type {
+		case nl.IFLA_GRE_ERSPAN_VER:
+			erspan.Version = uint8(datum.Value[0])
+		case nl.IFLA_GRE_ERSPAN_INDEX:
+			erspan.Index = native.Uint32(datum.Value[0:4])
+		case nl.IFLA_GRE_ERSPAN_DIR:
+			erspan.Dir = uint8(datum.Value[0])
+		case nl.IFLA_GRE_ERSPAN_HWID:
+			erspan.HwID = native.Uint16(datum.Value[0:2])
+		}
+	}
+}
+
 func addGretunAttrs(gre *Gretun, linkInfo *nl.RtAttr) {
 	data := linkInfo.AddRtAttr(nl.IFLA_INFO_DATA, nil)
 
@@ -2973,36 +3476,203 @@ func parseVrfData(link Link, data []syscall.NetlinkRouteAttr) {
 
 func addBridgeAttrs(bridge *Bridge, linkInfo *nl.RtAttr) {
 	data := linkInfo.AddRtAttr(nl.IFLA_INFO_DATA, nil)
-	if bridge.MulticastSnooping != nil {
-		data.AddRtAttr(nl.IFLA_BR_MCAST_SNOOPING, boolToByte(*bridge.MulticastSnooping))
+	if bridge.ForwardDelay != nil {
+		data.AddRtAttr(nl.IFLA_BR_FORWARD_DELAY, nl.Uint32Attr(*bridge.ForwardDelay))
+	}
+	if bridge.HelloTime != nil {
+		data.AddRtAttr(nl.IFLA_BR_HELLO_TIME, nl.Uint32Attr(*bridge.HelloTime))
+	}
+	if bridge.MaxAge != nil {
+		data.AddRtAttr(nl.IFLA_BR_MAX_AGE, nl.Uint32Attr(*bridge.MaxAge))
 	}
 	if bridge.AgeingTime != nil {
 		data.AddRtAttr(nl.IFLA_BR_AGEING_TIME, nl.Uint32Attr(*bridge.AgeingTime))
 	}
-	if bridge.HelloTime != nil {
-		data.AddRtAttr(nl.IFLA_BR_HELLO_TIME, nl.Uint32Attr(*bridge.HelloTime))
+	if bridge.StpState != nil {
+		data.AddRtAttr(nl.IFLA_BR_STP_STATE, nl.Uint32Attr(*bridge.StpState))
+	}
+	if bridge.Priority != nil {
+		data.AddRtAttr(nl.IFLA_BR_PRIORITY, nl.Uint16Attr(*bridge.Priority))
+	}
+	if bridge.GroupFwdMask != nil {
+		data.AddRtAttr(nl.IFLA_BR_GROUP_FWD_MASK, nl.Uint16Attr(*bridge.GroupFwdMask))
 	}
 	if bridge.VlanFiltering != nil {
 		data.AddRtAttr(nl.IFLA_BR_VLAN_FILTERING, boolToByte(*bridge.VlanFiltering))
 	}
+	if bridge.VlanProtocol != nil {
+		data.AddRtAttr(nl.IFLA_BR_VLAN_PROTOCOL, htons(uint16(*bridge.VlanProtocol)))
+	}
+	if bridge.VlanDefaultPVID != nil {
+		data.AddRtAttr(nl.IFLA_BR_VLAN_DEFAULT_PVID, nl.Uint16Attr(*bridge.VlanDefaultPVID))
+	}
+	if bridge.VlanStatsEnabled != nil {
+		data.AddRtAttr(nl.IFLA_BR_VLAN_STATS_ENABLED, boolToByte(*bridge.VlanStatsEnabled))
+	}
+	if bridge.VlanStatsPerPort != nil {
+		data.AddRtAttr(nl.IFLA_BR_VLAN_STATS_PER_PORT, boolToByte(*bridge.VlanStatsPerPort))
+	}
+	if bridge.MulticastSnooping != nil {
+		data.AddRtAttr(nl.IFLA_BR_MCAST_SNOOPING, boolToByte(*bridge.MulticastSnooping))
+	}
+	if bridge.MulticastRouter != nil {
+		data.AddRtAttr(nl.IFLA_BR_MCAST_ROUTER, nl.Uint8Attr(*bridge.MulticastRouter))
+	}
+	if bridge.MulticastQuerier != nil {
+		data.AddRtAttr(nl.IFLA_BR_MCAST_QUERIER, boolToByte(*bridge.MulticastQuerier))
+	}
+	if bridge.MulticastQueryUseIfaddr != nil {
+		data.AddRtAttr(nl.IFLA_BR_MCAST_QUERY_USE_IFADDR, boolToByte(*bridge.MulticastQueryUseIfaddr))
+	}
+	if bridge.MulticastHashMax != nil {
+		data.AddRtAttr(nl.IFLA_BR_MCAST_HASH_MAX, nl.Uint32Attr(*bridge.MulticastHashMax))
+	}
+	if bridge.MulticastLastMemberCount != nil {
+		data.AddRtAttr(nl.IFLA_BR_MCAST_LAST_MEMBER_CNT, nl.Uint32Attr(*bridge.MulticastLastMemberCount))
+	}
+	if bridge.MulticastStartupQueryCount != nil {
+		data.AddRtAttr(nl.IFLA_BR_MCAST_STARTUP_QUERY_CNT, nl.Uint32Attr(*bridge.MulticastStartupQueryCount))
+	}
+	if bridge.MulticastLastMemberInterval != nil {
+		data.AddRtAttr(nl.IFLA_BR_MCAST_LAST_MEMBER_INTVL, nl.Uint64Attr(*bridge.MulticastLastMemberInterval))
+	}
+	if bridge.MulticastMembershipInterval != nil {
+		data.AddRtAttr(nl.IFLA_BR_MCAST_MEMBERSHIP_INTVL, nl.Uint64Attr(*bridge.MulticastMembershipInterval))
+	}
+	if bridge.MulticastQuerierInterval != nil {
+		data.AddRtAttr(nl.IFLA_BR_MCAST_QUERIER_INTVL, nl.Uint64Attr(*bridge.MulticastQuerierInterval))
+	}
+	if bridge.MulticastQueryInterval != nil {
+		data.AddRtAttr(nl.IFLA_BR_MCAST_QUERY_INTVL, nl.Uint64Attr(*bridge.MulticastQueryInterval))
+	}
+	if bridge.MulticastQueryResponseInterval != nil {
+		data.AddRtAttr(nl.IFLA_BR_MCAST_QUERY_RESPONSE_INTVL, nl.Uint64Attr(*bridge.MulticastQueryResponseInterval))
+	}
+	if bridge.MulticastStartupQueryInterval != nil {
+		data.AddRtAttr(nl.IFLA_BR_MCAST_STARTUP_QUERY_INTVL, nl.Uint64Attr(*bridge.MulticastStartupQueryInterval))
+	}
+	if bridge.MulticastStatsEnabled != nil {
+		data.AddRtAttr(nl.IFLA_BR_MCAST_STATS_ENABLED, boolToByte(*bridge.MulticastStatsEnabled))
+	}
+	if bridge.MulticastIgmpVersion != nil {
+		data.AddRtAttr(nl.IFLA_BR_MCAST_IGMP_VERSION, nl.Uint8Attr(*bridge.MulticastIgmpVersion))
+	}
+	if bridge.MulticastMldVersion != nil {
+		data.AddRtAttr(nl.IFLA_BR_MCAST_MLD_VERSION, nl.Uint8Attr(*bridge.MulticastMldVersion))
+	}
+	if bridge.NfCallIptables != nil {
+		data.AddRtAttr(nl.IFLA_BR_NF_CALL_IPTABLES, boolToByte(*bridge.NfCallIptables))
+	}
+	if bridge.NfCallIp6tables != nil {
+		data.AddRtAttr(nl.IFLA_BR_NF_CALL_IP6TABLES, boolToByte(*bridge.NfCallIp6tables))
+	}
+	if bridge.NfCallArptables != nil {
+		data.AddRtAttr(nl.IFLA_BR_NF_CALL_ARPTABLES, boolToByte(*bridge.NfCallArptables))
+	}
+	if bridge.GroupAddr != nil {
+		data.AddRtAttr(nl.IFLA_BR_GROUP_ADDR, []byte(bridge.GroupAddr))
+	}
 }
 
 func parseBridgeData(bridge Link, data []syscall.NetlinkRouteAttr) {
 	br := bridge.(*Bridge)
 	for _, datum := range data {
 		switch datum.Attr.Type {
-		case nl.IFLA_BR_AGEING_TIME:
-			ageingTime := native.Uint32(datum.Value[0:4])
-			br.AgeingTime = &ageingTime
+		case nl.IFLA_BR_FORWARD_DELAY:
+			forwardDelay := native.Uint32(datum.Value[0:4])
+			br.ForwardDelay = &forwardDelay
 		case nl.IFLA_BR_HELLO_TIME:
 			helloTime := native.Uint32(datum.Value[0:4])
 			br.HelloTime = &helloTime
-		case nl.IFLA_BR_MCAST_SNOOPING:
-			mcastSnooping := datum.Value[0] == 1
-			br.MulticastSnooping = &mcastSnooping
+		case nl.IFLA_BR_MAX_AGE:
+			maxAge := native.Uint32(datum.Value[0:4])
+			br.MaxAge = &maxAge
+		case nl.IFLA_BR_AGEING_TIME:
+			ageingTime := native.Uint32(datum.Value[0:4])
+			br.AgeingTime = &ageingTime
+		case nl.IFLA_BR_STP_STATE:
+			stpState := native.Uint32(datum.Value[0:4])
+			br.StpState = &stpState
+		case nl.IFLA_BR_PRIORITY:
+			priority := native.Uint16(datum.Value[0:2])
+			br.Priority = &priority
+		case nl.IFLA_BR_GROUP_FWD_MASK:
+			groupFwdMask := native.Uint16(datum.Value[0:2])
+			br.GroupFwdMask = &groupFwdMask
 		case nl.IFLA_BR_VLAN_FILTERING:
 			vlanFiltering := datum.Value[0] == 1
 			br.VlanFiltering = &vlanFiltering
+		case nl.IFLA_BR_VLAN_PROTOCOL:
+			vlanProtocol := VlanProtocol(ntohs(datum.Value[0:2]))
+			br.VlanProtocol = &vlanProtocol
+		case nl.IFLA_BR_VLAN_DEFAULT_PVID:
+			vlanDefaultPVID := native.Uint16(datum.Value[0:2])
+			br.VlanDefaultPVID = &vlanDefaultPVID
+		case nl.IFLA_BR_VLAN_STATS_ENABLED:
+			vlanStatsEnabled := datum.Value[0] == 1
+			br.VlanStatsEnabled = &vlanStatsEnabled
+		case nl.IFLA_BR_VLAN_STATS_PER_PORT:
+			vlanStatsPerPort := datum.Value[0] == 1
+			br.VlanStatsPerPort = &vlanStatsPerPort
+		case nl.IFLA_BR_MCAST_SNOOPING:
+			multicastSnooping := datum.Value[0] == 1
+			br.MulticastSnooping = &multicastSnooping
+		case nl.IFLA_BR_MCAST_ROUTER:
+			multicastRouter := datum.Value[0]
+			br.MulticastRouter = &multicastRouter
+		case nl.IFLA_BR_MCAST_QUERIER:
+			multicastQuerier := datum.Value[0] == 1
+			br.MulticastQuerier = &multicastQuerier
+		case nl.IFLA_BR_MCAST_QUERY_USE_IFADDR:
+			multicastQueryUseIfaddr := datum.Value[0] == 1
+			br.MulticastQueryUseIfaddr = &multicastQueryUseIfaddr
+		case nl.IFLA_BR_MCAST_HASH_MAX:
+			multicastHashMax := native.Uint32(datum.Value[0:4])
+			br.MulticastHashMax = &multicastHashMax
+		case nl.IFLA_BR_MCAST_LAST_MEMBER_CNT:
+			multicastLastMemberCount := native.Uint32(datum.Value[0:4])
+			br.MulticastLastMemberCount = &multicastLastMemberCount
+		case nl.IFLA_BR_MCAST_STARTUP_QUERY_CNT:
+			multicastStartupQueryCount := native.Uint32(datum.Value[0:4])
+			br.MulticastStartupQueryCount = &multicastStartupQueryCount
+		case nl.IFLA_BR_MCAST_LAST_MEMBER_INTVL:
+			multicastLastMemberInterval := native.Uint64(datum.Value[0:8])
+			br.MulticastLastMemberInterval = &multicastLastMemberInterval
+		case nl.IFLA_BR_MCAST_MEMBERSHIP_INTVL:
+			multicastMembershipInterval := native.Uint64(datum.Value[0:8])
+			br.MulticastMembershipInterval = &multicastMembershipInterval
+		case nl.IFLA_BR_MCAST_QUERIER_INTVL:
+			multicastQuerierInterval := native.Uint64(datum.Value[0:8])
+			br.MulticastQuerierInterval = &multicastQuerierInterval
+		case nl.IFLA_BR_MCAST_QUERY_INTVL:
+			multicastQueryInterval := native.Uint64(datum.Value[0:8])
+			br.MulticastQueryInterval = &multicastQueryInterval
+		case nl.IFLA_BR_MCAST_QUERY_RESPONSE_INTVL:
+			multicastQueryResponseInterval := native.Uint64(datum.Value[0:8])
+			br.MulticastQueryResponseInterval = &multicastQueryResponseInterval
+		case nl.IFLA_BR_MCAST_STARTUP_QUERY_INTVL:
+			multicastStartupQueryInterval := native.Uint64(datum.Value[0:8])
+			br.MulticastStartupQueryInterval = &multicastStartupQueryInterval
+		case nl.IFLA_BR_MCAST_STATS_ENABLED:
+			multicastStatsEnabled := datum.Value[0] == 1
+			br.MulticastStatsEnabled = &multicastStatsEnabled
+		case nl.IFLA_BR_MCAST_IGMP_VERSION:
+			multicastIgmpVersion := datum.Value[0]
+			br.MulticastIgmpVersion = &multicastIgmpVersion
+		case nl.IFLA_BR_MCAST_MLD_VERSION:
+			multicastMldVersion := datum.Value[0]
+			br.MulticastMldVersion = &multicastMldVersion
+		case nl.IFLA_BR_NF_CALL_IPTABLES:
+			nfCallIptables := datum.Value[0] == 1
+			br.NfCallIptables = &nfCallIptables
+		case nl.IFLA_BR_NF_CALL_IP6TABLES:
+			nfCallIp6tables := datum.Value[0] == 1
+			br.NfCallIp6tables = &nfCallIp6tables
+		case nl.IFLA_BR_NF_CALL_ARPTABLES:
+			nfCallArptables := datum.Value[0] == 1
+			br.NfCallArptables = &nfCallArptables
+		case nl.IFLA_BR_GROUP_ADDR:
+			br.GroupAddr = net.HardwareAddr(append([]byte(nil), datum.Value[0:6]...))
 		}
 	}
 }
@@ -3091,6 +3761,27 @@ func parseVfInfo(data []syscall.NetlinkRouteAttr, id int) VfInfo {
 		case nl.IFLA_VF_TRUST:
 			result := nl.DeserializeVfTrust(element.Value)
 			vf.Trust = result.Setting
+
+		case nl.IFLA_VF_VLAN_LIST:
+			vlans, err := nl.ParseRouteAttr(element.Value)
+			if err != nil || len(vlans) == 0 || len(vlans[0].Value) < nl.SizeofVfVlanInfo {
+				continue
+			}
+			// the kernel supports a single vlan, also reported by IFLA_VF_VLAN
+			vlan := nl.DeserializeVfVlanInfo(vlans[0].Value)
+			proto := make([]byte, 2)
+			native.PutUint16(proto, vlan.VlanProto)
+			vf.VlanProto = VlanProtocol(ntohs(proto))
+
+		case nl.IFLA_VF_IB_NODE_GUID, nl.IFLA_VF_IB_PORT_GUID:
+			result := nl.DeserializeVfGUID(element.Value)
+			guid := make(net.HardwareAddr, 8)
+			networkOrder.PutUint64(guid, result.GUID)
+			if element.Attr.Type == nl.IFLA_VF_IB_NODE_GUID {
+				vf.NodeGUID = guid
+			} else {
+				vf.PortGUID = guid
+			}
 		}
 	}
 	return vf
@@ -3249,6 +3940,98 @@ func parseIPoIBData(link Link, data []syscall.NetlinkRouteAttr) {
 	}
 }
 
+func addMacsecAttrs(macsec *Macsec, linkInfo *nl.RtAttr) {
+	data := linkInfo.AddRtAttr(nl.IFLA_INFO_DATA, nil)
+	if macsec.SCI != 0 {
+		sci := make([]byte, 8)
+		binary.BigEndian.PutUint64(sci, macsec.SCI)
+		data.AddRtAttr(nl.IFLA_MACSEC_SCI, sci)
+	} else if macsec.Port != 0 {
+		data.AddRtAttr(nl.IFLA_MACSEC_PORT, htons(macsec.Port))
+	}
+	if macsec.CipherSuite != 0 {
+		data.AddRtAttr(nl.IFLA_MACSEC_CIPHER_SUITE, nl.Uint64Attr(macsec.CipherSuite))
+	}
+	if macsec.IcvLen != 0 {
+		data.AddRtAttr(nl.IFLA_MACSEC_ICV_LEN, nl.Uint8Attr(macsec.IcvLen))
+	}
+	if macsec.EncodingSA != nil {
+		data.AddRtAttr(nl.IFLA_MACSEC_ENCODING_SA, nl.Uint8Attr(*macsec.EncodingSA))
+	}
+	if macsec.Encrypt != nil {
+		data.AddRtAttr(nl.IFLA_MACSEC_ENCRYPT, boolToByte(*macsec.Encrypt))
+	}
+	if macsec.Protect != nil {
+		data.AddRtAttr(nl.IFLA_MACSEC_PROTECT, boolToByte(*macsec.Protect))
+	}
+	if macsec.IncludeSCI != nil {
+		data.AddRtAttr(nl.IFLA_MACSEC_INC_SCI, boolToByte(*macsec.IncludeSCI))
+	}
+	if macsec.EndStation != nil {
+		data.AddRtAttr(nl.IFLA_MACSEC_ES, boolToByte(*macsec.EndStation))
+	}
+	if macsec.SCB != nil {
+		data.AddRtAttr(nl.IFLA_MACSEC_SCB, boolToByte(*macsec.SCB))
+	}
+	if macsec.ReplayProtect != nil {
+		data.AddRtAttr(nl.IFLA_MACSEC_REPLAY_PROTECT, boolToByte(*macsec.ReplayProtect))
+	}
+	if macsec.Window != nil {
+		data.AddRtAttr(nl.IFLA_MACSEC_WINDOW, nl.Uint32Attr(*macsec.Window))
+	}
+	if macsec.Validation != nil {
+		data.AddRtAttr(nl.IFLA_MACSEC_VALIDATION, nl.Uint8Attr(uint8(*macsec.Validation)))
+	}
+	if macsec.Offload != nil {
+		data.AddRtAttr(nl.IFLA_MACSEC_OFFLOAD, nl.Uint8Attr(uint8(*macsec.Offload)))
+	}
+}
+
+func parseMacsecData(link Link, data []syscall.NetlinkRouteAttr) {
+	macsec := link.(*Macsec)
+	for _, datum := range data {
+		switch datum.Attr.Type {
+		case nl.IFLA_MACSEC_SCI:
+			macsec.SCI = binary.BigEndian.Uint64(datum.Value[0:8])
+			macsec.Port = uint16(macsec.SCI)
+		case nl.IFLA_MACSEC_CIPHER_SUITE:
+			macsec.CipherSuite = native.Uint64(datum.Value[0:8])
+		case nl.IFLA_MACSEC_ICV_LEN:
+			macsec.IcvLen = datum.Value[0]
+		case nl.IFLA_MACSEC_ENCODING_SA:
+			encodingSA := datum.Value[0]
+			macsec.EncodingSA = &encodingSA
+		case nl.IFLA_MACSEC_ENCRYPT:
+			encrypt := datum.Value[0] == 1
+			macsec.Encrypt = &encrypt
+		case nl.IFLA_MACSEC_PROTECT:
+			protect := datum.Value[0] == 1
+			macsec.Protect = &protect
+		case nl.IFLA_MACSEC_INC_SCI:
+			includeSCI := datum.Value[0] == 1
+			macsec.IncludeSCI = &includeSCI
+		case nl.IFLA_MACSEC_ES:
+			endStation := datum.Value[0] == 1
+			macsec.EndStation = &endStation
+		case nl.IFLA_MACSEC_SCB:
+			scb := datum.Value[0] == 1
+			macsec.SCB = &scb
+		case nl.IFLA_MACSEC_REPLAY_PROTECT:
+			replayProtect := datum.Value[0] == 1
+			macsec.ReplayProtect = &replayProtect
+		case nl.IFLA_MACSEC_WINDOW:
+			window := native.Uint32(datum.Value[0:4])
+			macsec.Window = &window
+		case nl.IFLA_MACSEC_VALIDATION:
+			validation := MacsecValidation(datum.Value[0])
+			macsec.Validation = &validation
+		case nl.IFLA_MACSEC_OFFLOAD:
+			offload := MacsecOffload(datum.Value[0])
+			macsec.Offload = &offload
+		}
+	}
+}
+
 func parseCanData(link Link, data []syscall.NetlinkRouteAttr) {
 	can := link.(*Can)
 	for _, datum := range data {
@@ -3286,20 +4069,210 @@ func parseCanData(link Link, data []syscall.NetlinkRouteAttr) {
 		case nl.IFLA_CAN_RESTART_MS:
 			can.RestartMs = native.Uint32(datum.Value)
 		case nl.IFLA_CAN_DATA_BITTIMING_CONST:
+			can.DataTimeSegment1Min = native.Uint32(datum.Value[16:])
+			can.DataTimeSegment1Max = native.Uint32(datum.Value[20:])
+			can.DataTimeSegment2Min = native.Uint32(datum.Value[24:])
+			can.DataTimeSegment2Max = native.Uint32(datum.Value[28:])
+			can.DataSyncJumpWidthMax = native.Uint32(datum.Value[32:])
+			can.DataBitRatePreScalerMin = native.Uint32(datum.Value[36:])
+			can.DataBitRatePreScalerMax = native.Uint32(datum.Value[40:])
+			can.DataBitRatePreScalerInc = native.Uint32(datum.Value[44:])
 		case nl.IFLA_CAN_RESTART:
 		case nl.IFLA_CAN_DATA_BITTIMING:
+			can.DataBitRate = native.Uint32(datum.Value)
+			can.DataSamplePoint = native.Uint32(datum.Value[4:])
+			can.DataTimeQuanta = native.Uint32(datum.Value[8:])
+			can.DataPropagationSegment = native.Uint32(datum.Value[12:])
+			can.DataPhaseSegment1 = native.Uint32(datum.Value[16:])
+			can.DataPhaseSegment2 = native.Uint32(datum.Value[20:])
+			can.DataSyncJumpWidth = native.Uint32(datum.Value[24:])
+			can.DataBitRatePreScaler = native.Uint32(datum.Value[28:])
 		case nl.IFLA_CAN_TERMINATION:
+			termination := native.Uint16(datum.Value)
+			can.Termination = &termination
 		case nl.IFLA_CAN_TERMINATION_CONST:
+			can.TerminationConst = nil
+			for i := 0; i+2 <= len(datum.Value); i += 2 {
+				can.TerminationConst = append(can.TerminationConst, native.Uint16(datum.Value[i:]))
+			}
 		case nl.IFLA_CAN_BITRATE_CONST:
+			can.BitRateConst = nil
+			for i := 0; i+4 <= len(datum.Value); i += 4 {
+				can.BitRateConst = append(can.BitRateConst, native.Uint32(datum.Value[i:]))
+			}
 		case nl.IFLA_CAN_DATA_BITRATE_CONST:
+			can.DataBitRateConst = nil
+			for i := 0; i+4 <= len(datum.Value); i += 4 {
+				can.DataBitRateConst = append(can.DataBitRateConst, native.Uint32(datum.Value[i:]))
+			}
 		case nl.IFLA_CAN_BITRATE_MAX:
+			can.BitRateMax = native.Uint32(datum.Value)
 		}
 	}
 }
 
+func addCanAttrs(can *Can, linkInfo *nl.RtAttr) {
+	data := linkInfo.AddRtAttr(nl.IFLA_INFO_DATA, nil)
+	if bt := canBitTiming(can.BitRate, can.SamplePoint, can.TimeQuanta, can.PropagationSegment,
+		can.PhaseSegment1, can.PhaseSegment2, can.SyncJumpWidth); bt != nil {
+		data.AddRtAttr(nl.IFLA_CAN_BITTIMING, bt)
+	}
+	if bt := canBitTiming(can.DataBitRate, can.DataSamplePoint, can.DataTimeQuanta, can.DataPropagationSegment,
+		can.DataPhaseSegment1, can.DataPhaseSegment2, can.DataSyncJumpWidth); bt != nil {
+		data.AddRtAttr(nl.IFLA_CAN_DATA_BITTIMING, bt)
+	}
+	if can.Mask != 0 {
+		// struct can_ctrlmode
+		ctrlmode := make([]byte, 8)
+		native.PutUint32(ctrlmode[0:], can.Mask)
+		native.PutUint32(ctrlmode[4:], can.Flags&can.Mask)
+		data.AddRtAttr(nl.IFLA_CAN_CTRLMODE, ctrlmode)
+	}
+	if can.RestartMs != 0 {
+		data.AddRtAttr(nl.IFLA_CAN_RESTART_MS, nl.Uint32Attr(can.RestartMs))
+	}
+	if can.Termination != nil {
+		data.AddRtAttr(nl.IFLA_CAN_TERMINATION, nl.Uint16Attr(*can.Termination))
+	}
+}
+
+// canBitTiming returns a struct can_bittiming, or nil when neither bitRate
+// nor tq are set. The kernel computes the segments from the bit rate and
+// the sample point, the bit rate from the time quanta and the segments.
+func canBitTiming(bitRate, samplePoint, tq, propSeg, phaseSeg1, phaseSeg2, sjw uint32) []byte {
+	if bitRate == 0 && tq == 0 {
+		return nil
+	}
+	bt := make([]byte, 32)
+	native.PutUint32(bt[24:], sjw)
+	if bitRate != 0 {
+		native.PutUint32(bt[0:], bitRate)
+		native.PutUint32(bt[4:], samplePoint)
+		return bt
+	}
+	native.PutUint32(bt[8:], tq)
+	native.PutUint32(bt[12:], propSeg)
+	native.PutUint32(bt[16:], phaseSeg1)
+	native.PutUint32(bt[20:], phaseSeg2)
+	return bt
+}
+
 func addIPoIBAttrs(ipoib *IPoIB, linkInfo *nl.RtAttr) {
 	data := linkInfo.AddRtAttr(nl.IFLA_INFO_DATA, nil)
 	data.AddRtAttr(nl.IFLA_IPOIB_PKEY, nl.Uint16Attr(uint16(ipoib.Pkey)))
 	data.AddRtAttr(nl.IFLA_IPOIB_MODE, nl.Uint16Attr(uint16(ipoib.Mode)))
 	data.AddRtAttr(nl.IFLA_IPOIB_UMCAST, nl.Uint16Attr(uint16(ipoib.Umcast)))
 }
+
+func addBareUDPAttrs(bareudp *BareUDP, linkInfo *nl.RtAttr) {
+	data := linkInfo.AddRtAttr(nl.IFLA_INFO_DATA, nil)
+
+	data.AddRtAttr(nl.IFLA_BAREUDP_PORT, htons(bareudp.Port))
+	data.AddRtAttr(nl.IFLA_BAREUDP_ETHERTYPE, htons(bareudp.EtherType))
+	if bareudp.SrcPortMin != 0 {
+		data.AddRtAttr(nl.IFLA_BAREUDP_SRCPORT_MIN, nl.Uint16Attr(bareudp.SrcPortMin))
+	}
+	if bareudp.MultiProto {
+		data.AddRtAttr(nl.IFLA_BAREUDP_MULTIPROTO_MODE, []byte{})
+	}
+}
+
+func parseBareUDPData(link Link, data []syscall.NetlinkRouteAttr) {
+	bareudp := link.(*BareUDP)
+	for _, datum := range data {
+		switch datum.Attr.Type {
+		case nl.IFLA_BAREUDP_PORT:
+			bareudp.Port = ntohs(datum.Value[0:2])
+		case nl.IFLA_BAREUDP_ETHERTYPE:
+			bareudp.EtherType = ntohs(datum.Value[0:2])
+		case nl.IFLA_BAREUDP_SRCPORT_MIN:
+			bareudp.SrcPortMin = native.Uint16(datum.Value[0:2])
+		case nl.IFLA_BAREUDP_MULTIPROTO_MODE:
+			bareudp.MultiProto = true
+		}
+	}
+}
+
+func parseBatmanAdvData(link Link, data []syscall.NetlinkRouteAttr) {
+	batadv := link.(*BatmanAdv)
+	for _, datum := range data {
+		switch datum.Attr.Type {
+		case nl.IFLA_BATADV_ALGO_NAME:
+			batadv.Algorithm = string(bytes.TrimRight(datum.Value, "\x00"))
+		}
+	}
+}
+
+func addHsrAttrs(hsr *Hsr, linkInfo *nl.RtAttr) {
+	data := linkInfo.AddRtAttr(nl.IFLA_INFO_DATA, nil)
+
+	data.AddRtAttr(nl.IFLA_HSR_SLAVE1, nl.Uint32Attr(uint32(hsr.Slave1Index)))
+	data.AddRtAttr(nl.IFLA_HSR_SLAVE2, nl.Uint32Attr(uint32(hsr.Slave2Index)))
+	if hsr.InterlinkIndex != 0 {
+		data.AddRtAttr(nl.IFLA_HSR_INTERLINK, nl.Uint32Attr(uint32(hsr.InterlinkIndex)))
+	}
+	if hsr.MulticastSpec != 0 {
+		data.AddRtAttr(nl.IFLA_HSR_MULTICAST_SPEC, nl.Uint8Attr(hsr.MulticastSpec))
+	}
+	if hsr.Version != 0 {
+		data.AddRtAttr(nl.IFLA_HSR_VERSION, nl.Uint8Attr(hsr.Version))
+	}
+	if hsr.Protocol != HSR_PROTOCOL_HSR {
+		data.AddRtAttr(nl.IFLA_HSR_PROTOCOL, nl.Uint8Attr(uint8(hsr.Protocol)))
+	}
+}
+
+func parseHsrData(link Link, data []syscall.NetlinkRouteAttr) {
+	hsr := link.(*Hsr)
+	for _, datum := range data {
+		switch datum.Attr.Type {
+		case nl.IFLA_HSR_SLAVE1:
+			hsr.Slave1Index = int(native.Uint32(datum.Value[0:4]))
+		case nl.IFLA_HSR_SLAVE2:
+			hsr.Slave2Index = int(native.Uint32(datum.Value[0:4]))
+		case nl.IFLA_HSR_INTERLINK:
+			hsr.InterlinkIndex = int(native.Uint32(datum.Value[0:4]))
+		case nl.IFLA_HSR_SUPERVISION_ADDR:
+			hsr.SupervisionAddr = net.HardwareAddr(datum.Value[0:6])
+		case nl.IFLA_HSR_SEQ_NR:
+			hsr.SeqNr = native.Uint16(datum.Value[0:2])
+		case nl.IFLA_HSR_VERSION:
+			hsr.Version = uint8(datum.Value[0])
+		case nl.IFLA_HSR_PROTOCOL:
+			hsr.Protocol = HsrProtocol(datum.Value[0])
+		}
+	}
+}
+
+func addNetkitAttrs(netkit *Netkit, linkInfo *nl.RtAttr, modify bool) {
+	data := linkInfo.AddRtAttr(nl.IFLA_INFO_DATA, nil)
+
+	data.AddRtAttr(nl.IFLA_NETKIT_POLICY, nl.Uint32Attr(uint32(netkit.Policy)))
+	data.AddRtAttr(nl.IFLA_NETKIT_PEER_POLICY, nl.Uint32Attr(uint32(netkit.PeerPolicy)))
+	if modify {
+		// the mode and the peer can't be changed
+		return
+	}
+	data.AddRtAttr(nl.IFLA_NETKIT_MODE, nl.Uint32Attr(uint32(netkit.Mode)))
+	peer := data.AddRtAttr(nl.IFLA_NETKIT_PEER_INFO, nil)
+	nl.NewIfInfomsgChild(peer, unix.AF_UNSPEC)
+	if netkit.PeerName != "" {
+		peer.AddRtAttr(unix.IFLA_IFNAME, nl.ZeroTerminated(netkit.PeerName))
+	}
+}
+
+func parseNetkitData(link Link, data []syscall.NetlinkRouteAttr) {
+	netkit := link.(*Netkit)
+	for _, datum := range data {
+		switch datum.Attr.Type {
+		case nl.IFLA_NETKIT_PRIMARY:
+			netkit.Primary = datum.Value[0] != 0
+		case nl.IFLA_NETKIT_POLICY:
+			netkit.Policy = NetkitPolicy(native.Uint32(datum.Value[0:4]))
+		case nl.IFLA_NETKIT_PEER_POLICY:
+			netkit.PeerPolicy = NetkitPolicy(native.Uint32(datum.Value[0:4]))
+		case nl.IFLA_NETKIT_MODE:
+			netkit.Mode = NetkitMode(native.Uint32(datum.Value[0:4]))
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package netlink
+
+// Filters selecting the statistics returned by LinkStats and LinkStatsList
+const (
+	LINK_STATS_FILTER_LINK_64 uint32 = 1 << iota
+	LINK_STATS_FILTER_LINK_XSTATS
+	LINK_STATS_FILTER_LINK_XSTATS_SLAVE
+	LINK_STATS_FILTER_LINK_OFFLOAD_XSTATS
+	LINK_STATS_FILTER_AF_SPEC
+	LINK_STATS_FILTER_ALL = LINK_STATS_FILTER_LINK_64 | LINK_STATS_FILTER_LINK_XSTATS |
+		LINK_STATS_FILTER_LINK_XSTATS_SLAVE | LINK_STATS_FILTER_LINK_OFFLOAD_XSTATS |
+		LINK_STATS_FILTER_AF_SPEC
+)
+
+// LinkStatsInfo holds the statistics of a link returned by RTM_GETSTATS.
+// Only the statistics selected by the filter mask of the request and
+// supported by the link are set.
+type LinkStatsInfo struct {
+	LinkIndex int
+	Link64    *LinkStatistics64
+	// CPUHit counts the traffic handled by the CPU, the rest of Link64
+	// being forwarded by the hardware.
+	CPUHit *LinkStatistics64
+	// L3HwStats counts the traffic routed by the hardware when enabled
+	L3HwStats  *LinkHwStatistics64
+	Bridge     *BridgeXstats // bridge master
+	BridgePort *BridgeXstats // bridge port
+	Bond       *BondXstats   // bond master
+	BondSlave  *BondXstats   // bond slave
+	MPLS       *MPLSLinkStats
+}
+
+/*
+Ref: struct rtnl_hw_stats64 {...}
+*/
+type LinkHwStatistics64 struct {
+	RxPackets uint64
+	TxPackets uint64
+	RxBytes   uint64
+	TxBytes   uint64
+	RxErrors  uint64
+	TxErrors  uint64
+	RxDropped uint64
+	TxDropped uint64
+	Multicast uint64
+}
+
+// BridgeXstats holds the extended statistics of a bridge or a bridge port
+type BridgeXstats struct {
+	Vlans []BridgeVlanXstats
+	Mcast *BridgeMcastStats
+	Stp   *BridgeStpXstats // bridge ports only
+}
+
+/*
+Ref: struct bridge_vlan_xstats {...}
+*/
+type BridgeVlanXstats struct {
+	RxBytes   uint64
+	RxPackets uint64
+	TxBytes   uint64
+	TxPackets uint64
+	Vid       uint16
+	Flags     uint16
+}
+
+/*
+Ref: struct br_mcast_stats {...}
+The arrays are indexed by direction, 0 for received and 1 for sent.
+*/
+type BridgeMcastStats struct {
+	IgmpV1Queries   [2]uint64
+	IgmpV2Queries   [2]uint64
+	IgmpV3Queries   [2]uint64
+	IgmpLeaves      [2]uint64
+	IgmpV1Reports   [2]uint64
+	IgmpV2Reports   [2]uint64
+	IgmpV3Reports   [2]uint64
+	IgmpParseErrors uint64
+	MldV1Queries    [2]uint64
+	MldV2Queries    [2]uint64
+	MldLeaves       [2]uint64
+	MldV1Reports    [2]uint64
+	MldV2Reports    [2]uint64
+	MldParseErrors  uint64
+	McastBytes      [2]uint64
+	McastPackets    [2]uint64
+}
+
+/*
+Ref: struct bridge_stp_xstats {...}
+*/
+type BridgeStpXstats struct {
+	TransitionBlk uint64
+	TransitionFwd uint64
+	RxBpdu        uint64
+	TxBpdu        uint64
+	RxTcn         uint64
+	TxTcn         uint64
+}
+
+// BondXstats holds the 802.3ad statistics of a bond or a bond slave
+type BondXstats struct {
+	LacpduRx        uint64
+	LacpduTx        uint64
+	LacpduUnknownRx uint64
+	LacpduIllegalRx uint64
+	MarkerRx        uint64
+	MarkerTx        uint64
+	MarkerRespRx    uint64
+	MarkerRespTx    uint64
+	MarkerUnknownRx uint64
+}
+
+/*
+Ref: struct mpls_link_stats {...}
+*/
+type MPLSLinkStats struct {
+	RxPackets uint64
+	TxPackets uint64
+	RxBytes   uint64
+	TxBytes   uint64
+	RxErrors  uint64
+	TxErrors  uint64
+	RxDropped uint64
+	TxDropped uint64
+	RxNoroute uint64
+}
@@ -0,0 +1,234 @@
+package netlink
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"syscall"
+
+	"github.com/ndupreez/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+// LinkStats returns the statistics of a link selected by filterMask, a
+// combination of LINK_STATS_FILTER_*.
+// Equivalent to: `ip stats show dev $link`
+func LinkStats(link Link, filterMask uint32) (*LinkStatsInfo, error) {
+	return pkgHandle.LinkStats(link, filterMask)
+}
+
+// LinkStats returns the statistics of a link selected by filterMask, a
+// combination of LINK_STATS_FILTER_*.
+// Equivalent to: `ip stats show dev $link`
+func (h *Handle) LinkStats(link Link, filterMask uint32) (*LinkStatsInfo, error) {
+	base := link.Attrs()
+	h.ensureIndex(base)
+	msgs, err := h.linkStatsRequest(uint32(base.Index), filterMask, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(msgs) != 1 {
+		return nil, fmt.Errorf("expected 1 stats message for link %d, got %d", base.Index, len(msgs))
+	}
+	return parseLinkStats(msgs[0])
+}
+
+// LinkStatsList returns the statistics selected by filterMask, a
+// combination of LINK_STATS_FILTER_*, of all the links. The kernel only
+// encodes the selected statistics, LINK_STATS_FILTER_LINK_64 alone makes
+// for a cheap dump of the counters.
+// Equivalent to: `ip stats show`
+func LinkStatsList(filterMask uint32) ([]LinkStatsInfo, error) {
+	return pkgHandle.LinkStatsList(filterMask)
+}
+
+// LinkStatsList returns the statistics selected by filterMask, a
+// combination of LINK_STATS_FILTER_*, of all the links. The kernel only
+// encodes the selected statistics, LINK_STATS_FILTER_LINK_64 alone makes
+// for a cheap dump of the counters.
+// Equivalent to: `ip stats show`
+func (h *Handle) LinkStatsList(filterMask uint32) ([]LinkStatsInfo, error) {
+	msgs, err := h.linkStatsRequest(0, filterMask, unix.NLM_F_DUMP)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]LinkStatsInfo, 0, len(msgs))
+	for _, m := range msgs {
+		stats, err := parseLinkStats(m)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *stats)
+	}
+	return res, nil
+}
+
+func (h *Handle) linkStatsRequest(index, filterMask uint32, flags int) ([][]byte, error) {
+	req := h.newNetlinkRequest(unix.RTM_GETSTATS, flags)
+	msg := &nl.IfStatsMsg{
+		Family:     unix.AF_UNSPEC,
+		Ifindex:    index,
+		FilterMask: filterMask,
+	}
+	req.AddData(msg)
+	return req.Execute(unix.NETLINK_ROUTE, unix.RTM_NEWSTATS)
+}
+
+func parseLinkStats(m []byte) (*LinkStatsInfo, error) {
+	if len(m) < nl.SizeofIfStatsMsg {
+		return nil, fmt.Errorf("stats message too short: %d", len(m))
+	}
+	msg := nl.DeserializeIfStatsMsg(m)
+	stats := &LinkStatsInfo{LinkIndex: int(msg.Ifindex)}
+	attrs, err := nl.ParseRouteAttr(m[msg.Len():])
+	if err != nil {
+		return nil, err
+	}
+	for _, attr := range attrs {
+		switch attr.Attr.Type &^ unix.NLA_F_NESTED {
+		case nl.IFLA_STATS_LINK_64:
+			stats.Link64 = new(LinkStatistics64)
+			if err := binary.Read(bytes.NewBuffer(attr.Value), nl.NativeEndian(), stats.Link64); err != nil {
+				return nil, err
+			}
+		case nl.IFLA_STATS_LINK_XSTATS:
+			if err := parseLinkXstats(attr.Value, &stats.Bridge, &stats.Bond); err != nil {
+				return nil, err
+			}
+		case nl.IFLA_STATS_LINK_XSTATS_SLAVE:
+			if err := parseLinkXstats(attr.Value, &stats.BridgePort, &stats.BondSlave); err != nil {
+				return nil, err
+			}
+		case nl.IFLA_STATS_LINK_OFFLOAD_XSTATS:
+			offload, err := nl.ParseRouteAttr(attr.Value)
+			if err != nil {
+				return nil, err
+			}
+			for _, o := range offload {
+				switch o.Attr.Type {
+				case nl.IFLA_OFFLOAD_XSTATS_CPU_HIT:
+					stats.CPUHit = new(LinkStatistics64)
+					if err := binary.Read(bytes.NewBuffer(o.Value), nl.NativeEndian(), stats.CPUHit); err != nil {
+						return nil, err
+					}
+				case nl.IFLA_OFFLOAD_XSTATS_L3_STATS:
+					stats.L3HwStats = new(LinkHwStatistics64)
+					if err := binary.Read(bytes.NewBuffer(o.Value), nl.NativeEndian(), stats.L3HwStats); err != nil {
+						return nil, err
+					}
+				}
+			}
+		case nl.IFLA_STATS_AF_SPEC:
+			families, err := nl.ParseRouteAttr(attr.Value)
+			if err != nil {
+				return nil, err
+			}
+			for _, family := range families {
+				if family.Attr.Type&^unix.NLA_F_NESTED != unix.AF_MPLS {
+					continue
+				}
+				mpls, err := nl.ParseRouteAttr(family.Value)
+				if err != nil {
+					return nil, err
+				}
+				for _, datum := range mpls {
+					if datum.Attr.Type == nl.MPLS_STATS_LINK {
+						stats.MPLS = new(MPLSLinkStats)
+						if err := binary.Read(bytes.NewBuffer(datum.Value), nl.NativeEndian(), stats.MPLS); err != nil {
+							return nil, err
+						}
+					}
+				}
+			}
+		}
+	}
+	return stats, nil
+}
+
+// parseLinkXstats parses the extended statistics of a bridge or bond,
+// master or slave.
+func parseLinkXstats(b []byte, bridge **BridgeXstats, bond **BondXstats) error {
+	xstats, err := nl.ParseRouteAttr(b)
+	if err != nil {
+		return err
+	}
+	for _, x := range xstats {
+		data, err := nl.ParseRouteAttr(x.Value)
+		if err != nil {
+			return err
+		}
+		switch x.Attr.Type &^ unix.NLA_F_NESTED {
+		case nl.LINK_XSTATS_TYPE_BRIDGE:
+			if *bridge, err = parseBridgeXstats(data); err != nil {
+				return err
+			}
+		case nl.LINK_XSTATS_TYPE_BOND:
+			if *bond, err = parseBondXstats(data); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func parseBridgeXstats(data []syscall.NetlinkRouteAttr) (*BridgeXstats, error) {
+	xstats := &BridgeXstats{}
+	for _, datum := range data {
+		switch datum.Attr.Type {
+		case nl.BRIDGE_XSTATS_VLAN:
+			// struct bridge_vlan_xstats
+			if len(datum.Value) < 36 {
+				return nil, fmt.Errorf("bridge vlan xstats too short: %d", len(datum.Value))
+			}
+			xstats.Vlans = append(xstats.Vlans, BridgeVlanXstats{
+				RxBytes:   native.Uint64(datum.Value[0:8]),
+				RxPackets: native.Uint64(datum.Value[8:16]),
+				TxBytes:   native.Uint64(datum.Value[16:24]),
+				TxPackets: native.Uint64(datum.Value[24:32]),
+				Vid:       native.Uint16(datum.Value[32:34]),
+				Flags:     native.Uint16(datum.Value[34:36]),
+			})
+		case nl.BRIDGE_XSTATS_MCAST:
+			xstats.Mcast = new(BridgeMcastStats)
+			if err := binary.Read(bytes.NewBuffer(datum.Value), nl.NativeEndian(), xstats.Mcast); err != nil {
+				return nil, err
+			}
+		case nl.BRIDGE_XSTATS_STP:
+			xstats.Stp = new(BridgeStpXstats)
+			if err := binary.Read(bytes.NewBuffer(datum.Value), nl.NativeEndian(), xstats.Stp); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return xstats, nil
+}
+
+func parseBondXstats(data []syscall.NetlinkRouteAttr) (*BondXstats, error) {
+	xstats := &BondXstats{}
+	for _, datum := range data {
+		if datum.Attr.Type&^unix.NLA_F_NESTED != nl.BOND_XSTATS_3AD {
+			continue
+		}
+		stats, err := nl.ParseRouteAttr(datum.Value)
+		if err != nil {
+			return nil, err
+		}
+		counters := []*uint64{
+			nl.BOND_3AD_STAT_LACPDU_RX:         &xstats.LacpduRx,
+			nl.BOND_3AD_STAT_LACPDU_TX:         &xstats.LacpduTx,
+			nl.BOND_3AD_STAT_LACPDU_UNKNOWN_RX: &xstats.LacpduUnknownRx,
+			nl.BOND_3AD_STAT_LACPDU_ILLEGAL_RX: &xstats.LacpduIllegalRx,
+			nl.BOND_3AD_STAT_MARKER_RX:         &xstats.MarkerRx,
+			nl.BOND_3AD_STAT_MARKER_TX:         &xstats.MarkerTx,
+			nl.BOND_3AD_STAT_MARKER_RESP_RX:    &xstats.MarkerRespRx,
+			nl.BOND_3AD_STAT_MARKER_RESP_TX:    &xstats.MarkerRespTx,
+			nl.BOND_3AD_STAT_MARKER_UNKNOWN_RX: &xstats.MarkerUnknownRx,
+		}
+		for _, stat := range stats {
+			if int(stat.Attr.Type) < len(counters) && len(stat.Value) >= 8 {
+				*counters[stat.Attr.Type] = native.Uint64(stat.Value[0:8])
+			}
+		}
+	}
+	return xstats, nil
+}
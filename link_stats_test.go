@@ -0,0 +1,123 @@
+// +build linux
+
+package netlink
+
+import (
+	"testing"
+
+	"github.com/ndupreez/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+func TestParseLinkStats(t *testing.T) {
+	u64s := func(vals ...uint64) []byte {
+		b := make([]byte, 8*len(vals))
+		for i, v := range vals {
+			native.PutUint64(b[8*i:], v)
+		}
+		return b
+	}
+
+	msg := &nl.IfStatsMsg{Ifindex: 7, FilterMask: LINK_STATS_FILTER_ALL}
+	b := msg.Serialize()
+
+	link64 := make([]uint64, 23)
+	for i := range link64 {
+		link64[i] = uint64(i + 1)
+	}
+	b = append(b, nl.NewRtAttr(nl.IFLA_STATS_LINK_64, u64s(link64...)).Serialize()...)
+
+	xstats := nl.NewRtAttr(nl.IFLA_STATS_LINK_XSTATS|unix.NLA_F_NESTED, nil)
+	bridge := xstats.AddRtAttr(nl.LINK_XSTATS_TYPE_BRIDGE|unix.NLA_F_NESTED, nil)
+	vlan := append(u64s(100, 10, 200, 20), make([]byte, 8)...)
+	native.PutUint16(vlan[32:], 42)
+	native.PutUint16(vlan[34:], 1)
+	bridge.AddRtAttr(nl.BRIDGE_XSTATS_VLAN, vlan)
+	b = append(b, xstats.Serialize()...)
+
+	slave := nl.NewRtAttr(nl.IFLA_STATS_LINK_XSTATS_SLAVE|unix.NLA_F_NESTED, nil)
+	bond := slave.AddRtAttr(nl.LINK_XSTATS_TYPE_BOND|unix.NLA_F_NESTED, nil)
+	lacp := bond.AddRtAttr(nl.BOND_XSTATS_3AD|unix.NLA_F_NESTED, nil)
+	lacp.AddRtAttr(nl.BOND_3AD_STAT_LACPDU_RX, u64s(5))
+	lacp.AddRtAttr(nl.BOND_3AD_STAT_MARKER_UNKNOWN_RX, u64s(9))
+	b = append(b, slave.Serialize()...)
+
+	offload := nl.NewRtAttr(nl.IFLA_STATS_LINK_OFFLOAD_XSTATS|unix.NLA_F_NESTED, nil)
+	offload.AddRtAttr(nl.IFLA_OFFLOAD_XSTATS_CPU_HIT, u64s(link64...))
+	b = append(b, offload.Serialize()...)
+
+	afSpec := nl.NewRtAttr(nl.IFLA_STATS_AF_SPEC|unix.NLA_F_NESTED, nil)
+	mpls := afSpec.AddRtAttr(unix.AF_MPLS|unix.NLA_F_NESTED, nil)
+	mpls.AddRtAttr(nl.MPLS_STATS_LINK, u64s(1, 2, 3, 4, 5, 6, 7, 8, 9))
+	b = append(b, afSpec.Serialize()...)
+
+	stats, err := parseLinkStats(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.LinkIndex != 7 {
+		t.Fatalf("expected link index 7, got %d", stats.LinkIndex)
+	}
+	if stats.Link64 == nil || stats.Link64.RxPackets != 1 || stats.Link64.TxCompressed != 23 {
+		t.Fatalf("unexpected link64 stats: %+v", stats.Link64)
+	}
+	if stats.CPUHit == nil || *stats.CPUHit != *stats.Link64 {
+		t.Fatalf("unexpected cpu hit stats: %+v", stats.CPUHit)
+	}
+	if stats.Bridge == nil || len(stats.Bridge.Vlans) != 1 {
+		t.Fatalf("unexpected bridge xstats: %+v", stats.Bridge)
+	}
+	if v := stats.Bridge.Vlans[0]; v.RxBytes != 100 || v.TxPackets != 20 || v.Vid != 42 || v.Flags != 1 {
+		t.Fatalf("unexpected bridge vlan xstats: %+v", v)
+	}
+	if stats.BondSlave == nil || stats.BondSlave.LacpduRx != 5 || stats.BondSlave.MarkerUnknownRx != 9 {
+		t.Fatalf("unexpected bond slave xstats: %+v", stats.BondSlave)
+	}
+	if stats.Bond != nil || stats.BridgePort != nil || stats.L3HwStats != nil {
+		t.Fatal("unexpected statistics set")
+	}
+	if stats.MPLS == nil || stats.MPLS.RxPackets != 1 || stats.MPLS.RxNoroute != 9 {
+		t.Fatalf("unexpected mpls stats: %+v", stats.MPLS)
+	}
+}
+
+func TestLinkStatsGet(t *testing.T) {
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+
+	if err := LinkAdd(&Dummy{LinkAttrs{Name: "foo"}}); err != nil {
+		t.Fatal(err)
+	}
+	link, err := LinkByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := LinkStats(link, LINK_STATS_FILTER_LINK_64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.LinkIndex != link.Attrs().Index {
+		t.Fatalf("expected link index %d, got %d", link.Attrs().Index, stats.LinkIndex)
+	}
+	if stats.Link64 == nil {
+		t.Fatal("link64 statistics not returned")
+	}
+	if stats.CPUHit != nil || stats.Bridge != nil {
+		t.Fatal("statistics returned outside of the filter mask")
+	}
+
+	list, err := LinkStatsList(LINK_STATS_FILTER_LINK_64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, s := range list {
+		if s.LinkIndex == link.Attrs().Index {
+			found = s.Link64 != nil
+		}
+	}
+	if !found {
+		t.Fatal("link64 statistics of the dummy link not found in dump")
+	}
+}
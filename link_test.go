@@ -167,6 +167,16 @@ func testLinkAddDel(t *testing.T, link Link) {
 		}
 	}
 
+	if ipv, ok := link.(*IPVtap); ok {
+		other, ok := result.(*IPVtap)
+		if !ok {
+			t.Fatal("Result of create is not a ipvtap")
+		}
+		if ipv.Mode != other.Mode {
+			t.Fatalf("Got unexpected mode: %d, expected: %d", other.Mode, ipv.Mode)
+		}
+	}
+
 	if _, ok := link.(*Vti); ok {
 		_, ok := result.(*Vti)
 		if !ok {
@@ -263,6 +273,14 @@ func testLinkAddDel(t *testing.T, link Link) {
 		compareGretap(t, gretap, other)
 	}
 
+	if erspan, ok := link.(*Erspan); ok {
+		other, ok := result.(*Erspan)
+		if !ok {
+			t.Fatal("Result of create is not a Erspan")
+		}
+		compareErspan(t, erspan, other)
+	}
+
 	if gretun, ok := link.(*Gretun); ok {
 		other, ok := result.(*Gretun)
 		if !ok {
@@ -279,6 +297,14 @@ func testLinkAddDel(t *testing.T, link Link) {
 		compareXfrmi(t, xfrmi, other)
 	}
 
+	if macsec, ok := link.(*Macsec); ok {
+		other, ok := result.(*Macsec)
+		if !ok {
+			t.Fatal("Result of create is not a macsec")
+		}
+		compareMacsec(t, macsec, other)
+	}
+
 	if tuntap, ok := link.(*Tuntap); ok {
 		other, ok := result.(*Tuntap)
 		if !ok {
@@ -287,6 +313,60 @@ func testLinkAddDel(t *testing.T, link Link) {
 		compareTuntap(t, tuntap, other)
 	}
 
+	if bareudp, ok := link.(*BareUDP); ok {
+		other, ok := result.(*BareUDP)
+		if !ok {
+			t.Fatal("Result of create is not a bareudp")
+		}
+		compareBareUDP(t, bareudp, other)
+	}
+
+	if _, ok := link.(*Nlmon); ok {
+		if _, ok := result.(*Nlmon); !ok {
+			t.Fatal("Result of create is not a nlmon")
+		}
+	}
+
+	if _, ok := link.(*Vcan); ok {
+		if _, ok := result.(*Vcan); !ok {
+			t.Fatal("Result of create is not a vcan")
+		}
+	}
+
+	if _, ok := link.(*Vxcan); ok {
+		if _, ok := result.(*Vxcan); !ok {
+			t.Fatal("Result of create is not a vxcan")
+		}
+	}
+
+	if _, ok := link.(*BatmanAdv); ok {
+		if _, ok := result.(*BatmanAdv); !ok {
+			t.Fatal("Result of create is not a batadv")
+		}
+	}
+
+	if hsr, ok := link.(*Hsr); ok {
+		other, ok := result.(*Hsr)
+		if !ok {
+			t.Fatal("Result of create is not a hsr")
+		}
+		compareHsr(t, hsr, other)
+	}
+
+	if _, ok := link.(*Team); ok {
+		if _, ok := result.(*Team); !ok {
+			t.Fatal("Result of create is not a team")
+		}
+	}
+
+	if netkit, ok := link.(*Netkit); ok {
+		other, ok := result.(*Netkit)
+		if !ok {
+			t.Fatal("Result of create is not a netkit")
+		}
+		compareNetkit(t, netkit, other)
+	}
+
 	if err = LinkDel(link); err != nil {
 		t.Fatal(err)
 	}
@@ -492,9 +572,15 @@ func compareVxlan(t *testing.T, expected, actual *Vxlan) {
 	if actual.GBP != expected.GBP {
 		t.Fatal("Vxlan.GBP doesn't match")
 	}
+	if actual.GPE != expected.GPE {
+		t.Fatal("Vxlan.GPE doesn't match")
+	}
 	if actual.FlowBased != expected.FlowBased {
 		t.Fatal("Vxlan.FlowBased doesn't match")
 	}
+	if actual.VniFilter != expected.VniFilter {
+		t.Fatal("Vxlan.VniFilter doesn't match")
+	}
 	if actual.UDP6ZeroCSumTx != expected.UDP6ZeroCSumTx {
 		t.Fatal("Vxlan.UDP6ZeroCSumTx doesn't match")
 	}
@@ -524,12 +610,113 @@ func compareVxlan(t *testing.T, expected, actual *Vxlan) {
 	}
 }
 
+func compareErspan(t *testing.T, expected, actual *Erspan) {
+	// the sequence flag is added when the link is created
+	gretap := expected.Gretap
+	if !gretap.FlowBased {
+		gretap.IFlags |= nl.GRE_SEQ
+		gretap.OFlags |= nl.GRE_SEQ
+	}
+	compareGretap(t, &gretap, &actual.Gretap)
+	if expected.Version != 0 && actual.Version != expected.Version {
+		t.Fatal("Erspan.Version doesn't match")
+	}
+	switch expected.Version {
+	case 1:
+		if actual.Index != expected.Index {
+			t.Fatal("Erspan.Index doesn't match")
+		}
+	case 2:
+		if actual.Dir != expected.Dir {
+			t.Fatal("Erspan.Dir doesn't match")
+		}
+		if actual.HwID != expected.HwID {
+			t.Fatal("Erspan.HwID doesn't match")
+		}
+	}
+}
+
+func compareBareUDP(t *testing.T, expected, actual *BareUDP) {
+	if actual.Port != expected.Port {
+		t.Fatal("BareUDP.Port doesn't match")
+	}
+	if actual.EtherType != expected.EtherType {
+		t.Fatal("BareUDP.EtherType doesn't match")
+	}
+	if expected.SrcPortMin != 0 && actual.SrcPortMin != expected.SrcPortMin {
+		t.Fatal("BareUDP.SrcPortMin doesn't match")
+	}
+	if actual.MultiProto != expected.MultiProto {
+		t.Fatal("BareUDP.MultiProto doesn't match")
+	}
+}
+
+func compareHsr(t *testing.T, expected, actual *Hsr) {
+	if actual.Slave1Index != expected.Slave1Index {
+		t.Fatal("Hsr.Slave1Index doesn't match")
+	}
+	if actual.Slave2Index != expected.Slave2Index {
+		t.Fatal("Hsr.Slave2Index doesn't match")
+	}
+	if actual.Protocol != expected.Protocol {
+		t.Fatal("Hsr.Protocol doesn't match")
+	}
+	if expected.Version != 0 && actual.Version != expected.Version {
+		t.Fatal("Hsr.Version doesn't match")
+	}
+}
+
+func compareNetkit(t *testing.T, expected, actual *Netkit) {
+	if actual.Mode != expected.Mode {
+		t.Fatal("Netkit.Mode doesn't match")
+	}
+	if actual.Policy != expected.Policy {
+		t.Fatal("Netkit.Policy doesn't match")
+	}
+	if actual.PeerPolicy != expected.PeerPolicy {
+		t.Fatal("Netkit.PeerPolicy doesn't match")
+	}
+}
+
 func compareXfrmi(t *testing.T, expected, actual *Xfrmi) {
 	if expected.Ifid != actual.Ifid {
 		t.Fatal("Xfrmi.Ifid doesn't match")
 	}
 }
 
+func compareMacsec(t *testing.T, expected, actual *Macsec) {
+	if expected.SCI != 0 && expected.SCI != actual.SCI {
+		t.Fatalf("Macsec.SCI doesn't match: expected %x, got %x", expected.SCI, actual.SCI)
+	}
+	if expected.Port != 0 && expected.Port != actual.Port {
+		t.Fatal("Macsec.Port doesn't match")
+	}
+	if expected.CipherSuite != 0 && expected.CipherSuite != actual.CipherSuite {
+		t.Fatal("Macsec.CipherSuite doesn't match")
+	}
+	if expected.IcvLen != 0 && expected.IcvLen != actual.IcvLen {
+		t.Fatal("Macsec.IcvLen doesn't match")
+	}
+	if expected.EncodingSA != nil && (actual.EncodingSA == nil || *expected.EncodingSA != *actual.EncodingSA) {
+		t.Fatal("Macsec.EncodingSA doesn't match")
+	}
+	if expected.Encrypt != nil && (actual.Encrypt == nil || *expected.Encrypt != *actual.Encrypt) {
+		t.Fatal("Macsec.Encrypt doesn't match")
+	}
+	if expected.Protect != nil && (actual.Protect == nil || *expected.Protect != *actual.Protect) {
+		t.Fatal("Macsec.Protect doesn't match")
+	}
+	if expected.ReplayProtect != nil && (actual.ReplayProtect == nil || *expected.ReplayProtect != *actual.ReplayProtect) {
+		t.Fatal("Macsec.ReplayProtect doesn't match")
+	}
+	if expected.Window != nil && (actual.Window == nil || *expected.Window != *actual.Window) {
+		t.Fatal("Macsec.Window doesn't match")
+	}
+	if expected.Validation != nil && (actual.Validation == nil || *expected.Validation != *actual.Validation) {
+		t.Fatal("Macsec.Validation doesn't match")
+	}
+}
+
 func compareTuntap(t *testing.T, expected, actual *Tuntap) {
 	if expected.Mode != actual.Mode {
 		t.Fatalf("Tuntap.Mode doesn't match: expected : %+v, got %+v", expected.Mode, actual.Mode)
@@ -600,6 +787,157 @@ func TestLinkModify(t *testing.T) {
 	}
 }
 
+func TestLinkModifyBond(t *testing.T) {
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+
+	bond := NewLinkBond(LinkAttrs{Name: "foo"})
+	bond.Mode = BOND_MODE_802_3AD
+	bond.Miimon = 100
+	if err := LinkAdd(bond); err != nil {
+		t.Fatal(err)
+	}
+
+	bond = NewLinkBond(LinkAttrs{Name: "foo"})
+	bond.Miimon = 200
+	bond.XmitHashPolicy = BOND_XMIT_HASH_POLICY_LAYER3_4
+	if err := LinkModify(bond); err != nil {
+		t.Fatal(err)
+	}
+
+	link, err := LinkByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, ok := link.(*Bond)
+	if !ok {
+		t.Fatal("Result of modify is not a bond")
+	}
+	if other.Mode != BOND_MODE_802_3AD {
+		t.Fatalf("Bond.Mode is %v, should be %v", other.Mode, BOND_MODE_802_3AD)
+	}
+	if other.Miimon != 200 {
+		t.Fatalf("Bond.Miimon is %d, should be 200", other.Miimon)
+	}
+	if other.XmitHashPolicy != BOND_XMIT_HASH_POLICY_LAYER3_4 {
+		t.Fatalf("Bond.XmitHashPolicy is %v, should be %v", other.XmitHashPolicy, BOND_XMIT_HASH_POLICY_LAYER3_4)
+	}
+}
+
+func TestLinkModifyBridge(t *testing.T) {
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+
+	if err := LinkAdd(&Bridge{LinkAttrs: LinkAttrs{Name: "foo"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	ageingTime := uint32(20000)
+	vlanFiltering := true
+	if err := LinkModify(&Bridge{
+		LinkAttrs:     LinkAttrs{Name: "foo"},
+		AgeingTime:    &ageingTime,
+		VlanFiltering: &vlanFiltering,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	link, err := LinkByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bridge := link.(*Bridge)
+	if *bridge.AgeingTime != ageingTime {
+		t.Fatalf("Bridge.AgeingTime is %d, should be %d", *bridge.AgeingTime, ageingTime)
+	}
+	if !*bridge.VlanFiltering {
+		t.Fatal("Bridge.VlanFiltering not set")
+	}
+}
+
+func TestLinkModifyVxlan(t *testing.T) {
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+
+	parent := &Dummy{LinkAttrs{Name: "foo"}}
+	if err := LinkAdd(parent); err != nil {
+		t.Fatal(err)
+	}
+	vxlan := &Vxlan{
+		LinkAttrs:    LinkAttrs{Name: "bar"},
+		VxlanId:      10,
+		VtepDevIndex: parent.Index,
+		Port:         4789,
+		Proxy:        true,
+		Age:          300,
+	}
+	if err := LinkAdd(vxlan); err != nil {
+		t.Fatal(err)
+	}
+
+	vxlan.Age = 600
+	vxlan.TTL = 32
+	if err := LinkModify(vxlan); err != nil {
+		t.Fatal(err)
+	}
+
+	link, err := LinkByName("bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := link.(*Vxlan)
+	if other.Age != 600 || other.TTL != 32 {
+		t.Fatalf("Vxlan.Age and Vxlan.TTL are %d and %d, should be 600 and 32", other.Age, other.TTL)
+	}
+	if !other.Proxy || other.Port != 4789 {
+		t.Fatal("Vxlan.Proxy and Vxlan.Port should be unchanged")
+	}
+}
+
+func TestCanAttrs(t *testing.T) {
+	termination := uint16(120)
+	can := &Can{
+		BitRate:           500000,
+		SamplePoint:       875,
+		DataTimeQuanta:    25,
+		DataPhaseSegment1: 15,
+		DataPhaseSegment2: 4,
+		Mask:              CAN_CTRLMODE_FD | CAN_CTRLMODE_ONE_SHOT,
+		Flags:             CAN_CTRLMODE_FD | CAN_CTRLMODE_LOOPBACK,
+		RestartMs:         100,
+		Termination:       &termination,
+	}
+	linkInfo := nl.NewRtAttr(unix.IFLA_LINKINFO, nil)
+	addCanAttrs(can, linkInfo)
+
+	info, err := nl.ParseRouteAttr(linkInfo.Serialize()[unix.SizeofRtAttr:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := nl.ParseRouteAttr(info[0].Value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &Can{}
+	parseCanData(result, data)
+
+	if result.BitRate != 500000 || result.SamplePoint != 875 || result.TimeQuanta != 0 {
+		t.Fatalf("unexpected bit timing: %+v", result)
+	}
+	if result.DataBitRate != 0 || result.DataTimeQuanta != 25 || result.DataPhaseSegment1 != 15 || result.DataPhaseSegment2 != 4 {
+		t.Fatalf("unexpected data bit timing: %+v", result)
+	}
+	if result.Mask != CAN_CTRLMODE_FD|CAN_CTRLMODE_ONE_SHOT || result.Flags != CAN_CTRLMODE_FD {
+		t.Fatalf("unexpected ctrlmode mask %x flags %x", result.Mask, result.Flags)
+	}
+	if result.RestartMs != 100 {
+		t.Fatalf("RestartMs is %d, should be 100", result.RestartMs)
+	}
+	if result.Termination == nil || *result.Termination != termination {
+		t.Fatal("Termination doesn't match")
+	}
+}
+
 func TestLinkAddDelIfb(t *testing.T) {
 	tearDown := setUpNetlinkTest(t)
 	defer tearDown()
@@ -731,6 +1069,149 @@ func TestLinkAddDelGretapFlowBased(t *testing.T) {
 		FlowBased: true})
 }
 
+func TestLinkAddDelErspan(t *testing.T) {
+	minKernelRequired(t, 4, 16)
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+
+	testLinkAddDel(t, &Erspan{
+		Gretap: Gretap{
+			LinkAttrs: LinkAttrs{Name: "foo4"},
+			IKey:      0x101,
+			OKey:      0x101,
+			Local:     net.IPv4(127, 0, 0, 1),
+			Remote:    net.IPv4(127, 0, 0, 1)},
+		Version: 1,
+		Index:   123})
+
+	testLinkAddDel(t, &Erspan{
+		Gretap: Gretap{
+			LinkAttrs: LinkAttrs{Name: "foo6"},
+			IKey:      0x101,
+			OKey:      0x101,
+			Local:     net.ParseIP("2001:db8:abcd::1"),
+			Remote:    net.ParseIP("2001:db8:ef33::2")},
+		Version: 2,
+		Dir:     1,
+		HwID:    7})
+}
+
+func TestLinkAddDelBareUDP(t *testing.T) {
+	minKernelRequired(t, 5, 8)
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+
+	testLinkAddDel(t, &BareUDP{
+		LinkAttrs:  LinkAttrs{Name: "foo"},
+		Port:       6635,
+		EtherType:  unix.ETH_P_MPLS_UC,
+		SrcPortMin: 1000,
+		MultiProto: true})
+}
+
+func TestLinkAddDelNlmon(t *testing.T) {
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+
+	testLinkAddDel(t, &Nlmon{LinkAttrs{Name: "foo"}})
+}
+
+func TestLinkAddDelVcan(t *testing.T) {
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+
+	testLinkAddDel(t, &Vcan{LinkAttrs{Name: "foo"}})
+}
+
+func TestLinkAddDelVxcan(t *testing.T) {
+	minKernelRequired(t, 4, 12)
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+
+	testLinkAddDel(t, &Vxcan{LinkAttrs: LinkAttrs{Name: "foo"}, PeerName: "bar"})
+}
+
+func TestLinkAddDelBatmanAdv(t *testing.T) {
+	minKernelRequired(t, 5, 1)
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+
+	testLinkAddDel(t, &BatmanAdv{LinkAttrs: LinkAttrs{Name: "foo"}, Algorithm: "BATMAN_IV"})
+}
+
+func TestLinkAddDelHsr(t *testing.T) {
+	minKernelRequired(t, 5, 9)
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+
+	slave1 := &Dummy{LinkAttrs{Name: "foo1"}}
+	slave2 := &Dummy{LinkAttrs{Name: "foo2"}}
+	for _, slave := range []*Dummy{slave1, slave2} {
+		if err := LinkAdd(slave); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	testLinkAddDel(t, &Hsr{
+		LinkAttrs:   LinkAttrs{Name: "hsr0"},
+		Slave1Index: slave1.Index,
+		Slave2Index: slave2.Index,
+		Version:     1})
+
+	testLinkAddDel(t, &Hsr{
+		LinkAttrs:   LinkAttrs{Name: "prp0"},
+		Slave1Index: slave1.Index,
+		Slave2Index: slave2.Index,
+		Protocol:    HSR_PROTOCOL_PRP})
+}
+
+func TestLinkAddDelTeam(t *testing.T) {
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+
+	testLinkAddDel(t, &Team{LinkAttrs{Name: "foo"}})
+}
+
+func TestLinkAddDelNetkit(t *testing.T) {
+	minKernelRequired(t, 6, 7)
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+
+	testLinkAddDel(t, &Netkit{
+		LinkAttrs:  LinkAttrs{Name: "foo"},
+		Mode:       NETKIT_MODE_L2,
+		PeerPolicy: NETKIT_POLICY_BLACKHOLE,
+		PeerName:   "bar"})
+
+	netkit := &Netkit{LinkAttrs: LinkAttrs{Name: "foo"}, Mode: NETKIT_MODE_L3, PeerName: "bar"}
+	if err := LinkAdd(netkit); err != nil {
+		t.Fatal(err)
+	}
+	link, err := LinkByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	primary, ok := link.(*Netkit)
+	if !ok || !primary.Primary {
+		t.Fatalf("expected a primary netkit, got %+v", link)
+	}
+	if link, err = LinkByName("bar"); err != nil {
+		t.Fatal(err)
+	}
+	if peer, ok := link.(*Netkit); !ok || peer.Primary {
+		t.Fatalf("expected a netkit peer, got %+v", link)
+	}
+
+	primary.Policy = NETKIT_POLICY_BLACKHOLE
+	if err := LinkModify(primary); err != nil {
+		t.Fatal(err)
+	}
+	if link, err = LinkByName("foo"); err != nil {
+		t.Fatal(err)
+	}
+	compareNetkit(t, primary, link.(*Netkit))
+}
+
 func TestLinkAddDelVlan(t *testing.T) {
 	tearDown := setUpNetlinkTest(t)
 	defer tearDown()
@@ -979,6 +1460,218 @@ func TestLinkAddDummyWithTxQLen(t *testing.T) {
 	}
 }
 
+func TestVfConfigAttrs(t *testing.T) {
+	minRate, maxRate := uint32(100), uint32(1000)
+	spoofchk, trust, rssQuery := true, true, true
+	linkState := uint32(nl.IFLA_VF_LINK_STATE_DISABLE)
+	config := VfConfig{
+		Mac:       net.HardwareAddr{0x02, 0x00, 0x00, 0x00, 0x00, 0x01},
+		Vlans:     []VfVlan{{Vlan: 100, Qos: 3, Proto: VLAN_PROTOCOL_8021AD}},
+		MinTxRate: &minRate,
+		MaxTxRate: &maxRate,
+		Spoofchk:  &spoofchk,
+		Trust:     &trust,
+		LinkState: &linkState,
+		RssQuery:  &rssQuery,
+		NodeGUID:  net.HardwareAddr{0, 1, 2, 3, 4, 5, 6, 7},
+		PortGUID:  net.HardwareAddr{8, 9, 10, 11, 12, 13, 14, 15},
+	}
+
+	info := nl.NewRtAttr(nl.IFLA_VF_INFO, nil)
+	if err := addVfConfigAttrs(info, 2, &config); err != nil {
+		t.Fatal(err)
+	}
+	stats := info.AddRtAttr(nl.IFLA_VF_STATS, nil)
+	stats.AddRtAttr(nl.IFLA_VF_STATS_RX_DROPPED, nl.Uint64Attr(5))
+	stats.AddRtAttr(nl.IFLA_VF_STATS_TX_DROPPED, nl.Uint64Attr(7))
+	attrs, err := nl.ParseRouteAttr(info.Serialize()[unix.SizeofRtAttr:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	vf := parseVfInfo(attrs, 2)
+
+	if !bytes.Equal(vf.Mac, config.Mac) {
+		t.Fatalf("expected mac %s, got %s", config.Mac, vf.Mac)
+	}
+	if vf.VlanProto != VLAN_PROTOCOL_8021AD {
+		t.Fatalf("expected vlan protocol %s, got %s", VLAN_PROTOCOL_8021AD, vf.VlanProto)
+	}
+	if vf.MinTxRate != minRate || vf.MaxTxRate != maxRate {
+		t.Fatalf("expected rates %d/%d, got %d/%d", minRate, maxRate, vf.MinTxRate, vf.MaxTxRate)
+	}
+	if !vf.Spoofchk || vf.Trust != 1 || vf.RssQuery != 1 || vf.LinkState != linkState {
+		t.Fatalf("unexpected vf settings: %+v", vf)
+	}
+	if !bytes.Equal(vf.NodeGUID, config.NodeGUID) || !bytes.Equal(vf.PortGUID, config.PortGUID) {
+		t.Fatalf("expected guids %s/%s, got %s/%s", config.NodeGUID, config.PortGUID, vf.NodeGUID, vf.PortGUID)
+	}
+	if vf.RxDropped != 5 || vf.TxDropped != 7 {
+		t.Fatalf("expected dropped 5/7, got %d/%d", vf.RxDropped, vf.TxDropped)
+	}
+
+	if err := addVfConfigAttrs(info, 2, &VfConfig{NodeGUID: net.HardwareAddr{1, 2}}); err == nil {
+		t.Fatal("expected an error for a short GUID")
+	}
+}
+
+func TestVfConfigSingleRate(t *testing.T) {
+	vfs := []VfInfo{{ID: 1, MinTxRate: 10, MaxTxRate: 20}, {ID: 2, MinTxRate: 100, MaxTxRate: 1000}}
+	maxRate := uint32(2000)
+	config := VfConfig{MaxTxRate: &maxRate}
+	if err := fillVfRates(&config, 2, vfs); err != nil {
+		t.Fatal(err)
+	}
+
+	info := nl.NewRtAttr(nl.IFLA_VF_INFO, nil)
+	if err := addVfConfigAttrs(info, 2, &config); err != nil {
+		t.Fatal(err)
+	}
+	attrs, err := nl.ParseRouteAttr(info.Serialize()[unix.SizeofRtAttr:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	vf := parseVfInfo(attrs, 2)
+	if vf.MinTxRate != 100 || vf.MaxTxRate != maxRate {
+		t.Fatalf("expected rates 100/%d, got %d/%d", maxRate, vf.MinTxRate, vf.MaxTxRate)
+	}
+
+	if err := fillVfRates(&VfConfig{MinTxRate: &maxRate}, 3, vfs); err == nil {
+		t.Fatal("expected an error for a missing vf")
+	}
+}
+
+func TestLinkInfoDataAttrs(t *testing.T) {
+	erspan := &Erspan{
+		Gretap: Gretap{
+			Local:  net.IPv4(10, 0, 0, 1).To4(),
+			Remote: net.IPv4(10, 0, 0, 2).To4(),
+		},
+		Version: 2,
+		Dir:     1,
+		HwID:    0x2a,
+	}
+	linkInfo := nl.NewRtAttr(unix.IFLA_LINKINFO, nil)
+	addErspanAttrs(erspan, linkInfo)
+	if erspan.IFlags != 0 || erspan.OFlags != 0 {
+		t.Fatalf("erspan flags of the link were modified: %x/%x", erspan.IFlags, erspan.OFlags)
+	}
+	data, err := nl.ParseRouteAttr(linkInfo.Serialize()[2*unix.SizeofRtAttr:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed := &Erspan{}
+	parseErspanData(parsed, data)
+	if parsed.IFlags&nl.GRE_SEQ == 0 || parsed.OFlags&nl.GRE_SEQ == 0 {
+		t.Fatalf("erspan sequence flag not set: %x/%x", parsed.IFlags, parsed.OFlags)
+	}
+	compareErspan(t, erspan, parsed)
+	if !parsed.Local.Equal(erspan.Local) || !parsed.Remote.Equal(erspan.Remote) {
+		t.Fatalf("unexpected erspan addresses: %s/%s", parsed.Local, parsed.Remote)
+	}
+
+	bareudp := &BareUDP{Port: 6635, EtherType: unix.ETH_P_IP, SrcPortMin: 2000, MultiProto: true}
+	linkInfo = nl.NewRtAttr(unix.IFLA_LINKINFO, nil)
+	addBareUDPAttrs(bareudp, linkInfo)
+	if data, err = nl.ParseRouteAttr(linkInfo.Serialize()[2*unix.SizeofRtAttr:]); err != nil {
+		t.Fatal(err)
+	}
+	parsedUDP := &BareUDP{}
+	parseBareUDPData(parsedUDP, data)
+	compareBareUDP(t, bareudp, parsedUDP)
+
+	hsr := &Hsr{Slave1Index: 3, Slave2Index: 4, InterlinkIndex: 5, Protocol: HSR_PROTOCOL_PRP}
+	linkInfo = nl.NewRtAttr(unix.IFLA_LINKINFO, nil)
+	addHsrAttrs(hsr, linkInfo)
+	if data, err = nl.ParseRouteAttr(linkInfo.Serialize()[2*unix.SizeofRtAttr:]); err != nil {
+		t.Fatal(err)
+	}
+	parsedHsr := &Hsr{}
+	parseHsrData(parsedHsr, data)
+	compareHsr(t, hsr, parsedHsr)
+	if parsedHsr.InterlinkIndex != hsr.InterlinkIndex {
+		t.Fatal("Hsr.InterlinkIndex doesn't match")
+	}
+
+	netkit := &Netkit{Mode: NETKIT_MODE_L2, Policy: NETKIT_POLICY_BLACKHOLE, PeerName: "bar"}
+	linkInfo = nl.NewRtAttr(unix.IFLA_LINKINFO, nil)
+	addNetkitAttrs(netkit, linkInfo, false)
+	if data, err = nl.ParseRouteAttr(linkInfo.Serialize()[2*unix.SizeofRtAttr:]); err != nil {
+		t.Fatal(err)
+	}
+	parsedNetkit := &Netkit{}
+	parseNetkitData(parsedNetkit, data)
+	compareNetkit(t, netkit, parsedNetkit)
+}
+
+func TestBridgeAttrs(t *testing.T) {
+	forwardDelay := uint32(1500)
+	stpState := uint32(1)
+	priority := uint16(4096)
+	vlanProtocol := VLAN_PROTOCOL_8021AD
+	pvid := uint16(10)
+	querier := true
+	queryInterval := uint64(12500)
+	igmpVersion := uint8(3)
+	bridge := &Bridge{
+		ForwardDelay:           &forwardDelay,
+		StpState:               &stpState,
+		Priority:               &priority,
+		GroupAddr:              net.HardwareAddr{0x01, 0x80, 0xc2, 0x00, 0x00, 0x00},
+		VlanProtocol:           &vlanProtocol,
+		VlanDefaultPVID:        &pvid,
+		MulticastQuerier:       &querier,
+		MulticastQueryInterval: &queryInterval,
+		MulticastIgmpVersion:   &igmpVersion,
+	}
+	linkInfo := nl.NewRtAttr(unix.IFLA_LINKINFO, nil)
+	addBridgeAttrs(bridge, linkInfo)
+
+	info, err := nl.ParseRouteAttr(linkInfo.Serialize()[unix.SizeofRtAttr:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := nl.ParseRouteAttr(info[0].Value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 9 {
+		t.Fatalf("expected 9 bridge attributes, got %d", len(data))
+	}
+	result := &Bridge{}
+	parseBridgeData(result, data)
+
+	if result.ForwardDelay == nil || *result.ForwardDelay != forwardDelay {
+		t.Fatal("Bridge.ForwardDelay doesn't match")
+	}
+	if result.StpState == nil || *result.StpState != stpState {
+		t.Fatal("Bridge.StpState doesn't match")
+	}
+	if result.Priority == nil || *result.Priority != priority {
+		t.Fatal("Bridge.Priority doesn't match")
+	}
+	if result.GroupAddr.String() != bridge.GroupAddr.String() {
+		t.Fatalf("Bridge.GroupAddr is %s, should be %s", result.GroupAddr, bridge.GroupAddr)
+	}
+	if result.VlanProtocol == nil || *result.VlanProtocol != vlanProtocol {
+		t.Fatal("Bridge.VlanProtocol doesn't match")
+	}
+	if result.VlanDefaultPVID == nil || *result.VlanDefaultPVID != pvid {
+		t.Fatal("Bridge.VlanDefaultPVID doesn't match")
+	}
+	if result.MulticastQuerier == nil || !*result.MulticastQuerier {
+		t.Fatal("Bridge.MulticastQuerier doesn't match")
+	}
+	if result.MulticastQueryInterval == nil || *result.MulticastQueryInterval != queryInterval {
+		t.Fatal("Bridge.MulticastQueryInterval doesn't match")
+	}
+	if result.MulticastIgmpVersion == nil || *result.MulticastIgmpVersion != igmpVersion {
+		t.Fatal("Bridge.MulticastIgmpVersion doesn't match")
+	}
+	if result.AgeingTime != nil || result.MulticastSnooping != nil {
+		t.Fatal("unset Bridge fields should stay nil")
+	}
+}
+
 func TestLinkAddDelBridgeMaster(t *testing.T) {
 	tearDown := setUpNetlinkTest(t)
 	defer tearDown()
@@ -1386,6 +2079,57 @@ func TestLinkAddDelVxlanFlowBased(t *testing.T) {
 	testLinkAddDel(t, &vxlan)
 }
 
+func TestLinkAddDelVxlanGpe(t *testing.T) {
+	minKernelRequired(t, 4, 12)
+
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+
+	vxlan := Vxlan{
+		LinkAttrs: LinkAttrs{
+			Name: "foo",
+		},
+		Learning:  false,
+		GPE:       true,
+		FlowBased: true,
+	}
+
+	testLinkAddDel(t, &vxlan)
+}
+
+func TestParseVxlanDataFlags(t *testing.T) {
+	var b []byte
+	b = append(b, nl.NewRtAttr(nl.IFLA_VXLAN_GBP, []byte{}).Serialize()...)
+	b = append(b, nl.NewRtAttr(nl.IFLA_VXLAN_GPE, []byte{}).Serialize()...)
+	data, err := nl.ParseRouteAttr(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vxlan := &Vxlan{}
+	parseVxlanData(vxlan, data)
+	if !vxlan.GBP || !vxlan.GPE {
+		t.Fatalf("Flag attributes not parsed: GBP %v GPE %v", vxlan.GBP, vxlan.GPE)
+	}
+}
+
+func TestLinkAddDelVxlanVniFilter(t *testing.T) {
+	minKernelRequired(t, 5, 18)
+
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+
+	vxlan := Vxlan{
+		LinkAttrs: LinkAttrs{
+			Name: "foo",
+		},
+		Learning:  false,
+		FlowBased: true,
+		VniFilter: true,
+	}
+
+	testLinkAddDel(t, &vxlan)
+}
+
 func TestLinkAddDelIPVlanL2(t *testing.T) {
 	minKernelRequired(t, 4, 2)
 	tearDown := setUpNetlinkTest(t)
@@ -1406,6 +2150,26 @@ func TestLinkAddDelIPVlanL2(t *testing.T) {
 	testLinkAddDel(t, &ipv)
 }
 
+func TestLinkAddDelIPVtap(t *testing.T) {
+	minKernelRequired(t, 4, 15)
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+	parent := &Dummy{LinkAttrs{Name: "foo"}}
+	if err := LinkAdd(parent); err != nil {
+		t.Fatal(err)
+	}
+
+	testLinkAddDel(t, &IPVtap{
+		IPVlan: IPVlan{
+			LinkAttrs: LinkAttrs{
+				Name:        "bar",
+				ParentIndex: parent.Index,
+			},
+			Mode: IPVLAN_MODE_L3,
+		},
+	})
+}
+
 func TestLinkAddDelIPVlanL3(t *testing.T) {
 	minKernelRequired(t, 4, 2)
 	tearDown := setUpNetlinkTest(t)
@@ -2318,6 +3082,33 @@ func TestLinkAddDelXfrmiNoId(t *testing.T) {
 		LinkAttrs: LinkAttrs{Name: "xfrm0", ParentIndex: lo.Attrs().Index}})
 }
 
+func TestLinkAddDelMacsec(t *testing.T) {
+	tearDown := setUpNetlinkTestWithKModule(t, "macsec")
+	defer tearDown()
+
+	parent := &Dummy{LinkAttrs{Name: "foo"}}
+	if err := LinkAdd(parent); err != nil {
+		t.Fatal(err)
+	}
+
+	encodingSA := uint8(2)
+	encrypt := true
+	replayProtect := true
+	window := uint32(32)
+	validation := MACSEC_VALIDATE_CHECK
+	testLinkAddDel(t, &Macsec{
+		LinkAttrs:     LinkAttrs{Name: "macsec0", ParentIndex: parent.Attrs().Index},
+		Port:          11,
+		CipherSuite:   MACSEC_CIPHER_ID_GCM_AES_256,
+		IcvLen:        16,
+		EncodingSA:    &encodingSA,
+		Encrypt:       &encrypt,
+		ReplayProtect: &replayProtect,
+		Window:        &window,
+		Validation:    &validation,
+	})
+}
+
 func TestLinkByNameWhenLinkIsNotFound(t *testing.T) {
 	_, err := LinkByName("iammissing")
 	if err == nil {
@@ -2342,6 +3133,69 @@ func TestLinkByAliasWhenLinkIsNotFound(t *testing.T) {
 	}
 }
 
+func TestLinkAltName(t *testing.T) {
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+	minKernelRequired(t, 5, 5)
+
+	if err := LinkAdd(&Dummy{LinkAttrs{Name: "foo"}}); err != nil {
+		t.Fatal(err)
+	}
+	link, err := LinkByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	short, long := "bar", "enp0s31f6-management-uplink"
+	for _, name := range []string{short, long} {
+		if err := LinkAddAltName(link, name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, name := range []string{short, long} {
+		result, err := LinkByName(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Attrs().Index != link.Attrs().Index {
+			t.Fatalf("link found by alternative name %s has index %d, expected %d",
+				name, result.Attrs().Index, link.Attrs().Index)
+		}
+		altNames := result.Attrs().AltNames
+		if len(altNames) != 2 || altNames[0] != short || altNames[1] != long {
+			t.Fatalf("unexpected alternative names: %v", altNames)
+		}
+	}
+
+	if err := LinkDelAltName(link, long); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LinkByName(long); err == nil {
+		t.Fatal("link still found by deleted alternative name")
+	}
+}
+
+func TestLinkDeserializeAltNames(t *testing.T) {
+	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
+	msg.Index = 3
+	b := msg.Serialize()
+	b = append(b, nl.NewRtAttr(unix.IFLA_IFNAME, nl.ZeroTerminated("foo")).Serialize()...)
+	props := nl.NewRtAttr(unix.IFLA_PROP_LIST|unix.NLA_F_NESTED, nil)
+	props.AddRtAttr(unix.IFLA_ALT_IFNAME, nl.ZeroTerminated("bar"))
+	props.AddRtAttr(unix.IFLA_ALT_IFNAME, nl.ZeroTerminated("enp0s31f6-management-uplink"))
+	b = append(b, props.Serialize()...)
+
+	link, err := LinkDeserialize(nil, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	altNames := link.Attrs().AltNames
+	if len(altNames) != 2 || altNames[0] != "bar" || altNames[1] != "enp0s31f6-management-uplink" {
+		t.Fatalf("unexpected alternative names: %v", altNames)
+	}
+}
+
 func TestLinkAddDelTuntap(t *testing.T) {
 	tearDown := setUpNetlinkTest(t)
 	defer tearDown()
@@ -0,0 +1,107 @@
+package netlink
+
+import (
+	"fmt"
+)
+
+// MacsecSA is a transmit or receive secure association of a MACsec SecY,
+// identified by its association number.
+type MacsecSA struct {
+	AN     uint8
+	Active bool
+	PN     uint64 // next packet number, 0 to leave it unchanged
+	Key    []byte // write only
+	KeyID  []byte // MACSEC_KEYID_LEN bytes
+	SSCI   uint32 // XPN cipher suites only
+	Salt   []byte // XPN cipher suites only, write only
+	Stats  MacsecSAStats
+}
+
+func (sa *MacsecSA) String() string {
+	return fmt.Sprintf("{AN: %d, Active: %t, PN: %d, KeyID: %x}", sa.AN, sa.Active, sa.PN, sa.KeyID)
+}
+
+// MacsecSAStats holds the packet counters of a secure association, the In
+// counters for a receive SA and the Out counters for a transmit SA.
+type MacsecSAStats struct {
+	InPktsOK         uint64
+	InPktsInvalid    uint64
+	InPktsNotValid   uint64
+	InPktsNotUsingSA uint64
+	InPktsUnusedSA   uint64
+	OutPktsProtected uint64
+	OutPktsEncrypted uint64
+}
+
+// MacsecRxSC is a receive secure channel of a MACsec SecY, identified by
+// the SCI of the peer.
+type MacsecRxSC struct {
+	SCI    uint64
+	Active bool
+	SAs    []MacsecSA // read only, managed with MacsecRxSAAdd and friends
+	Stats  MacsecRxSCStats
+}
+
+func (rxsc *MacsecRxSC) String() string {
+	return fmt.Sprintf("{SCI: %016x, Active: %t, SAs: %v}", rxsc.SCI, rxsc.Active, rxsc.SAs)
+}
+
+type MacsecRxSCStats struct {
+	InOctetsValidated uint64
+	InOctetsDecrypted uint64
+	InPktsUnchecked   uint64
+	InPktsDelayed     uint64
+	InPktsOK          uint64
+	InPktsInvalid     uint64
+	InPktsLate        uint64
+	InPktsNotValid    uint64
+	InPktsNotUsingSA  uint64
+	InPktsUnusedSA    uint64
+}
+
+type MacsecTxSCStats struct {
+	OutPktsProtected   uint64
+	OutPktsEncrypted   uint64
+	OutOctetsProtected uint64
+	OutOctetsEncrypted uint64
+}
+
+type MacsecSecYStats struct {
+	OutPktsUntagged  uint64
+	InPktsUntagged   uint64
+	OutPktsTooLong   uint64
+	InPktsNoTag      uint64
+	InPktsBadTag     uint64
+	InPktsUnknownSCI uint64
+	InPktsNoSCI      uint64
+	InPktsOverrun    uint64
+}
+
+// MacsecSecY is the state of the secure entity of a MACsec link with its
+// transmit SAs and receive channels.
+type MacsecSecY struct {
+	LinkIndex     int
+	SCI           uint64
+	EncodingSA    uint8
+	Window        uint32
+	CipherSuite   uint64
+	IcvLen        uint8
+	Protect       bool
+	ReplayProtect bool
+	Operational   bool
+	Validation    MacsecValidation
+	Encrypt       bool
+	IncludeSCI    bool
+	EndStation    bool
+	SCB           bool
+	Offload       MacsecOffload
+	TxSAs         []MacsecSA
+	RxSCs         []MacsecRxSC
+	TxSCStats     MacsecTxSCStats
+	SecYStats     MacsecSecYStats
+}
+
+func (secy *MacsecSecY) String() string {
+	return fmt.Sprintf("{LinkIndex: %d, SCI: %016x, CipherSuite: %016x, EncodingSA: %d, TxSAs: %v, RxSCs: %v}",
+		secy.LinkIndex, secy.SCI, secy.CipherSuite, secy.EncodingSA, secy.TxSAs, secy.RxSCs)
+}
@@ -0,0 +1,454 @@
+package netlink
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/ndupreez/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+// MacsecSecYGet returns the SecY of a MACsec link with its transmit SAs,
+// receive channels and counters.
+// Equivalent to: `ip macsec show $link`
+func MacsecSecYGet(link Link) (*MacsecSecY, error) {
+	return pkgHandle.MacsecSecYGet(link)
+}
+
+// MacsecSecYGet returns the SecY of a MACsec link with its transmit SAs,
+// receive channels and counters.
+// Equivalent to: `ip macsec show $link`
+func (h *Handle) MacsecSecYGet(link Link) (*MacsecSecY, error) {
+	base := link.Attrs()
+	h.ensureIndex(base)
+	// The kernel dumps the SecYs of every MACsec link of the namespace
+	req, err := h.newMacsecRequest(nl.MACSEC_CMD_GET_TXSC, unix.NLM_F_DUMP)
+	if err != nil {
+		return nil, err
+	}
+	req.AddData(nl.NewRtAttr(nl.MACSEC_ATTR_IFINDEX, nl.Uint32Attr(uint32(base.Index))))
+	msgs, err := req.Execute(unix.NETLINK_GENERIC, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range msgs {
+		secy, err := parseMacsecSecY(m[nl.SizeofGenlmsg:])
+		if err != nil {
+			return nil, err
+		}
+		if secy.LinkIndex == base.Index {
+			return secy, nil
+		}
+	}
+	return nil, fmt.Errorf("no MACsec SecY found on link %d", base.Index)
+}
+
+// MacsecRxSCAdd adds a receive secure channel to a MACsec link.
+// Equivalent to: `ip macsec add $link rx sci $sci [on|off]`
+func MacsecRxSCAdd(link Link, rxsc *MacsecRxSC) error {
+	return pkgHandle.MacsecRxSCAdd(link, rxsc)
+}
+
+// MacsecRxSCAdd adds a receive secure channel to a MACsec link.
+// Equivalent to: `ip macsec add $link rx sci $sci [on|off]`
+func (h *Handle) MacsecRxSCAdd(link Link, rxsc *MacsecRxSC) error {
+	return h.macsecRxSCChange(link, nl.MACSEC_CMD_ADD_RXSC, rxsc)
+}
+
+// MacsecRxSCUpdate activates or deactivates a receive secure channel of a
+// MACsec link.
+// Equivalent to: `ip macsec set $link rx sci $sci [on|off]`
+func MacsecRxSCUpdate(link Link, rxsc *MacsecRxSC) error {
+	return pkgHandle.MacsecRxSCUpdate(link, rxsc)
+}
+
+// MacsecRxSCUpdate activates or deactivates a receive secure channel of a
+// MACsec link.
+// Equivalent to: `ip macsec set $link rx sci $sci [on|off]`
+func (h *Handle) MacsecRxSCUpdate(link Link, rxsc *MacsecRxSC) error {
+	return h.macsecRxSCChange(link, nl.MACSEC_CMD_UPD_RXSC, rxsc)
+}
+
+// MacsecRxSCDel removes a receive secure channel and its SAs from a MACsec
+// link.
+// Equivalent to: `ip macsec del $link rx sci $sci`
+func MacsecRxSCDel(link Link, rxsc *MacsecRxSC) error {
+	return pkgHandle.MacsecRxSCDel(link, rxsc)
+}
+
+// MacsecRxSCDel removes a receive secure channel and its SAs from a MACsec
+// link.
+// Equivalent to: `ip macsec del $link rx sci $sci`
+func (h *Handle) MacsecRxSCDel(link Link, rxsc *MacsecRxSC) error {
+	return h.macsecRxSCChange(link, nl.MACSEC_CMD_DEL_RXSC, rxsc)
+}
+
+func (h *Handle) macsecRxSCChange(link Link, cmd uint8, rxsc *MacsecRxSC) error {
+	base := link.Attrs()
+	h.ensureIndex(base)
+	req, err := h.newMacsecRequest(cmd, unix.NLM_F_ACK)
+	if err != nil {
+		return err
+	}
+	req.AddData(nl.NewRtAttr(nl.MACSEC_ATTR_IFINDEX, nl.Uint32Attr(uint32(base.Index))))
+	rxscAttr := macsecRxSCAttr(rxsc.SCI)
+	if cmd != nl.MACSEC_CMD_DEL_RXSC {
+		rxscAttr.AddRtAttr(nl.MACSEC_RXSC_ATTR_ACTIVE, boolToByte(rxsc.Active))
+	}
+	req.AddData(rxscAttr)
+	_, err = req.Execute(unix.NETLINK_GENERIC, 0)
+	return err
+}
+
+// MacsecTxSAAdd adds a transmit secure association to a MACsec link.
+// Equivalent to: `ip macsec add $link tx sa $an pn $pn [on|off] key $keyid $key`
+func MacsecTxSAAdd(link Link, sa *MacsecSA) error {
+	return pkgHandle.MacsecTxSAAdd(link, sa)
+}
+
+// MacsecTxSAAdd adds a transmit secure association to a MACsec link.
+// Equivalent to: `ip macsec add $link tx sa $an pn $pn [on|off] key $keyid $key`
+func (h *Handle) MacsecTxSAAdd(link Link, sa *MacsecSA) error {
+	return h.macsecSAChange(link, nl.MACSEC_CMD_ADD_TXSA, nil, sa)
+}
+
+// MacsecTxSAUpdate activates or deactivates a transmit secure association of
+// a MACsec link and sets its next packet number if PN is not 0.
+// Equivalent to: `ip macsec set $link tx sa $an [pn $pn] [on|off]`
+func MacsecTxSAUpdate(link Link, sa *MacsecSA) error {
+	return pkgHandle.MacsecTxSAUpdate(link, sa)
+}
+
+// MacsecTxSAUpdate activates or deactivates a transmit secure association of
+// a MACsec link and sets its next packet number if PN is not 0.
+// Equivalent to: `ip macsec set $link tx sa $an [pn $pn] [on|off]`
+func (h *Handle) MacsecTxSAUpdate(link Link, sa *MacsecSA) error {
+	return h.macsecSAChange(link, nl.MACSEC_CMD_UPD_TXSA, nil, sa)
+}
+
+// MacsecTxSADel removes a transmit secure association from a MACsec link.
+// Equivalent to: `ip macsec del $link tx sa $an`
+func MacsecTxSADel(link Link, sa *MacsecSA) error {
+	return pkgHandle.MacsecTxSADel(link, sa)
+}
+
+// MacsecTxSADel removes a transmit secure association from a MACsec link.
+// Equivalent to: `ip macsec del $link tx sa $an`
+func (h *Handle) MacsecTxSADel(link Link, sa *MacsecSA) error {
+	return h.macsecSAChange(link, nl.MACSEC_CMD_DEL_TXSA, nil, sa)
+}
+
+// MacsecRxSAAdd adds a receive secure association to the receive channel sci
+// of a MACsec link.
+// Equivalent to: `ip macsec add $link rx sci $sci sa $an [pn $pn] [on|off] key $keyid $key`
+func MacsecRxSAAdd(link Link, sci uint64, sa *MacsecSA) error {
+	return pkgHandle.MacsecRxSAAdd(link, sci, sa)
+}
+
+// MacsecRxSAAdd adds a receive secure association to the receive channel sci
+// of a MACsec link.
+// Equivalent to: `ip macsec add $link rx sci $sci sa $an [pn $pn] [on|off] key $keyid $key`
+func (h *Handle) MacsecRxSAAdd(link Link, sci uint64, sa *MacsecSA) error {
+	return h.macsecSAChange(link, nl.MACSEC_CMD_ADD_RXSA, &sci, sa)
+}
+
+// MacsecRxSAUpdate activates or deactivates a receive secure association of
+// the receive channel sci and sets its lowest acceptable packet number if PN
+// is not 0.
+// Equivalent to: `ip macsec set $link rx sci $sci sa $an [pn $pn] [on|off]`
+func MacsecRxSAUpdate(link Link, sci uint64, sa *MacsecSA) error {
+	return pkgHandle.MacsecRxSAUpdate(link, sci, sa)
+}
+
+// MacsecRxSAUpdate activates or deactivates a receive secure association of
+// the receive channel sci and sets its lowest acceptable packet number if PN
+// is not 0.
+// Equivalent to: `ip macsec set $link rx sci $sci sa $an [pn $pn] [on|off]`
+func (h *Handle) MacsecRxSAUpdate(link Link, sci uint64, sa *MacsecSA) error {
+	return h.macsecSAChange(link, nl.MACSEC_CMD_UPD_RXSA, &sci, sa)
+}
+
+// MacsecRxSADel removes a receive secure association from the receive
+// channel sci of a MACsec link.
+// Equivalent to: `ip macsec del $link rx sci $sci sa $an`
+func MacsecRxSADel(link Link, sci uint64, sa *MacsecSA) error {
+	return pkgHandle.MacsecRxSADel(link, sci, sa)
+}
+
+// MacsecRxSADel removes a receive secure association from the receive
+// channel sci of a MACsec link.
+// Equivalent to: `ip macsec del $link rx sci $sci sa $an`
+func (h *Handle) MacsecRxSADel(link Link, sci uint64, sa *MacsecSA) error {
+	return h.macsecSAChange(link, nl.MACSEC_CMD_DEL_RXSA, &sci, sa)
+}
+
+func (h *Handle) macsecSAChange(link Link, cmd uint8, sci *uint64, sa *MacsecSA) error {
+	base := link.Attrs()
+	h.ensureIndex(base)
+	if sa.AN > 3 {
+		return fmt.Errorf("invalid MACsec association number: %d", sa.AN)
+	}
+	req, err := h.newMacsecRequest(cmd, unix.NLM_F_ACK)
+	if err != nil {
+		return err
+	}
+	req.AddData(nl.NewRtAttr(nl.MACSEC_ATTR_IFINDEX, nl.Uint32Attr(uint32(base.Index))))
+	if sci != nil {
+		req.AddData(macsecRxSCAttr(*sci))
+	}
+	saAttr := nl.NewRtAttr(nl.MACSEC_ATTR_SA_CONFIG|unix.NLA_F_NESTED, nil)
+	saAttr.AddRtAttr(nl.MACSEC_SA_ATTR_AN, nl.Uint8Attr(sa.AN))
+	if cmd != nl.MACSEC_CMD_DEL_TXSA && cmd != nl.MACSEC_CMD_DEL_RXSA {
+		saAttr.AddRtAttr(nl.MACSEC_SA_ATTR_ACTIVE, boolToByte(sa.Active))
+		if sa.PN != 0 {
+			// The packet numbers are 64 bits wide with the extended
+			// packet numbering cipher suites only
+			xpn, err := h.macsecXPN(link)
+			if err != nil {
+				return err
+			}
+			if xpn {
+				saAttr.AddRtAttr(nl.MACSEC_SA_ATTR_PN, nl.Uint64Attr(sa.PN))
+			} else if sa.PN > 0xffffffff {
+				return fmt.Errorf("MACsec packet number %d needs an XPN cipher suite", sa.PN)
+			} else {
+				saAttr.AddRtAttr(nl.MACSEC_SA_ATTR_PN, nl.Uint32Attr(uint32(sa.PN)))
+			}
+		}
+	}
+	if cmd == nl.MACSEC_CMD_ADD_TXSA || cmd == nl.MACSEC_CMD_ADD_RXSA {
+		if len(sa.KeyID) != nl.MACSEC_KEYID_LEN {
+			return fmt.Errorf("invalid MACsec key identifier length: %d", len(sa.KeyID))
+		}
+		saAttr.AddRtAttr(nl.MACSEC_SA_ATTR_KEY, sa.Key)
+		saAttr.AddRtAttr(nl.MACSEC_SA_ATTR_KEYID, sa.KeyID)
+		if len(sa.Salt) != 0 {
+			saAttr.AddRtAttr(nl.MACSEC_SA_ATTR_SSCI, htonl(sa.SSCI))
+			saAttr.AddRtAttr(nl.MACSEC_SA_ATTR_SALT, sa.Salt)
+		}
+	}
+	req.AddData(saAttr)
+	_, err = req.Execute(unix.NETLINK_GENERIC, 0)
+	return err
+}
+
+// macsecXPN tells whether link uses an extended packet numbering cipher suite
+func (h *Handle) macsecXPN(link Link) (bool, error) {
+	macsec, ok := link.(*Macsec)
+	if !ok || macsec.CipherSuite == 0 {
+		l, err := h.LinkByIndex(link.Attrs().Index)
+		if err != nil {
+			return false, err
+		}
+		if macsec, ok = l.(*Macsec); !ok {
+			return false, fmt.Errorf("%s is not a MACsec link", link.Attrs().Name)
+		}
+	}
+	return macsec.CipherSuite == MACSEC_CIPHER_ID_GCM_AES_XPN_128 ||
+		macsec.CipherSuite == MACSEC_CIPHER_ID_GCM_AES_XPN_256, nil
+}
+
+func (h *Handle) newMacsecRequest(cmd uint8, flags int) (*nl.NetlinkRequest, error) {
+	f, err := h.GenlFamilyGet(nl.MACSEC_GENL_NAME)
+	if err != nil {
+		return nil, err
+	}
+	msg := &nl.Genlmsg{
+		Command: cmd,
+		Version: nl.MACSEC_GENL_VERSION,
+	}
+	req := h.newNetlinkRequest(int(f.ID), flags)
+	req.AddData(msg)
+	return req, nil
+}
+
+func macsecRxSCAttr(sci uint64) *nl.RtAttr {
+	rxsc := nl.NewRtAttr(nl.MACSEC_ATTR_RXSC_CONFIG|unix.NLA_F_NESTED, nil)
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, sci)
+	rxsc.AddRtAttr(nl.MACSEC_RXSC_ATTR_SCI, b)
+	return rxsc
+}
+
+func parseMacsecSecY(b []byte) (*MacsecSecY, error) {
+	attrs, err := nl.ParseRouteAttr(b)
+	if err != nil {
+		return nil, err
+	}
+	secy := &MacsecSecY{}
+	for _, attr := range attrs {
+		switch attr.Attr.Type &^ unix.NLA_F_NESTED {
+		case nl.MACSEC_ATTR_IFINDEX:
+			secy.LinkIndex = int(native.Uint32(attr.Value[0:4]))
+		case nl.MACSEC_ATTR_SECY:
+			data, err := nl.ParseRouteAttr(attr.Value)
+			if err != nil {
+				return nil, err
+			}
+			for _, datum := range data {
+				switch datum.Attr.Type {
+				case nl.MACSEC_SECY_ATTR_SCI:
+					secy.SCI = binary.BigEndian.Uint64(datum.Value[0:8])
+				case nl.MACSEC_SECY_ATTR_ENCODING_SA:
+					secy.EncodingSA = datum.Value[0]
+				case nl.MACSEC_SECY_ATTR_WINDOW:
+					secy.Window = native.Uint32(datum.Value[0:4])
+				case nl.MACSEC_SECY_ATTR_CIPHER_SUITE:
+					secy.CipherSuite = native.Uint64(datum.Value[0:8])
+				case nl.MACSEC_SECY_ATTR_ICV_LEN:
+					secy.IcvLen = datum.Value[0]
+				case nl.MACSEC_SECY_ATTR_PROTECT:
+					secy.Protect = datum.Value[0] == 1
+				case nl.MACSEC_SECY_ATTR_REPLAY:
+					secy.ReplayProtect = datum.Value[0] == 1
+				case nl.MACSEC_SECY_ATTR_OPER:
+					secy.Operational = datum.Value[0] == 1
+				case nl.MACSEC_SECY_ATTR_VALIDATE:
+					secy.Validation = MacsecValidation(datum.Value[0])
+				case nl.MACSEC_SECY_ATTR_ENCRYPT:
+					secy.Encrypt = datum.Value[0] == 1
+				case nl.MACSEC_SECY_ATTR_INC_SCI:
+					secy.IncludeSCI = datum.Value[0] == 1
+				case nl.MACSEC_SECY_ATTR_ES:
+					secy.EndStation = datum.Value[0] == 1
+				case nl.MACSEC_SECY_ATTR_SCB:
+					secy.SCB = datum.Value[0] == 1
+				}
+			}
+		case nl.MACSEC_ATTR_OFFLOAD:
+			data, err := nl.ParseRouteAttr(attr.Value)
+			if err != nil {
+				return nil, err
+			}
+			for _, datum := range data {
+				if datum.Attr.Type == nl.MACSEC_OFFLOAD_ATTR_TYPE {
+					secy.Offload = MacsecOffload(datum.Value[0])
+				}
+			}
+		case nl.MACSEC_ATTR_TXSC_STATS:
+			s := &secy.TxSCStats
+			if err := parseMacsecCounters(attr.Value, &s.OutPktsProtected, &s.OutPktsEncrypted,
+				&s.OutOctetsProtected, &s.OutOctetsEncrypted); err != nil {
+				return nil, err
+			}
+		case nl.MACSEC_ATTR_SECY_STATS:
+			s := &secy.SecYStats
+			if err := parseMacsecCounters(attr.Value, &s.OutPktsUntagged, &s.InPktsUntagged,
+				&s.OutPktsTooLong, &s.InPktsNoTag, &s.InPktsBadTag, &s.InPktsUnknownSCI,
+				&s.InPktsNoSCI, &s.InPktsOverrun); err != nil {
+				return nil, err
+			}
+		case nl.MACSEC_ATTR_TXSA_LIST:
+			sas, err := parseMacsecSAList(attr.Value)
+			if err != nil {
+				return nil, err
+			}
+			secy.TxSAs = sas
+		case nl.MACSEC_ATTR_RXSC_LIST:
+			list, err := nl.ParseRouteAttr(attr.Value)
+			if err != nil {
+				return nil, err
+			}
+			for _, entry := range list {
+				rxsc, err := parseMacsecRxSC(entry.Value)
+				if err != nil {
+					return nil, err
+				}
+				secy.RxSCs = append(secy.RxSCs, *rxsc)
+			}
+		}
+	}
+	return secy, nil
+}
+
+func parseMacsecRxSC(b []byte) (*MacsecRxSC, error) {
+	attrs, err := nl.ParseRouteAttr(b)
+	if err != nil {
+		return nil, err
+	}
+	rxsc := &MacsecRxSC{}
+	for _, attr := range attrs {
+		switch attr.Attr.Type &^ unix.NLA_F_NESTED {
+		case nl.MACSEC_RXSC_ATTR_SCI:
+			rxsc.SCI = binary.BigEndian.Uint64(attr.Value[0:8])
+		case nl.MACSEC_RXSC_ATTR_ACTIVE:
+			rxsc.Active = attr.Value[0] == 1
+		case nl.MACSEC_RXSC_ATTR_SA_LIST:
+			if rxsc.SAs, err = parseMacsecSAList(attr.Value); err != nil {
+				return nil, err
+			}
+		case nl.MACSEC_RXSC_ATTR_STATS:
+			s := &rxsc.Stats
+			if err := parseMacsecCounters(attr.Value, &s.InOctetsValidated, &s.InOctetsDecrypted,
+				&s.InPktsUnchecked, &s.InPktsDelayed, &s.InPktsOK, &s.InPktsInvalid,
+				&s.InPktsLate, &s.InPktsNotValid, &s.InPktsNotUsingSA, &s.InPktsUnusedSA); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return rxsc, nil
+}
+
+func parseMacsecSAList(b []byte) ([]MacsecSA, error) {
+	list, err := nl.ParseRouteAttr(b)
+	if err != nil {
+		return nil, err
+	}
+	var sas []MacsecSA
+	for _, entry := range list {
+		attrs, err := nl.ParseRouteAttr(entry.Value)
+		if err != nil {
+			return nil, err
+		}
+		sa := MacsecSA{}
+		for _, attr := range attrs {
+			switch attr.Attr.Type &^ unix.NLA_F_NESTED {
+			case nl.MACSEC_SA_ATTR_AN:
+				sa.AN = attr.Value[0]
+			case nl.MACSEC_SA_ATTR_ACTIVE:
+				sa.Active = attr.Value[0] == 1
+			case nl.MACSEC_SA_ATTR_PN:
+				if len(attr.Value) >= 8 {
+					sa.PN = native.Uint64(attr.Value[0:8])
+				} else {
+					sa.PN = uint64(native.Uint32(attr.Value[0:4]))
+				}
+			case nl.MACSEC_SA_ATTR_KEYID:
+				sa.KeyID = append([]byte(nil), attr.Value...)
+			case nl.MACSEC_SA_ATTR_SSCI:
+				sa.SSCI = binary.BigEndian.Uint32(attr.Value[0:4])
+			case nl.MACSEC_SA_ATTR_STATS:
+				s := &sa.Stats
+				if err := parseMacsecCounters(attr.Value, &s.InPktsOK, &s.InPktsInvalid,
+					&s.InPktsNotValid, &s.InPktsNotUsingSA, &s.InPktsUnusedSA,
+					&s.OutPktsProtected, &s.OutPktsEncrypted); err != nil {
+					return nil, err
+				}
+			}
+		}
+		sas = append(sas, sa)
+	}
+	return sas, nil
+}
+
+// parseMacsecCounters stores the 32 or 64 bits counter attributes of type
+// 1, 2... of a MACsec stats attribute in the matching counters.
+func parseMacsecCounters(b []byte, counters ...*uint64) error {
+	attrs, err := nl.ParseRouteAttr(b)
+	if err != nil {
+		return err
+	}
+	for _, attr := range attrs {
+		i := int(attr.Attr.Type) - 1
+		if i < 0 || i >= len(counters) {
+			continue
+		}
+		switch len(attr.Value) {
+		case 4:
+			*counters[i] = uint64(native.Uint32(attr.Value))
+		case 8:
+			*counters[i] = native.Uint64(attr.Value)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+// +build linux
+
+package netlink
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ndupreez/netlink/nl"
+)
+
+func TestMacsecSAAddUpdateDel(t *testing.T) {
+	tearDown := setUpNetlinkTestWithKModule(t, "macsec")
+	defer tearDown()
+
+	parent := &Dummy{LinkAttrs{Name: "foo"}}
+	if err := LinkAdd(parent); err != nil {
+		t.Fatal(err)
+	}
+	macsec := &Macsec{
+		LinkAttrs:   LinkAttrs{Name: "macsec0", ParentIndex: parent.Attrs().Index},
+		CipherSuite: MACSEC_CIPHER_ID_GCM_AES_128,
+	}
+	if err := LinkAdd(macsec); err != nil {
+		t.Fatal(err)
+	}
+
+	key := bytes.Repeat([]byte{0xaa}, 16)
+	keyID := bytes.Repeat([]byte{0x01}, 16)
+	sci := uint64(0x0200000000010001)
+
+	if err := MacsecTxSAAdd(macsec, &MacsecSA{AN: 0, Active: true, PN: 1, Key: key, KeyID: keyID}); err != nil {
+		t.Fatal(err)
+	}
+	if err := MacsecRxSCAdd(macsec, &MacsecRxSC{SCI: sci, Active: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := MacsecRxSAAdd(macsec, sci, &MacsecSA{AN: 1, Active: true, PN: 100, Key: key, KeyID: keyID}); err != nil {
+		t.Fatal(err)
+	}
+
+	secy, err := MacsecSecYGet(macsec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secy.LinkIndex != macsec.Attrs().Index || secy.CipherSuite != MACSEC_CIPHER_ID_GCM_AES_128 {
+		t.Fatalf("unexpected SecY %v", secy)
+	}
+	if len(secy.TxSAs) != 1 || secy.TxSAs[0].AN != 0 || !secy.TxSAs[0].Active || secy.TxSAs[0].PN != 1 {
+		t.Fatalf("unexpected transmit SAs %v", secy.TxSAs)
+	}
+	if !bytes.Equal(secy.TxSAs[0].KeyID, keyID) {
+		t.Fatalf("expected key id %x, got %x", keyID, secy.TxSAs[0].KeyID)
+	}
+	if len(secy.RxSCs) != 1 || secy.RxSCs[0].SCI != sci || !secy.RxSCs[0].Active {
+		t.Fatalf("unexpected receive channels %v", secy.RxSCs)
+	}
+	if sas := secy.RxSCs[0].SAs; len(sas) != 1 || sas[0].AN != 1 || sas[0].PN != 100 {
+		t.Fatalf("unexpected receive SAs %v", sas)
+	}
+
+	if err := MacsecTxSAUpdate(macsec, &MacsecSA{AN: 0, Active: false, PN: 1000}); err != nil {
+		t.Fatal(err)
+	}
+	if err := MacsecRxSCUpdate(macsec, &MacsecRxSC{SCI: sci, Active: false}); err != nil {
+		t.Fatal(err)
+	}
+	secy, err = MacsecSecYGet(macsec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secy.TxSAs[0].Active || secy.TxSAs[0].PN != 1000 {
+		t.Fatalf("transmit SA not updated: %v", secy.TxSAs[0])
+	}
+	if secy.RxSCs[0].Active {
+		t.Fatal("receive channel not deactivated")
+	}
+
+	if err := MacsecRxSADel(macsec, sci, &MacsecSA{AN: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := MacsecRxSCDel(macsec, &MacsecRxSC{SCI: sci}); err != nil {
+		t.Fatal(err)
+	}
+	if err := MacsecTxSADel(macsec, &MacsecSA{AN: 0}); err != nil {
+		t.Fatal(err)
+	}
+	secy, err = MacsecSecYGet(macsec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(secy.TxSAs) != 0 || len(secy.RxSCs) != 0 {
+		t.Fatalf("SAs and channels not removed: %v", secy)
+	}
+}
+
+func TestParseMacsecCounters(t *testing.T) {
+	var stats []byte
+	stats = append(stats, nl.NewRtAttr(1, nl.Uint32Attr(1)).Serialize()...)
+	stats = append(stats, nl.NewRtAttr(2, nl.Uint64Attr(2)).Serialize()...)
+	stats = append(stats, nl.NewRtAttr(3, nl.Uint64Attr(3)).Serialize()...)
+	var a, b uint64
+	if err := parseMacsecCounters(stats, &a, &b); err != nil {
+		t.Fatal(err)
+	}
+	if a != 1 || b != 2 {
+		t.Fatalf("expected counters 1 and 2, got %d and %d", a, b)
+	}
+}
@@ -36,6 +36,14 @@ func LinkSetAlias(link Link, name string) error {
 	return ErrNotImplemented
 }
 
+func LinkAddAltName(link Link, name string) error {
+	return ErrNotImplemented
+}
+
+func LinkDelAltName(link Link, name string) error {
+	return ErrNotImplemented
+}
+
 func LinkSetHardwareAddr(link Link, hwaddr net.HardwareAddr) error {
 	return ErrNotImplemented
 }
@@ -60,6 +68,10 @@ func LinkSetVfRate(link Link, vf, minRate, maxRate int) error {
 	return ErrNotImplemented
 }
 
+func LinkSetVf(link Link, vf int, config VfConfig) error {
+	return ErrNotImplemented
+}
+
 func LinkSetNoMaster(link Link) error {
 	return ErrNotImplemented
 }
@@ -120,6 +132,42 @@ func LinkSetFlood(link Link, mode bool) error {
 	return ErrNotImplemented
 }
 
+func LinkSetMcastFlood(link Link, mode bool) error {
+	return ErrNotImplemented
+}
+
+func LinkSetBcastFlood(link Link, mode bool) error {
+	return ErrNotImplemented
+}
+
+func LinkSetMcastToUcast(link Link, mode bool) error {
+	return ErrNotImplemented
+}
+
+func LinkSetBrNeighSuppress(link Link, mode bool) error {
+	return ErrNotImplemented
+}
+
+func LinkSetIsolated(link Link, mode bool) error {
+	return ErrNotImplemented
+}
+
+func LinkSetBrPortState(link Link, state uint8) error {
+	return ErrNotImplemented
+}
+
+func LinkSetBrPortPriority(link Link, prio uint16) error {
+	return ErrNotImplemented
+}
+
+func LinkSetBrPortCost(link Link, cost uint32) error {
+	return ErrNotImplemented
+}
+
+func LinkSetBrPortMcastRouter(link Link, router uint8) error {
+	return ErrNotImplemented
+}
+
 func LinkSetTxQLen(link Link, qlen int) error {
 	return ErrNotImplemented
 }
@@ -128,6 +176,10 @@ func LinkAdd(link Link) error {
 	return ErrNotImplemented
 }
 
+func LinkModify(link Link) error {
+	return ErrNotImplemented
+}
+
 func LinkDel(link Link) error {
 	return ErrNotImplemented
 }
@@ -160,6 +212,14 @@ func LinkList() ([]Link, error) {
 	return nil, ErrNotImplemented
 }
 
+func LinkStats(link Link, filterMask uint32) (*LinkStatsInfo, error) {
+	return nil, ErrNotImplemented
+}
+
+func LinkStatsList(filterMask uint32) ([]LinkStatsInfo, error) {
+	return nil, ErrNotImplemented
+}
+
 func AddrAdd(link Link, addr *Addr) error {
 	return ErrNotImplemented
 }
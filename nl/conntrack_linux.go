@@ -31,10 +31,46 @@ var L4ProtoMap = map[uint8]string{
 // 	IPCTNL_MSG_MAX
 // };
 const (
+	IPCTNL_MSG_CT_NEW    = 0
 	IPCTNL_MSG_CT_GET    = 1
 	IPCTNL_MSG_CT_DELETE = 2
 )
 
+// enum ctnl_exp_msg_types {
+// 	IPCTNL_MSG_EXP_NEW,
+// 	IPCTNL_MSG_EXP_GET,
+// 	IPCTNL_MSG_EXP_DELETE,
+// 	IPCTNL_MSG_EXP_GET_STATS_CPU,
+//
+// 	IPCTNL_MSG_EXP_MAX
+// };
+const (
+	IPCTNL_MSG_EXP_NEW    = 0
+	IPCTNL_MSG_EXP_GET    = 1
+	IPCTNL_MSG_EXP_DELETE = 2
+)
+
+// https://github.com/torvalds/linux/blob/master/include/uapi/linux/netfilter/nfnetlink.h
+// enum nfnetlink_groups {
+// 	NFNLGRP_NONE,
+// 	NFNLGRP_CONNTRACK_NEW,
+// 	NFNLGRP_CONNTRACK_UPDATE,
+// 	NFNLGRP_CONNTRACK_DESTROY,
+// 	NFNLGRP_CONNTRACK_EXP_NEW,
+// 	NFNLGRP_CONNTRACK_EXP_UPDATE,
+// 	NFNLGRP_CONNTRACK_EXP_DESTROY,
+// 	...
+// };
+const (
+	NFNLGRP_NONE = iota
+	NFNLGRP_CONNTRACK_NEW
+	NFNLGRP_CONNTRACK_UPDATE
+	NFNLGRP_CONNTRACK_DESTROY
+	NFNLGRP_CONNTRACK_EXP_NEW
+	NFNLGRP_CONNTRACK_EXP_UPDATE
+	NFNLGRP_CONNTRACK_EXP_DESTROY
+)
+
 // #define NFNETLINK_V0	0
 const (
 	NFNETLINK_V0 = 0
@@ -81,13 +117,65 @@ const (
 	CTA_TUPLE_REPLY    = 2
 	CTA_STATUS         = 3
 	CTA_PROTOINFO      = 4
+	CTA_HELP           = 5
+	CTA_NAT_SRC        = 6
 	CTA_TIMEOUT        = 7
 	CTA_MARK           = 8
 	CTA_COUNTERS_ORIG  = 9
 	CTA_COUNTERS_REPLY = 10
 	CTA_USE            = 11
 	CTA_ID             = 12
+	CTA_NAT_DST        = 13
+	CTA_TUPLE_MASTER   = 14
+	CTA_SEQ_ADJ_ORIG   = 15
+	CTA_SEQ_ADJ_REPLY  = 16
+	CTA_SECMARK        = 17
+	CTA_ZONE           = 18
+	CTA_SECCTX         = 19
 	CTA_TIMESTAMP      = 20
+	CTA_MARK_MASK      = 21
+	CTA_LABELS         = 22
+	CTA_LABELS_MASK    = 23
+)
+
+// enum ctattr_expect {
+// 	CTA_EXPECT_UNSPEC,
+// 	CTA_EXPECT_MASTER,
+// 	CTA_EXPECT_TUPLE,
+// 	CTA_EXPECT_MASK,
+// 	CTA_EXPECT_TIMEOUT,
+// 	CTA_EXPECT_ID,
+// 	CTA_EXPECT_HELP_NAME,
+// 	CTA_EXPECT_ZONE,
+// 	CTA_EXPECT_FLAGS,
+// 	CTA_EXPECT_CLASS,
+// 	CTA_EXPECT_NAT,
+// 	CTA_EXPECT_FN,
+// 	__CTA_EXPECT_MAX
+// };
+const (
+	CTA_EXPECT_MASTER    = 1
+	CTA_EXPECT_TUPLE     = 2
+	CTA_EXPECT_MASK      = 3
+	CTA_EXPECT_TIMEOUT   = 4
+	CTA_EXPECT_ID        = 5
+	CTA_EXPECT_HELP_NAME = 6
+	CTA_EXPECT_ZONE      = 7
+	CTA_EXPECT_FLAGS     = 8
+	CTA_EXPECT_CLASS     = 9
+	CTA_EXPECT_NAT       = 10
+	CTA_EXPECT_FN        = 11
+)
+
+// enum ctattr_expect_nat {
+// 	CTA_EXPECT_NAT_UNSPEC,
+// 	CTA_EXPECT_NAT_DIR,
+// 	CTA_EXPECT_NAT_TUPLE,
+// 	__CTA_EXPECT_NAT_MAX
+// };
+const (
+	CTA_EXPECT_NAT_DIR   = 1
+	CTA_EXPECT_NAT_TUPLE = 2
 )
 
 // enum ctattr_tuple {
@@ -101,6 +189,7 @@ const (
 const (
 	CTA_TUPLE_IP    = 1
 	CTA_TUPLE_PROTO = 2
+	CTA_TUPLE_ZONE  = 3
 )
 
 // enum ctattr_ip {
@@ -134,9 +223,15 @@ const (
 // };
 // #define CTA_PROTO_MAX (__CTA_PROTO_MAX - 1)
 const (
-	CTA_PROTO_NUM      = 1
-	CTA_PROTO_SRC_PORT = 2
-	CTA_PROTO_DST_PORT = 3
+	CTA_PROTO_NUM         = 1
+	CTA_PROTO_SRC_PORT    = 2
+	CTA_PROTO_DST_PORT    = 3
+	CTA_PROTO_ICMP_ID     = 4
+	CTA_PROTO_ICMP_TYPE   = 5
+	CTA_PROTO_ICMP_CODE   = 6
+	CTA_PROTO_ICMPV6_ID   = 7
+	CTA_PROTO_ICMPV6_TYPE = 8
+	CTA_PROTO_ICMPV6_CODE = 9
 )
 
 // enum ctattr_protoinfo {
@@ -148,7 +243,9 @@ const (
 // };
 // #define CTA_PROTOINFO_MAX (__CTA_PROTOINFO_MAX - 1)
 const (
-	CTA_PROTOINFO_TCP = 1
+	CTA_PROTOINFO_TCP  = 1
+	CTA_PROTOINFO_DCCP = 2
+	CTA_PROTOINFO_SCTP = 3
 )
 
 // enum ctattr_protoinfo_tcp {
@@ -169,6 +266,31 @@ const (
 	CTA_PROTOINFO_TCP_FLAGS_REPLY     = 5
 )
 
+// enum ctattr_protoinfo_dccp {
+// 	CTA_PROTOINFO_DCCP_UNSPEC,
+// 	CTA_PROTOINFO_DCCP_STATE,
+// 	CTA_PROTOINFO_DCCP_ROLE,
+// 	CTA_PROTOINFO_DCCP_HANDSHAKE_SEQ,
+// 	CTA_PROTOINFO_DCCP_PAD,
+// 	__CTA_PROTOINFO_DCCP_MAX,
+// };
+const (
+	CTA_PROTOINFO_DCCP_STATE = 1
+)
+
+// enum ctattr_protoinfo_sctp {
+// 	CTA_PROTOINFO_SCTP_UNSPEC,
+// 	CTA_PROTOINFO_SCTP_STATE,
+// 	CTA_PROTOINFO_SCTP_VTAG_ORIGINAL,
+// 	CTA_PROTOINFO_SCTP_VTAG_REPLY,
+// 	__CTA_PROTOINFO_SCTP_MAX
+// };
+const (
+	CTA_PROTOINFO_SCTP_STATE         = 1
+	CTA_PROTOINFO_SCTP_VTAG_ORIGINAL = 2
+	CTA_PROTOINFO_SCTP_VTAG_REPLY    = 3
+)
+
 // enum ctattr_counters {
 // 	CTA_COUNTERS_UNSPEC,
 // 	CTA_COUNTERS_PACKETS,		/* 64bit counters */
@@ -192,6 +314,38 @@ const (
 	CTA_TIMESTAMP_STOP  = 2
 )
 
+// enum ctattr_seqadj {
+// 	CTA_SEQADJ_UNSPEC,
+// 	CTA_SEQADJ_CORRECTION_POS,
+// 	CTA_SEQADJ_OFFSET_BEFORE,
+// 	CTA_SEQADJ_OFFSET_AFTER,
+// 	__CTA_SEQADJ_MAX
+// };
+const (
+	CTA_SEQADJ_CORRECTION_POS = 1
+	CTA_SEQADJ_OFFSET_BEFORE  = 2
+	CTA_SEQADJ_OFFSET_AFTER   = 3
+)
+
+// enum ctattr_help {
+// 	CTA_HELP_UNSPEC,
+// 	CTA_HELP_NAME,
+// 	CTA_HELP_INFO,
+// 	__CTA_HELP_MAX
+// };
+const (
+	CTA_HELP_NAME = 1
+)
+
+// enum ctattr_secctx {
+// 	CTA_SECCTX_UNSPEC,
+// 	CTA_SECCTX_NAME,
+// 	__CTA_SECCTX_MAX
+// };
+const (
+	CTA_SECCTX_NAME = 1
+)
+
 // /* General form of address family dependent message.
 //  */
 // struct nfgenmsg {
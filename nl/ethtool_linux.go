@@ -0,0 +1,239 @@
+package nl
+
+// All the following constants are coming from:
+// https://github.com/torvalds/linux/blob/master/include/uapi/linux/ethtool_netlink.h
+
+const (
+	ETHTOOL_GENL_NAME          = "ethtool"
+	ETHTOOL_GENL_VERSION       = 1
+	ETHTOOL_MCGRP_MONITOR_NAME = "monitor"
+)
+
+// message types - userspace to kernel
+const (
+	ETHTOOL_MSG_USER_NONE = iota
+	ETHTOOL_MSG_STRSET_GET
+	ETHTOOL_MSG_LINKINFO_GET
+	ETHTOOL_MSG_LINKINFO_SET
+	ETHTOOL_MSG_LINKMODES_GET
+	ETHTOOL_MSG_LINKMODES_SET
+	ETHTOOL_MSG_LINKSTATE_GET
+	ETHTOOL_MSG_DEBUG_GET
+	ETHTOOL_MSG_DEBUG_SET
+	ETHTOOL_MSG_WOL_GET
+	ETHTOOL_MSG_WOL_SET
+	ETHTOOL_MSG_FEATURES_GET
+	ETHTOOL_MSG_FEATURES_SET
+	ETHTOOL_MSG_PRIVFLAGS_GET
+	ETHTOOL_MSG_PRIVFLAGS_SET
+	ETHTOOL_MSG_RINGS_GET
+	ETHTOOL_MSG_RINGS_SET
+	ETHTOOL_MSG_CHANNELS_GET
+	ETHTOOL_MSG_CHANNELS_SET
+	ETHTOOL_MSG_COALESCE_GET
+	ETHTOOL_MSG_COALESCE_SET
+	ETHTOOL_MSG_PAUSE_GET
+	ETHTOOL_MSG_PAUSE_SET
+	ETHTOOL_MSG_EEE_GET
+	ETHTOOL_MSG_EEE_SET
+)
+
+// message types - kernel to userspace
+const (
+	ETHTOOL_MSG_KERNEL_NONE = iota
+	ETHTOOL_MSG_STRSET_GET_REPLY
+	ETHTOOL_MSG_LINKINFO_GET_REPLY
+	ETHTOOL_MSG_LINKINFO_NTF
+	ETHTOOL_MSG_LINKMODES_GET_REPLY
+	ETHTOOL_MSG_LINKMODES_NTF
+	ETHTOOL_MSG_LINKSTATE_GET_REPLY
+	ETHTOOL_MSG_DEBUG_GET_REPLY
+	ETHTOOL_MSG_DEBUG_NTF
+	ETHTOOL_MSG_WOL_GET_REPLY
+	ETHTOOL_MSG_WOL_NTF
+	ETHTOOL_MSG_FEATURES_GET_REPLY
+	ETHTOOL_MSG_FEATURES_SET_REPLY
+	ETHTOOL_MSG_FEATURES_NTF
+	ETHTOOL_MSG_PRIVFLAGS_GET_REPLY
+	ETHTOOL_MSG_PRIVFLAGS_NTF
+	ETHTOOL_MSG_RINGS_GET_REPLY
+	ETHTOOL_MSG_RINGS_NTF
+	ETHTOOL_MSG_CHANNELS_GET_REPLY
+	ETHTOOL_MSG_CHANNELS_NTF
+	ETHTOOL_MSG_COALESCE_GET_REPLY
+	ETHTOOL_MSG_COALESCE_NTF
+	ETHTOOL_MSG_PAUSE_GET_REPLY
+	ETHTOOL_MSG_PAUSE_NTF
+	ETHTOOL_MSG_EEE_GET_REPLY
+	ETHTOOL_MSG_EEE_NTF
+)
+
+// request header flags
+const (
+	ETHTOOL_FLAG_COMPACT_BITSETS = 1 << iota
+	ETHTOOL_FLAG_OMIT_REPLY
+	ETHTOOL_FLAG_STATS
+)
+
+const (
+	ETHTOOL_A_HEADER_UNSPEC = iota
+	ETHTOOL_A_HEADER_DEV_INDEX
+	ETHTOOL_A_HEADER_DEV_NAME
+	ETHTOOL_A_HEADER_FLAGS
+)
+
+const (
+	ETHTOOL_A_BITSET_BIT_UNSPEC = iota
+	ETHTOOL_A_BITSET_BIT_INDEX
+	ETHTOOL_A_BITSET_BIT_NAME
+	ETHTOOL_A_BITSET_BIT_VALUE
+)
+
+const (
+	ETHTOOL_A_BITSET_BITS_UNSPEC = iota
+	ETHTOOL_A_BITSET_BITS_BIT
+)
+
+const (
+	ETHTOOL_A_BITSET_UNSPEC = iota
+	ETHTOOL_A_BITSET_NOMASK
+	ETHTOOL_A_BITSET_SIZE
+	ETHTOOL_A_BITSET_BITS
+	ETHTOOL_A_BITSET_VALUE
+	ETHTOOL_A_BITSET_MASK
+)
+
+const (
+	ETHTOOL_A_STRING_UNSPEC = iota
+	ETHTOOL_A_STRING_INDEX
+	ETHTOOL_A_STRING_VALUE
+)
+
+const (
+	ETHTOOL_A_STRINGS_UNSPEC = iota
+	ETHTOOL_A_STRINGS_STRING
+)
+
+const (
+	ETHTOOL_A_STRINGSET_UNSPEC = iota
+	ETHTOOL_A_STRINGSET_ID
+	ETHTOOL_A_STRINGSET_COUNT
+	ETHTOOL_A_STRINGSET_STRINGS
+)
+
+const (
+	ETHTOOL_A_STRINGSETS_UNSPEC = iota
+	ETHTOOL_A_STRINGSETS_STRINGSET
+)
+
+const (
+	ETHTOOL_A_STRSET_UNSPEC = iota
+	ETHTOOL_A_STRSET_HEADER
+	ETHTOOL_A_STRSET_STRINGSETS
+	ETHTOOL_A_STRSET_COUNTS_ONLY
+)
+
+const (
+	ETHTOOL_A_LINKMODES_UNSPEC = iota
+	ETHTOOL_A_LINKMODES_HEADER
+	ETHTOOL_A_LINKMODES_AUTONEG
+	ETHTOOL_A_LINKMODES_OURS
+	ETHTOOL_A_LINKMODES_PEER
+	ETHTOOL_A_LINKMODES_SPEED
+	ETHTOOL_A_LINKMODES_DUPLEX
+	ETHTOOL_A_LINKMODES_MASTER_SLAVE_CFG
+	ETHTOOL_A_LINKMODES_MASTER_SLAVE_STATE
+	ETHTOOL_A_LINKMODES_LANES
+)
+
+const (
+	ETHTOOL_A_LINKSTATE_UNSPEC = iota
+	ETHTOOL_A_LINKSTATE_HEADER
+	ETHTOOL_A_LINKSTATE_LINK
+	ETHTOOL_A_LINKSTATE_SQI
+	ETHTOOL_A_LINKSTATE_SQI_MAX
+	ETHTOOL_A_LINKSTATE_EXT_STATE
+	ETHTOOL_A_LINKSTATE_EXT_SUBSTATE
+)
+
+const (
+	ETHTOOL_A_FEATURES_UNSPEC = iota
+	ETHTOOL_A_FEATURES_HEADER
+	ETHTOOL_A_FEATURES_HW
+	ETHTOOL_A_FEATURES_WANTED
+	ETHTOOL_A_FEATURES_ACTIVE
+	ETHTOOL_A_FEATURES_NOCHANGE
+)
+
+const (
+	ETHTOOL_A_RINGS_UNSPEC = iota
+	ETHTOOL_A_RINGS_HEADER
+	ETHTOOL_A_RINGS_RX_MAX
+	ETHTOOL_A_RINGS_RX_MINI_MAX
+	ETHTOOL_A_RINGS_RX_JUMBO_MAX
+	ETHTOOL_A_RINGS_TX_MAX
+	ETHTOOL_A_RINGS_RX
+	ETHTOOL_A_RINGS_RX_MINI
+	ETHTOOL_A_RINGS_RX_JUMBO
+	ETHTOOL_A_RINGS_TX
+)
+
+const (
+	ETHTOOL_A_CHANNELS_UNSPEC = iota
+	ETHTOOL_A_CHANNELS_HEADER
+	ETHTOOL_A_CHANNELS_RX_MAX
+	ETHTOOL_A_CHANNELS_TX_MAX
+	ETHTOOL_A_CHANNELS_OTHER_MAX
+	ETHTOOL_A_CHANNELS_COMBINED_MAX
+	ETHTOOL_A_CHANNELS_RX_COUNT
+	ETHTOOL_A_CHANNELS_TX_COUNT
+	ETHTOOL_A_CHANNELS_OTHER_COUNT
+	ETHTOOL_A_CHANNELS_COMBINED_COUNT
+)
+
+const (
+	ETHTOOL_A_COALESCE_UNSPEC = iota
+	ETHTOOL_A_COALESCE_HEADER
+	ETHTOOL_A_COALESCE_RX_USECS
+	ETHTOOL_A_COALESCE_RX_MAX_FRAMES
+	ETHTOOL_A_COALESCE_RX_USECS_IRQ
+	ETHTOOL_A_COALESCE_RX_MAX_FRAMES_IRQ
+	ETHTOOL_A_COALESCE_TX_USECS
+	ETHTOOL_A_COALESCE_TX_MAX_FRAMES
+	ETHTOOL_A_COALESCE_TX_USECS_IRQ
+	ETHTOOL_A_COALESCE_TX_MAX_FRAMES_IRQ
+	ETHTOOL_A_COALESCE_STATS_BLOCK_USECS
+	ETHTOOL_A_COALESCE_USE_ADAPTIVE_RX
+	ETHTOOL_A_COALESCE_USE_ADAPTIVE_TX
+	ETHTOOL_A_COALESCE_PKT_RATE_LOW
+	ETHTOOL_A_COALESCE_RX_USECS_LOW
+	ETHTOOL_A_COALESCE_RX_MAX_FRAMES_LOW
+	ETHTOOL_A_COALESCE_TX_USECS_LOW
+	ETHTOOL_A_COALESCE_TX_MAX_FRAMES_LOW
+	ETHTOOL_A_COALESCE_PKT_RATE_HIGH
+	ETHTOOL_A_COALESCE_RX_USECS_HIGH
+	ETHTOOL_A_COALESCE_RX_MAX_FRAMES_HIGH
+	ETHTOOL_A_COALESCE_TX_USECS_HIGH
+	ETHTOOL_A_COALESCE_TX_MAX_FRAMES_HIGH
+	ETHTOOL_A_COALESCE_RATE_SAMPLE_INTERVAL
+)
+
+const (
+	ETHTOOL_A_PAUSE_UNSPEC = iota
+	ETHTOOL_A_PAUSE_HEADER
+	ETHTOOL_A_PAUSE_AUTONEG
+	ETHTOOL_A_PAUSE_RX
+	ETHTOOL_A_PAUSE_TX
+	ETHTOOL_A_PAUSE_STATS
+)
+
+const (
+	ETHTOOL_A_EEE_UNSPEC = iota
+	ETHTOOL_A_EEE_HEADER
+	ETHTOOL_A_EEE_MODES_OURS
+	ETHTOOL_A_EEE_MODES_PEER
+	ETHTOOL_A_EEE_ACTIVE
+	ETHTOOL_A_EEE_ENABLED
+	ETHTOOL_A_EEE_TX_LPI_ENABLED
+	ETHTOOL_A_EEE_TX_LPI_TIMER
+)
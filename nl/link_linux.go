@@ -63,7 +63,12 @@ const (
 	IFLA_VXLAN_GBP
 	IFLA_VXLAN_REMCSUM_NOPARTIAL
 	IFLA_VXLAN_FLOWBASED
-	IFLA_VXLAN_MAX = IFLA_VXLAN_FLOWBASED
+	IFLA_VXLAN_LABEL
+	IFLA_VXLAN_GPE
+	IFLA_VXLAN_TTL_INHERIT
+	IFLA_VXLAN_DF
+	IFLA_VXLAN_VNIFILTER
+	IFLA_VXLAN_MAX = IFLA_VXLAN_VNIFILTER
 )
 
 const (
@@ -85,7 +90,46 @@ const (
 	IFLA_BRPORT_PROXYARP
 	IFLA_BRPORT_LEARNING_SYNC
 	IFLA_BRPORT_PROXYARP_WIFI
-	IFLA_BRPORT_MAX = IFLA_BRPORT_PROXYARP_WIFI
+	IFLA_BRPORT_ROOT_ID
+	IFLA_BRPORT_BRIDGE_ID
+	IFLA_BRPORT_DESIGNATED_PORT
+	IFLA_BRPORT_DESIGNATED_COST
+	IFLA_BRPORT_ID
+	IFLA_BRPORT_NO
+	IFLA_BRPORT_TOPOLOGY_CHANGE_ACK
+	IFLA_BRPORT_CONFIG_PENDING
+	IFLA_BRPORT_MESSAGE_AGE_TIMER
+	IFLA_BRPORT_FORWARD_DELAY_TIMER
+	IFLA_BRPORT_HOLD_TIMER
+	IFLA_BRPORT_FLUSH
+	IFLA_BRPORT_MULTICAST_ROUTER
+	IFLA_BRPORT_PAD
+	IFLA_BRPORT_MCAST_FLOOD
+	IFLA_BRPORT_MCAST_TO_UCAST
+	IFLA_BRPORT_VLAN_TUNNEL
+	IFLA_BRPORT_BCAST_FLOOD
+	IFLA_BRPORT_GROUP_FWD_MASK
+	IFLA_BRPORT_NEIGH_SUPPRESS
+	IFLA_BRPORT_ISOLATED
+	IFLA_BRPORT_BACKUP_PORT
+	IFLA_BRPORT_MAX = IFLA_BRPORT_BACKUP_PORT
+)
+
+// Bridge port STP states
+const (
+	BR_STATE_DISABLED = iota
+	BR_STATE_LISTENING
+	BR_STATE_LEARNING
+	BR_STATE_FORWARDING
+	BR_STATE_BLOCKING
+)
+
+// Bridge and bridge port multicast router types
+const (
+	MDB_RTR_TYPE_DISABLED = iota
+	MDB_RTR_TYPE_TEMP_QUERY
+	MDB_RTR_TYPE_PERM
+	MDB_RTR_TYPE_TEMP
 )
 
 const (
@@ -209,7 +253,13 @@ const (
 	IFLA_GRE_ENCAP_SPORT
 	IFLA_GRE_ENCAP_DPORT
 	IFLA_GRE_COLLECT_METADATA
-	IFLA_GRE_MAX = IFLA_GRE_COLLECT_METADATA
+	IFLA_GRE_IGNORE_DF
+	IFLA_GRE_FWMARK
+	IFLA_GRE_ERSPAN_INDEX
+	IFLA_GRE_ERSPAN_VER
+	IFLA_GRE_ERSPAN_DIR
+	IFLA_GRE_ERSPAN_HWID
+	IFLA_GRE_MAX = IFLA_GRE_ERSPAN_HWID
 )
 
 const (
@@ -244,7 +294,15 @@ const (
 	IFLA_VF_TRUST        /* Trust state of VF */
 	IFLA_VF_IB_NODE_GUID /* VF Infiniband node GUID */
 	IFLA_VF_IB_PORT_GUID /* VF Infiniband port GUID */
-	IFLA_VF_MAX          = IFLA_VF_IB_PORT_GUID
+	IFLA_VF_VLAN_LIST    /* nested list of vlans, option for QinQ */
+	IFLA_VF_BROADCAST    /* VF broadcast */
+	IFLA_VF_MAX          = IFLA_VF_BROADCAST
+)
+
+const (
+	IFLA_VF_VLAN_INFO_UNSPEC = iota
+	IFLA_VF_VLAN_INFO        /* VLAN ID, QoS and VLAN protocol */
+	IFLA_VF_VLAN_INFO_MAX    = IFLA_VF_VLAN_INFO
 )
 
 const (
@@ -261,6 +319,7 @@ const (
 	IFLA_VF_STATS_TX_BYTES
 	IFLA_VF_STATS_BROADCAST
 	IFLA_VF_STATS_MULTICAST
+	IFLA_VF_STATS_PAD
 	IFLA_VF_STATS_RX_DROPPED
 	IFLA_VF_STATS_TX_DROPPED
 	IFLA_VF_STATS_MAX = IFLA_VF_STATS_TX_DROPPED
@@ -269,6 +328,7 @@ const (
 const (
 	SizeofVfMac        = 0x24
 	SizeofVfVlan       = 0x0c
+	SizeofVfVlanInfo   = 0x10
 	SizeofVfTxRate     = 0x08
 	SizeofVfRate       = 0x0c
 	SizeofVfSpoofchk   = 0x08
@@ -324,6 +384,31 @@ func (msg *VfVlan) Serialize() []byte {
 	return (*(*[SizeofVfVlan]byte)(unsafe.Pointer(msg)))[:]
 }
 
+// struct ifla_vf_vlan_info {
+//   __u32 vf;
+//   __u32 vlan; /* 0 - 4095, 0 disables VLAN filter */
+//   __u32 qos;
+//   __be16 vlan_proto; /* VLAN protocol either 802.1Q or 802.1ad */
+// };
+
+type VfVlanInfo struct {
+	VfVlan
+	VlanProto uint16 // network byte order
+	_         uint16
+}
+
+func (msg *VfVlanInfo) Len() int {
+	return SizeofVfVlanInfo
+}
+
+func DeserializeVfVlanInfo(b []byte) *VfVlanInfo {
+	return (*VfVlanInfo)(unsafe.Pointer(&b[0:SizeofVfVlanInfo][0]))
+}
+
+func (msg *VfVlanInfo) Serialize() []byte {
+	return (*(*[SizeofVfVlanInfo]byte)(unsafe.Pointer(msg)))[:]
+}
+
 // struct ifla_vf_tx_rate {
 //   __u32 vf;
 //   __u32 rate; /* Max TX bandwidth in Mbps, 0 disables throttling */
@@ -644,7 +729,9 @@ const (
 	IFLA_BR_MCAST_STATS_ENABLED
 	IFLA_BR_MCAST_IGMP_VERSION
 	IFLA_BR_MCAST_MLD_VERSION
-	IFLA_BR_MAX = IFLA_BR_MCAST_MLD_VERSION
+	IFLA_BR_VLAN_STATS_PER_PORT
+	IFLA_BR_MULTI_BOOLOPT
+	IFLA_BR_MAX = IFLA_BR_MULTI_BOOLOPT
 )
 
 const (
@@ -668,6 +755,54 @@ const (
 	IFLA_XFRM_MAX = iota - 1
 )
 
+const (
+	IFLA_BAREUDP_UNSPEC = iota
+	IFLA_BAREUDP_PORT
+	IFLA_BAREUDP_ETHERTYPE
+	IFLA_BAREUDP_SRCPORT_MIN
+	IFLA_BAREUDP_MULTIPROTO_MODE
+	IFLA_BAREUDP_MAX = IFLA_BAREUDP_MULTIPROTO_MODE
+)
+
+const (
+	IFLA_HSR_UNSPEC = iota
+	IFLA_HSR_SLAVE1
+	IFLA_HSR_SLAVE2
+	IFLA_HSR_MULTICAST_SPEC
+	IFLA_HSR_SUPERVISION_ADDR
+	IFLA_HSR_SEQ_NR
+	IFLA_HSR_VERSION
+	IFLA_HSR_PROTOCOL
+	IFLA_HSR_INTERLINK
+	IFLA_HSR_MAX = IFLA_HSR_INTERLINK
+)
+
+const (
+	VXCAN_INFO_UNSPEC = iota
+	VXCAN_INFO_PEER
+	VXCAN_INFO_MAX = VXCAN_INFO_PEER
+)
+
+const (
+	IFLA_BATADV_UNSPEC = iota
+	IFLA_BATADV_ALGO_NAME
+	IFLA_BATADV_MAX = IFLA_BATADV_ALGO_NAME
+)
+
+const (
+	IFLA_NETKIT_UNSPEC = iota
+	IFLA_NETKIT_PEER_INFO
+	IFLA_NETKIT_PRIMARY
+	IFLA_NETKIT_POLICY
+	IFLA_NETKIT_PEER_POLICY
+	IFLA_NETKIT_MODE
+	IFLA_NETKIT_SCRUB
+	IFLA_NETKIT_PEER_SCRUB
+	IFLA_NETKIT_HEADROOM
+	IFLA_NETKIT_TAILROOM
+	IFLA_NETKIT_MAX = IFLA_NETKIT_TAILROOM
+)
+
 const (
 	IFLA_TUN_UNSPEC = iota
 	IFLA_TUN_OWNER
@@ -690,6 +825,26 @@ const (
 	IFLA_IPOIB_MAX = IFLA_IPOIB_UMCAST
 )
 
+const (
+	IFLA_MACSEC_UNSPEC = iota
+	IFLA_MACSEC_SCI
+	IFLA_MACSEC_PORT
+	IFLA_MACSEC_ICV_LEN
+	IFLA_MACSEC_CIPHER_SUITE
+	IFLA_MACSEC_WINDOW
+	IFLA_MACSEC_ENCODING_SA
+	IFLA_MACSEC_ENCRYPT
+	IFLA_MACSEC_PROTECT
+	IFLA_MACSEC_INC_SCI
+	IFLA_MACSEC_ES
+	IFLA_MACSEC_SCB
+	IFLA_MACSEC_REPLAY_PROTECT
+	IFLA_MACSEC_VALIDATION
+	IFLA_MACSEC_PAD
+	IFLA_MACSEC_OFFLOAD
+	IFLA_MACSEC_MAX = IFLA_MACSEC_OFFLOAD
+)
+
 const (
 	IFLA_CAN_UNSPEC = iota
 	IFLA_CAN_BITTIMING
@@ -709,3 +864,88 @@ const (
 	IFLA_CAN_BITRATE_MAX
 	IFLA_CAN_MAX = IFLA_CAN_BITRATE_MAX
 )
+
+const (
+	IFLA_STATS_UNSPEC = iota
+	IFLA_STATS_LINK_64
+	IFLA_STATS_LINK_XSTATS
+	IFLA_STATS_LINK_XSTATS_SLAVE
+	IFLA_STATS_LINK_OFFLOAD_XSTATS
+	IFLA_STATS_AF_SPEC
+	IFLA_STATS_MAX = IFLA_STATS_AF_SPEC
+)
+
+const (
+	IFLA_OFFLOAD_XSTATS_UNSPEC = iota
+	IFLA_OFFLOAD_XSTATS_CPU_HIT
+	IFLA_OFFLOAD_XSTATS_HW_S_INFO
+	IFLA_OFFLOAD_XSTATS_L3_STATS
+)
+
+const (
+	LINK_XSTATS_TYPE_UNSPEC = iota
+	LINK_XSTATS_TYPE_BRIDGE
+	LINK_XSTATS_TYPE_BOND
+)
+
+const (
+	BRIDGE_XSTATS_UNSPEC = iota
+	BRIDGE_XSTATS_VLAN
+	BRIDGE_XSTATS_MCAST
+	BRIDGE_XSTATS_PAD
+	BRIDGE_XSTATS_STP
+)
+
+const (
+	BOND_XSTATS_UNSPEC = iota
+	BOND_XSTATS_3AD
+)
+
+// The bond 802.3ad statistics attributes start at 0
+const (
+	BOND_3AD_STAT_LACPDU_RX = iota
+	BOND_3AD_STAT_LACPDU_TX
+	BOND_3AD_STAT_LACPDU_UNKNOWN_RX
+	BOND_3AD_STAT_LACPDU_ILLEGAL_RX
+	BOND_3AD_STAT_MARKER_RX
+	BOND_3AD_STAT_MARKER_TX
+	BOND_3AD_STAT_MARKER_RESP_RX
+	BOND_3AD_STAT_MARKER_RESP_TX
+	BOND_3AD_STAT_MARKER_UNKNOWN_RX
+	BOND_3AD_STAT_PAD
+)
+
+const (
+	MPLS_STATS_UNSPEC = iota
+	MPLS_STATS_LINK
+)
+
+const SizeofIfStatsMsg = 0x0c
+
+// IfStatsMsg is the header of the RTM_GETSTATS requests and responses
+// struct if_stats_msg {
+//   __u8  family;
+//   __u8  pad1;
+//   __u16 pad2;
+//   __u32 ifindex;
+//   __u32 filter_mask;
+// };
+type IfStatsMsg struct {
+	Family     uint8
+	Pad1       uint8
+	Pad2       uint16
+	Ifindex    uint32
+	FilterMask uint32
+}
+
+func (msg *IfStatsMsg) Len() int {
+	return SizeofIfStatsMsg
+}
+
+func DeserializeIfStatsMsg(b []byte) *IfStatsMsg {
+	return (*IfStatsMsg)(unsafe.Pointer(&b[0:SizeofIfStatsMsg][0]))
+}
+
+func (msg *IfStatsMsg) Serialize() []byte {
+	return (*(*[SizeofIfStatsMsg]byte)(unsafe.Pointer(msg)))[:]
+}
@@ -62,6 +62,33 @@ func TestVfVlanDeserializeSerialize(t *testing.T) {
 	testDeserializeSerialize(t, orig, safemsg, msg)
 }
 
+func (msg *VfVlanInfo) write(b []byte) {
+	native := NativeEndian()
+	msg.VfVlan.write(b[0:12])
+	native.PutUint16(b[12:14], msg.VlanProto)
+}
+
+func (msg *VfVlanInfo) serializeSafe() []byte {
+	length := SizeofVfVlanInfo
+	b := make([]byte, length)
+	msg.write(b)
+	return b
+}
+
+func deserializeVfVlanInfoSafe(b []byte) *VfVlanInfo {
+	var msg = VfVlanInfo{}
+	binary.Read(bytes.NewReader(b[0:SizeofVfVlanInfo]), NativeEndian(), &msg)
+	return &msg
+}
+
+func TestVfVlanInfoDeserializeSerialize(t *testing.T) {
+	var orig = make([]byte, SizeofVfVlanInfo)
+	rand.Read(orig[:14])
+	safemsg := deserializeVfVlanInfoSafe(orig)
+	msg := DeserializeVfVlanInfo(orig)
+	testDeserializeSerialize(t, orig, safemsg, msg)
+}
+
 func (msg *VfTxRate) write(b []byte) {
 	native := NativeEndian()
 	native.PutUint32(b[0:4], uint32(msg.Vf))
@@ -197,3 +224,33 @@ func TestVfRssQueryEnDeserializeSerialize(t *testing.T) {
 	msg := DeserializeVfRssQueryEn(orig)
 	testDeserializeSerialize(t, orig, safemsg, msg)
 }
+
+func (msg *IfStatsMsg) write(b []byte) {
+	native := NativeEndian()
+	b[0] = msg.Family
+	b[1] = msg.Pad1
+	native.PutUint16(b[2:4], msg.Pad2)
+	native.PutUint32(b[4:8], msg.Ifindex)
+	native.PutUint32(b[8:12], msg.FilterMask)
+}
+
+func (msg *IfStatsMsg) serializeSafe() []byte {
+	length := SizeofIfStatsMsg
+	b := make([]byte, length)
+	msg.write(b)
+	return b
+}
+
+func deserializeIfStatsMsgSafe(b []byte) *IfStatsMsg {
+	var msg = IfStatsMsg{}
+	binary.Read(bytes.NewReader(b[0:SizeofIfStatsMsg]), NativeEndian(), &msg)
+	return &msg
+}
+
+func TestIfStatsMsgDeserializeSerialize(t *testing.T) {
+	var orig = make([]byte, SizeofIfStatsMsg)
+	rand.Read(orig)
+	safemsg := deserializeIfStatsMsgSafe(orig)
+	msg := DeserializeIfStatsMsg(orig)
+	testDeserializeSerialize(t, orig, safemsg, msg)
+}
@@ -0,0 +1,136 @@
+package nl
+
+// All the following constants are coming from:
+// https://github.com/torvalds/linux/blob/master/include/uapi/linux/if_macsec.h
+
+const (
+	MACSEC_GENL_NAME    = "macsec"
+	MACSEC_GENL_VERSION = 1
+)
+
+const (
+	MACSEC_KEYID_LEN = 16
+	MACSEC_SALT_LEN  = 12
+)
+
+const (
+	MACSEC_CMD_GET_TXSC = iota
+	MACSEC_CMD_ADD_RXSC
+	MACSEC_CMD_DEL_RXSC
+	MACSEC_CMD_UPD_RXSC
+	MACSEC_CMD_ADD_TXSA
+	MACSEC_CMD_DEL_TXSA
+	MACSEC_CMD_UPD_TXSA
+	MACSEC_CMD_ADD_RXSA
+	MACSEC_CMD_DEL_RXSA
+	MACSEC_CMD_UPD_RXSA
+	MACSEC_CMD_UPD_OFFLOAD
+)
+
+const (
+	MACSEC_ATTR_UNSPEC = iota
+	MACSEC_ATTR_IFINDEX
+	MACSEC_ATTR_RXSC_CONFIG
+	MACSEC_ATTR_SA_CONFIG
+	MACSEC_ATTR_SECY
+	MACSEC_ATTR_TXSA_LIST
+	MACSEC_ATTR_RXSC_LIST
+	MACSEC_ATTR_TXSC_STATS
+	MACSEC_ATTR_SECY_STATS
+	MACSEC_ATTR_OFFLOAD
+)
+
+const (
+	MACSEC_SECY_ATTR_UNSPEC = iota
+	MACSEC_SECY_ATTR_SCI
+	MACSEC_SECY_ATTR_ENCODING_SA
+	MACSEC_SECY_ATTR_WINDOW
+	MACSEC_SECY_ATTR_CIPHER_SUITE
+	MACSEC_SECY_ATTR_ICV_LEN
+	MACSEC_SECY_ATTR_PROTECT
+	MACSEC_SECY_ATTR_REPLAY
+	MACSEC_SECY_ATTR_OPER
+	MACSEC_SECY_ATTR_VALIDATE
+	MACSEC_SECY_ATTR_ENCRYPT
+	MACSEC_SECY_ATTR_INC_SCI
+	MACSEC_SECY_ATTR_ES
+	MACSEC_SECY_ATTR_SCB
+	MACSEC_SECY_ATTR_PAD
+)
+
+const (
+	MACSEC_RXSC_ATTR_UNSPEC = iota
+	MACSEC_RXSC_ATTR_SCI
+	MACSEC_RXSC_ATTR_ACTIVE
+	MACSEC_RXSC_ATTR_SA_LIST
+	MACSEC_RXSC_ATTR_STATS
+	MACSEC_RXSC_ATTR_PAD
+)
+
+const (
+	MACSEC_SA_ATTR_UNSPEC = iota
+	MACSEC_SA_ATTR_AN
+	MACSEC_SA_ATTR_ACTIVE
+	MACSEC_SA_ATTR_PN
+	MACSEC_SA_ATTR_KEY
+	MACSEC_SA_ATTR_KEYID
+	MACSEC_SA_ATTR_STATS
+	MACSEC_SA_ATTR_PAD
+	MACSEC_SA_ATTR_SSCI
+	MACSEC_SA_ATTR_SALT
+)
+
+const (
+	MACSEC_OFFLOAD_ATTR_UNSPEC = iota
+	MACSEC_OFFLOAD_ATTR_TYPE
+	MACSEC_OFFLOAD_ATTR_PAD
+)
+
+const (
+	MACSEC_SA_STATS_ATTR_UNSPEC = iota
+	MACSEC_SA_STATS_ATTR_IN_PKTS_OK
+	MACSEC_SA_STATS_ATTR_IN_PKTS_INVALID
+	MACSEC_SA_STATS_ATTR_IN_PKTS_NOT_VALID
+	MACSEC_SA_STATS_ATTR_IN_PKTS_NOT_USING_SA
+	MACSEC_SA_STATS_ATTR_IN_PKTS_UNUSED_SA
+	MACSEC_SA_STATS_ATTR_OUT_PKTS_PROTECTED
+	MACSEC_SA_STATS_ATTR_OUT_PKTS_ENCRYPTED
+	MACSEC_SA_STATS_ATTR_PAD
+)
+
+const (
+	MACSEC_RXSC_STATS_ATTR_UNSPEC = iota
+	MACSEC_RXSC_STATS_ATTR_IN_OCTETS_VALIDATED
+	MACSEC_RXSC_STATS_ATTR_IN_OCTETS_DECRYPTED
+	MACSEC_RXSC_STATS_ATTR_IN_PKTS_UNCHECKED
+	MACSEC_RXSC_STATS_ATTR_IN_PKTS_DELAYED
+	MACSEC_RXSC_STATS_ATTR_IN_PKTS_OK
+	MACSEC_RXSC_STATS_ATTR_IN_PKTS_INVALID
+	MACSEC_RXSC_STATS_ATTR_IN_PKTS_LATE
+	MACSEC_RXSC_STATS_ATTR_IN_PKTS_NOT_VALID
+	MACSEC_RXSC_STATS_ATTR_IN_PKTS_NOT_USING_SA
+	MACSEC_RXSC_STATS_ATTR_IN_PKTS_UNUSED_SA
+	MACSEC_RXSC_STATS_ATTR_PAD
+)
+
+const (
+	MACSEC_TXSC_STATS_ATTR_UNSPEC = iota
+	MACSEC_TXSC_STATS_ATTR_OUT_PKTS_PROTECTED
+	MACSEC_TXSC_STATS_ATTR_OUT_PKTS_ENCRYPTED
+	MACSEC_TXSC_STATS_ATTR_OUT_OCTETS_PROTECTED
+	MACSEC_TXSC_STATS_ATTR_OUT_OCTETS_ENCRYPTED
+	MACSEC_TXSC_STATS_ATTR_PAD
+)
+
+const (
+	MACSEC_SECY_STATS_ATTR_UNSPEC = iota
+	MACSEC_SECY_STATS_ATTR_OUT_PKTS_UNTAGGED
+	MACSEC_SECY_STATS_ATTR_IN_PKTS_UNTAGGED
+	MACSEC_SECY_STATS_ATTR_OUT_PKTS_TOO_LONG
+	MACSEC_SECY_STATS_ATTR_IN_PKTS_NO_TAG
+	MACSEC_SECY_STATS_ATTR_IN_PKTS_BAD_TAG
+	MACSEC_SECY_STATS_ATTR_IN_PKTS_UNKNOWN_SCI
+	MACSEC_SECY_STATS_ATTR_IN_PKTS_NO_SCI
+	MACSEC_SECY_STATS_ATTR_IN_PKTS_OVERRUN
+	MACSEC_SECY_STATS_ATTR_PAD
+)
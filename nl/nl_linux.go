@@ -256,6 +256,12 @@ func rtaAlignOf(attrlen int) int {
 	return (attrlen + unix.RTA_ALIGNTO - 1) & ^(unix.RTA_ALIGNTO - 1)
 }
 
+// RtaAlignOf returns attrlen rounded up to the alignment of netlink attributes.
+// It is useful to locate attributes trailing a fixed size payload.
+func RtaAlignOf(attrlen int) int {
+	return rtaAlignOf(attrlen)
+}
+
 func NewIfInfomsgChild(parent *RtAttr, family int) *IfInfomsg {
 	msg := NewIfInfomsg(family)
 	parent.children = append(parent.children, msg)
@@ -694,6 +700,23 @@ func (s *NetlinkSocket) SetReceiveTimeout(timeout *unix.Timeval) error {
 	return unix.SetsockoptTimeval(int(s.fd), unix.SOL_SOCKET, unix.SO_RCVTIMEO, timeout)
 }
 
+// SetReceiveBufferSize sets the receive buffer size of the socket. If force
+// is set, SO_RCVBUFFORCE is used to override the rmem_max limit.
+func (s *NetlinkSocket) SetReceiveBufferSize(size int, force bool) error {
+	opt := unix.SO_RCVBUF
+	if force {
+		opt = unix.SO_RCVBUFFORCE
+	}
+	return unix.SetsockoptInt(int(atomic.LoadInt32(&s.fd)), unix.SOL_SOCKET, opt, size)
+}
+
+// JoinGroup subscribes the socket to a multicast group. Unlike the groups
+// passed to Subscribe, it works for any group id, such as the generic
+// netlink ones.
+func (s *NetlinkSocket) JoinGroup(group uint32) error {
+	return unix.SetsockoptInt(int(atomic.LoadInt32(&s.fd)), unix.SOL_NETLINK, unix.NETLINK_ADD_MEMBERSHIP, int(group))
+}
+
 func (s *NetlinkSocket) GetPid() (uint32, error) {
 	fd := int(atomic.LoadInt32(&s.fd))
 	lsa, err := unix.Getsockname(fd)
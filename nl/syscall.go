@@ -74,3 +74,8 @@ const (
 	IPV6_SRCRT_TYPE_2 = 2    // IPv6 type 2 Routing Header
 	IPV6_SRCRT_TYPE_4 = 4    // Segment Routing with IPv6
 )
+
+// number of tx queue ranges of the mqprio and taprio qdiscs
+const (
+	TC_QOPT_MAX_QUEUE = 16
+)
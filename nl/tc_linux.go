@@ -71,7 +71,11 @@ const (
 	TCA_STATS_RATE_EST
 	TCA_STATS_QUEUE
 	TCA_STATS_APP
-	TCA_STATS_MAX = TCA_STATS_APP
+	TCA_STATS_RATE_EST64
+	TCA_STATS_PAD
+	TCA_STATS_BASIC_HW
+	TCA_STATS_PKT64
+	TCA_STATS_MAX = TCA_STATS_PKT64
 )
 
 const (
@@ -83,6 +87,10 @@ const (
 	SizeofTcNetemCorr    = 0x0c
 	SizeofTcNetemReorder = 0x08
 	SizeofTcNetemCorrupt = 0x08
+	SizeofTcNetemRate    = 0x10
+	SizeofTcNetemGimodel = 0x14
+	SizeofTcNetemGemodel = 0x10
+	SizeofTcNetemSlot    = 0x28
 	SizeofTcTbfQopt      = 2*SizeofTcRateSpec + 0x0c
 	SizeofTcHtbCopt      = 2*SizeofTcRateSpec + 0x14
 	SizeofTcHtbGlob      = 0x14
@@ -97,6 +105,13 @@ const (
 	SizeofTcSfqQopt      = 0x0b
 	SizeofTcSfqRedStats  = 0x18
 	SizeofTcSfqQoptV1    = SizeofTcSfqQopt + SizeofTcSfqRedStats + 0x1c
+	SizeofTcMqprioQopt   = 0x52
+	SizeofTcEtfQopt      = 0x0c
+	SizeofTcCbsQopt      = 0x14
+	SizeofTcRedQopt      = 0x10
+	SizeofTcGredQopt     = 0x34
+	SizeofTcGredSopt     = 0x0c
+	SizeofTcMultiqQopt   = 0x04
 )
 
 // struct tcmsg {
@@ -235,7 +250,25 @@ const (
 	TCA_NETEM_RATE
 	TCA_NETEM_ECN
 	TCA_NETEM_RATE64
-	TCA_NETEM_MAX = TCA_NETEM_RATE64
+	TCA_NETEM_PAD
+	TCA_NETEM_LATENCY64
+	TCA_NETEM_JITTER64
+	TCA_NETEM_SLOT
+	TCA_NETEM_SLOT_DIST
+	TCA_NETEM_PRNG_SEED
+	TCA_NETEM_MAX = TCA_NETEM_PRNG_SEED
+)
+
+const (
+	NETEM_LOSS_UNSPEC = iota
+	NETEM_LOSS_GI     // General Intuitive - 4 state model
+	NETEM_LOSS_GE     // Gilbert Elliot models
+	NETEM_LOSS_MAX    = NETEM_LOSS_GE
+)
+
+const (
+	NETEM_DIST_SCALE = 8192
+	NETEM_DIST_MAX   = 16384
 )
 
 // struct tc_netem_qopt {
@@ -336,6 +369,116 @@ func (x *TcNetemCorrupt) Serialize() []byte {
 	return (*(*[SizeofTcNetemCorrupt]byte)(unsafe.Pointer(x)))[:]
 }
 
+// struct tc_netem_rate {
+// 	__u32	rate;	/* byte/s */
+// 	__s32	packet_overhead;
+// 	__u32	cell_size;
+// 	__s32	cell_overhead;
+// };
+
+type TcNetemRate struct {
+	Rate           uint32
+	PacketOverhead int32
+	CellSize       uint32
+	CellOverhead   int32
+}
+
+func (msg *TcNetemRate) Len() int {
+	return SizeofTcNetemRate
+}
+
+func DeserializeTcNetemRate(b []byte) *TcNetemRate {
+	return (*TcNetemRate)(unsafe.Pointer(&b[0:SizeofTcNetemRate][0]))
+}
+
+func (x *TcNetemRate) Serialize() []byte {
+	return (*(*[SizeofTcNetemRate]byte)(unsafe.Pointer(x)))[:]
+}
+
+// struct tc_netem_gimodel {
+// 	__u32	p13;
+// 	__u32	p31;
+// 	__u32	p32;
+// 	__u32	p14;
+// 	__u32	p23;
+// };
+
+type TcNetemGimodel struct {
+	P13 uint32
+	P31 uint32
+	P32 uint32
+	P14 uint32
+	P23 uint32
+}
+
+func (msg *TcNetemGimodel) Len() int {
+	return SizeofTcNetemGimodel
+}
+
+func DeserializeTcNetemGimodel(b []byte) *TcNetemGimodel {
+	return (*TcNetemGimodel)(unsafe.Pointer(&b[0:SizeofTcNetemGimodel][0]))
+}
+
+func (x *TcNetemGimodel) Serialize() []byte {
+	return (*(*[SizeofTcNetemGimodel]byte)(unsafe.Pointer(x)))[:]
+}
+
+// struct tc_netem_gemodel {
+// 	__u32 p;
+// 	__u32 r;
+// 	__u32 h;
+// 	__u32 k1;
+// };
+
+type TcNetemGemodel struct {
+	P  uint32
+	R  uint32
+	H  uint32
+	K1 uint32
+}
+
+func (msg *TcNetemGemodel) Len() int {
+	return SizeofTcNetemGemodel
+}
+
+func DeserializeTcNetemGemodel(b []byte) *TcNetemGemodel {
+	return (*TcNetemGemodel)(unsafe.Pointer(&b[0:SizeofTcNetemGemodel][0]))
+}
+
+func (x *TcNetemGemodel) Serialize() []byte {
+	return (*(*[SizeofTcNetemGemodel]byte)(unsafe.Pointer(x)))[:]
+}
+
+// struct tc_netem_slot {
+// 	__s64   min_delay; /* nsec */
+// 	__s64   max_delay;
+// 	__s32   max_packets;
+// 	__s32   max_bytes;
+// 	__s64	dist_delay; /* nsec */
+// 	__s64	dist_jitter; /* nsec */
+// };
+
+type TcNetemSlot struct {
+	MinDelay   int64
+	MaxDelay   int64
+	MaxPackets int32
+	MaxBytes   int32
+	DistDelay  int64
+	DistJitter int64
+}
+
+func (msg *TcNetemSlot) Len() int {
+	return SizeofTcNetemSlot
+}
+
+func DeserializeTcNetemSlot(b []byte) *TcNetemSlot {
+	return (*TcNetemSlot)(unsafe.Pointer(&b[0:SizeofTcNetemSlot][0]))
+}
+
+func (x *TcNetemSlot) Serialize() []byte {
+	return (*(*[SizeofTcNetemSlot]byte)(unsafe.Pointer(x)))[:]
+}
+
 // struct tc_tbf_qopt {
 //   struct tc_ratespec rate;
 //   struct tc_ratespec peakrate;
@@ -875,6 +1018,11 @@ const (
 	TCA_FQ_CODEL_MEMORY_LIMIT
 )
 
+const (
+	TCA_FQ_CODEL_XSTATS_QDISC = iota
+	TCA_FQ_CODEL_XSTATS_CLASS
+)
+
 const (
 	TCA_HFSC_UNSPEC = iota
 	TCA_HFSC_RSC
@@ -981,3 +1129,385 @@ func DeserializeTcSfqQoptV1(b []byte) *TcSfqQoptV1 {
 func (x *TcSfqQoptV1) Serialize() []byte {
 	return (*(*[SizeofTcSfqQoptV1]byte)(unsafe.Pointer(x)))[:]
 }
+
+const (
+	TCA_CAKE_UNSPEC = iota
+	TCA_CAKE_PAD
+	TCA_CAKE_BASE_RATE64
+	TCA_CAKE_DIFFSERV_MODE
+	TCA_CAKE_ATM
+	TCA_CAKE_FLOW_MODE
+	TCA_CAKE_OVERHEAD
+	TCA_CAKE_RTT
+	TCA_CAKE_TARGET
+	TCA_CAKE_AUTORATE
+	TCA_CAKE_MEMORY
+	TCA_CAKE_NAT
+	TCA_CAKE_RAW
+	TCA_CAKE_WASH
+	TCA_CAKE_MPU
+	TCA_CAKE_INGRESS
+	TCA_CAKE_ACK_FILTER
+	TCA_CAKE_SPLIT_GSO
+	TCA_CAKE_FWMARK
+	TCA_CAKE_MAX = TCA_CAKE_FWMARK
+)
+
+const (
+	TC_QOPT_BITMASK = 15
+)
+
+const (
+	TCA_MQPRIO_UNSPEC = iota
+	TCA_MQPRIO_MODE
+	TCA_MQPRIO_SHAPER
+	TCA_MQPRIO_MIN_RATE64
+	TCA_MQPRIO_MAX_RATE64
+	TCA_MQPRIO_MAX = TCA_MQPRIO_MAX_RATE64
+)
+
+// struct tc_mqprio_qopt {
+// 	__u8	num_tc;
+// 	__u8	prio_tc_map[TC_QOPT_BITMASK + 1];
+// 	__u8	hw;
+// 	__u16	count[TC_QOPT_MAX_QUEUE];
+// 	__u16	offset[TC_QOPT_MAX_QUEUE];
+// };
+
+type TcMqprioQopt struct {
+	NumTc     uint8
+	PrioTcMap [TC_QOPT_BITMASK + 1]uint8
+	Hw        uint8
+	Count     [TC_QOPT_MAX_QUEUE]uint16
+	Offset    [TC_QOPT_MAX_QUEUE]uint16
+}
+
+func (x *TcMqprioQopt) Len() int {
+	return SizeofTcMqprioQopt
+}
+
+func DeserializeTcMqprioQopt(b []byte) *TcMqprioQopt {
+	return (*TcMqprioQopt)(unsafe.Pointer(&b[0:SizeofTcMqprioQopt][0]))
+}
+
+func (x *TcMqprioQopt) Serialize() []byte {
+	return (*(*[SizeofTcMqprioQopt]byte)(unsafe.Pointer(x)))[:]
+}
+
+const (
+	TCA_TAPRIO_ATTR_UNSPEC = iota
+	TCA_TAPRIO_ATTR_PRIOMAP
+	TCA_TAPRIO_ATTR_SCHED_ENTRY_LIST
+	TCA_TAPRIO_ATTR_SCHED_BASE_TIME
+	TCA_TAPRIO_ATTR_SCHED_SINGLE_ENTRY
+	TCA_TAPRIO_ATTR_SCHED_CLOCKID
+	TCA_TAPRIO_PAD
+	TCA_TAPRIO_ATTR_ADMIN_SCHED
+	TCA_TAPRIO_ATTR_SCHED_CYCLE_TIME
+	TCA_TAPRIO_ATTR_SCHED_CYCLE_TIME_EXTENSION
+	TCA_TAPRIO_ATTR_FLAGS
+	TCA_TAPRIO_ATTR_TXTIME_DELAY
+	TCA_TAPRIO_ATTR_MAX = TCA_TAPRIO_ATTR_TXTIME_DELAY
+)
+
+const (
+	TCA_TAPRIO_SCHED_UNSPEC = iota
+	TCA_TAPRIO_SCHED_ENTRY
+)
+
+const (
+	TCA_TAPRIO_SCHED_ENTRY_UNSPEC = iota
+	TCA_TAPRIO_SCHED_ENTRY_INDEX
+	TCA_TAPRIO_SCHED_ENTRY_CMD
+	TCA_TAPRIO_SCHED_ENTRY_GATE_MASK
+	TCA_TAPRIO_SCHED_ENTRY_INTERVAL
+)
+
+const (
+	TCA_ETF_UNSPEC = iota
+	TCA_ETF_PARMS
+	TCA_ETF_MAX = TCA_ETF_PARMS
+)
+
+const (
+	TC_ETF_DEADLINE_MODE_ON = 1 << iota
+	TC_ETF_OFFLOAD_ON
+	TC_ETF_SKIP_SOCK_CHECK
+)
+
+// struct tc_etf_qopt {
+// 	__s32 delta;
+// 	__s32 clockid;
+// 	__u32 flags;
+// };
+
+type TcEtfQopt struct {
+	Delta   int32
+	ClockID int32
+	Flags   uint32
+}
+
+func (x *TcEtfQopt) Len() int {
+	return SizeofTcEtfQopt
+}
+
+func DeserializeTcEtfQopt(b []byte) *TcEtfQopt {
+	return (*TcEtfQopt)(unsafe.Pointer(&b[0:SizeofTcEtfQopt][0]))
+}
+
+func (x *TcEtfQopt) Serialize() []byte {
+	return (*(*[SizeofTcEtfQopt]byte)(unsafe.Pointer(x)))[:]
+}
+
+const (
+	TCA_CBS_UNSPEC = iota
+	TCA_CBS_PARMS
+	TCA_CBS_MAX = TCA_CBS_PARMS
+)
+
+// struct tc_cbs_qopt {
+// 	__u8 offload;
+// 	__u8 _pad[3];
+// 	__s32 hicredit;
+// 	__s32 locredit;
+// 	__s32 idleslope;
+// 	__s32 sendslope;
+// };
+
+type TcCbsQopt struct {
+	Offload   uint8
+	Pad       [3]uint8
+	HiCredit  int32
+	LoCredit  int32
+	IdleSlope int32
+	SendSlope int32
+}
+
+func (x *TcCbsQopt) Len() int {
+	return SizeofTcCbsQopt
+}
+
+func DeserializeTcCbsQopt(b []byte) *TcCbsQopt {
+	return (*TcCbsQopt)(unsafe.Pointer(&b[0:SizeofTcCbsQopt][0]))
+}
+
+func (x *TcCbsQopt) Serialize() []byte {
+	return (*(*[SizeofTcCbsQopt]byte)(unsafe.Pointer(x)))[:]
+}
+
+const (
+	TCA_RED_UNSPEC = iota
+	TCA_RED_PARMS
+	TCA_RED_STAB
+	TCA_RED_MAX_P
+	TCA_RED_FLAGS
+	TCA_RED_EARLY_DROP_BLOCK
+	TCA_RED_MARK_BLOCK
+	TCA_RED_MAX = TCA_RED_MARK_BLOCK
+)
+
+const (
+	TC_RED_ECN = 1 << iota
+	TC_RED_HARDDROP
+	TC_RED_ADAPTATIVE
+	TC_RED_NODROP
+)
+
+const (
+	TC_RED_HISTORIC_FLAGS  = TC_RED_ECN | TC_RED_HARDDROP | TC_RED_ADAPTATIVE
+	TC_RED_SUPPORTED_FLAGS = TC_RED_HISTORIC_FLAGS | TC_RED_NODROP
+)
+
+// The idle damping table (TCA_*_STAB) of the RED family of qdiscs
+const RED_STAB_SIZE = 256
+
+// struct tc_red_qopt {
+// 	__u32		limit;		/* HARD maximal queue length (bytes)	*/
+// 	__u32		qth_min;	/* Min average length threshold (bytes) */
+// 	__u32		qth_max;	/* Max average length threshold (bytes) */
+// 	unsigned char   Wlog;		/* log(W)		*/
+// 	unsigned char   Plog;		/* log(P_max/(qth_max-qth_min))	*/
+// 	unsigned char   Scell_log;	/* cell size for idle damping */
+// 	unsigned char	flags;
+// };
+//
+// struct tc_choke_qopt shares the same layout.
+
+type TcRedQopt struct {
+	Limit    uint32
+	QthMin   uint32
+	QthMax   uint32
+	Wlog     uint8
+	Plog     uint8
+	ScellLog uint8
+	Flags    uint8
+}
+
+func (x *TcRedQopt) Len() int {
+	return SizeofTcRedQopt
+}
+
+func DeserializeTcRedQopt(b []byte) *TcRedQopt {
+	return (*TcRedQopt)(unsafe.Pointer(&b[0:SizeofTcRedQopt][0]))
+}
+
+func (x *TcRedQopt) Serialize() []byte {
+	return (*(*[SizeofTcRedQopt]byte)(unsafe.Pointer(x)))[:]
+}
+
+const (
+	TCA_GRED_UNSPEC = iota
+	TCA_GRED_PARMS
+	TCA_GRED_STAB
+	TCA_GRED_DPS
+	TCA_GRED_MAX_P
+	TCA_GRED_LIMIT
+	TCA_GRED_VQ_LIST
+	TCA_GRED_MAX = TCA_GRED_VQ_LIST
+)
+
+const MAX_DPs = 16
+
+// struct tc_gred_qopt {
+// 	__u32		limit;        /* HARD maximal queue length (bytes)    */
+// 	__u32		qth_min;      /* Min average length threshold (bytes) */
+// 	__u32		qth_max;      /* Max average length threshold (bytes) */
+// 	__u32		DP;           /* up to 2^32 DPs */
+// 	__u32		backlog;
+// 	__u32		qave;
+// 	__u32		forced;
+// 	__u32		early;
+// 	__u32		other;
+// 	__u32		pdrop;
+// 	__u8		Wlog;         /* log(W)               */
+// 	__u8		Plog;         /* log(P_max/(qth_max-qth_min)) */
+// 	__u8		Scell_log;    /* cell size for idle damping */
+// 	__u8		prio;         /* prio of this VQ */
+// 	__u32		packets;
+// 	__u32		bytesin;
+// };
+
+type TcGredQopt struct {
+	Limit    uint32
+	QthMin   uint32
+	QthMax   uint32
+	DP       uint32
+	Backlog  uint32
+	Qave     uint32
+	Forced   uint32
+	Early    uint32
+	Other    uint32
+	Pdrop    uint32
+	Wlog     uint8
+	Plog     uint8
+	ScellLog uint8
+	Prio     uint8
+	Packets  uint32
+	Bytesin  uint32
+}
+
+func (x *TcGredQopt) Len() int {
+	return SizeofTcGredQopt
+}
+
+func DeserializeTcGredQopt(b []byte) *TcGredQopt {
+	return (*TcGredQopt)(unsafe.Pointer(&b[0:SizeofTcGredQopt][0]))
+}
+
+func (x *TcGredQopt) Serialize() []byte {
+	return (*(*[SizeofTcGredQopt]byte)(unsafe.Pointer(x)))[:]
+}
+
+// struct tc_gred_sopt {
+// 	__u32		DPs;
+// 	__u32		def_DP;
+// 	__u8		grio;
+// 	__u8		flags;
+// 	__u16		pad1;
+// };
+
+type TcGredSopt struct {
+	DPs   uint32
+	DefDP uint32
+	Grio  uint8
+	Flags uint8
+	Pad   uint16
+}
+
+func (x *TcGredSopt) Len() int {
+	return SizeofTcGredSopt
+}
+
+func DeserializeTcGredSopt(b []byte) *TcGredSopt {
+	return (*TcGredSopt)(unsafe.Pointer(&b[0:SizeofTcGredSopt][0]))
+}
+
+func (x *TcGredSopt) Serialize() []byte {
+	return (*(*[SizeofTcGredSopt]byte)(unsafe.Pointer(x)))[:]
+}
+
+const (
+	TCA_PIE_UNSPEC = iota
+	TCA_PIE_TARGET
+	TCA_PIE_LIMIT
+	TCA_PIE_TUPDATE
+	TCA_PIE_ALPHA
+	TCA_PIE_BETA
+	TCA_PIE_ECN
+	TCA_PIE_BYTEMODE
+	TCA_PIE_DQ_RATE_ESTIMATOR
+	TCA_PIE_MAX = TCA_PIE_DQ_RATE_ESTIMATOR
+)
+
+const (
+	TCA_CODEL_UNSPEC = iota
+	TCA_CODEL_TARGET
+	TCA_CODEL_LIMIT
+	TCA_CODEL_INTERVAL
+	TCA_CODEL_ECN
+	TCA_CODEL_CE_THRESHOLD
+	TCA_CODEL_MAX = TCA_CODEL_CE_THRESHOLD
+)
+
+const (
+	TCA_CHOKE_UNSPEC = iota
+	TCA_CHOKE_PARMS
+	TCA_CHOKE_STAB
+	TCA_CHOKE_MAX_P
+	TCA_CHOKE_MAX = TCA_CHOKE_MAX_P
+)
+
+const (
+	TCA_DRR_UNSPEC = iota
+	TCA_DRR_QUANTUM
+	TCA_DRR_MAX = TCA_DRR_QUANTUM
+)
+
+const (
+	TCA_QFQ_UNSPEC = iota
+	TCA_QFQ_WEIGHT
+	TCA_QFQ_LMAX
+	TCA_QFQ_MAX = TCA_QFQ_LMAX
+)
+
+// struct tc_multiq_qopt {
+// 	__u16	bands;			/* Number of bands */
+// 	__u16	max_bands;		/* Maximum number of queues */
+// };
+
+type TcMultiqQopt struct {
+	Bands    uint16
+	MaxBands uint16
+}
+
+func (x *TcMultiqQopt) Len() int {
+	return SizeofTcMultiqQopt
+}
+
+func DeserializeTcMultiqQopt(b []byte) *TcMultiqQopt {
+	return (*TcMultiqQopt)(unsafe.Pointer(&b[0:SizeofTcMultiqQopt][0]))
+}
+
+func (x *TcMultiqQopt) Serialize() []byte {
+	return (*(*[SizeofTcMultiqQopt]byte)(unsafe.Pointer(x)))[:]
+}
@@ -171,3 +171,359 @@ func TestTcHtbCoptDeserializeSerialize(t *testing.T) {
 	msg := DeserializeTcHtbCopt(orig)
 	testDeserializeSerialize(t, orig, safemsg, msg)
 }
+
+/* TcMqprioQopt */
+func (msg *TcMqprioQopt) write(b []byte) {
+	native := NativeEndian()
+	b[0] = msg.NumTc
+	copy(b[1:17], msg.PrioTcMap[:])
+	b[17] = msg.Hw
+	start := 18
+	for _, count := range msg.Count {
+		native.PutUint16(b[start:start+2], count)
+		start += 2
+	}
+	for _, offset := range msg.Offset {
+		native.PutUint16(b[start:start+2], offset)
+		start += 2
+	}
+}
+
+func (msg *TcMqprioQopt) serializeSafe() []byte {
+	length := SizeofTcMqprioQopt
+	b := make([]byte, length)
+	msg.write(b)
+	return b
+}
+
+func deserializeTcMqprioQoptSafe(b []byte) *TcMqprioQopt {
+	var msg = TcMqprioQopt{}
+	binary.Read(bytes.NewReader(b[0:SizeofTcMqprioQopt]), NativeEndian(), &msg)
+	return &msg
+}
+
+func TestTcMqprioQoptDeserializeSerialize(t *testing.T) {
+	var orig = make([]byte, SizeofTcMqprioQopt)
+	rand.Read(orig)
+	safemsg := deserializeTcMqprioQoptSafe(orig)
+	msg := DeserializeTcMqprioQopt(orig)
+	testDeserializeSerialize(t, orig, safemsg, msg)
+}
+
+/* TcEtfQopt */
+func (msg *TcEtfQopt) write(b []byte) {
+	native := NativeEndian()
+	native.PutUint32(b[0:4], uint32(msg.Delta))
+	native.PutUint32(b[4:8], uint32(msg.ClockID))
+	native.PutUint32(b[8:12], msg.Flags)
+}
+
+func (msg *TcEtfQopt) serializeSafe() []byte {
+	length := SizeofTcEtfQopt
+	b := make([]byte, length)
+	msg.write(b)
+	return b
+}
+
+func deserializeTcEtfQoptSafe(b []byte) *TcEtfQopt {
+	var msg = TcEtfQopt{}
+	binary.Read(bytes.NewReader(b[0:SizeofTcEtfQopt]), NativeEndian(), &msg)
+	return &msg
+}
+
+func TestTcEtfQoptDeserializeSerialize(t *testing.T) {
+	var orig = make([]byte, SizeofTcEtfQopt)
+	rand.Read(orig)
+	safemsg := deserializeTcEtfQoptSafe(orig)
+	msg := DeserializeTcEtfQopt(orig)
+	testDeserializeSerialize(t, orig, safemsg, msg)
+}
+
+/* TcCbsQopt */
+func (msg *TcCbsQopt) write(b []byte) {
+	native := NativeEndian()
+	b[0] = msg.Offload
+	copy(b[1:4], msg.Pad[:])
+	native.PutUint32(b[4:8], uint32(msg.HiCredit))
+	native.PutUint32(b[8:12], uint32(msg.LoCredit))
+	native.PutUint32(b[12:16], uint32(msg.IdleSlope))
+	native.PutUint32(b[16:20], uint32(msg.SendSlope))
+}
+
+func (msg *TcCbsQopt) serializeSafe() []byte {
+	length := SizeofTcCbsQopt
+	b := make([]byte, length)
+	msg.write(b)
+	return b
+}
+
+func deserializeTcCbsQoptSafe(b []byte) *TcCbsQopt {
+	var msg = TcCbsQopt{}
+	binary.Read(bytes.NewReader(b[0:SizeofTcCbsQopt]), NativeEndian(), &msg)
+	return &msg
+}
+
+func TestTcCbsQoptDeserializeSerialize(t *testing.T) {
+	var orig = make([]byte, SizeofTcCbsQopt)
+	rand.Read(orig)
+	safemsg := deserializeTcCbsQoptSafe(orig)
+	msg := DeserializeTcCbsQopt(orig)
+	testDeserializeSerialize(t, orig, safemsg, msg)
+}
+
+/* TcRedQopt */
+func (msg *TcRedQopt) write(b []byte) {
+	native := NativeEndian()
+	native.PutUint32(b[0:4], msg.Limit)
+	native.PutUint32(b[4:8], msg.QthMin)
+	native.PutUint32(b[8:12], msg.QthMax)
+	b[12] = msg.Wlog
+	b[13] = msg.Plog
+	b[14] = msg.ScellLog
+	b[15] = msg.Flags
+}
+
+func (msg *TcRedQopt) serializeSafe() []byte {
+	length := SizeofTcRedQopt
+	b := make([]byte, length)
+	msg.write(b)
+	return b
+}
+
+func deserializeTcRedQoptSafe(b []byte) *TcRedQopt {
+	var msg = TcRedQopt{}
+	binary.Read(bytes.NewReader(b[0:SizeofTcRedQopt]), NativeEndian(), &msg)
+	return &msg
+}
+
+func TestTcRedQoptDeserializeSerialize(t *testing.T) {
+	var orig = make([]byte, SizeofTcRedQopt)
+	rand.Read(orig)
+	safemsg := deserializeTcRedQoptSafe(orig)
+	msg := DeserializeTcRedQopt(orig)
+	testDeserializeSerialize(t, orig, safemsg, msg)
+}
+
+/* TcGredQopt */
+func (msg *TcGredQopt) write(b []byte) {
+	native := NativeEndian()
+	native.PutUint32(b[0:4], msg.Limit)
+	native.PutUint32(b[4:8], msg.QthMin)
+	native.PutUint32(b[8:12], msg.QthMax)
+	native.PutUint32(b[12:16], msg.DP)
+	native.PutUint32(b[16:20], msg.Backlog)
+	native.PutUint32(b[20:24], msg.Qave)
+	native.PutUint32(b[24:28], msg.Forced)
+	native.PutUint32(b[28:32], msg.Early)
+	native.PutUint32(b[32:36], msg.Other)
+	native.PutUint32(b[36:40], msg.Pdrop)
+	b[40] = msg.Wlog
+	b[41] = msg.Plog
+	b[42] = msg.ScellLog
+	b[43] = msg.Prio
+	native.PutUint32(b[44:48], msg.Packets)
+	native.PutUint32(b[48:52], msg.Bytesin)
+}
+
+func (msg *TcGredQopt) serializeSafe() []byte {
+	length := SizeofTcGredQopt
+	b := make([]byte, length)
+	msg.write(b)
+	return b
+}
+
+func deserializeTcGredQoptSafe(b []byte) *TcGredQopt {
+	var msg = TcGredQopt{}
+	binary.Read(bytes.NewReader(b[0:SizeofTcGredQopt]), NativeEndian(), &msg)
+	return &msg
+}
+
+func TestTcGredQoptDeserializeSerialize(t *testing.T) {
+	var orig = make([]byte, SizeofTcGredQopt)
+	rand.Read(orig)
+	safemsg := deserializeTcGredQoptSafe(orig)
+	msg := DeserializeTcGredQopt(orig)
+	testDeserializeSerialize(t, orig, safemsg, msg)
+}
+
+/* TcGredSopt */
+func (msg *TcGredSopt) write(b []byte) {
+	native := NativeEndian()
+	native.PutUint32(b[0:4], msg.DPs)
+	native.PutUint32(b[4:8], msg.DefDP)
+	b[8] = msg.Grio
+	b[9] = msg.Flags
+	native.PutUint16(b[10:12], msg.Pad)
+}
+
+func (msg *TcGredSopt) serializeSafe() []byte {
+	length := SizeofTcGredSopt
+	b := make([]byte, length)
+	msg.write(b)
+	return b
+}
+
+func deserializeTcGredSoptSafe(b []byte) *TcGredSopt {
+	var msg = TcGredSopt{}
+	binary.Read(bytes.NewReader(b[0:SizeofTcGredSopt]), NativeEndian(), &msg)
+	return &msg
+}
+
+func TestTcGredSoptDeserializeSerialize(t *testing.T) {
+	var orig = make([]byte, SizeofTcGredSopt)
+	rand.Read(orig)
+	safemsg := deserializeTcGredSoptSafe(orig)
+	msg := DeserializeTcGredSopt(orig)
+	testDeserializeSerialize(t, orig, safemsg, msg)
+}
+
+/* TcMultiqQopt */
+func (msg *TcMultiqQopt) write(b []byte) {
+	native := NativeEndian()
+	native.PutUint16(b[0:2], msg.Bands)
+	native.PutUint16(b[2:4], msg.MaxBands)
+}
+
+func (msg *TcMultiqQopt) serializeSafe() []byte {
+	length := SizeofTcMultiqQopt
+	b := make([]byte, length)
+	msg.write(b)
+	return b
+}
+
+func deserializeTcMultiqQoptSafe(b []byte) *TcMultiqQopt {
+	var msg = TcMultiqQopt{}
+	binary.Read(bytes.NewReader(b[0:SizeofTcMultiqQopt]), NativeEndian(), &msg)
+	return &msg
+}
+
+func TestTcMultiqQoptDeserializeSerialize(t *testing.T) {
+	var orig = make([]byte, SizeofTcMultiqQopt)
+	rand.Read(orig)
+	safemsg := deserializeTcMultiqQoptSafe(orig)
+	msg := DeserializeTcMultiqQopt(orig)
+	testDeserializeSerialize(t, orig, safemsg, msg)
+}
+
+/* TcNetemRate */
+func (msg *TcNetemRate) write(b []byte) {
+	native := NativeEndian()
+	native.PutUint32(b[0:4], msg.Rate)
+	native.PutUint32(b[4:8], uint32(msg.PacketOverhead))
+	native.PutUint32(b[8:12], msg.CellSize)
+	native.PutUint32(b[12:16], uint32(msg.CellOverhead))
+}
+
+func (msg *TcNetemRate) serializeSafe() []byte {
+	length := SizeofTcNetemRate
+	b := make([]byte, length)
+	msg.write(b)
+	return b
+}
+
+func deserializeTcNetemRateSafe(b []byte) *TcNetemRate {
+	var msg = TcNetemRate{}
+	binary.Read(bytes.NewReader(b[0:SizeofTcNetemRate]), NativeEndian(), &msg)
+	return &msg
+}
+
+func TestTcNetemRateDeserializeSerialize(t *testing.T) {
+	var orig = make([]byte, SizeofTcNetemRate)
+	rand.Read(orig)
+	safemsg := deserializeTcNetemRateSafe(orig)
+	msg := DeserializeTcNetemRate(orig)
+	testDeserializeSerialize(t, orig, safemsg, msg)
+}
+
+/* TcNetemGimodel */
+func (msg *TcNetemGimodel) write(b []byte) {
+	native := NativeEndian()
+	native.PutUint32(b[0:4], msg.P13)
+	native.PutUint32(b[4:8], msg.P31)
+	native.PutUint32(b[8:12], msg.P32)
+	native.PutUint32(b[12:16], msg.P14)
+	native.PutUint32(b[16:20], msg.P23)
+}
+
+func (msg *TcNetemGimodel) serializeSafe() []byte {
+	length := SizeofTcNetemGimodel
+	b := make([]byte, length)
+	msg.write(b)
+	return b
+}
+
+func deserializeTcNetemGimodelSafe(b []byte) *TcNetemGimodel {
+	var msg = TcNetemGimodel{}
+	binary.Read(bytes.NewReader(b[0:SizeofTcNetemGimodel]), NativeEndian(), &msg)
+	return &msg
+}
+
+func TestTcNetemGimodelDeserializeSerialize(t *testing.T) {
+	var orig = make([]byte, SizeofTcNetemGimodel)
+	rand.Read(orig)
+	safemsg := deserializeTcNetemGimodelSafe(orig)
+	msg := DeserializeTcNetemGimodel(orig)
+	testDeserializeSerialize(t, orig, safemsg, msg)
+}
+
+/* TcNetemGemodel */
+func (msg *TcNetemGemodel) write(b []byte) {
+	native := NativeEndian()
+	native.PutUint32(b[0:4], msg.P)
+	native.PutUint32(b[4:8], msg.R)
+	native.PutUint32(b[8:12], msg.H)
+	native.PutUint32(b[12:16], msg.K1)
+}
+
+func (msg *TcNetemGemodel) serializeSafe() []byte {
+	length := SizeofTcNetemGemodel
+	b := make([]byte, length)
+	msg.write(b)
+	return b
+}
+
+func deserializeTcNetemGemodelSafe(b []byte) *TcNetemGemodel {
+	var msg = TcNetemGemodel{}
+	binary.Read(bytes.NewReader(b[0:SizeofTcNetemGemodel]), NativeEndian(), &msg)
+	return &msg
+}
+
+func TestTcNetemGemodelDeserializeSerialize(t *testing.T) {
+	var orig = make([]byte, SizeofTcNetemGemodel)
+	rand.Read(orig)
+	safemsg := deserializeTcNetemGemodelSafe(orig)
+	msg := DeserializeTcNetemGemodel(orig)
+	testDeserializeSerialize(t, orig, safemsg, msg)
+}
+
+/* TcNetemSlot */
+func (msg *TcNetemSlot) write(b []byte) {
+	native := NativeEndian()
+	native.PutUint64(b[0:8], uint64(msg.MinDelay))
+	native.PutUint64(b[8:16], uint64(msg.MaxDelay))
+	native.PutUint32(b[16:20], uint32(msg.MaxPackets))
+	native.PutUint32(b[20:24], uint32(msg.MaxBytes))
+	native.PutUint64(b[24:32], uint64(msg.DistDelay))
+	native.PutUint64(b[32:40], uint64(msg.DistJitter))
+}
+
+func (msg *TcNetemSlot) serializeSafe() []byte {
+	length := SizeofTcNetemSlot
+	b := make([]byte, length)
+	msg.write(b)
+	return b
+}
+
+func deserializeTcNetemSlotSafe(b []byte) *TcNetemSlot {
+	var msg = TcNetemSlot{}
+	binary.Read(bytes.NewReader(b[0:SizeofTcNetemSlot]), NativeEndian(), &msg)
+	return &msg
+}
+
+func TestTcNetemSlotDeserializeSerialize(t *testing.T) {
+	var orig = make([]byte, SizeofTcNetemSlot)
+	rand.Read(orig)
+	safemsg := deserializeTcNetemSlotSafe(orig)
+	msg := DeserializeTcNetemSlot(orig)
+	testDeserializeSerialize(t, orig, safemsg, msg)
+}
@@ -88,6 +88,26 @@ const (
 
 const XFRMA_OUTPUT_MARK = XFRMA_SET_MARK
 
+// SAD info attribute types
+const (
+	XFRMA_SAD_UNSPEC = iota
+	XFRMA_SAD_CNT    /* __u32 */
+	XFRMA_SAD_HINFO  /* struct xfrmu_sadhinfo */
+
+	XFRMA_SAD_MAX = iota - 1
+)
+
+// SPD info attribute types
+const (
+	XFRMA_SPD_UNSPEC       = iota
+	XFRMA_SPD_INFO         /* struct xfrmu_spdinfo */
+	XFRMA_SPD_HINFO        /* struct xfrmu_spdhinfo */
+	XFRMA_SPD_IPV4_HTHRESH /* struct xfrmu_spdhthresh */
+	XFRMA_SPD_IPV6_HTHRESH /* struct xfrmu_spdhthresh */
+
+	XFRMA_SPD_MAX = iota - 1
+)
+
 const (
 	SizeofXfrmAddress     = 0x10
 	SizeofXfrmSelector    = 0x38
@@ -95,6 +115,28 @@ const (
 	SizeofXfrmLifetimeCur = 0x20
 	SizeofXfrmId          = 0x18
 	SizeofXfrmMark        = 0x08
+	SizeofXfrmuSpdHthresh = 0x02
+	SizeofXfrmUserSecCtx  = 0x08
+)
+
+// Security context domains of interpretation and algorithms
+const (
+	XFRM_SC_DOI_RESERVED = 0x0
+	XFRM_SC_DOI_LSM      = 0x1
+	XFRM_SC_ALG_RESERVED = 0x0
+	XFRM_SC_ALG_SELINUX  = 0x1
+)
+
+// Async event flags
+const (
+	XFRM_AE_UNSPEC = 0x0
+	XFRM_AE_RTHR   = 0x1  /* replay threshold*/
+	XFRM_AE_RVAL   = 0x2  /* replay value */
+	XFRM_AE_LVAL   = 0x4  /* lifetime value */
+	XFRM_AE_ETHR   = 0x8  /* expiry timer threshold */
+	XFRM_AE_CR     = 0x10 /* Event cause is replay update */
+	XFRM_AE_CE     = 0x20 /* Event cause is timer expiry */
+	XFRM_AE_CU     = 0x40 /* Event cause is policy update */
 )
 
 // Netlink groups
@@ -304,3 +346,53 @@ func DeserializeXfrmMark(b []byte) *XfrmMark {
 func (msg *XfrmMark) Serialize() []byte {
 	return (*(*[SizeofXfrmMark]byte)(unsafe.Pointer(msg)))[:]
 }
+
+// struct xfrmu_spdhthresh {
+//   __u8 lbits;
+//   __u8 rbits;
+// };
+
+type XfrmuSpdHthresh struct {
+	Lbits uint8
+	Rbits uint8
+}
+
+func (msg *XfrmuSpdHthresh) Len() int {
+	return SizeofXfrmuSpdHthresh
+}
+
+func DeserializeXfrmuSpdHthresh(b []byte) *XfrmuSpdHthresh {
+	return (*XfrmuSpdHthresh)(unsafe.Pointer(&b[0:SizeofXfrmuSpdHthresh][0]))
+}
+
+func (msg *XfrmuSpdHthresh) Serialize() []byte {
+	return (*(*[SizeofXfrmuSpdHthresh]byte)(unsafe.Pointer(msg)))[:]
+}
+
+// struct xfrm_user_sec_ctx {
+//   __u16     len;
+//   __u16     exttype;
+//   __u8      ctx_alg;  /* LSMs: e.g., selinux == 1 */
+//   __u8      ctx_doi;
+//   __u16     ctx_len;
+// };
+
+type XfrmUserSecCtx struct {
+	Length  uint16
+	Exttype uint16
+	CtxAlg  uint8
+	CtxDoi  uint8
+	CtxLen  uint16
+}
+
+func (msg *XfrmUserSecCtx) Len() int {
+	return SizeofXfrmUserSecCtx
+}
+
+func DeserializeXfrmUserSecCtx(b []byte) *XfrmUserSecCtx {
+	return (*XfrmUserSecCtx)(unsafe.Pointer(&b[0:SizeofXfrmUserSecCtx][0]))
+}
+
+func (msg *XfrmUserSecCtx) Serialize() []byte {
+	return (*(*[SizeofXfrmUserSecCtx]byte)(unsafe.Pointer(msg)))[:]
+}
@@ -159,3 +159,57 @@ func TestXfrmIdDeserializeSerialize(t *testing.T) {
 	msg := DeserializeXfrmId(orig)
 	testDeserializeSerialize(t, orig, safemsg, msg)
 }
+
+func (msg *XfrmuSpdHthresh) write(b []byte) {
+	b[0] = msg.Lbits
+	b[1] = msg.Rbits
+}
+
+func (msg *XfrmuSpdHthresh) serializeSafe() []byte {
+	b := make([]byte, SizeofXfrmuSpdHthresh)
+	msg.write(b)
+	return b
+}
+
+func deserializeXfrmuSpdHthreshSafe(b []byte) *XfrmuSpdHthresh {
+	var msg = XfrmuSpdHthresh{}
+	binary.Read(bytes.NewReader(b[0:SizeofXfrmuSpdHthresh]), NativeEndian(), &msg)
+	return &msg
+}
+
+func TestXfrmuSpdHthreshDeserializeSerialize(t *testing.T) {
+	var orig = make([]byte, SizeofXfrmuSpdHthresh)
+	rand.Read(orig)
+	safemsg := deserializeXfrmuSpdHthreshSafe(orig)
+	msg := DeserializeXfrmuSpdHthresh(orig)
+	testDeserializeSerialize(t, orig, safemsg, msg)
+}
+
+func (msg *XfrmUserSecCtx) write(b []byte) {
+	native := NativeEndian()
+	native.PutUint16(b[0:2], msg.Length)
+	native.PutUint16(b[2:4], msg.Exttype)
+	b[4] = msg.CtxAlg
+	b[5] = msg.CtxDoi
+	native.PutUint16(b[6:8], msg.CtxLen)
+}
+
+func (msg *XfrmUserSecCtx) serializeSafe() []byte {
+	b := make([]byte, SizeofXfrmUserSecCtx)
+	msg.write(b)
+	return b
+}
+
+func deserializeXfrmUserSecCtxSafe(b []byte) *XfrmUserSecCtx {
+	var msg = XfrmUserSecCtx{}
+	binary.Read(bytes.NewReader(b[0:SizeofXfrmUserSecCtx]), NativeEndian(), &msg)
+	return &msg
+}
+
+func TestXfrmUserSecCtxDeserializeSerialize(t *testing.T) {
+	var orig = make([]byte, SizeofXfrmUserSecCtx)
+	rand.Read(orig)
+	safemsg := deserializeXfrmUserSecCtxSafe(orig)
+	msg := DeserializeXfrmUserSecCtx(orig)
+	testDeserializeSerialize(t, orig, safemsg, msg)
+}
@@ -5,7 +5,11 @@ import (
 )
 
 const (
-	SizeofXfrmUserExpire = 0xe8
+	SizeofXfrmUserExpire    = 0xe8
+	SizeofXfrmUserAcquire   = 0x118
+	SizeofXfrmUserPolexpire = 0xb0
+	SizeofXfrmUserReport    = 0x3c
+	SizeofXfrmUserMapping   = 0x40
 )
 
 // struct xfrm_user_expire {
@@ -30,3 +34,113 @@ func DeserializeXfrmUserExpire(b []byte) *XfrmUserExpire {
 func (msg *XfrmUserExpire) Serialize() []byte {
 	return (*(*[SizeofXfrmUserExpire]byte)(unsafe.Pointer(msg)))[:]
 }
+
+// struct xfrm_user_acquire {
+// 	struct xfrm_id			id;
+// 	xfrm_address_t			saddr;
+// 	struct xfrm_selector		sel;
+// 	struct xfrm_userpolicy_info	policy;
+// 	__u32				aalgos;
+// 	__u32				ealgos;
+// 	__u32				calgos;
+// 	__u32				seq;
+// };
+
+type XfrmUserAcquire struct {
+	Id     XfrmId
+	Saddr  XfrmAddress
+	Sel    XfrmSelector
+	Policy XfrmUserpolicyInfo
+	Aalgos uint32
+	Ealgos uint32
+	Calgos uint32
+	Seq    uint32
+}
+
+func (msg *XfrmUserAcquire) Len() int {
+	return SizeofXfrmUserAcquire
+}
+
+func DeserializeXfrmUserAcquire(b []byte) *XfrmUserAcquire {
+	return (*XfrmUserAcquire)(unsafe.Pointer(&b[0:SizeofXfrmUserAcquire][0]))
+}
+
+func (msg *XfrmUserAcquire) Serialize() []byte {
+	return (*(*[SizeofXfrmUserAcquire]byte)(unsafe.Pointer(msg)))[:]
+}
+
+// struct xfrm_user_polexpire {
+// 	struct xfrm_userpolicy_info	pol;
+// 	__u8				hard;
+// };
+
+type XfrmUserPolexpire struct {
+	Pol  XfrmUserpolicyInfo
+	Hard uint8
+	Pad  [7]byte
+}
+
+func (msg *XfrmUserPolexpire) Len() int {
+	return SizeofXfrmUserPolexpire
+}
+
+func DeserializeXfrmUserPolexpire(b []byte) *XfrmUserPolexpire {
+	return (*XfrmUserPolexpire)(unsafe.Pointer(&b[0:SizeofXfrmUserPolexpire][0]))
+}
+
+func (msg *XfrmUserPolexpire) Serialize() []byte {
+	return (*(*[SizeofXfrmUserPolexpire]byte)(unsafe.Pointer(msg)))[:]
+}
+
+// struct xfrm_user_report {
+// 	__u8				proto;
+// 	struct xfrm_selector		sel;
+// };
+
+type XfrmUserReport struct {
+	Proto uint8
+	Pad   [3]byte
+	Sel   XfrmSelector
+}
+
+func (msg *XfrmUserReport) Len() int {
+	return SizeofXfrmUserReport
+}
+
+func DeserializeXfrmUserReport(b []byte) *XfrmUserReport {
+	return (*XfrmUserReport)(unsafe.Pointer(&b[0:SizeofXfrmUserReport][0]))
+}
+
+func (msg *XfrmUserReport) Serialize() []byte {
+	return (*(*[SizeofXfrmUserReport]byte)(unsafe.Pointer(msg)))[:]
+}
+
+// struct xfrm_user_mapping {
+// 	struct xfrm_usersa_id		id;
+// 	__u32				reqid;
+// 	xfrm_address_t			old_saddr;
+// 	xfrm_address_t			new_saddr;
+// 	__be16				old_sport;
+// 	__be16				new_sport;
+// };
+
+type XfrmUserMapping struct {
+	Id       XfrmUsersaId
+	Reqid    uint32
+	OldSaddr XfrmAddress
+	NewSaddr XfrmAddress
+	OldSport uint16 // big endian
+	NewSport uint16 // big endian
+}
+
+func (msg *XfrmUserMapping) Len() int {
+	return SizeofXfrmUserMapping
+}
+
+func DeserializeXfrmUserMapping(b []byte) *XfrmUserMapping {
+	return (*XfrmUserMapping)(unsafe.Pointer(&b[0:SizeofXfrmUserMapping][0]))
+}
+
+func (msg *XfrmUserMapping) Serialize() []byte {
+	return (*(*[SizeofXfrmUserMapping]byte)(unsafe.Pointer(msg)))[:]
+}
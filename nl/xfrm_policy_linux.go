@@ -8,6 +8,9 @@ const (
 	SizeofXfrmUserpolicyId   = 0x40
 	SizeofXfrmUserpolicyInfo = 0xa8
 	SizeofXfrmUserTmpl       = 0x40
+	SizeofXfrmUserMigrate    = 0x4c
+	SizeofXfrmUserKmaddress  = 0x28
+	SizeofXfrmUserpolicyType = 0x06
 )
 
 // struct xfrm_userpolicy_id {
@@ -117,3 +120,94 @@ func DeserializeXfrmUserTmpl(b []byte) *XfrmUserTmpl {
 func (msg *XfrmUserTmpl) Serialize() []byte {
 	return (*(*[SizeofXfrmUserTmpl]byte)(unsafe.Pointer(msg)))[:]
 }
+
+// struct xfrm_user_migrate {
+//   xfrm_address_t      old_daddr;
+//   xfrm_address_t      old_saddr;
+//   xfrm_address_t      new_daddr;
+//   xfrm_address_t      new_saddr;
+//   __u8        proto;
+//   __u8        mode;
+//   __u16       reserved;
+//   __u32       reqid;
+//   __u16       old_family;
+//   __u16       new_family;
+// };
+
+type XfrmUserMigrate struct {
+	OldDaddr  XfrmAddress
+	OldSaddr  XfrmAddress
+	NewDaddr  XfrmAddress
+	NewSaddr  XfrmAddress
+	Proto     uint8
+	Mode      uint8
+	Reserved  uint16
+	Reqid     uint32
+	OldFamily uint16
+	NewFamily uint16
+}
+
+func (msg *XfrmUserMigrate) Len() int {
+	return SizeofXfrmUserMigrate
+}
+
+func DeserializeXfrmUserMigrate(b []byte) *XfrmUserMigrate {
+	return (*XfrmUserMigrate)(unsafe.Pointer(&b[0:SizeofXfrmUserMigrate][0]))
+}
+
+func (msg *XfrmUserMigrate) Serialize() []byte {
+	return (*(*[SizeofXfrmUserMigrate]byte)(unsafe.Pointer(msg)))[:]
+}
+
+// struct xfrm_user_kmaddress {
+//   xfrm_address_t      local;
+//   xfrm_address_t      remote;
+//   __u32       reserved;
+//   __u16       family;
+// };
+
+type XfrmUserKmaddress struct {
+	Local    XfrmAddress
+	Remote   XfrmAddress
+	Reserved uint32
+	Family   uint16
+	Pad      [2]byte
+}
+
+func (msg *XfrmUserKmaddress) Len() int {
+	return SizeofXfrmUserKmaddress
+}
+
+func DeserializeXfrmUserKmaddress(b []byte) *XfrmUserKmaddress {
+	return (*XfrmUserKmaddress)(unsafe.Pointer(&b[0:SizeofXfrmUserKmaddress][0]))
+}
+
+func (msg *XfrmUserKmaddress) Serialize() []byte {
+	return (*(*[SizeofXfrmUserKmaddress]byte)(unsafe.Pointer(msg)))[:]
+}
+
+// struct xfrm_userpolicy_type {
+//   __u8        type;
+//   __u16       reserved1;
+//   __u8        reserved2;
+// };
+
+type XfrmUserpolicyType struct {
+	Type      uint8
+	Pad1      uint8
+	Reserved1 uint16
+	Reserved2 uint8
+	Pad2      uint8
+}
+
+func (msg *XfrmUserpolicyType) Len() int {
+	return SizeofXfrmUserpolicyType
+}
+
+func DeserializeXfrmUserpolicyType(b []byte) *XfrmUserpolicyType {
+	return (*XfrmUserpolicyType)(unsafe.Pointer(&b[0:SizeofXfrmUserpolicyType][0]))
+}
+
+func (msg *XfrmUserpolicyType) Serialize() []byte {
+	return (*(*[SizeofXfrmUserpolicyType]byte)(unsafe.Pointer(msg)))[:]
+}
@@ -107,3 +107,97 @@ func TestXfrmUserTmplDeserializeSerialize(t *testing.T) {
 	msg := DeserializeXfrmUserTmpl(orig)
 	testDeserializeSerialize(t, orig, safemsg, msg)
 }
+
+func (msg *XfrmUserMigrate) write(b []byte) {
+	const AddrsEnd = 4 * SizeofXfrmAddress
+	native := NativeEndian()
+	msg.OldDaddr.write(b[0:SizeofXfrmAddress])
+	msg.OldSaddr.write(b[SizeofXfrmAddress : 2*SizeofXfrmAddress])
+	msg.NewDaddr.write(b[2*SizeofXfrmAddress : 3*SizeofXfrmAddress])
+	msg.NewSaddr.write(b[3*SizeofXfrmAddress : AddrsEnd])
+	b[AddrsEnd] = msg.Proto
+	b[AddrsEnd+1] = msg.Mode
+	native.PutUint16(b[AddrsEnd+2:AddrsEnd+4], msg.Reserved)
+	native.PutUint32(b[AddrsEnd+4:AddrsEnd+8], msg.Reqid)
+	native.PutUint16(b[AddrsEnd+8:AddrsEnd+10], msg.OldFamily)
+	native.PutUint16(b[AddrsEnd+10:AddrsEnd+12], msg.NewFamily)
+}
+
+func (msg *XfrmUserMigrate) serializeSafe() []byte {
+	b := make([]byte, SizeofXfrmUserMigrate)
+	msg.write(b)
+	return b
+}
+
+func deserializeXfrmUserMigrateSafe(b []byte) *XfrmUserMigrate {
+	var msg = XfrmUserMigrate{}
+	binary.Read(bytes.NewReader(b[0:SizeofXfrmUserMigrate]), NativeEndian(), &msg)
+	return &msg
+}
+
+func TestXfrmUserMigrateDeserializeSerialize(t *testing.T) {
+	var orig = make([]byte, SizeofXfrmUserMigrate)
+	rand.Read(orig)
+	safemsg := deserializeXfrmUserMigrateSafe(orig)
+	msg := DeserializeXfrmUserMigrate(orig)
+	testDeserializeSerialize(t, orig, safemsg, msg)
+}
+
+func (msg *XfrmUserKmaddress) write(b []byte) {
+	const AddrsEnd = 2 * SizeofXfrmAddress
+	native := NativeEndian()
+	msg.Local.write(b[0:SizeofXfrmAddress])
+	msg.Remote.write(b[SizeofXfrmAddress:AddrsEnd])
+	native.PutUint32(b[AddrsEnd:AddrsEnd+4], msg.Reserved)
+	native.PutUint16(b[AddrsEnd+4:AddrsEnd+6], msg.Family)
+	copy(b[AddrsEnd+6:AddrsEnd+8], msg.Pad[:])
+}
+
+func (msg *XfrmUserKmaddress) serializeSafe() []byte {
+	b := make([]byte, SizeofXfrmUserKmaddress)
+	msg.write(b)
+	return b
+}
+
+func deserializeXfrmUserKmaddressSafe(b []byte) *XfrmUserKmaddress {
+	var msg = XfrmUserKmaddress{}
+	binary.Read(bytes.NewReader(b[0:SizeofXfrmUserKmaddress]), NativeEndian(), &msg)
+	return &msg
+}
+
+func TestXfrmUserKmaddressDeserializeSerialize(t *testing.T) {
+	var orig = make([]byte, SizeofXfrmUserKmaddress)
+	rand.Read(orig)
+	safemsg := deserializeXfrmUserKmaddressSafe(orig)
+	msg := DeserializeXfrmUserKmaddress(orig)
+	testDeserializeSerialize(t, orig, safemsg, msg)
+}
+
+func (msg *XfrmUserpolicyType) write(b []byte) {
+	native := NativeEndian()
+	b[0] = msg.Type
+	b[1] = msg.Pad1
+	native.PutUint16(b[2:4], msg.Reserved1)
+	b[4] = msg.Reserved2
+	b[5] = msg.Pad2
+}
+
+func (msg *XfrmUserpolicyType) serializeSafe() []byte {
+	b := make([]byte, SizeofXfrmUserpolicyType)
+	msg.write(b)
+	return b
+}
+
+func deserializeXfrmUserpolicyTypeSafe(b []byte) *XfrmUserpolicyType {
+	var msg = XfrmUserpolicyType{}
+	binary.Read(bytes.NewReader(b[0:SizeofXfrmUserpolicyType]), NativeEndian(), &msg)
+	return &msg
+}
+
+func TestXfrmUserpolicyTypeDeserializeSerialize(t *testing.T) {
+	var orig = make([]byte, SizeofXfrmUserpolicyType)
+	rand.Read(orig)
+	safemsg := deserializeXfrmUserpolicyTypeSafe(orig)
+	msg := DeserializeXfrmUserpolicyType(orig)
+	testDeserializeSerialize(t, orig, safemsg, msg)
+}
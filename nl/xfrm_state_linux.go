@@ -15,6 +15,9 @@ const (
 	SizeofXfrmEncapTmpl      = 0x18
 	SizeofXfrmUsersaFlush    = 0x1
 	SizeofXfrmReplayStateEsn = 0x18
+	SizeofXfrmReplayState    = 0x0c
+	SizeofXfrmAeventId       = 0x30
+	SizeofXfrmAddressFilter  = 0x24
 )
 
 const (
@@ -28,6 +31,11 @@ const (
 	XFRM_STATE_ESN        = 128
 )
 
+const (
+	XFRM_SA_XFLAG_DONT_ENCAP_DSCP = 1
+	XFRM_SA_XFLAG_OSEQ_MAY_WRAP   = 2
+)
+
 // struct xfrm_usersa_id {
 //   xfrm_address_t      daddr;
 //   __be32        spi;
@@ -103,6 +111,7 @@ func (msg *XfrmStats) Serialize() []byte {
 // };
 //
 // #define XFRM_SA_XFLAG_DONT_ENCAP_DSCP 1
+// #define XFRM_SA_XFLAG_OSEQ_MAY_WRAP 2
 //
 
 type XfrmUsersaInfo struct {
@@ -328,7 +337,103 @@ type XfrmReplayStateEsn struct {
 	Bmp          []uint32
 }
 
+// DeserializeXfrmReplayStateEsn copies the replay state and its bitmap
+func DeserializeXfrmReplayStateEsn(b []byte) *XfrmReplayStateEsn {
+	native := NativeEndian()
+	msg := &XfrmReplayStateEsn{
+		BmpLen:       native.Uint32(b[0:4]),
+		OSeq:         native.Uint32(b[4:8]),
+		Seq:          native.Uint32(b[8:12]),
+		OSeqHi:       native.Uint32(b[12:16]),
+		SeqHi:        native.Uint32(b[16:20]),
+		ReplayWindow: native.Uint32(b[20:24]),
+	}
+	bmp := b[SizeofXfrmReplayStateEsn:]
+	for i := 0; i < int(msg.BmpLen) && len(bmp) >= 4*(i+1); i++ {
+		msg.Bmp = append(msg.Bmp, native.Uint32(bmp[4*i:4*(i+1)]))
+	}
+	return msg
+}
+
 func (msg *XfrmReplayStateEsn) Serialize() []byte {
 	// We deliberately do not pass Bmp, as it gets set by the kernel.
 	return (*(*[SizeofXfrmReplayStateEsn]byte)(unsafe.Pointer(msg)))[:]
 }
+
+// struct xfrm_replay_state {
+//     __u32   oseq;
+//     __u32   seq;
+//     __u32   bitmap;
+// };
+
+type XfrmReplayState struct {
+	OSeq   uint32
+	Seq    uint32
+	BitMap uint32
+}
+
+func (msg *XfrmReplayState) Len() int {
+	return SizeofXfrmReplayState
+}
+
+func DeserializeXfrmReplayState(b []byte) *XfrmReplayState {
+	return (*XfrmReplayState)(unsafe.Pointer(&b[0:SizeofXfrmReplayState][0]))
+}
+
+func (msg *XfrmReplayState) Serialize() []byte {
+	return (*(*[SizeofXfrmReplayState]byte)(unsafe.Pointer(msg)))[:]
+}
+
+// struct xfrm_aevent_id {
+//     struct xfrm_usersa_id   sa_id;
+//     xfrm_address_t          saddr;
+//     __u32                   flags;
+//     __u32                   reqid;
+// };
+
+type XfrmAeventId struct {
+	SaId  XfrmUsersaId
+	Saddr XfrmAddress
+	Flags uint32
+	Reqid uint32
+}
+
+func (msg *XfrmAeventId) Len() int {
+	return SizeofXfrmAeventId
+}
+
+func DeserializeXfrmAeventId(b []byte) *XfrmAeventId {
+	return (*XfrmAeventId)(unsafe.Pointer(&b[0:SizeofXfrmAeventId][0]))
+}
+
+func (msg *XfrmAeventId) Serialize() []byte {
+	return (*(*[SizeofXfrmAeventId]byte)(unsafe.Pointer(msg)))[:]
+}
+
+// struct xfrm_address_filter {
+//     xfrm_address_t          saddr;
+//     xfrm_address_t          daddr;
+//     __u16                   family;
+//     __u8                    splen;
+//     __u8                    dplen;
+// };
+
+type XfrmAddressFilter struct {
+	Saddr  XfrmAddress
+	Daddr  XfrmAddress
+	Family uint16
+	Splen  uint8
+	Dplen  uint8
+}
+
+func (msg *XfrmAddressFilter) Len() int {
+	return SizeofXfrmAddressFilter
+}
+
+func DeserializeXfrmAddressFilter(b []byte) *XfrmAddressFilter {
+	return (*XfrmAddressFilter)(unsafe.Pointer(&b[0:SizeofXfrmAddressFilter][0]))
+}
+
+func (msg *XfrmAddressFilter) Serialize() []byte {
+	return (*(*[SizeofXfrmAddressFilter]byte)(unsafe.Pointer(msg)))[:]
+}
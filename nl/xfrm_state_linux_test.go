@@ -302,3 +302,104 @@ func TestXfrmXfrmAlgoAeadDeserializeSerialize(t *testing.T) {
 	msg := DeserializeXfrmAlgoAEAD(orig)
 	testDeserializeSerialize(t, orig, safemsg, msg)
 }
+
+func (msg *XfrmReplayState) write(b []byte) {
+	native := NativeEndian()
+	native.PutUint32(b[0:4], msg.OSeq)
+	native.PutUint32(b[4:8], msg.Seq)
+	native.PutUint32(b[8:12], msg.BitMap)
+}
+
+func (msg *XfrmReplayState) serializeSafe() []byte {
+	b := make([]byte, SizeofXfrmReplayState)
+	msg.write(b)
+	return b
+}
+
+func deserializeXfrmReplayStateSafe(b []byte) *XfrmReplayState {
+	var msg = XfrmReplayState{}
+	binary.Read(bytes.NewReader(b[0:SizeofXfrmReplayState]), NativeEndian(), &msg)
+	return &msg
+}
+
+func TestXfrmReplayStateDeserializeSerialize(t *testing.T) {
+	var orig = make([]byte, SizeofXfrmReplayState)
+	rand.Read(orig)
+	safemsg := deserializeXfrmReplayStateSafe(orig)
+	msg := DeserializeXfrmReplayState(orig)
+	testDeserializeSerialize(t, orig, safemsg, msg)
+}
+
+func (msg *XfrmAeventId) write(b []byte) {
+	const SaddrEnd = SizeofXfrmUsersaId + SizeofXfrmAddress
+	native := NativeEndian()
+	msg.SaId.write(b[0:SizeofXfrmUsersaId])
+	msg.Saddr.write(b[SizeofXfrmUsersaId:SaddrEnd])
+	native.PutUint32(b[SaddrEnd:SaddrEnd+4], msg.Flags)
+	native.PutUint32(b[SaddrEnd+4:SaddrEnd+8], msg.Reqid)
+}
+
+func (msg *XfrmAeventId) serializeSafe() []byte {
+	b := make([]byte, SizeofXfrmAeventId)
+	msg.write(b)
+	return b
+}
+
+func deserializeXfrmAeventIdSafe(b []byte) *XfrmAeventId {
+	var msg = XfrmAeventId{}
+	binary.Read(bytes.NewReader(b[0:SizeofXfrmAeventId]), NativeEndian(), &msg)
+	return &msg
+}
+
+func TestXfrmAeventIdDeserializeSerialize(t *testing.T) {
+	var orig = make([]byte, SizeofXfrmAeventId)
+	rand.Read(orig)
+	safemsg := deserializeXfrmAeventIdSafe(orig)
+	msg := DeserializeXfrmAeventId(orig)
+	testDeserializeSerialize(t, orig, safemsg, msg)
+}
+
+func TestXfrmReplayStateEsnDeserialize(t *testing.T) {
+	native := NativeEndian()
+	b := make([]byte, SizeofXfrmReplayStateEsn+8)
+	for i, v := range []uint32{2, 10, 20, 1, 2, 64, 0xf0f0f0f0, 0x0f0f0f0f} {
+		native.PutUint32(b[4*i:4*(i+1)], v)
+	}
+	msg := DeserializeXfrmReplayStateEsn(b)
+	if msg.BmpLen != 2 || msg.OSeq != 10 || msg.Seq != 20 || msg.OSeqHi != 1 || msg.SeqHi != 2 || msg.ReplayWindow != 64 {
+		t.Fatalf("unexpected replay state: %+v", msg)
+	}
+	if len(msg.Bmp) != 2 || msg.Bmp[0] != 0xf0f0f0f0 || msg.Bmp[1] != 0x0f0f0f0f {
+		t.Fatalf("unexpected bitmap: %x", msg.Bmp)
+	}
+}
+
+func (msg *XfrmAddressFilter) write(b []byte) {
+	const AddrsEnd = 2 * SizeofXfrmAddress
+	native := NativeEndian()
+	msg.Saddr.write(b[0:SizeofXfrmAddress])
+	msg.Daddr.write(b[SizeofXfrmAddress:AddrsEnd])
+	native.PutUint16(b[AddrsEnd:AddrsEnd+2], msg.Family)
+	b[AddrsEnd+2] = msg.Splen
+	b[AddrsEnd+3] = msg.Dplen
+}
+
+func (msg *XfrmAddressFilter) serializeSafe() []byte {
+	b := make([]byte, SizeofXfrmAddressFilter)
+	msg.write(b)
+	return b
+}
+
+func deserializeXfrmAddressFilterSafe(b []byte) *XfrmAddressFilter {
+	var msg = XfrmAddressFilter{}
+	binary.Read(bytes.NewReader(b[0:SizeofXfrmAddressFilter]), NativeEndian(), &msg)
+	return &msg
+}
+
+func TestXfrmAddressFilterDeserializeSerialize(t *testing.T) {
+	var orig = make([]byte, SizeofXfrmAddressFilter)
+	rand.Read(orig)
+	safemsg := deserializeXfrmAddressFilterSafe(orig)
+	msg := DeserializeXfrmAddressFilter(orig)
+	testDeserializeSerialize(t, orig, safemsg, msg)
+}
@@ -6,14 +6,26 @@ import (
 
 // Protinfo represents bridge flags from netlink.
 type Protinfo struct {
-	Hairpin      bool
-	Guard        bool
-	FastLeave    bool
-	RootBlock    bool
-	Learning     bool
-	Flood        bool
-	ProxyArp     bool
-	ProxyArpWiFi bool
+	Hairpin       bool
+	Guard         bool
+	FastLeave     bool
+	RootBlock     bool
+	Learning      bool
+	Flood         bool
+	ProxyArp      bool
+	ProxyArpWiFi  bool
+	McastFlood    bool
+	BcastFlood    bool
+	McastToUcast  bool
+	NeighSuppress bool
+	Isolated      bool
+	VlanTunnel    bool
+
+	State           uint8  // nl.BR_STATE_*
+	Priority        uint16 // port STP priority
+	Cost            uint32 // port STP path cost
+	MulticastRouter uint8  // nl.MDB_RTR_TYPE_*
+	GroupFwdMask    uint16
 }
 
 // String returns a list of enabled flags
@@ -47,6 +59,24 @@ func (prot *Protinfo) String() string {
 	if prot.ProxyArpWiFi {
 		boolStrings = append(boolStrings, "ProxyArpWiFi")
 	}
+	if prot.McastFlood {
+		boolStrings = append(boolStrings, "McastFlood")
+	}
+	if prot.BcastFlood {
+		boolStrings = append(boolStrings, "BcastFlood")
+	}
+	if prot.McastToUcast {
+		boolStrings = append(boolStrings, "McastToUcast")
+	}
+	if prot.NeighSuppress {
+		boolStrings = append(boolStrings, "NeighSuppress")
+	}
+	if prot.Isolated {
+		boolStrings = append(boolStrings, "Isolated")
+	}
+	if prot.VlanTunnel {
+		boolStrings = append(boolStrings, "VlanTunnel")
+	}
 	return strings.Join(boolStrings, " ")
 }
 
@@ -68,6 +68,28 @@ func parseProtinfo(infos []syscall.NetlinkRouteAttr) (pi Protinfo) {
 			pi.ProxyArp = byteToBool(info.Value[0])
 		case nl.IFLA_BRPORT_PROXYARP_WIFI:
 			pi.ProxyArpWiFi = byteToBool(info.Value[0])
+		case nl.IFLA_BRPORT_MCAST_FLOOD:
+			pi.McastFlood = byteToBool(info.Value[0])
+		case nl.IFLA_BRPORT_BCAST_FLOOD:
+			pi.BcastFlood = byteToBool(info.Value[0])
+		case nl.IFLA_BRPORT_MCAST_TO_UCAST:
+			pi.McastToUcast = byteToBool(info.Value[0])
+		case nl.IFLA_BRPORT_NEIGH_SUPPRESS:
+			pi.NeighSuppress = byteToBool(info.Value[0])
+		case nl.IFLA_BRPORT_ISOLATED:
+			pi.Isolated = byteToBool(info.Value[0])
+		case nl.IFLA_BRPORT_VLAN_TUNNEL:
+			pi.VlanTunnel = byteToBool(info.Value[0])
+		case nl.IFLA_BRPORT_STATE:
+			pi.State = info.Value[0]
+		case nl.IFLA_BRPORT_PRIORITY:
+			pi.Priority = native.Uint16(info.Value[0:2])
+		case nl.IFLA_BRPORT_COST:
+			pi.Cost = native.Uint32(info.Value[0:4])
+		case nl.IFLA_BRPORT_MULTICAST_ROUTER:
+			pi.MulticastRouter = info.Value[0]
+		case nl.IFLA_BRPORT_GROUP_FWD_MASK:
+			pi.GroupFwdMask = native.Uint16(info.Value[0:2])
 		}
 	}
 	return
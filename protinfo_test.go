@@ -4,6 +4,8 @@ package netlink
 
 import (
 	"testing"
+
+	"github.com/ndupreez/netlink/nl"
 )
 
 func TestProtinfo(t *testing.T) {
@@ -159,3 +161,68 @@ func TestProtinfo(t *testing.T) {
 		t.Fatalf("Flood field was changed for %s but shouldn't", iface4.Name)
 	}
 }
+
+func TestProtinfoPortAttrs(t *testing.T) {
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+	master := &Bridge{LinkAttrs: LinkAttrs{Name: "foo"}}
+	if err := LinkAdd(master); err != nil {
+		t.Fatal(err)
+	}
+	iface := &Dummy{LinkAttrs{Name: "bar1", MasterIndex: master.Index}}
+	if err := LinkAdd(iface); err != nil {
+		t.Fatal(err)
+	}
+	// The port state can only be set on a running port
+	if err := LinkSetUp(master); err != nil {
+		t.Fatal(err)
+	}
+	if err := LinkSetUp(iface); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := LinkSetBrPortCost(iface, 42); err != nil {
+		t.Fatal(err)
+	}
+	if err := LinkSetBrPortPriority(iface, 7); err != nil {
+		t.Fatal(err)
+	}
+	if err := LinkSetBrPortState(iface, nl.BR_STATE_BLOCKING); err != nil {
+		t.Fatal(err)
+	}
+	if err := LinkSetBrPortMcastRouter(iface, nl.MDB_RTR_TYPE_PERM); err != nil {
+		t.Fatal(err)
+	}
+	if err := LinkSetIsolated(iface, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := LinkSetBrNeighSuppress(iface, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := LinkSetMcastFlood(iface, false); err != nil {
+		t.Fatal(err)
+	}
+
+	pi, err := LinkGetProtinfo(iface)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pi.Cost != 42 {
+		t.Fatalf("Cost is %d for %s, should be 42", pi.Cost, iface.Name)
+	}
+	if pi.Priority != 7 {
+		t.Fatalf("Priority is %d for %s, should be 7", pi.Priority, iface.Name)
+	}
+	if pi.State != nl.BR_STATE_BLOCKING {
+		t.Fatalf("State is %d for %s, should be %d", pi.State, iface.Name, nl.BR_STATE_BLOCKING)
+	}
+	if pi.MulticastRouter != nl.MDB_RTR_TYPE_PERM {
+		t.Fatalf("MulticastRouter is %d for %s, should be %d", pi.MulticastRouter, iface.Name, nl.MDB_RTR_TYPE_PERM)
+	}
+	if !pi.Isolated || !pi.NeighSuppress {
+		t.Fatalf("Isolated and NeighSuppress are not enabled for %s, but should", iface.Name)
+	}
+	if pi.McastFlood {
+		t.Fatalf("McastFlood is enabled for %s, but shouldn't", iface.Name)
+	}
+}
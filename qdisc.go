@@ -3,6 +3,8 @@ package netlink
 import (
 	"fmt"
 	"math"
+
+	"github.com/ndupreez/netlink/nl"
 )
 
 const (
@@ -26,12 +28,17 @@ type Qdisc interface {
 // has a handle, a parent and a refcnt. The root qdisc of a device should
 // have parent == HANDLE_ROOT.
 type QdiscAttrs struct {
-	LinkIndex int
-	Handle    uint32
-	Parent    uint32
-	Refcnt    uint32 // read only
+	LinkIndex  int
+	Handle     uint32
+	Parent     uint32
+	Refcnt     uint32           // read only
+	Statistics *QdiscStatistics // read only
 }
 
+// QdiscStatistics holds the generic networking statistics of a qdisc. It
+// shares its representation with ClassStatistics.
+type QdiscStatistics ClassStatistics
+
 func (q QdiscAttrs) String() string {
 	return fmt.Sprintf("{LinkIndex: %d, Handle: %s, Parent: %s, Refcnt: %d}", q.LinkIndex, HandleStr(q.Handle), HandleStr(q.Parent), q.Refcnt)
 }
@@ -150,6 +157,40 @@ type NetemQdiscAttrs struct {
 	ReorderCorr   float32 // in %
 	CorruptProb   float32 // in %
 	CorruptCorr   float32 // in %
+	// Loss models, replacing Loss and LossCorr when set
+	LossState   *NetemLossStateAttrs
+	LossGEModel *NetemLossGEModelAttrs
+	// Delay distribution table, see NetemDistribution
+	DelayDist []int16
+	// Rate emulation
+	Rate           uint64 // in bytes per second
+	PacketOverhead int32  // in bytes
+	CellSize       uint32 // in bytes
+	CellOverhead   int32  // in bytes
+	// Slot based emulation, delaying packets until the next slot
+	Slot     *NetemSlot
+	SlotDist []int16
+	ECN      bool   // mark packets instead of dropping them
+	PrngSeed uint64 // 0 lets the kernel pick a random seed
+}
+
+// NetemLossStateAttrs is the 4-state Markov loss model, the probabilities
+// of the transitions between the states. P31 defaults to 100 - P13.
+type NetemLossStateAttrs struct {
+	P13 float32 // in %
+	P31 float32 // in %
+	P32 float32 // in %
+	P23 float32 // in %
+	P14 float32 // in %
+}
+
+// NetemLossGEModelAttrs is the Gilbert-Elliott loss model. R defaults to
+// 100 - P.
+type NetemLossGEModelAttrs struct {
+	P  float32 // in %, probability to go from the good to the bad state
+	R  float32 // in %, probability to go from the bad to the good state
+	H  float32 // in %, probability of a packet not being lost in the bad state
+	K1 float32 // in %, probability of a packet being lost in the good state
 }
 
 func (q NetemQdiscAttrs) String() string {
@@ -174,6 +215,53 @@ type Netem struct {
 	ReorderCorr   uint32
 	CorruptProb   uint32
 	CorruptCorr   uint32
+	// Latency64 and Jitter64 take precedence over Latency and Jitter when
+	// set and allow for delays not fitting in 32bit ticks. They are only
+	// read back when Latency and Jitter can't hold the delays.
+	Latency64      int64 // in ns
+	Jitter64       int64 // in ns
+	LossState      *NetemLossState
+	LossGEModel    *NetemLossGEModel
+	DelayDist      []int16 // write only, not reported by the kernel
+	Rate           uint64  // in bytes per second
+	PacketOverhead int32
+	CellSize       uint32
+	CellOverhead   int32
+	Slot           *NetemSlot
+	SlotDist       []int16 // write only, not reported by the kernel
+	ECN            bool
+	PrngSeed       uint64
+}
+
+// NetemLossState Ref: struct tc_netem_gimodel { ... }
+type NetemLossState struct {
+	P13 uint32
+	P31 uint32
+	P32 uint32
+	P14 uint32
+	P23 uint32
+}
+
+// NetemLossGEModel Ref: struct tc_netem_gemodel { ... }
+type NetemLossGEModel struct {
+	P  uint32
+	R  uint32
+	H  uint32
+	K1 uint32
+}
+
+// NetemSlot Ref: struct tc_netem_slot { ... }
+// When DistDelay or DistJitter are set, the slots are drawn from the slot
+// distribution table instead of being uniformly picked between MinDelay and
+// MaxDelay. MaxPackets and MaxBytes bound the size of a slot, 0 meaning
+// unlimited.
+type NetemSlot struct {
+	MinDelay   int64 // in ns
+	MaxDelay   int64 // in ns
+	MaxPackets int32
+	MaxBytes   int32
+	DistDelay  int64 // in ns
+	DistJitter int64 // in ns
 }
 
 func (netem *Netem) String() string {
@@ -281,6 +369,26 @@ type Fq struct {
 	Buckets          uint32
 	FlowRefillDelay  uint32
 	LowRateThreshold uint32
+	Stats            *FqQdStats // read only
+}
+
+// FqQdStats Ref: struct tc_fq_qd_stats { ... }
+type FqQdStats struct {
+	GcFlows             uint64
+	HighprioPackets     uint64
+	TcpRetrans          uint64
+	Throttled           uint64
+	FlowsPlimit         uint64
+	PktsTooLong         uint64
+	AllocationErrors    uint64
+	TimeNextDelayedFlow int64
+	Flows               uint32
+	InactiveFlows       uint32
+	ThrottledFlows      uint32
+	UnthrottleLatencyNs uint32
+	CeMark              uint64 // packets above ce_threshold
+	HorizonDrops        uint64
+	HorizonCaps         uint64
 }
 
 func (fq *Fq) String() string {
@@ -317,6 +425,20 @@ type FqCodel struct {
 	CEThreshold   uint32
 	DropBatchSize uint32
 	MemoryLimit   uint32
+	Stats         *FqCodelQdStats // read only
+}
+
+// FqCodelQdStats Ref: struct tc_fq_codel_qd_stats { ... }
+type FqCodelQdStats struct {
+	MaxPacket      uint32 // largest packet we've seen so far
+	DropOverlimit  uint32 // number of time max qdisc packet limit was hit
+	EcnMark        uint32 // number of packets we ECN marked instead of being dropped
+	NewFlowCount   uint32 // number of time packets created a 'new flow'
+	NewFlowsLen    uint32 // count of flows in new list
+	OldFlowsLen    uint32 // count of flows in old list
+	CeMark         uint32 // packets above ce_threshold
+	MemoryUsage    uint32 // memory usage (bytes)
+	DropOvermemory uint32
 }
 
 func (fqcodel *FqCodel) String() string {
@@ -364,3 +486,552 @@ func (qdisc *Sfq) Attrs() *QdiscAttrs {
 func (qdisc *Sfq) Type() string {
 	return "sfq"
 }
+
+type CakeDiffservMode uint32
+
+const (
+	CAKE_DIFFSERV_DIFFSERV3 CakeDiffservMode = iota
+	CAKE_DIFFSERV_DIFFSERV4
+	CAKE_DIFFSERV_DIFFSERV8
+	CAKE_DIFFSERV_BESTEFFORT
+	CAKE_DIFFSERV_PRECEDENCE
+)
+
+type CakeFlowMode uint32
+
+const (
+	CAKE_FLOW_NONE CakeFlowMode = iota
+	CAKE_FLOW_SRC_IP
+	CAKE_FLOW_DST_IP
+	CAKE_FLOW_HOSTS
+	CAKE_FLOW_FLOWS
+	CAKE_FLOW_DUAL_SRC
+	CAKE_FLOW_DUAL_DST
+	CAKE_FLOW_TRIPLE
+)
+
+type CakeAtmMode uint32
+
+const (
+	CAKE_ATM_NONE CakeAtmMode = iota
+	CAKE_ATM_ATM
+	CAKE_ATM_PTM
+)
+
+type CakeAckFilter uint32
+
+const (
+	CAKE_ACK_NONE CakeAckFilter = iota
+	CAKE_ACK_FILTER
+	CAKE_ACK_AGGRESSIVE
+)
+
+// Cake (Common Applications Kept Enhanced) is a shaping-capable queue
+// discipline combining flow isolation with the COBALT AQM. Raw disables the
+// overhead compensation, it is also reported by the kernel when no Overhead
+// was configured since both modes are the same.
+type Cake struct {
+	QdiscAttrs
+	BaseRate     uint64 // in bytes per second, 0 means unlimited
+	DiffservMode CakeDiffservMode
+	FlowMode     CakeFlowMode
+	Atm          CakeAtmMode
+	Overhead     int32 // in bytes, ignored when Raw is set
+	Mpu          uint32
+	Rtt          uint32 // in us
+	Target       uint32 // in us
+	Memory       uint32 // in bytes
+	Autorate     bool
+	Nat          bool
+	Raw          bool
+	Wash         bool
+	Ingress      bool
+	AckFilter    CakeAckFilter
+	SplitGso     bool
+	FwMark       uint32
+}
+
+func NewCake(attrs QdiscAttrs) *Cake {
+	return &Cake{
+		QdiscAttrs: attrs,
+		FlowMode:   CAKE_FLOW_TRIPLE,
+		SplitGso:   true,
+	}
+}
+
+func (cake *Cake) String() string {
+	return fmt.Sprintf(
+		"{%v -- BaseRate: %v, DiffservMode: %v, FlowMode: %v, Overhead: %v, Rtt: %v}",
+		cake.Attrs(), cake.BaseRate, cake.DiffservMode, cake.FlowMode, cake.Overhead, cake.Rtt,
+	)
+}
+
+func (qdisc *Cake) Attrs() *QdiscAttrs {
+	return &qdisc.QdiscAttrs
+}
+
+func (qdisc *Cake) Type() string {
+	return "cake"
+}
+
+type MqprioMode uint16
+
+const (
+	MQPRIO_MODE_DCB MqprioMode = iota
+	MQPRIO_MODE_CHANNEL
+)
+
+type MqprioShaper uint16
+
+const (
+	MQPRIO_SHAPER_DCB MqprioShaper = iota
+	MQPRIO_SHAPER_BW_RATE
+)
+
+// Mqprio is a multiqueue priority qdisc mapping traffic classes to ranges of
+// hardware transmit queues. It can only be attached to the root of a
+// multiqueue device.
+type Mqprio struct {
+	QdiscAttrs
+	NumTc     uint8
+	PrioTcMap [PRIORITY_MAP_LEN]uint8
+	HwOffload uint8
+	// Count and Offset describe the queue range of each traffic class
+	Count  [nl.TC_QOPT_MAX_QUEUE]uint16
+	Offset [nl.TC_QOPT_MAX_QUEUE]uint16
+	Mode   MqprioMode
+	Shaper MqprioShaper
+	// MinRate and MaxRate are per traffic class rates in bytes per second and
+	// require Shaper to be MQPRIO_SHAPER_BW_RATE
+	MinRate []uint64
+	MaxRate []uint64
+}
+
+func (mqprio *Mqprio) String() string {
+	return fmt.Sprintf(
+		"{%v -- NumTc: %v, PrioTcMap: %v, HwOffload: %v, Count: %v, Offset: %v}",
+		mqprio.Attrs(), mqprio.NumTc, mqprio.PrioTcMap, mqprio.HwOffload, mqprio.Count, mqprio.Offset,
+	)
+}
+
+func (qdisc *Mqprio) Attrs() *QdiscAttrs {
+	return &qdisc.QdiscAttrs
+}
+
+func (qdisc *Mqprio) Type() string {
+	return "mqprio"
+}
+
+const (
+	TAPRIO_CMD_SET_GATES uint8 = iota
+	TAPRIO_CMD_SET_AND_HOLD
+	TAPRIO_CMD_SET_AND_RELEASE
+)
+
+const (
+	TAPRIO_FLAG_TXTIME_ASSIST uint32 = 1 << iota
+	TAPRIO_FLAG_FULL_OFFLOAD
+)
+
+// TaprioSchedEntry is a single entry of a taprio gate control list.
+type TaprioSchedEntry struct {
+	Index    uint32 // read only
+	Command  uint8
+	GateMask uint32
+	Interval uint32 // in ns
+}
+
+// TaprioSchedule is a gate control list with its timing parameters.
+type TaprioSchedule struct {
+	BaseTime           int64 // in ns
+	CycleTime          int64 // in ns
+	CycleTimeExtension int64 // in ns
+	Entries            []TaprioSchedEntry
+}
+
+// Taprio is the time aware priority shaper (IEEE 802.1Qbv). Schedule holds the
+// operational schedule on read and the new admin schedule on add or change.
+type Taprio struct {
+	QdiscAttrs
+	NumTc       uint8
+	PrioTcMap   [PRIORITY_MAP_LEN]uint8
+	Count       [nl.TC_QOPT_MAX_QUEUE]uint16
+	Offset      [nl.TC_QOPT_MAX_QUEUE]uint16
+	ClockID     int32
+	Flags       uint32
+	TxTimeDelay uint32 // in ns
+	Schedule    TaprioSchedule
+	// AdminSchedule is the pending schedule not yet in effect
+	AdminSchedule *TaprioSchedule // read only
+}
+
+func (taprio *Taprio) String() string {
+	return fmt.Sprintf(
+		"{%v -- NumTc: %v, ClockID: %v, Flags: %v, BaseTime: %v, CycleTime: %v, Entries: %v}",
+		taprio.Attrs(), taprio.NumTc, taprio.ClockID, taprio.Flags, taprio.Schedule.BaseTime, taprio.Schedule.CycleTime, len(taprio.Schedule.Entries),
+	)
+}
+
+func (qdisc *Taprio) Attrs() *QdiscAttrs {
+	return &qdisc.QdiscAttrs
+}
+
+func (qdisc *Taprio) Type() string {
+	return "taprio"
+}
+
+// Etf (Earliest TxTime First) transmits packets according to their
+// SO_TXTIME launch time.
+type Etf struct {
+	QdiscAttrs
+	ClockID       int32
+	Delta         int32 // in ns
+	DeadlineMode  bool
+	Offload       bool
+	SkipSockCheck bool
+}
+
+func (etf *Etf) String() string {
+	return fmt.Sprintf(
+		"{%v -- ClockID: %v, Delta: %v, DeadlineMode: %v, Offload: %v, SkipSockCheck: %v}",
+		etf.Attrs(), etf.ClockID, etf.Delta, etf.DeadlineMode, etf.Offload, etf.SkipSockCheck,
+	)
+}
+
+func (qdisc *Etf) Attrs() *QdiscAttrs {
+	return &qdisc.QdiscAttrs
+}
+
+func (qdisc *Etf) Type() string {
+	return "etf"
+}
+
+// Cbs is the credit based shaper (IEEE 802.1Qav).
+type Cbs struct {
+	QdiscAttrs
+	Offload   bool
+	HiCredit  int32 // in bytes
+	LoCredit  int32 // in bytes
+	IdleSlope int32 // in kbit/s
+	SendSlope int32 // in kbit/s
+}
+
+func (cbs *Cbs) String() string {
+	return fmt.Sprintf(
+		"{%v -- Offload: %v, HiCredit: %v, LoCredit: %v, IdleSlope: %v, SendSlope: %v}",
+		cbs.Attrs(), cbs.Offload, cbs.HiCredit, cbs.LoCredit, cbs.IdleSlope, cbs.SendSlope,
+	)
+}
+
+func (qdisc *Cbs) Attrs() *QdiscAttrs {
+	return &qdisc.QdiscAttrs
+}
+
+func (qdisc *Cbs) Type() string {
+	return "cbs"
+}
+
+// Clsact is a qdisc for adding both ingress and egress filters. Its parent
+// must be HANDLE_CLSACT.
+type Clsact struct {
+	QdiscAttrs
+}
+
+func (qdisc *Clsact) Attrs() *QdiscAttrs {
+	return &qdisc.QdiscAttrs
+}
+
+func (qdisc *Clsact) Type() string {
+	return "clsact"
+}
+
+// RedQdiscAttrs holds the user facing parameters of the RED family of qdiscs
+// (red, gred virtual queues and choke) from which the kernel parameters are
+// derived, like tc does. Limit, Min and Max are in bytes for red and gred and
+// in packets for choke.
+type RedQdiscAttrs struct {
+	Limit       uint32
+	Min         uint32
+	Max         uint32
+	Avpkt       uint32  // in bytes
+	Burst       uint32  // in packets
+	Probability float64 // maximum marking probability, between 0 and 1
+	Bandwidth   uint64  // in bytes per second, used for idle damping
+	ECN         bool
+	Harddrop    bool
+	Adaptive    bool
+	Nodrop      bool
+}
+
+// RedXstats Ref: struct tc_red_xstats { ... }
+type RedXstats struct {
+	Early  uint32 // Early drops
+	Pdrop  uint32 // Drops due to queue limits
+	Other  uint32 // Drops due to drop() calls
+	Marked uint32 // Marked packets
+}
+
+// Red (Random Early Detection) is a classless qdisc dropping or marking
+// packets with a probability growing with the average queue length.
+type Red struct {
+	QdiscAttrs
+	Limit    uint32 // in bytes
+	Min      uint32 // in bytes
+	Max      uint32 // in bytes
+	Wlog     uint8
+	Plog     uint8
+	ScellLog uint8
+	MaxP     uint32
+	ECN      bool
+	Harddrop bool
+	Adaptive bool
+	Nodrop   bool
+	// Stab is the idle damping table. It is not reported by the kernel and
+	// defaults to no damping when empty.
+	Stab  []byte
+	Stats *RedXstats // read only
+}
+
+func (red *Red) String() string {
+	return fmt.Sprintf(
+		"{%v -- Limit: %v, Min: %v, Max: %v, Wlog: %v, Plog: %v, ScellLog: %v, MaxP: %v}",
+		red.Attrs(), red.Limit, red.Min, red.Max, red.Wlog, red.Plog, red.ScellLog, red.MaxP,
+	)
+}
+
+func (qdisc *Red) Attrs() *QdiscAttrs {
+	return &qdisc.QdiscAttrs
+}
+
+func (qdisc *Red) Type() string {
+	return "red"
+}
+
+// GredVQ is a virtual queue of a Gred qdisc.
+type GredVQ struct {
+	DP       uint32
+	Limit    uint32 // in bytes
+	Min      uint32 // in bytes
+	Max      uint32 // in bytes
+	Wlog     uint8
+	Plog     uint8
+	ScellLog uint8
+	Prio     uint8
+	MaxP     uint32
+	// Stab is the idle damping table. It is not reported by the kernel and
+	// defaults to no damping when empty.
+	Stab []byte
+	// Statistics, read only
+	Backlog uint32
+	Qave    uint32
+	Forced  uint32
+	Early   uint32
+	Other   uint32
+	Pdrop   uint32
+	Packets uint32
+	Bytesin uint32
+}
+
+// Gred (Generic RED) runs several RED virtual queues selected by the
+// tc_index of the packets. The virtual queues are configured once the qdisc
+// exists, one request per queue.
+type Gred struct {
+	QdiscAttrs
+	DPs       uint32
+	DefaultDP uint32
+	Grio      bool
+	ECN       bool
+	Harddrop  bool
+	Limit     uint32 // in bytes, 0 keeps the kernel default
+	VQs       []GredVQ
+}
+
+func (gred *Gred) String() string {
+	return fmt.Sprintf(
+		"{%v -- DPs: %v, DefaultDP: %v, Grio: %v, VQs: %v}",
+		gred.Attrs(), gred.DPs, gred.DefaultDP, gred.Grio, len(gred.VQs),
+	)
+}
+
+func (qdisc *Gred) Attrs() *QdiscAttrs {
+	return &qdisc.QdiscAttrs
+}
+
+func (qdisc *Gred) Type() string {
+	return "gred"
+}
+
+// PieXstats Ref: struct tc_pie_xstats { ... }
+type PieXstats struct {
+	Prob             uint64 // current probability
+	Delay            uint32 // current delay in ms
+	AvgDqRate        uint32 // current average dq_rate in bits/pie_time
+	DqRateEstimating uint32 // is avg_dq_rate being calculated?
+	PacketsIn        uint32 // total number of packets enqueued
+	Dropped          uint32 // packets dropped due to pie_action
+	Overlimit        uint32 // dropped due to lack of space in queue
+	Maxq             uint32 // maximum queue size
+	EcnMark          uint32 // packets marked with ecn
+}
+
+// Pie (Proportional Integral controller Enhanced) is a classless AQM qdisc.
+type Pie struct {
+	QdiscAttrs
+	Target          uint32 // in us
+	Limit           uint32 // in packets
+	Tupdate         uint32 // in us
+	Alpha           uint32
+	Beta            uint32
+	ECN             bool
+	Bytemode        bool
+	DqRateEstimator bool
+	Stats           *PieXstats // read only
+}
+
+func (pie *Pie) String() string {
+	return fmt.Sprintf(
+		"{%v -- Target: %v, Limit: %v, Tupdate: %v, Alpha: %v, Beta: %v, ECN: %v}",
+		pie.Attrs(), pie.Target, pie.Limit, pie.Tupdate, pie.Alpha, pie.Beta, pie.ECN,
+	)
+}
+
+func (qdisc *Pie) Attrs() *QdiscAttrs {
+	return &qdisc.QdiscAttrs
+}
+
+func (qdisc *Pie) Type() string {
+	return "pie"
+}
+
+// CodelXstats Ref: struct tc_codel_xstats { ... }
+type CodelXstats struct {
+	MaxPacket     uint32 // largest packet we've seen so far
+	Count         uint32 // how many drops we've done since the last time we entered dropping state
+	LastCount     uint32 // count at entry to dropping state
+	Ldelay        uint32 // in-queue delay seen by most recently dequeued packet
+	DropNext      int32  // time to drop next packet
+	DropOverlimit uint32 // number of time max qdisc packet limit was hit
+	EcnMark       uint32 // number of packets we ECN marked instead of dropped
+	Dropping      uint32 // are we in dropping state ?
+	CeMark        uint32 // number of CE marked packets because of ce_threshold
+}
+
+// Codel (Controlled Delay) is a classless AQM qdisc.
+type Codel struct {
+	QdiscAttrs
+	Target      uint32 // in us
+	Limit       uint32 // in packets
+	Interval    uint32 // in us
+	ECN         uint32
+	CEThreshold uint32       // in us
+	Stats       *CodelXstats // read only
+}
+
+func NewCodel(attrs QdiscAttrs) *Codel {
+	return &Codel{
+		QdiscAttrs: attrs,
+		ECN:        1,
+	}
+}
+
+func (codel *Codel) String() string {
+	return fmt.Sprintf(
+		"{%v -- Target: %v, Limit: %v, Interval: %v, ECN: %v, CEThreshold: %v}",
+		codel.Attrs(), codel.Target, codel.Limit, codel.Interval, codel.ECN, codel.CEThreshold,
+	)
+}
+
+func (qdisc *Codel) Attrs() *QdiscAttrs {
+	return &qdisc.QdiscAttrs
+}
+
+func (qdisc *Codel) Type() string {
+	return "codel"
+}
+
+// ChokeXstats Ref: struct tc_choke_xstats { ... }
+type ChokeXstats struct {
+	Early   uint32 // Early drops
+	Pdrop   uint32 // Drops due to queue limits
+	Other   uint32 // Drops due to drop() calls
+	Marked  uint32 // Marked packets
+	Matched uint32 // Drops due to flow match
+}
+
+// Choke (CHOose and Keep for responsive flows, CHOose and Kill for
+// unresponsive flows) is a RED variant penalizing flows hogging the queue.
+type Choke struct {
+	QdiscAttrs
+	Limit    uint32 // in packets
+	Min      uint32 // in packets
+	Max      uint32 // in packets
+	Wlog     uint8
+	Plog     uint8
+	ScellLog uint8
+	MaxP     uint32
+	ECN      bool
+	Harddrop bool
+	// Stab is the idle damping table. It is not reported by the kernel and
+	// defaults to no damping when empty.
+	Stab  []byte
+	Stats *ChokeXstats // read only
+}
+
+func (choke *Choke) String() string {
+	return fmt.Sprintf(
+		"{%v -- Limit: %v, Min: %v, Max: %v, Wlog: %v, Plog: %v, ScellLog: %v, MaxP: %v}",
+		choke.Attrs(), choke.Limit, choke.Min, choke.Max, choke.Wlog, choke.Plog, choke.ScellLog, choke.MaxP,
+	)
+}
+
+func (qdisc *Choke) Attrs() *QdiscAttrs {
+	return &qdisc.QdiscAttrs
+}
+
+func (qdisc *Choke) Type() string {
+	return "choke"
+}
+
+// Drr (Deficit Round Robin) is a classful qdisc. Its parameters are set on
+// its classes, see DrrClass.
+type Drr struct {
+	QdiscAttrs
+}
+
+func (qdisc *Drr) Attrs() *QdiscAttrs {
+	return &qdisc.QdiscAttrs
+}
+
+func (qdisc *Drr) Type() string {
+	return "drr"
+}
+
+// Qfq (Quick Fair Queueing) is a classful qdisc. Its parameters are set on
+// its classes, see QfqClass.
+type Qfq struct {
+	QdiscAttrs
+}
+
+func (qdisc *Qfq) Attrs() *QdiscAttrs {
+	return &qdisc.QdiscAttrs
+}
+
+func (qdisc *Qfq) Type() string {
+	return "qfq"
+}
+
+// Multiq is a classful qdisc with one band per hardware transmit queue. The
+// number of bands is chosen by the kernel.
+type Multiq struct {
+	QdiscAttrs
+	Bands    uint16 // read only
+	MaxBands uint16 // read only
+}
+
+func (qdisc *Multiq) Attrs() *QdiscAttrs {
+	return &qdisc.QdiscAttrs
+}
+
+func (qdisc *Multiq) Type() string {
+	return "multiq"
+}
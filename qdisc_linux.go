@@ -1,8 +1,14 @@
 package netlink
 
 import (
+	"bufio"
+	"encoding/binary"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"syscall"
@@ -58,22 +64,357 @@ func NewNetem(attrs QdiscAttrs, nattrs NetemQdiscAttrs) *Netem {
 	corruptProb = Percentage2u32(nattrs.CorruptProb)
 	corruptCorr = Percentage2u32(nattrs.CorruptCorr)
 
+	var lossState *NetemLossState
+	if gi := nattrs.LossState; gi != nil {
+		p31 := gi.P31
+		if p31 == 0 {
+			p31 = 100 - gi.P13
+		}
+		lossState = &NetemLossState{
+			P13: Percentage2u32(gi.P13),
+			P31: Percentage2u32(p31),
+			P32: Percentage2u32(gi.P32),
+			P23: Percentage2u32(gi.P23),
+			P14: Percentage2u32(gi.P14),
+		}
+	}
+	var lossGEModel *NetemLossGEModel
+	if ge := nattrs.LossGEModel; ge != nil {
+		r := ge.R
+		if r == 0 {
+			r = 100 - ge.P
+		}
+		lossGEModel = &NetemLossGEModel{
+			P:  Percentage2u32(ge.P),
+			R:  Percentage2u32(r),
+			H:  Percentage2u32(ge.H),
+			K1: Percentage2u32(ge.K1),
+		}
+	}
+	var slot *NetemSlot
+	if nattrs.Slot != nil {
+		s := *nattrs.Slot
+		if s.MaxDelay < s.MinDelay {
+			s.MaxDelay = s.MinDelay
+		}
+		slot = &s
+	}
+
 	return &Netem{
-		QdiscAttrs:    attrs,
-		Latency:       latency,
-		DelayCorr:     delayCorr,
-		Limit:         limit,
-		Loss:          loss,
-		LossCorr:      lossCorr,
-		Gap:           gap,
-		Duplicate:     duplicate,
-		DuplicateCorr: duplicateCorr,
-		Jitter:        jitter,
-		ReorderProb:   reorderProb,
-		ReorderCorr:   reorderCorr,
-		CorruptProb:   corruptProb,
-		CorruptCorr:   corruptCorr,
+		QdiscAttrs:     attrs,
+		Latency:        latency,
+		DelayCorr:      delayCorr,
+		Limit:          limit,
+		Loss:           loss,
+		LossCorr:       lossCorr,
+		Gap:            gap,
+		Duplicate:      duplicate,
+		DuplicateCorr:  duplicateCorr,
+		Jitter:         jitter,
+		ReorderProb:    reorderProb,
+		ReorderCorr:    reorderCorr,
+		CorruptProb:    corruptProb,
+		CorruptCorr:    corruptCorr,
+		LossState:      lossState,
+		LossGEModel:    lossGEModel,
+		DelayDist:      nattrs.DelayDist,
+		Rate:           nattrs.Rate,
+		PacketOverhead: nattrs.PacketOverhead,
+		CellSize:       nattrs.CellSize,
+		CellOverhead:   nattrs.CellOverhead,
+		Slot:           slot,
+		SlotDist:       nattrs.SlotDist,
+		ECN:            nattrs.ECN,
+		PrngSeed:       nattrs.PrngSeed,
+	}
+}
+
+// NetemDistribution loads a netem distribution table as generated by the
+// iproute2 maketable tool, like the normal, pareto, paretonormal and
+// experimental tables shipped with tc. A name without a path is looked up in
+// $TC_LIB_DIR and then in the default tc library directories.
+func NetemDistribution(name string) ([]int16, error) {
+	paths := []string{name}
+	if !strings.Contains(name, "/") {
+		paths = nil
+		dirs := []string{"/usr/lib/tc", "/usr/lib64/tc", "/usr/local/lib/tc"}
+		if dir := os.Getenv("TC_LIB_DIR"); dir != "" {
+			dirs = append([]string{dir}, dirs...)
+		}
+		for _, dir := range dirs {
+			paths = append(paths, filepath.Join(dir, name+".dist"))
+		}
+	}
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return parseNetemDistribution(f)
+	}
+	return nil, fmt.Errorf("netem distribution %q not found", name)
+}
+
+func parseNetemDistribution(r io.Reader) ([]int16, error) {
+	var table []int16
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		for _, field := range strings.Fields(line) {
+			val, err := strconv.ParseInt(field, 10, 16)
+			if err != nil {
+				return nil, fmt.Errorf("invalid netem distribution value %q: %v", field, err)
+			}
+			if len(table) >= nl.NETEM_DIST_MAX {
+				return nil, fmt.Errorf("netem distribution has more than %d values", nl.NETEM_DIST_MAX)
+			}
+			table = append(table, int16(val))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return table, nil
+}
+
+func serializeNetemDistribution(table []int16) ([]byte, error) {
+	if len(table) > nl.NETEM_DIST_MAX {
+		return nil, fmt.Errorf("netem distribution has more than %d values", nl.NETEM_DIST_MAX)
+	}
+	b := make([]byte, 2*len(table))
+	for i, val := range table {
+		native.PutUint16(b[2*i:], uint16(val))
+	}
+	return b, nil
+}
+
+// NewRed returns a Red qdisc with the kernel parameters derived from rattrs
+// the same way `tc qdisc add ... red` does. Limit is mandatory, the other
+// values default to the ones of tc.
+// NOTE function is here because it uses other linux functions
+func NewRed(attrs QdiscAttrs, rattrs RedQdiscAttrs) (*Red, error) {
+	p, err := newRedParams(rattrs, false)
+	if err != nil {
+		return nil, err
+	}
+	return &Red{
+		QdiscAttrs: attrs,
+		Limit:      p.limit,
+		Min:        p.min,
+		Max:        p.max,
+		Wlog:       p.wlog,
+		Plog:       p.plog,
+		ScellLog:   p.scellLog,
+		MaxP:       p.maxP,
+		Stab:       p.stab,
+		ECN:        rattrs.ECN,
+		Harddrop:   rattrs.Harddrop,
+		Adaptive:   rattrs.Adaptive,
+		Nodrop:     rattrs.Nodrop,
+	}, nil
+}
+
+// NewChoke returns a Choke qdisc with the kernel parameters derived from
+// rattrs the same way `tc qdisc add ... choke` does. Limit, Min and Max are
+// in packets.
+// NOTE function is here because it uses other linux functions
+func NewChoke(attrs QdiscAttrs, rattrs RedQdiscAttrs) (*Choke, error) {
+	p, err := newRedParams(rattrs, true)
+	if err != nil {
+		return nil, err
+	}
+	return &Choke{
+		QdiscAttrs: attrs,
+		Limit:      p.limit,
+		Min:        p.min,
+		Max:        p.max,
+		Wlog:       p.wlog,
+		Plog:       p.plog,
+		ScellLog:   p.scellLog,
+		MaxP:       p.maxP,
+		Stab:       p.stab,
+		ECN:        rattrs.ECN,
+		Harddrop:   rattrs.Harddrop,
+	}, nil
+}
+
+// NewGredVQ returns the virtual queue dp of a Gred qdisc with the kernel
+// parameters derived from rattrs the same way `tc qdisc change ... gred`
+// does. The ECN, Harddrop, Adaptive and Nodrop flags of rattrs are ignored,
+// they are set on the Gred qdisc.
+// NOTE function is here because it uses other linux functions
+func NewGredVQ(dp uint32, rattrs RedQdiscAttrs) (GredVQ, error) {
+	p, err := newRedParams(rattrs, false)
+	if err != nil {
+		return GredVQ{}, err
 	}
+	return GredVQ{
+		DP:       dp,
+		Limit:    p.limit,
+		Min:      p.min,
+		Max:      p.max,
+		Wlog:     p.wlog,
+		Plog:     p.plog,
+		ScellLog: p.scellLog,
+		MaxP:     p.maxP,
+		Stab:     p.stab,
+	}, nil
+}
+
+type redParams struct {
+	limit, min, max uint32
+	wlog, plog      uint8
+	scellLog        uint8
+	maxP            uint32
+	stab            []byte
+}
+
+func newRedParams(rattrs RedQdiscAttrs, inPackets bool) (*redParams, error) {
+	if rattrs.Limit == 0 {
+		return nil, fmt.Errorf("RED limit is required")
+	}
+	p := &redParams{
+		limit: rattrs.Limit,
+		min:   rattrs.Min,
+		max:   rattrs.Max,
+	}
+	avpkt := rattrs.Avpkt
+	if avpkt == 0 {
+		avpkt = 1000
+	}
+	bandwidth := rattrs.Bandwidth
+	if bandwidth == 0 {
+		bandwidth = 1250000 // 10Mbit
+	}
+	prob := rattrs.Probability
+	if prob == 0 {
+		prob = 0.02
+	}
+	if prob < 0 || prob > 1 {
+		return nil, fmt.Errorf("RED probability %v is not between 0 and 1", prob)
+	}
+	if p.max == 0 {
+		if p.min != 0 {
+			p.max = p.min * 3
+		} else {
+			p.max = p.limit / 4
+		}
+	}
+	if p.min == 0 {
+		p.min = p.max / 3
+	}
+	if p.min >= p.max {
+		return nil, fmt.Errorf("RED min %d must be lower than max %d", p.min, p.max)
+	}
+	// choke thresholds are in packets while red ones are in bytes
+	qmin, qmax := p.min, p.max
+	burst := rattrs.Burst
+	if inPackets {
+		qmin *= avpkt
+		qmax *= avpkt
+		if burst == 0 {
+			burst = (2*p.min + p.max) / 3
+		}
+	} else if burst == 0 {
+		burst = (2*p.min + p.max) / (3 * avpkt)
+	}
+
+	wlog, err := redEvalEwma(qmin, burst, avpkt)
+	if err != nil {
+		return nil, err
+	}
+	plog, err := redEvalP(qmin, qmax, prob)
+	if err != nil {
+		return nil, err
+	}
+	p.stab = make([]byte, nl.RED_STAB_SIZE)
+	scellLog, err := redEvalIdleDamping(wlog, avpkt, bandwidth, p.stab)
+	if err != nil {
+		return nil, err
+	}
+	p.wlog, p.plog, p.scellLog = wlog, plog, scellLog
+
+	maxP := prob * math.Pow(2, 32)
+	if maxP >= math.MaxUint32 {
+		p.maxP = math.MaxUint32
+	} else {
+		p.maxP = uint32(maxP)
+	}
+	return p, nil
+}
+
+// redEvalEwma returns the logarithm of the weight of the average queue
+// length computation.
+// https://git.kernel.org/pub/scm/network/iproute2/iproute2.git/tree/tc/tc_red.c
+func redEvalEwma(qmin, burst, avpkt uint32) (uint8, error) {
+	a := float64(burst) + 1 - float64(qmin)/float64(avpkt)
+	if a < 1.0 {
+		return 0, fmt.Errorf("RED burst %d is too small, try burst %d", burst, (3*burst+qmin/avpkt)/2)
+	}
+	w := 0.5
+	for wlog := uint8(1); wlog < 32; wlog++ {
+		if a <= (1-math.Pow(1-w, float64(burst)))/w {
+			return wlog, nil
+		}
+		w /= 2
+	}
+	return 0, fmt.Errorf("RED burst %d is too large", burst)
+}
+
+// redEvalP returns the logarithm of the marking probability scaled by the
+// distance between the thresholds.
+func redEvalP(qmin, qmax uint32, prob float64) (uint8, error) {
+	if qmax == qmin {
+		return 0, nil
+	}
+	if qmax < qmin {
+		return 0, fmt.Errorf("RED max %d is lower than min %d", qmax, qmin)
+	}
+	prob /= float64(qmax - qmin)
+	for plog := uint8(0); plog < 32; plog++ {
+		if prob > 1.0 {
+			return plog, nil
+		}
+		prob *= 2
+	}
+	return 0, fmt.Errorf("RED probability %v is too small", prob)
+}
+
+// redEvalIdleDamping fills stab with the idle damping table and returns the
+// logarithm of its cell size.
+func redEvalIdleDamping(wlog uint8, avpkt uint32, bandwidth uint64, stab []byte) (uint8, error) {
+	xmitTime := float64(Xmittime(bandwidth, avpkt))
+	if xmitTime == 0 {
+		return 0, fmt.Errorf("RED bandwidth %d is too large", bandwidth)
+	}
+	lW := -math.Log(1.0-1.0/float64(uint32(1)<<wlog)) / xmitTime
+	maxTime := 31 / lW
+	var clog uint8
+	for ; clog < 32; clog++ {
+		if maxTime/float64(uint64(1)<<clog) < 512 {
+			break
+		}
+	}
+	if clog >= 32 {
+		return 0, fmt.Errorf("RED bandwidth %d is too small", bandwidth)
+	}
+	stab[0] = 0
+	for i := 1; i < nl.RED_STAB_SIZE-1; i++ {
+		v := float64(uint64(i)<<clog) * lW
+		if v > 31 {
+			v = 31
+		}
+		stab[i] = byte(v)
+	}
+	stab[nl.RED_STAB_SIZE-1] = 31
+	return clog, nil
 }
 
 // QdiscDel will delete a qdisc from the system.
@@ -152,6 +493,43 @@ func (h *Handle) qdiscModify(cmd, flags int, qdisc Qdisc) error {
 		}
 	}
 
+	_, err := req.Execute(unix.NETLINK_ROUTE, 0)
+	if err != nil {
+		return err
+	}
+
+	// The gred virtual queues can only be configured once the table exists
+	if gred, ok := qdisc.(*Gred); ok && cmd != unix.RTM_DELQDISC {
+		for i := range gred.VQs {
+			if err := h.gredVQChange(msg, &gred.VQs[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (h *Handle) gredVQChange(msg *nl.TcMsg, vq *GredVQ) error {
+	req := h.newNetlinkRequest(unix.RTM_NEWQDISC, unix.NLM_F_ACK)
+	req.AddData(msg)
+	req.AddData(nl.NewRtAttr(nl.TCA_KIND, nl.ZeroTerminated("gred")))
+
+	options := nl.NewRtAttr(nl.TCA_OPTIONS, nil)
+	opt := nl.TcGredQopt{
+		Limit:    vq.Limit,
+		QthMin:   vq.Min,
+		QthMax:   vq.Max,
+		DP:       vq.DP,
+		Wlog:     vq.Wlog,
+		Plog:     vq.Plog,
+		ScellLog: vq.ScellLog,
+		Prio:     vq.Prio,
+	}
+	options.AddRtAttr(nl.TCA_GRED_PARMS, opt.Serialize())
+	options.AddRtAttr(nl.TCA_GRED_STAB, redStab(vq.Stab))
+	options.AddRtAttr(nl.TCA_GRED_MAX_P, nl.Uint32Attr(vq.MaxP))
+	req.AddData(options)
+
 	_, err := req.Execute(unix.NETLINK_ROUTE, 0)
 	return err
 }
@@ -231,6 +609,71 @@ func qdiscPayload(req *nl.NetlinkRequest, qdisc Qdisc) error {
 		if reorder.Probability > 0 {
 			options.AddRtAttr(nl.TCA_NETEM_REORDER, reorder.Serialize())
 		}
+		if qdisc.Latency64 != 0 {
+			options.AddRtAttr(nl.TCA_NETEM_LATENCY64, nl.Uint64Attr(uint64(qdisc.Latency64)))
+		}
+		if qdisc.Jitter64 != 0 {
+			options.AddRtAttr(nl.TCA_NETEM_JITTER64, nl.Uint64Attr(uint64(qdisc.Jitter64)))
+		}
+		// Loss models
+		if qdisc.LossState != nil || qdisc.LossGEModel != nil {
+			loss := options.AddRtAttr(nl.TCA_NETEM_LOSS, nil)
+			if gi := qdisc.LossState; gi != nil {
+				model := nl.TcNetemGimodel{P13: gi.P13, P31: gi.P31, P32: gi.P32, P14: gi.P14, P23: gi.P23}
+				loss.AddRtAttr(nl.NETEM_LOSS_GI, model.Serialize())
+			}
+			if ge := qdisc.LossGEModel; ge != nil {
+				model := nl.TcNetemGemodel{P: ge.P, R: ge.R, H: ge.H, K1: ge.K1}
+				loss.AddRtAttr(nl.NETEM_LOSS_GE, model.Serialize())
+			}
+		}
+		if len(qdisc.DelayDist) > 0 {
+			dist, err := serializeNetemDistribution(qdisc.DelayDist)
+			if err != nil {
+				return err
+			}
+			options.AddRtAttr(nl.TCA_NETEM_DELAY_DIST, dist)
+		}
+		// Rate
+		if qdisc.Rate > 0 {
+			rate := nl.TcNetemRate{
+				PacketOverhead: qdisc.PacketOverhead,
+				CellSize:       qdisc.CellSize,
+				CellOverhead:   qdisc.CellOverhead,
+			}
+			if qdisc.Rate >= uint64(1<<32) {
+				rate.Rate = math.MaxUint32
+				options.AddRtAttr(nl.TCA_NETEM_RATE64, nl.Uint64Attr(qdisc.Rate))
+			} else {
+				rate.Rate = uint32(qdisc.Rate)
+			}
+			options.AddRtAttr(nl.TCA_NETEM_RATE, rate.Serialize())
+		}
+		if qdisc.ECN {
+			options.AddRtAttr(nl.TCA_NETEM_ECN, nl.Uint32Attr(1))
+		}
+		// Slots
+		if s := qdisc.Slot; s != nil {
+			slot := nl.TcNetemSlot{
+				MinDelay:   s.MinDelay,
+				MaxDelay:   s.MaxDelay,
+				MaxPackets: s.MaxPackets,
+				MaxBytes:   s.MaxBytes,
+				DistDelay:  s.DistDelay,
+				DistJitter: s.DistJitter,
+			}
+			options.AddRtAttr(nl.TCA_NETEM_SLOT, slot.Serialize())
+		}
+		if len(qdisc.SlotDist) > 0 {
+			dist, err := serializeNetemDistribution(qdisc.SlotDist)
+			if err != nil {
+				return err
+			}
+			options.AddRtAttr(nl.TCA_NETEM_SLOT_DIST, dist)
+		}
+		if qdisc.PrngSeed != 0 {
+			options.AddRtAttr(nl.TCA_NETEM_PRNG_SEED, nl.Uint64Attr(qdisc.PrngSeed))
+		}
 	case *Ingress:
 		// ingress filters must use the proper handle
 		if qdisc.Attrs().Parent != HANDLE_INGRESS {
@@ -294,6 +737,228 @@ func qdiscPayload(req *nl.NetlinkRequest, qdisc Qdisc) error {
 		opt.TcSfqQopt.Divisor = qdisc.Divisor
 
 		options = nl.NewRtAttr(nl.TCA_OPTIONS, opt.Serialize())
+	case *Cake:
+		options.AddRtAttr(nl.TCA_CAKE_BASE_RATE64, nl.Uint64Attr(qdisc.BaseRate))
+		options.AddRtAttr(nl.TCA_CAKE_DIFFSERV_MODE, nl.Uint32Attr(uint32(qdisc.DiffservMode)))
+		options.AddRtAttr(nl.TCA_CAKE_FLOW_MODE, nl.Uint32Attr(uint32(qdisc.FlowMode)))
+		options.AddRtAttr(nl.TCA_CAKE_ATM, nl.Uint32Attr(uint32(qdisc.Atm)))
+		if qdisc.Raw {
+			options.AddRtAttr(nl.TCA_CAKE_RAW, nl.Uint32Attr(0))
+		} else if qdisc.Overhead != 0 {
+			options.AddRtAttr(nl.TCA_CAKE_OVERHEAD, nl.Uint32Attr(uint32(qdisc.Overhead)))
+		}
+		if qdisc.Mpu > 0 {
+			options.AddRtAttr(nl.TCA_CAKE_MPU, nl.Uint32Attr(qdisc.Mpu))
+		}
+		if qdisc.Rtt > 0 {
+			options.AddRtAttr(nl.TCA_CAKE_RTT, nl.Uint32Attr(qdisc.Rtt))
+		}
+		if qdisc.Target > 0 {
+			options.AddRtAttr(nl.TCA_CAKE_TARGET, nl.Uint32Attr(qdisc.Target))
+		}
+		if qdisc.Memory > 0 {
+			options.AddRtAttr(nl.TCA_CAKE_MEMORY, nl.Uint32Attr(qdisc.Memory))
+		}
+		options.AddRtAttr(nl.TCA_CAKE_AUTORATE, boolUint32Attr(qdisc.Autorate))
+		options.AddRtAttr(nl.TCA_CAKE_NAT, boolUint32Attr(qdisc.Nat))
+		options.AddRtAttr(nl.TCA_CAKE_WASH, boolUint32Attr(qdisc.Wash))
+		options.AddRtAttr(nl.TCA_CAKE_INGRESS, boolUint32Attr(qdisc.Ingress))
+		options.AddRtAttr(nl.TCA_CAKE_ACK_FILTER, nl.Uint32Attr(uint32(qdisc.AckFilter)))
+		options.AddRtAttr(nl.TCA_CAKE_SPLIT_GSO, boolUint32Attr(qdisc.SplitGso))
+		if qdisc.FwMark > 0 {
+			options.AddRtAttr(nl.TCA_CAKE_FWMARK, nl.Uint32Attr(qdisc.FwMark))
+		}
+	case *Mqprio:
+		opt := nl.TcMqprioQopt{
+			NumTc:     qdisc.NumTc,
+			PrioTcMap: qdisc.PrioTcMap,
+			Hw:        qdisc.HwOffload,
+			Count:     qdisc.Count,
+			Offset:    qdisc.Offset,
+		}
+		options = nl.NewRtAttr(nl.TCA_OPTIONS, opt.Serialize())
+		if qdisc.Mode != MQPRIO_MODE_DCB {
+			options.AddRtAttr(nl.TCA_MQPRIO_MODE, nl.Uint16Attr(uint16(qdisc.Mode)))
+		}
+		if qdisc.Shaper != MQPRIO_SHAPER_DCB {
+			options.AddRtAttr(nl.TCA_MQPRIO_SHAPER, nl.Uint16Attr(uint16(qdisc.Shaper)))
+		}
+		if len(qdisc.MinRate) > 0 {
+			rates := options.AddRtAttr(nl.TCA_MQPRIO_MIN_RATE64, nil)
+			for _, rate := range qdisc.MinRate {
+				rates.AddRtAttr(nl.TCA_MQPRIO_MIN_RATE64, nl.Uint64Attr(rate))
+			}
+		}
+		if len(qdisc.MaxRate) > 0 {
+			rates := options.AddRtAttr(nl.TCA_MQPRIO_MAX_RATE64, nil)
+			for _, rate := range qdisc.MaxRate {
+				rates.AddRtAttr(nl.TCA_MQPRIO_MAX_RATE64, nl.Uint64Attr(rate))
+			}
+		}
+	case *Taprio:
+		opt := nl.TcMqprioQopt{
+			NumTc:     qdisc.NumTc,
+			PrioTcMap: qdisc.PrioTcMap,
+			Count:     qdisc.Count,
+			Offset:    qdisc.Offset,
+		}
+		options.AddRtAttr(nl.TCA_TAPRIO_ATTR_PRIOMAP, opt.Serialize())
+		if qdisc.Flags&TAPRIO_FLAG_FULL_OFFLOAD == 0 {
+			options.AddRtAttr(nl.TCA_TAPRIO_ATTR_SCHED_CLOCKID, nl.Uint32Attr(uint32(qdisc.ClockID)))
+		}
+		if qdisc.Flags != 0 {
+			options.AddRtAttr(nl.TCA_TAPRIO_ATTR_FLAGS, nl.Uint32Attr(qdisc.Flags))
+		}
+		if qdisc.TxTimeDelay > 0 {
+			options.AddRtAttr(nl.TCA_TAPRIO_ATTR_TXTIME_DELAY, nl.Uint32Attr(qdisc.TxTimeDelay))
+		}
+		addTaprioSchedule(options, &qdisc.Schedule)
+	case *Etf:
+		opt := nl.TcEtfQopt{
+			Delta:   qdisc.Delta,
+			ClockID: qdisc.ClockID,
+		}
+		if qdisc.DeadlineMode {
+			opt.Flags |= nl.TC_ETF_DEADLINE_MODE_ON
+		}
+		if qdisc.Offload {
+			opt.Flags |= nl.TC_ETF_OFFLOAD_ON
+		}
+		if qdisc.SkipSockCheck {
+			opt.Flags |= nl.TC_ETF_SKIP_SOCK_CHECK
+		}
+		options.AddRtAttr(nl.TCA_ETF_PARMS, opt.Serialize())
+	case *Cbs:
+		opt := nl.TcCbsQopt{
+			HiCredit:  qdisc.HiCredit,
+			LoCredit:  qdisc.LoCredit,
+			IdleSlope: qdisc.IdleSlope,
+			SendSlope: qdisc.SendSlope,
+		}
+		if qdisc.Offload {
+			opt.Offload = 1
+		}
+		options.AddRtAttr(nl.TCA_CBS_PARMS, opt.Serialize())
+	case *Red:
+		opt := nl.TcRedQopt{
+			Limit:    qdisc.Limit,
+			QthMin:   qdisc.Min,
+			QthMax:   qdisc.Max,
+			Wlog:     qdisc.Wlog,
+			Plog:     qdisc.Plog,
+			ScellLog: qdisc.ScellLog,
+		}
+		var flags uint32
+		if qdisc.ECN {
+			flags |= nl.TC_RED_ECN
+		}
+		if qdisc.Harddrop {
+			flags |= nl.TC_RED_HARDDROP
+		}
+		if qdisc.Adaptive {
+			flags |= nl.TC_RED_ADAPTATIVE
+		}
+		// nodrop is only known to the kernels supporting TCA_RED_FLAGS, which
+		// refuse the flags to be set both ways.
+		if qdisc.Nodrop {
+			flags |= nl.TC_RED_NODROP
+			bf := make([]byte, 8)
+			native.PutUint32(bf[0:4], flags)
+			native.PutUint32(bf[4:8], nl.TC_RED_SUPPORTED_FLAGS)
+			options.AddRtAttr(nl.TCA_RED_FLAGS, bf)
+		} else {
+			opt.Flags = uint8(flags)
+		}
+		options.AddRtAttr(nl.TCA_RED_PARMS, opt.Serialize())
+		options.AddRtAttr(nl.TCA_RED_STAB, redStab(qdisc.Stab))
+		options.AddRtAttr(nl.TCA_RED_MAX_P, nl.Uint32Attr(qdisc.MaxP))
+	case *Choke:
+		opt := nl.TcRedQopt{
+			Limit:    qdisc.Limit,
+			QthMin:   qdisc.Min,
+			QthMax:   qdisc.Max,
+			Wlog:     qdisc.Wlog,
+			Plog:     qdisc.Plog,
+			ScellLog: qdisc.ScellLog,
+		}
+		if qdisc.ECN {
+			opt.Flags |= nl.TC_RED_ECN
+		}
+		if qdisc.Harddrop {
+			opt.Flags |= nl.TC_RED_HARDDROP
+		}
+		options.AddRtAttr(nl.TCA_CHOKE_PARMS, opt.Serialize())
+		options.AddRtAttr(nl.TCA_CHOKE_STAB, redStab(qdisc.Stab))
+		options.AddRtAttr(nl.TCA_CHOKE_MAX_P, nl.Uint32Attr(qdisc.MaxP))
+	case *Gred:
+		if qdisc.DPs == 0 || qdisc.DPs > nl.MAX_DPs {
+			return fmt.Errorf("Gred DPs must be between 1 and %d", nl.MAX_DPs)
+		}
+		if qdisc.DefaultDP >= qdisc.DPs {
+			return fmt.Errorf("Gred DefaultDP must be lower than DPs")
+		}
+		opt := nl.TcGredSopt{
+			DPs:   qdisc.DPs,
+			DefDP: qdisc.DefaultDP,
+		}
+		if qdisc.Grio {
+			opt.Grio = 1
+		}
+		if qdisc.ECN {
+			opt.Flags |= nl.TC_RED_ECN
+		}
+		if qdisc.Harddrop {
+			opt.Flags |= nl.TC_RED_HARDDROP
+		}
+		options.AddRtAttr(nl.TCA_GRED_DPS, opt.Serialize())
+		if qdisc.Limit > 0 {
+			options.AddRtAttr(nl.TCA_GRED_LIMIT, nl.Uint32Attr(qdisc.Limit))
+		}
+	case *Pie:
+		if qdisc.Target > 0 {
+			options.AddRtAttr(nl.TCA_PIE_TARGET, nl.Uint32Attr(qdisc.Target))
+		}
+		if qdisc.Limit > 0 {
+			options.AddRtAttr(nl.TCA_PIE_LIMIT, nl.Uint32Attr(qdisc.Limit))
+		}
+		if qdisc.Tupdate > 0 {
+			options.AddRtAttr(nl.TCA_PIE_TUPDATE, nl.Uint32Attr(qdisc.Tupdate))
+		}
+		if qdisc.Alpha > 0 {
+			options.AddRtAttr(nl.TCA_PIE_ALPHA, nl.Uint32Attr(qdisc.Alpha))
+		}
+		if qdisc.Beta > 0 {
+			options.AddRtAttr(nl.TCA_PIE_BETA, nl.Uint32Attr(qdisc.Beta))
+		}
+		options.AddRtAttr(nl.TCA_PIE_ECN, boolUint32Attr(qdisc.ECN))
+		options.AddRtAttr(nl.TCA_PIE_BYTEMODE, boolUint32Attr(qdisc.Bytemode))
+		if qdisc.DqRateEstimator {
+			options.AddRtAttr(nl.TCA_PIE_DQ_RATE_ESTIMATOR, boolUint32Attr(true))
+		}
+	case *Codel:
+		options.AddRtAttr(nl.TCA_CODEL_ECN, nl.Uint32Attr(qdisc.ECN))
+		if qdisc.Target > 0 {
+			options.AddRtAttr(nl.TCA_CODEL_TARGET, nl.Uint32Attr(qdisc.Target))
+		}
+		if qdisc.Limit > 0 {
+			options.AddRtAttr(nl.TCA_CODEL_LIMIT, nl.Uint32Attr(qdisc.Limit))
+		}
+		if qdisc.Interval > 0 {
+			options.AddRtAttr(nl.TCA_CODEL_INTERVAL, nl.Uint32Attr(qdisc.Interval))
+		}
+		if qdisc.CEThreshold > 0 {
+			options.AddRtAttr(nl.TCA_CODEL_CE_THRESHOLD, nl.Uint32Attr(qdisc.CEThreshold))
+		}
+	case *Multiq:
+		// the number of bands is always the number of tx queues
+		opt := nl.TcMultiqQopt{}
+		options = nl.NewRtAttr(nl.TCA_OPTIONS, opt.Serialize())
+	case *Clsact:
+		// clsact is attached to the ingress parent, HANDLE_CLSACT
+		if qdisc.Attrs().Parent != HANDLE_CLSACT {
+			return fmt.Errorf("Clsact qdisc must set Parent to HANDLE_CLSACT")
+		}
+		options = nil
 	default:
 		options = nil
 	}
@@ -304,6 +969,44 @@ func qdiscPayload(req *nl.NetlinkRequest, qdisc Qdisc) error {
 	return nil
 }
 
+func addTaprioSchedule(options *nl.RtAttr, sched *TaprioSchedule) {
+	if sched.BaseTime != 0 {
+		options.AddRtAttr(nl.TCA_TAPRIO_ATTR_SCHED_BASE_TIME, nl.Uint64Attr(uint64(sched.BaseTime)))
+	}
+	if sched.CycleTime != 0 {
+		options.AddRtAttr(nl.TCA_TAPRIO_ATTR_SCHED_CYCLE_TIME, nl.Uint64Attr(uint64(sched.CycleTime)))
+	}
+	if sched.CycleTimeExtension != 0 {
+		options.AddRtAttr(nl.TCA_TAPRIO_ATTR_SCHED_CYCLE_TIME_EXTENSION, nl.Uint64Attr(uint64(sched.CycleTimeExtension)))
+	}
+	if len(sched.Entries) > 0 {
+		list := options.AddRtAttr(nl.TCA_TAPRIO_ATTR_SCHED_ENTRY_LIST, nil)
+		for _, entry := range sched.Entries {
+			e := list.AddRtAttr(nl.TCA_TAPRIO_SCHED_ENTRY, nil)
+			e.AddRtAttr(nl.TCA_TAPRIO_SCHED_ENTRY_CMD, nl.Uint8Attr(entry.Command))
+			e.AddRtAttr(nl.TCA_TAPRIO_SCHED_ENTRY_GATE_MASK, nl.Uint32Attr(entry.GateMask))
+			e.AddRtAttr(nl.TCA_TAPRIO_SCHED_ENTRY_INTERVAL, nl.Uint32Attr(entry.Interval))
+		}
+	}
+}
+
+// redStab returns the idle damping table to send for the RED family of
+// qdiscs, the kernel requires one even when no damping is wanted.
+func redStab(stab []byte) []byte {
+	if len(stab) == nl.RED_STAB_SIZE {
+		return stab
+	}
+	return make([]byte, nl.RED_STAB_SIZE)
+}
+
+func boolUint32Attr(val bool) []byte {
+	var v uint32
+	if val {
+		v = 1
+	}
+	return nl.Uint32Attr(v)
+}
+
 // QdiscList gets a list of qdiscs in the system.
 // Equivalent to: `tc qdisc show`.
 // The list can be filtered by link.
@@ -380,6 +1083,34 @@ func (h *Handle) QdiscList(link Link) ([]Qdisc, error) {
 					qdisc = &Netem{}
 				case "sfq":
 					qdisc = &Sfq{}
+				case "cake":
+					qdisc = &Cake{}
+				case "mqprio":
+					qdisc = &Mqprio{}
+				case "taprio":
+					qdisc = &Taprio{}
+				case "etf":
+					qdisc = &Etf{}
+				case "cbs":
+					qdisc = &Cbs{}
+				case "clsact":
+					qdisc = &Clsact{}
+				case "red":
+					qdisc = &Red{}
+				case "gred":
+					qdisc = &Gred{}
+				case "pie":
+					qdisc = &Pie{}
+				case "codel":
+					qdisc = &Codel{}
+				case "choke":
+					qdisc = &Choke{}
+				case "drr":
+					qdisc = &Drr{}
+				case "qfq":
+					qdisc = &Qfq{}
+				case "multiq":
+					qdisc = &Multiq{}
 				default:
 					qdisc = &GenericQdisc{QdiscType: qdiscType}
 				}
@@ -439,8 +1170,110 @@ func (h *Handle) QdiscList(link Link) ([]Qdisc, error) {
 					if err := parseSfqData(qdisc, attr.Value); err != nil {
 						return nil, err
 					}
+				case "cake":
+					data, err := nl.ParseRouteAttr(attr.Value)
+					if err != nil {
+						return nil, err
+					}
+					if err := parseCakeData(qdisc, data); err != nil {
+						return nil, err
+					}
+				case "mqprio":
+					if err := parseMqprioData(qdisc, attr.Value); err != nil {
+						return nil, err
+					}
+				case "taprio":
+					data, err := nl.ParseRouteAttr(attr.Value)
+					if err != nil {
+						return nil, err
+					}
+					if err := parseTaprioData(qdisc, data); err != nil {
+						return nil, err
+					}
+				case "etf":
+					data, err := nl.ParseRouteAttr(attr.Value)
+					if err != nil {
+						return nil, err
+					}
+					if err := parseEtfData(qdisc, data); err != nil {
+						return nil, err
+					}
+				case "cbs":
+					data, err := nl.ParseRouteAttr(attr.Value)
+					if err != nil {
+						return nil, err
+					}
+					if err := parseCbsData(qdisc, data); err != nil {
+						return nil, err
+					}
+				case "red":
+					data, err := nl.ParseRouteAttr(attr.Value)
+					if err != nil {
+						return nil, err
+					}
+					if err := parseRedData(qdisc, data); err != nil {
+						return nil, err
+					}
+				case "gred":
+					data, err := nl.ParseRouteAttr(attr.Value)
+					if err != nil {
+						return nil, err
+					}
+					if err := parseGredData(qdisc, data); err != nil {
+						return nil, err
+					}
+				case "pie":
+					data, err := nl.ParseRouteAttr(attr.Value)
+					if err != nil {
+						return nil, err
+					}
+					if err := parsePieData(qdisc, data); err != nil {
+						return nil, err
+					}
+				case "codel":
+					data, err := nl.ParseRouteAttr(attr.Value)
+					if err != nil {
+						return nil, err
+					}
+					if err := parseCodelData(qdisc, data); err != nil {
+						return nil, err
+					}
+				case "choke":
+					data, err := nl.ParseRouteAttr(attr.Value)
+					if err != nil {
+						return nil, err
+					}
+					if err := parseChokeData(qdisc, data); err != nil {
+						return nil, err
+					}
+				case "multiq":
+					// multiq returns TcMultiqQopt directly without wrapping it in rtattr
+					if err := parseMultiqData(qdisc, attr.Value); err != nil {
+						return nil, err
+					}
 
-					// no options for ingress
+					// no options for ingress, clsact, drr and qfq
+				}
+			case nl.TCA_STATS2:
+				s, err := parseTcStats2(attr.Value)
+				if err != nil {
+					return nil, err
+				}
+				base.Statistics = (*QdiscStatistics)(s)
+			case nl.TCA_STATS:
+				// Only fall back to the legacy tc_stats when TCA_STATS2 is
+				// missing, as the latter carries requeues and 64bit rates.
+				if base.Statistics != nil {
+					continue
+				}
+				s, err := parseTcStats(attr.Value)
+				if err != nil {
+					return nil, err
+				}
+				base.Statistics = (*QdiscStatistics)(s)
+			case nl.TCA_XSTATS:
+				if err := parseQdiscXstats(qdisc, attr.Value); err != nil {
+					return nil, err
 				}
 			}
 		}
@@ -556,6 +1389,7 @@ func parseFqData(qdisc Qdisc, data []syscall.NetlinkRouteAttr) error {
 }
 
 func parseNetemData(qdisc Qdisc, value []byte) error {
+	native = nl.NativeEndian()
 	netem := qdisc.(*Netem)
 	opt := nl.DeserializeTcNetemQopt(value)
 	netem.Latency = opt.Latency
@@ -583,11 +1417,149 @@ func parseNetemData(qdisc Qdisc, value []byte) error {
 			opt := nl.DeserializeTcNetemReorder(datum.Value)
 			netem.ReorderProb = opt.Probability
 			netem.ReorderCorr = opt.Correlation
+		case nl.TCA_NETEM_LATENCY64:
+			// the kernel always dumps the 64bit delays, they are only reported
+			// when Latency and Jitter can't hold them so that these stay editable
+			if latency := int64(native.Uint64(datum.Value[0:8])); latency != tick2Nsec(netem.Latency) {
+				netem.Latency64 = latency
+			}
+		case nl.TCA_NETEM_JITTER64:
+			if jitter := int64(native.Uint64(datum.Value[0:8])); jitter != tick2Nsec(netem.Jitter) {
+				netem.Jitter64 = jitter
+			}
+		case nl.TCA_NETEM_LOSS:
+			models, err := nl.ParseRouteAttr(datum.Value)
+			if err != nil {
+				return err
+			}
+			for _, model := range models {
+				switch model.Attr.Type {
+				case nl.NETEM_LOSS_GI:
+					opt := nl.DeserializeTcNetemGimodel(model.Value)
+					netem.LossState = &NetemLossState{
+						P13: opt.P13,
+						P31: opt.P31,
+						P32: opt.P32,
+						P14: opt.P14,
+						P23: opt.P23,
+					}
+				case nl.NETEM_LOSS_GE:
+					opt := nl.DeserializeTcNetemGemodel(model.Value)
+					netem.LossGEModel = &NetemLossGEModel{
+						P:  opt.P,
+						R:  opt.R,
+						H:  opt.H,
+						K1: opt.K1,
+					}
+				}
+			}
+		case nl.TCA_NETEM_RATE:
+			opt := nl.DeserializeTcNetemRate(datum.Value)
+			// the 64bit rate, when reported, takes precedence
+			if netem.Rate == 0 {
+				netem.Rate = uint64(opt.Rate)
+			}
+			netem.PacketOverhead = opt.PacketOverhead
+			netem.CellSize = opt.CellSize
+			netem.CellOverhead = opt.CellOverhead
+		case nl.TCA_NETEM_RATE64:
+			netem.Rate = native.Uint64(datum.Value[0:8])
+		case nl.TCA_NETEM_ECN:
+			netem.ECN = native.Uint32(datum.Value[0:4]) != 0
+		case nl.TCA_NETEM_SLOT:
+			opt := nl.DeserializeTcNetemSlot(datum.Value)
+			netem.Slot = &NetemSlot{
+				MinDelay:   opt.MinDelay,
+				MaxDelay:   opt.MaxDelay,
+				MaxPackets: opt.MaxPackets,
+				MaxBytes:   opt.MaxBytes,
+				DistDelay:  opt.DistDelay,
+				DistJitter: opt.DistJitter,
+			}
+			// the kernel reports unlimited slots as INT_MAX
+			if netem.Slot.MaxPackets == math.MaxInt32 {
+				netem.Slot.MaxPackets = 0
+			}
+			if netem.Slot.MaxBytes == math.MaxInt32 {
+				netem.Slot.MaxBytes = 0
+			}
+		case nl.TCA_NETEM_PRNG_SEED:
+			netem.PrngSeed = native.Uint64(datum.Value[0:8])
+		}
+	}
+	return nil
+}
+
+// parseQdiscXstats decodes the qdisc specific statistics (TCA_XSTATS) of the
+// qdisc types that report them. Other types are left untouched.
+func parseQdiscXstats(qdisc Qdisc, data []byte) error {
+	switch qdisc := qdisc.(type) {
+	case *FqCodel:
+		native = nl.NativeEndian()
+		if len(data) < 4 || native.Uint32(data[0:4]) != nl.TCA_FQ_CODEL_XSTATS_QDISC {
+			return nil
+		}
+		qdisc.Stats = &FqCodelQdStats{}
+		return parseXstats(data[4:], qdisc.Stats)
+	case *Fq:
+		qdisc.Stats = &FqQdStats{}
+		return parseXstats(data, qdisc.Stats)
+	case *Red:
+		qdisc.Stats = &RedXstats{}
+		return parseXstats(data, qdisc.Stats)
+	case *Choke:
+		qdisc.Stats = &ChokeXstats{}
+		return parseXstats(data, qdisc.Stats)
+	case *Codel:
+		qdisc.Stats = &CodelXstats{}
+		return parseXstats(data, qdisc.Stats)
+	case *Pie:
+		qdisc.Stats = &PieXstats{}
+		if len(data) != sizeofPieXstatsV0 {
+			return parseXstats(data, qdisc.Stats)
+		}
+		// kernels before 5.7 reported a 32bit probability and no
+		// dq_rate_estimating field
+		old := struct {
+			Prob      uint32
+			Delay     uint32
+			AvgDqRate uint32
+			PacketsIn uint32
+			Dropped   uint32
+			Overlimit uint32
+			Maxq      uint32
+			EcnMark   uint32
+		}{}
+		if err := parseGnetStats(data, &old); err != nil {
+			return err
+		}
+		*qdisc.Stats = PieXstats{
+			Prob:      uint64(old.Prob),
+			Delay:     old.Delay,
+			AvgDqRate: old.AvgDqRate,
+			PacketsIn: old.PacketsIn,
+			Dropped:   old.Dropped,
+			Overlimit: old.Overlimit,
+			Maxq:      old.Maxq,
+			EcnMark:   old.EcnMark,
 		}
 	}
 	return nil
 }
 
+const sizeofPieXstatsV0 = 32
+
+// parseXstats reads an xstats structure that may have been reported by an
+// older kernel, in which case the missing trailing fields are left as zero.
+func parseXstats(data []byte, xstats interface{}) error {
+	if size := binary.Size(xstats); len(data) < size {
+		buf := make([]byte, size)
+		copy(buf, data)
+		data = buf
+	}
+	return parseGnetStats(data, xstats)
+}
+
 func parseTbfData(qdisc Qdisc, data []syscall.NetlinkRouteAttr) error {
 	native = nl.NativeEndian()
 	tbf := qdisc.(*Tbf)
@@ -621,6 +1593,364 @@ func parseSfqData(qdisc Qdisc, value []byte) error {
 	return nil
 }
 
+func parseCakeData(qdisc Qdisc, data []syscall.NetlinkRouteAttr) error {
+	native = nl.NativeEndian()
+	cake := qdisc.(*Cake)
+	for _, datum := range data {
+		switch datum.Attr.Type {
+		case nl.TCA_CAKE_BASE_RATE64:
+			cake.BaseRate = native.Uint64(datum.Value[0:8])
+		case nl.TCA_CAKE_DIFFSERV_MODE:
+			cake.DiffservMode = CakeDiffservMode(native.Uint32(datum.Value))
+		case nl.TCA_CAKE_FLOW_MODE:
+			cake.FlowMode = CakeFlowMode(native.Uint32(datum.Value))
+		case nl.TCA_CAKE_ATM:
+			cake.Atm = CakeAtmMode(native.Uint32(datum.Value))
+		case nl.TCA_CAKE_OVERHEAD:
+			cake.Overhead = int32(native.Uint32(datum.Value))
+		case nl.TCA_CAKE_MPU:
+			cake.Mpu = native.Uint32(datum.Value)
+		case nl.TCA_CAKE_RTT:
+			cake.Rtt = native.Uint32(datum.Value)
+		case nl.TCA_CAKE_TARGET:
+			cake.Target = native.Uint32(datum.Value)
+		case nl.TCA_CAKE_MEMORY:
+			cake.Memory = native.Uint32(datum.Value)
+		case nl.TCA_CAKE_AUTORATE:
+			cake.Autorate = native.Uint32(datum.Value) != 0
+		case nl.TCA_CAKE_NAT:
+			cake.Nat = native.Uint32(datum.Value) != 0
+		case nl.TCA_CAKE_RAW:
+			cake.Raw = true
+		case nl.TCA_CAKE_WASH:
+			cake.Wash = native.Uint32(datum.Value) != 0
+		case nl.TCA_CAKE_INGRESS:
+			cake.Ingress = native.Uint32(datum.Value) != 0
+		case nl.TCA_CAKE_ACK_FILTER:
+			cake.AckFilter = CakeAckFilter(native.Uint32(datum.Value))
+		case nl.TCA_CAKE_SPLIT_GSO:
+			cake.SplitGso = native.Uint32(datum.Value) != 0
+		case nl.TCA_CAKE_FWMARK:
+			cake.FwMark = native.Uint32(datum.Value)
+		}
+	}
+	return nil
+}
+
+func parseMqprioData(qdisc Qdisc, value []byte) error {
+	native = nl.NativeEndian()
+	mqprio := qdisc.(*Mqprio)
+	if len(value) < nl.SizeofTcMqprioQopt {
+		return fmt.Errorf("mqprio options too short: %d", len(value))
+	}
+	opt := nl.DeserializeTcMqprioQopt(value)
+	mqprio.NumTc = opt.NumTc
+	mqprio.PrioTcMap = opt.PrioTcMap
+	mqprio.HwOffload = opt.Hw
+	mqprio.Count = opt.Count
+	mqprio.Offset = opt.Offset
+	if len(value) <= nl.RtaAlignOf(nl.SizeofTcMqprioQopt) {
+		return nil
+	}
+	data, err := nl.ParseRouteAttr(value[nl.RtaAlignOf(nl.SizeofTcMqprioQopt):])
+	if err != nil {
+		return err
+	}
+	for _, datum := range data {
+		switch datum.Attr.Type {
+		case nl.TCA_MQPRIO_MODE:
+			mqprio.Mode = MqprioMode(native.Uint16(datum.Value))
+		case nl.TCA_MQPRIO_SHAPER:
+			mqprio.Shaper = MqprioShaper(native.Uint16(datum.Value))
+		case nl.TCA_MQPRIO_MIN_RATE64, nl.TCA_MQPRIO_MAX_RATE64:
+			rates, err := nl.ParseRouteAttr(datum.Value)
+			if err != nil {
+				return err
+			}
+			var res []uint64
+			for _, rate := range rates {
+				res = append(res, native.Uint64(rate.Value[0:8]))
+			}
+			if datum.Attr.Type == nl.TCA_MQPRIO_MIN_RATE64 {
+				mqprio.MinRate = res
+			} else {
+				mqprio.MaxRate = res
+			}
+		}
+	}
+	return nil
+}
+
+func parseTaprioData(qdisc Qdisc, data []syscall.NetlinkRouteAttr) error {
+	native = nl.NativeEndian()
+	taprio := qdisc.(*Taprio)
+	for _, datum := range data {
+		switch datum.Attr.Type {
+		case nl.TCA_TAPRIO_ATTR_PRIOMAP:
+			if len(datum.Value) < nl.SizeofTcMqprioQopt {
+				return fmt.Errorf("taprio priomap too short: %d", len(datum.Value))
+			}
+			opt := nl.DeserializeTcMqprioQopt(datum.Value)
+			taprio.NumTc = opt.NumTc
+			taprio.PrioTcMap = opt.PrioTcMap
+			taprio.Count = opt.Count
+			taprio.Offset = opt.Offset
+		case nl.TCA_TAPRIO_ATTR_SCHED_CLOCKID:
+			taprio.ClockID = int32(native.Uint32(datum.Value))
+		case nl.TCA_TAPRIO_ATTR_FLAGS:
+			taprio.Flags = native.Uint32(datum.Value)
+		case nl.TCA_TAPRIO_ATTR_TXTIME_DELAY:
+			taprio.TxTimeDelay = native.Uint32(datum.Value)
+		case nl.TCA_TAPRIO_ATTR_ADMIN_SCHED:
+			attrs, err := nl.ParseRouteAttr(datum.Value)
+			if err != nil {
+				return err
+			}
+			taprio.AdminSchedule = &TaprioSchedule{}
+			if err := parseTaprioSchedule(taprio.AdminSchedule, attrs); err != nil {
+				return err
+			}
+		}
+	}
+	return parseTaprioSchedule(&taprio.Schedule, data)
+}
+
+func parseTaprioSchedule(sched *TaprioSchedule, data []syscall.NetlinkRouteAttr) error {
+	for _, datum := range data {
+		switch datum.Attr.Type {
+		case nl.TCA_TAPRIO_ATTR_SCHED_BASE_TIME:
+			sched.BaseTime = int64(native.Uint64(datum.Value[0:8]))
+		case nl.TCA_TAPRIO_ATTR_SCHED_CYCLE_TIME:
+			sched.CycleTime = int64(native.Uint64(datum.Value[0:8]))
+		case nl.TCA_TAPRIO_ATTR_SCHED_CYCLE_TIME_EXTENSION:
+			sched.CycleTimeExtension = int64(native.Uint64(datum.Value[0:8]))
+		case nl.TCA_TAPRIO_ATTR_SCHED_ENTRY_LIST:
+			entries, err := nl.ParseRouteAttr(datum.Value)
+			if err != nil {
+				return err
+			}
+			for _, e := range entries {
+				attrs, err := nl.ParseRouteAttr(e.Value)
+				if err != nil {
+					return err
+				}
+				var entry TaprioSchedEntry
+				for _, attr := range attrs {
+					switch attr.Attr.Type {
+					case nl.TCA_TAPRIO_SCHED_ENTRY_INDEX:
+						entry.Index = native.Uint32(attr.Value)
+					case nl.TCA_TAPRIO_SCHED_ENTRY_CMD:
+						entry.Command = attr.Value[0]
+					case nl.TCA_TAPRIO_SCHED_ENTRY_GATE_MASK:
+						entry.GateMask = native.Uint32(attr.Value)
+					case nl.TCA_TAPRIO_SCHED_ENTRY_INTERVAL:
+						entry.Interval = native.Uint32(attr.Value)
+					}
+				}
+				sched.Entries = append(sched.Entries, entry)
+			}
+		}
+	}
+	return nil
+}
+
+func parseEtfData(qdisc Qdisc, data []syscall.NetlinkRouteAttr) error {
+	etf := qdisc.(*Etf)
+	for _, datum := range data {
+		switch datum.Attr.Type {
+		case nl.TCA_ETF_PARMS:
+			opt := nl.DeserializeTcEtfQopt(datum.Value)
+			etf.Delta = opt.Delta
+			etf.ClockID = opt.ClockID
+			etf.DeadlineMode = opt.Flags&nl.TC_ETF_DEADLINE_MODE_ON != 0
+			etf.Offload = opt.Flags&nl.TC_ETF_OFFLOAD_ON != 0
+			etf.SkipSockCheck = opt.Flags&nl.TC_ETF_SKIP_SOCK_CHECK != 0
+		}
+	}
+	return nil
+}
+
+func parseCbsData(qdisc Qdisc, data []syscall.NetlinkRouteAttr) error {
+	cbs := qdisc.(*Cbs)
+	for _, datum := range data {
+		switch datum.Attr.Type {
+		case nl.TCA_CBS_PARMS:
+			opt := nl.DeserializeTcCbsQopt(datum.Value)
+			cbs.Offload = opt.Offload != 0
+			cbs.HiCredit = opt.HiCredit
+			cbs.LoCredit = opt.LoCredit
+			cbs.IdleSlope = opt.IdleSlope
+			cbs.SendSlope = opt.SendSlope
+		}
+	}
+	return nil
+}
+
+func parseRedData(qdisc Qdisc, data []syscall.NetlinkRouteAttr) error {
+	native = nl.NativeEndian()
+	red := qdisc.(*Red)
+	for _, datum := range data {
+		switch datum.Attr.Type {
+		case nl.TCA_RED_PARMS:
+			opt := nl.DeserializeTcRedQopt(datum.Value)
+			red.Limit = opt.Limit
+			red.Min = opt.QthMin
+			red.Max = opt.QthMax
+			red.Wlog = opt.Wlog
+			red.Plog = opt.Plog
+			red.ScellLog = opt.ScellLog
+			red.ECN = opt.Flags&nl.TC_RED_ECN != 0
+			red.Harddrop = opt.Flags&nl.TC_RED_HARDDROP != 0
+			red.Adaptive = opt.Flags&nl.TC_RED_ADAPTATIVE != 0
+		case nl.TCA_RED_MAX_P:
+			red.MaxP = native.Uint32(datum.Value[0:4])
+		case nl.TCA_RED_FLAGS:
+			flags := native.Uint32(datum.Value[0:4])
+			red.ECN = flags&nl.TC_RED_ECN != 0
+			red.Harddrop = flags&nl.TC_RED_HARDDROP != 0
+			red.Adaptive = flags&nl.TC_RED_ADAPTATIVE != 0
+			red.Nodrop = flags&nl.TC_RED_NODROP != 0
+		}
+	}
+	return nil
+}
+
+func parseChokeData(qdisc Qdisc, data []syscall.NetlinkRouteAttr) error {
+	native = nl.NativeEndian()
+	choke := qdisc.(*Choke)
+	for _, datum := range data {
+		switch datum.Attr.Type {
+		case nl.TCA_CHOKE_PARMS:
+			opt := nl.DeserializeTcRedQopt(datum.Value)
+			choke.Limit = opt.Limit
+			choke.Min = opt.QthMin
+			choke.Max = opt.QthMax
+			choke.Wlog = opt.Wlog
+			choke.Plog = opt.Plog
+			choke.ScellLog = opt.ScellLog
+			choke.ECN = opt.Flags&nl.TC_RED_ECN != 0
+			choke.Harddrop = opt.Flags&nl.TC_RED_HARDDROP != 0
+		case nl.TCA_CHOKE_MAX_P:
+			choke.MaxP = native.Uint32(datum.Value[0:4])
+		}
+	}
+	return nil
+}
+
+func parseGredData(qdisc Qdisc, data []syscall.NetlinkRouteAttr) error {
+	native = nl.NativeEndian()
+	gred := qdisc.(*Gred)
+	var maxP []uint32
+	for _, datum := range data {
+		switch datum.Attr.Type {
+		case nl.TCA_GRED_DPS:
+			opt := nl.DeserializeTcGredSopt(datum.Value)
+			gred.DPs = opt.DPs
+			gred.DefaultDP = opt.DefDP
+			gred.Grio = opt.Grio != 0
+			gred.ECN = opt.Flags&nl.TC_RED_ECN != 0
+			gred.Harddrop = opt.Flags&nl.TC_RED_HARDDROP != 0
+		case nl.TCA_GRED_LIMIT:
+			gred.Limit = native.Uint32(datum.Value[0:4])
+		case nl.TCA_GRED_MAX_P:
+			for i := 0; i+4 <= len(datum.Value); i += 4 {
+				maxP = append(maxP, native.Uint32(datum.Value[i:i+4]))
+			}
+		case nl.TCA_GRED_PARMS:
+			// the kernel reports all the MAX_DPs slots, the unused ones
+			// having their DP set to MAX_DPs
+			gred.VQs = nil
+			for i := 0; i+nl.SizeofTcGredQopt <= len(datum.Value); i += nl.SizeofTcGredQopt {
+				opt := nl.DeserializeTcGredQopt(datum.Value[i:])
+				if opt.DP >= nl.MAX_DPs {
+					continue
+				}
+				gred.VQs = append(gred.VQs, GredVQ{
+					DP:       opt.DP,
+					Limit:    opt.Limit,
+					Min:      opt.QthMin,
+					Max:      opt.QthMax,
+					Wlog:     opt.Wlog,
+					Plog:     opt.Plog,
+					ScellLog: opt.ScellLog,
+					Prio:     opt.Prio,
+					Backlog:  opt.Backlog,
+					Qave:     opt.Qave,
+					Forced:   opt.Forced,
+					Early:    opt.Early,
+					Other:    opt.Other,
+					Pdrop:    opt.Pdrop,
+					Packets:  opt.Packets,
+					Bytesin:  opt.Bytesin,
+				})
+			}
+		}
+	}
+	for i := range gred.VQs {
+		if dp := gred.VQs[i].DP; int(dp) < len(maxP) {
+			gred.VQs[i].MaxP = maxP[dp]
+		}
+	}
+	return nil
+}
+
+func parsePieData(qdisc Qdisc, data []syscall.NetlinkRouteAttr) error {
+	native = nl.NativeEndian()
+	pie := qdisc.(*Pie)
+	for _, datum := range data {
+		switch datum.Attr.Type {
+		case nl.TCA_PIE_TARGET:
+			pie.Target = native.Uint32(datum.Value)
+		case nl.TCA_PIE_LIMIT:
+			pie.Limit = native.Uint32(datum.Value)
+		case nl.TCA_PIE_TUPDATE:
+			pie.Tupdate = native.Uint32(datum.Value)
+		case nl.TCA_PIE_ALPHA:
+			pie.Alpha = native.Uint32(datum.Value)
+		case nl.TCA_PIE_BETA:
+			pie.Beta = native.Uint32(datum.Value)
+		case nl.TCA_PIE_ECN:
+			pie.ECN = native.Uint32(datum.Value) != 0
+		case nl.TCA_PIE_BYTEMODE:
+			pie.Bytemode = native.Uint32(datum.Value) != 0
+		case nl.TCA_PIE_DQ_RATE_ESTIMATOR:
+			pie.DqRateEstimator = native.Uint32(datum.Value) != 0
+		}
+	}
+	return nil
+}
+
+func parseCodelData(qdisc Qdisc, data []syscall.NetlinkRouteAttr) error {
+	native = nl.NativeEndian()
+	codel := qdisc.(*Codel)
+	for _, datum := range data {
+		switch datum.Attr.Type {
+		case nl.TCA_CODEL_TARGET:
+			codel.Target = native.Uint32(datum.Value)
+		case nl.TCA_CODEL_LIMIT:
+			codel.Limit = native.Uint32(datum.Value)
+		case nl.TCA_CODEL_INTERVAL:
+			codel.Interval = native.Uint32(datum.Value)
+		case nl.TCA_CODEL_ECN:
+			codel.ECN = native.Uint32(datum.Value)
+		case nl.TCA_CODEL_CE_THRESHOLD:
+			codel.CEThreshold = native.Uint32(datum.Value)
+		}
+	}
+	return nil
+}
+
+func parseMultiqData(qdisc Qdisc, value []byte) error {
+	multiq := qdisc.(*Multiq)
+	if len(value) < nl.SizeofTcMultiqQopt {
+		return fmt.Errorf("multiq options too short: %d", len(value))
+	}
+	opt := nl.DeserializeTcMultiqQopt(value)
+	multiq.Bands = opt.Bands
+	multiq.MaxBands = opt.MaxBands
+	return nil
+}
+
 const (
 	TIME_UNITS_PER_SEC = 1000000
 )
@@ -691,6 +2021,14 @@ func tick2Time(tick uint32) uint32 {
 	return uint32(float64(tick) / TickInUsec())
 }
 
+// tick2Nsec converts ticks to ns, the unit of the 64bit netem delays
+func tick2Nsec(tick uint32) int64 {
+	if TickInUsec() == 0.0 {
+		return -1
+	}
+	return int64(float64(tick) * 1000 / TickInUsec())
+}
+
 func time2Ktime(time uint32) uint32 {
 	return uint32(float64(time) * ClockFactor())
 }
@@ -3,7 +3,12 @@
 package netlink
 
 import (
+	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/ndupreez/netlink/nl"
+	"golang.org/x/sys/unix"
 )
 
 func TestTbfAddDel(t *testing.T) {
@@ -561,3 +566,996 @@ func TestIngressAddDel(t *testing.T) {
 		t.Fatal("Failed to remove qdisc")
 	}
 }
+
+func TestFqCodelStatistics(t *testing.T) {
+	minKernelRequired(t, 3, 4)
+
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+	if err := LinkAdd(&Ifb{LinkAttrs{Name: "foo"}}); err != nil {
+		t.Fatal(err)
+	}
+	link, err := LinkByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := LinkSetUp(link); err != nil {
+		t.Fatal(err)
+	}
+	qdisc := NewFqCodel(QdiscAttrs{
+		LinkIndex: link.Attrs().Index,
+		Handle:    MakeHandle(1, 0),
+		Parent:    HANDLE_ROOT,
+	})
+	qdisc.Flows = 512
+	if err := QdiscAdd(qdisc); err != nil {
+		t.Fatal(err)
+	}
+	qdiscs, err := SafeQdiscList(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(qdiscs) != 1 {
+		t.Fatal("Failed to add qdisc")
+	}
+	fqcodel, ok := qdiscs[0].(*FqCodel)
+	if !ok {
+		t.Fatal("Qdisc is the wrong type")
+	}
+	stats := fqcodel.Attrs().Statistics
+	if stats == nil {
+		t.Fatal("Statistics were not parsed")
+	}
+	if stats.Basic == nil || stats.Queue == nil || stats.RateEst == nil {
+		t.Fatalf("Incomplete statistics: %+v", stats)
+	}
+	if stats.Queue.Drops != 0 || stats.Queue.Backlog != 0 {
+		t.Fatalf("Unexpected queue statistics: %+v", stats.Queue)
+	}
+	if fqcodel.Stats == nil {
+		t.Fatal("Xstats were not parsed")
+	}
+	if fqcodel.Stats.NewFlowsLen != 0 || fqcodel.Stats.OldFlowsLen != 0 {
+		t.Fatalf("Unexpected xstats: %+v", fqcodel.Stats)
+	}
+
+	if err := QdiscDel(qdisc); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseQdiscXstats(t *testing.T) {
+	native := nl.NativeEndian()
+
+	// tc_fq_codel_xstats: type followed by tc_fq_codel_qd_stats, as sent by
+	// a kernel that does not know about memory_usage and drop_overmemory.
+	data := make([]byte, 4+7*4)
+	native.PutUint32(data[0:], nl.TCA_FQ_CODEL_XSTATS_QDISC)
+	native.PutUint32(data[4:], 1514)
+	native.PutUint32(data[8:], 3)
+	native.PutUint32(data[20:], 7)
+	fqcodel := &FqCodel{}
+	if err := parseQdiscXstats(fqcodel, data); err != nil {
+		t.Fatal(err)
+	}
+	expected := &FqCodelQdStats{MaxPacket: 1514, DropOverlimit: 3, NewFlowsLen: 7}
+	if !reflect.DeepEqual(fqcodel.Stats, expected) {
+		t.Fatalf("%#v is expected but it actually was %#v", expected, fqcodel.Stats)
+	}
+
+	// Class xstats must not be mistaken for qdisc xstats.
+	native.PutUint32(data[0:], nl.TCA_FQ_CODEL_XSTATS_CLASS)
+	fqcodel = &FqCodel{}
+	if err := parseQdiscXstats(fqcodel, data); err != nil {
+		t.Fatal(err)
+	}
+	if fqcodel.Stats != nil {
+		t.Fatal("Class xstats were parsed as qdisc xstats")
+	}
+
+	data = make([]byte, 8*8+4*4)
+	native.PutUint64(data[0:], 5)
+	native.PutUint32(data[64:], 42)
+	native.PutUint32(data[68:], 40)
+	fq := &Fq{}
+	if err := parseQdiscXstats(fq, data); err != nil {
+		t.Fatal(err)
+	}
+	if fq.Stats == nil || fq.Stats.GcFlows != 5 || fq.Stats.Flows != 42 || fq.Stats.InactiveFlows != 40 {
+		t.Fatalf("Unexpected fq xstats: %+v", fq.Stats)
+	}
+	// tc_pie_xstats as sent by kernels before the 64bit probability.
+	data = make([]byte, 8*4)
+	native.PutUint32(data[0:], 1000)
+	native.PutUint32(data[4:], 12)
+	native.PutUint32(data[28:], 9)
+	pie := &Pie{}
+	if err := parseQdiscXstats(pie, data); err != nil {
+		t.Fatal(err)
+	}
+	if pie.Stats == nil || pie.Stats.Prob != 1000 || pie.Stats.Delay != 12 || pie.Stats.EcnMark != 9 {
+		t.Fatalf("Unexpected pie xstats: %+v", pie.Stats)
+	}
+
+	data = make([]byte, 8+8*4)
+	native.PutUint64(data[0:], 1<<40)
+	native.PutUint32(data[16:], 1)
+	native.PutUint32(data[36:], 9)
+	pie = &Pie{}
+	if err := parseQdiscXstats(pie, data); err != nil {
+		t.Fatal(err)
+	}
+	if pie.Stats == nil || pie.Stats.Prob != 1<<40 || pie.Stats.DqRateEstimating != 1 || pie.Stats.EcnMark != 9 {
+		t.Fatalf("Unexpected pie xstats: %+v", pie.Stats)
+	}
+}
+
+func TestCakeAddChangeDel(t *testing.T) {
+	minKernelRequired(t, 4, 19)
+
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+	if err := LinkAdd(&Ifb{LinkAttrs{Name: "foo"}}); err != nil {
+		t.Fatal(err)
+	}
+	link, err := LinkByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := LinkSetUp(link); err != nil {
+		t.Fatal(err)
+	}
+	qdisc := NewCake(QdiscAttrs{
+		LinkIndex: link.Attrs().Index,
+		Handle:    MakeHandle(1, 0),
+		Parent:    HANDLE_ROOT,
+	})
+	qdisc.BaseRate = 12500000
+	qdisc.DiffservMode = CAKE_DIFFSERV_DIFFSERV4
+	qdisc.Overhead = 18
+	qdisc.Nat = true
+	if err := QdiscAdd(qdisc); err != nil {
+		t.Fatal(err)
+	}
+	qdiscs, err := SafeQdiscList(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(qdiscs) != 1 {
+		t.Fatal("Failed to add qdisc")
+	}
+	cake, ok := qdiscs[0].(*Cake)
+	if !ok {
+		t.Fatal("Qdisc is the wrong type")
+	}
+	if cake.BaseRate != qdisc.BaseRate {
+		t.Fatal("BaseRate does not match")
+	}
+	if cake.DiffservMode != qdisc.DiffservMode {
+		t.Fatal("DiffservMode does not match")
+	}
+	if cake.FlowMode != CAKE_FLOW_TRIPLE {
+		t.Fatal("FlowMode does not match")
+	}
+	if cake.Overhead != qdisc.Overhead {
+		t.Fatal("Overhead does not match")
+	}
+	if !cake.Nat {
+		t.Fatal("Nat does not match")
+	}
+
+	qdisc.Nat = false
+	qdisc.AckFilter = CAKE_ACK_FILTER
+	if err := QdiscChange(qdisc); err != nil {
+		t.Fatal(err)
+	}
+	qdiscs, err = SafeQdiscList(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cake, ok = qdiscs[0].(*Cake)
+	if !ok {
+		t.Fatal("Qdisc is the wrong type")
+	}
+	if cake.Nat {
+		t.Fatal("Nat was not changed")
+	}
+	if cake.AckFilter != CAKE_ACK_FILTER {
+		t.Fatal("AckFilter was not changed")
+	}
+
+	if err := QdiscDel(qdisc); err != nil {
+		t.Fatal(err)
+	}
+	qdiscs, err = SafeQdiscList(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(qdiscs) != 0 {
+		t.Fatal("Failed to remove qdisc")
+	}
+}
+
+func TestMqprioAddDel(t *testing.T) {
+	minKernelRequired(t, 4, 15)
+
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+	if err := LinkAdd(&Dummy{LinkAttrs{Name: "foo", NumTxQueues: 4}}); err != nil {
+		t.Fatal(err)
+	}
+	link, err := LinkByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := LinkSetUp(link); err != nil {
+		t.Fatal(err)
+	}
+	qdisc := &Mqprio{
+		QdiscAttrs: QdiscAttrs{
+			LinkIndex: link.Attrs().Index,
+			Handle:    MakeHandle(1, 0),
+			Parent:    HANDLE_ROOT,
+		},
+		NumTc:     2,
+		PrioTcMap: [PRIORITY_MAP_LEN]uint8{0, 0, 0, 0, 1, 1, 1, 1},
+	}
+	qdisc.Count[0], qdisc.Offset[0] = 2, 0
+	qdisc.Count[1], qdisc.Offset[1] = 2, 2
+	if err := QdiscAdd(qdisc); err != nil {
+		t.Fatal(err)
+	}
+	qdiscs, err := QdiscList(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mqprio *Mqprio
+	for _, q := range qdiscs {
+		if m, ok := q.(*Mqprio); ok {
+			mqprio = m
+		}
+	}
+	if mqprio == nil {
+		t.Fatal("Failed to add qdisc")
+	}
+	if mqprio.NumTc != qdisc.NumTc {
+		t.Fatal("NumTc does not match")
+	}
+	if mqprio.PrioTcMap != qdisc.PrioTcMap {
+		t.Fatal("PrioTcMap does not match")
+	}
+	if mqprio.Count != qdisc.Count || mqprio.Offset != qdisc.Offset {
+		t.Fatal("Queue mapping does not match")
+	}
+	if err := QdiscDel(qdisc); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTaprioAddDel(t *testing.T) {
+	minKernelRequired(t, 5, 4)
+
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+	if err := LinkAdd(&Dummy{LinkAttrs{Name: "foo", NumTxQueues: 4}}); err != nil {
+		t.Fatal(err)
+	}
+	link, err := LinkByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := LinkSetUp(link); err != nil {
+		t.Fatal(err)
+	}
+	qdisc := &Taprio{
+		QdiscAttrs: QdiscAttrs{
+			LinkIndex: link.Attrs().Index,
+			Handle:    MakeHandle(1, 0),
+			Parent:    HANDLE_ROOT,
+		},
+		NumTc:     2,
+		PrioTcMap: [PRIORITY_MAP_LEN]uint8{0, 0, 0, 0, 1, 1, 1, 1},
+		ClockID:   unix.CLOCK_TAI,
+		Schedule: TaprioSchedule{
+			BaseTime: 1000000000,
+			Entries: []TaprioSchedEntry{
+				{Command: TAPRIO_CMD_SET_GATES, GateMask: 0x1, Interval: 300000},
+				{Command: TAPRIO_CMD_SET_GATES, GateMask: 0x2, Interval: 700000},
+			},
+		},
+	}
+	qdisc.Count[0], qdisc.Offset[0] = 2, 0
+	qdisc.Count[1], qdisc.Offset[1] = 2, 2
+	if err := QdiscAdd(qdisc); err != nil {
+		t.Fatal(err)
+	}
+	qdiscs, err := QdiscList(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var taprio *Taprio
+	for _, q := range qdiscs {
+		if m, ok := q.(*Taprio); ok {
+			taprio = m
+		}
+	}
+	if taprio == nil {
+		t.Fatal("Failed to add qdisc")
+	}
+	if taprio.NumTc != qdisc.NumTc {
+		t.Fatal("NumTc does not match")
+	}
+	if taprio.ClockID != qdisc.ClockID {
+		t.Fatal("ClockID does not match")
+	}
+	sched := taprio.Schedule
+	if taprio.AdminSchedule != nil {
+		sched = *taprio.AdminSchedule
+	}
+	if sched.BaseTime != qdisc.Schedule.BaseTime {
+		t.Fatal("BaseTime does not match")
+	}
+	if sched.CycleTime != 1000000 {
+		t.Fatalf("CycleTime %d is not the sum of the intervals", sched.CycleTime)
+	}
+	if len(sched.Entries) != 2 {
+		t.Fatalf("Expected 2 schedule entries, got %d", len(sched.Entries))
+	}
+	for i, entry := range sched.Entries {
+		expected := qdisc.Schedule.Entries[i]
+		if entry.GateMask != expected.GateMask || entry.Interval != expected.Interval {
+			t.Fatalf("Entry %d does not match: %+v", i, entry)
+		}
+	}
+	if err := QdiscDel(qdisc); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEtfAddDel(t *testing.T) {
+	minKernelRequired(t, 4, 19)
+
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+	if err := LinkAdd(&Ifb{LinkAttrs{Name: "foo"}}); err != nil {
+		t.Fatal(err)
+	}
+	link, err := LinkByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	qdisc := &Etf{
+		QdiscAttrs: QdiscAttrs{
+			LinkIndex: link.Attrs().Index,
+			Handle:    MakeHandle(1, 0),
+			Parent:    HANDLE_ROOT,
+		},
+		ClockID:      unix.CLOCK_TAI,
+		Delta:        300000,
+		DeadlineMode: true,
+	}
+	if err := QdiscAdd(qdisc); err != nil {
+		t.Fatal(err)
+	}
+	qdiscs, err := SafeQdiscList(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(qdiscs) != 1 {
+		t.Fatal("Failed to add qdisc")
+	}
+	etf, ok := qdiscs[0].(*Etf)
+	if !ok {
+		t.Fatal("Qdisc is the wrong type")
+	}
+	if etf.ClockID != qdisc.ClockID || etf.Delta != qdisc.Delta {
+		t.Fatal("Parameters do not match")
+	}
+	if !etf.DeadlineMode || etf.Offload || etf.SkipSockCheck {
+		t.Fatal("Flags do not match")
+	}
+	if err := QdiscDel(qdisc); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCbsAddChangeDel(t *testing.T) {
+	minKernelRequired(t, 4, 15)
+
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+	if err := LinkAdd(&Ifb{LinkAttrs{Name: "foo"}}); err != nil {
+		t.Fatal(err)
+	}
+	link, err := LinkByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	qdisc := &Cbs{
+		QdiscAttrs: QdiscAttrs{
+			LinkIndex: link.Attrs().Index,
+			Handle:    MakeHandle(1, 0),
+			Parent:    HANDLE_ROOT,
+		},
+		HiCredit:  30,
+		LoCredit:  -1470,
+		IdleSlope: 20000,
+		SendSlope: -980000,
+	}
+	if err := QdiscAdd(qdisc); err != nil {
+		t.Fatal(err)
+	}
+	qdisc.IdleSlope = 40000
+	qdisc.SendSlope = -960000
+	if err := QdiscChange(qdisc); err != nil {
+		t.Fatal(err)
+	}
+	qdiscs, err := SafeQdiscList(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(qdiscs) != 1 {
+		t.Fatal("Failed to add qdisc")
+	}
+	cbs, ok := qdiscs[0].(*Cbs)
+	if !ok {
+		t.Fatal("Qdisc is the wrong type")
+	}
+	if cbs.HiCredit != qdisc.HiCredit || cbs.LoCredit != qdisc.LoCredit {
+		t.Fatal("Credits do not match")
+	}
+	if cbs.IdleSlope != qdisc.IdleSlope || cbs.SendSlope != qdisc.SendSlope {
+		t.Fatal("Slopes do not match")
+	}
+	if err := QdiscDel(qdisc); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClsactAddDel(t *testing.T) {
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+	if err := LinkAdd(&Ifb{LinkAttrs{Name: "foo"}}); err != nil {
+		t.Fatal(err)
+	}
+	link, err := LinkByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	qdisc := &Clsact{
+		QdiscAttrs: QdiscAttrs{
+			LinkIndex: link.Attrs().Index,
+			Handle:    MakeHandle(0xffff, 0),
+			Parent:    HANDLE_CLSACT,
+		},
+	}
+	if err := QdiscAdd(qdisc); err != nil {
+		t.Fatal(err)
+	}
+	qdiscs, err := SafeQdiscList(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(qdiscs) != 1 {
+		t.Fatal("Failed to add qdisc")
+	}
+	if _, ok := qdiscs[0].(*Clsact); !ok {
+		t.Fatal("Qdisc is the wrong type")
+	}
+	if err := QdiscDel(qdisc); err != nil {
+		t.Fatal(err)
+	}
+	qdiscs, err = SafeQdiscList(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(qdiscs) != 0 {
+		t.Fatal("Failed to remove qdisc")
+	}
+}
+
+func TestRedEval(t *testing.T) {
+	plog, err := redEvalP(30000, 90000, 0.02)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plog != 22 {
+		t.Fatalf("Plog %d, expected 22", plog)
+	}
+	if _, err := redEvalP(90000, 30000, 0.02); err == nil {
+		t.Fatal("Inverted thresholds should fail")
+	}
+	// burst = (2*30000 + 90000) / (3*1000)
+	wlog, err := redEvalEwma(30000, 50, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wlog != 5 {
+		t.Fatalf("Wlog %d, expected 5", wlog)
+	}
+	if _, err := redEvalEwma(30000, 10, 1000); err == nil {
+		t.Fatal("Too small burst should fail")
+	}
+	// choke thresholds are scaled by avpkt like the red ones in bytes
+	choke, err := NewChoke(QdiscAttrs{}, RedQdiscAttrs{Limit: 400, Min: 30, Max: 90, Avpkt: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if choke.Plog != 22 {
+		t.Fatalf("Choke Plog %d, expected 22", choke.Plog)
+	}
+	if _, err := NewRed(QdiscAttrs{}, RedQdiscAttrs{}); err == nil {
+		t.Fatal("Missing limit should fail")
+	}
+}
+
+func TestRedAddChangeDel(t *testing.T) {
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+	if err := LinkAdd(&Ifb{LinkAttrs{Name: "foo"}}); err != nil {
+		t.Fatal(err)
+	}
+	link, err := LinkByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	qdisc, err := NewRed(QdiscAttrs{
+		LinkIndex: link.Attrs().Index,
+		Handle:    MakeHandle(1, 0),
+		Parent:    HANDLE_ROOT,
+	}, RedQdiscAttrs{
+		Limit: 400000,
+		Min:   30000,
+		Max:   90000,
+		ECN:   true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := QdiscAdd(qdisc); err != nil {
+		t.Fatal(err)
+	}
+	qdisc.Harddrop = true
+	if err := QdiscChange(qdisc); err != nil {
+		t.Fatal(err)
+	}
+	qdiscs, err := SafeQdiscList(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(qdiscs) != 1 {
+		t.Fatal("Failed to add qdisc")
+	}
+	red, ok := qdiscs[0].(*Red)
+	if !ok {
+		t.Fatal("Qdisc is the wrong type")
+	}
+	if red.Limit != qdisc.Limit || red.Min != qdisc.Min || red.Max != qdisc.Max {
+		t.Fatal("Thresholds do not match")
+	}
+	if red.Wlog != qdisc.Wlog || red.Plog != qdisc.Plog || red.MaxP != qdisc.MaxP {
+		t.Fatal("Parameters do not match")
+	}
+	if !red.ECN || !red.Harddrop {
+		t.Fatal("Flags do not match")
+	}
+	if red.Stats == nil {
+		t.Fatal("Statistics are not reported")
+	}
+	if err := QdiscDel(qdisc); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestChokeAddDel(t *testing.T) {
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+	if err := LinkAdd(&Ifb{LinkAttrs{Name: "foo"}}); err != nil {
+		t.Fatal(err)
+	}
+	link, err := LinkByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	qdisc, err := NewChoke(QdiscAttrs{
+		LinkIndex: link.Attrs().Index,
+		Handle:    MakeHandle(1, 0),
+		Parent:    HANDLE_ROOT,
+	}, RedQdiscAttrs{
+		Limit: 1000,
+		Min:   100,
+		Max:   300,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := QdiscAdd(qdisc); err != nil {
+		t.Fatal(err)
+	}
+	qdiscs, err := SafeQdiscList(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(qdiscs) != 1 {
+		t.Fatal("Failed to add qdisc")
+	}
+	choke, ok := qdiscs[0].(*Choke)
+	if !ok {
+		t.Fatal("Qdisc is the wrong type")
+	}
+	if choke.Limit != qdisc.Limit || choke.Min != qdisc.Min || choke.Max != qdisc.Max {
+		t.Fatal("Thresholds do not match")
+	}
+	if err := QdiscDel(qdisc); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGredAddDel(t *testing.T) {
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+	if err := LinkAdd(&Ifb{LinkAttrs{Name: "foo"}}); err != nil {
+		t.Fatal(err)
+	}
+	link, err := LinkByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	qdisc := &Gred{
+		QdiscAttrs: QdiscAttrs{
+			LinkIndex: link.Attrs().Index,
+			Handle:    MakeHandle(1, 0),
+			Parent:    HANDLE_ROOT,
+		},
+		DPs:       4,
+		DefaultDP: 1,
+		Grio:      true,
+	}
+	for dp := uint32(0); dp < 2; dp++ {
+		vq, err := NewGredVQ(dp, RedQdiscAttrs{Limit: 400000, Min: 30000, Max: 90000})
+		if err != nil {
+			t.Fatal(err)
+		}
+		vq.Prio = uint8(dp + 1)
+		qdisc.VQs = append(qdisc.VQs, vq)
+	}
+	if err := QdiscAdd(qdisc); err != nil {
+		t.Fatal(err)
+	}
+	qdiscs, err := SafeQdiscList(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(qdiscs) != 1 {
+		t.Fatal("Failed to add qdisc")
+	}
+	gred, ok := qdiscs[0].(*Gred)
+	if !ok {
+		t.Fatal("Qdisc is the wrong type")
+	}
+	if gred.DPs != qdisc.DPs || gred.DefaultDP != qdisc.DefaultDP || !gred.Grio {
+		t.Fatal("Table does not match")
+	}
+	if len(gred.VQs) != len(qdisc.VQs) {
+		t.Fatalf("Got %d virtual queues, expected %d", len(gred.VQs), len(qdisc.VQs))
+	}
+	for i, vq := range gred.VQs {
+		if vq.DP != qdisc.VQs[i].DP || vq.Min != qdisc.VQs[i].Min || vq.Prio != qdisc.VQs[i].Prio {
+			t.Fatalf("Virtual queue %d does not match", i)
+		}
+	}
+	if err := QdiscDel(qdisc); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPieAddDel(t *testing.T) {
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+	if err := LinkAdd(&Ifb{LinkAttrs{Name: "foo"}}); err != nil {
+		t.Fatal(err)
+	}
+	link, err := LinkByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	qdisc := &Pie{
+		QdiscAttrs: QdiscAttrs{
+			LinkIndex: link.Attrs().Index,
+			Handle:    MakeHandle(1, 0),
+			Parent:    HANDLE_ROOT,
+		},
+		Target:  20000,
+		Limit:   2000,
+		Tupdate: 30000,
+		ECN:     true,
+	}
+	if err := QdiscAdd(qdisc); err != nil {
+		t.Fatal(err)
+	}
+	qdiscs, err := SafeQdiscList(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(qdiscs) != 1 {
+		t.Fatal("Failed to add qdisc")
+	}
+	pie, ok := qdiscs[0].(*Pie)
+	if !ok {
+		t.Fatal("Qdisc is the wrong type")
+	}
+	if pie.Limit != qdisc.Limit || pie.Target != qdisc.Target || pie.Tupdate != qdisc.Tupdate {
+		t.Fatal("Parameters do not match")
+	}
+	if !pie.ECN {
+		t.Fatal("ECN does not match")
+	}
+	if pie.Stats == nil {
+		t.Fatal("Statistics are not reported")
+	}
+	if err := QdiscDel(qdisc); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCodelAddChangeDel(t *testing.T) {
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+	if err := LinkAdd(&Ifb{LinkAttrs{Name: "foo"}}); err != nil {
+		t.Fatal(err)
+	}
+	link, err := LinkByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	qdisc := NewCodel(QdiscAttrs{
+		LinkIndex: link.Attrs().Index,
+		Handle:    MakeHandle(1, 0),
+		Parent:    HANDLE_ROOT,
+	})
+	qdisc.Target = 5000
+	qdisc.Limit = 1000
+	qdisc.Interval = 100000
+	if err := QdiscAdd(qdisc); err != nil {
+		t.Fatal(err)
+	}
+	qdisc.Limit = 2000
+	if err := QdiscChange(qdisc); err != nil {
+		t.Fatal(err)
+	}
+	qdiscs, err := SafeQdiscList(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(qdiscs) != 1 {
+		t.Fatal("Failed to add qdisc")
+	}
+	codel, ok := qdiscs[0].(*Codel)
+	if !ok {
+		t.Fatal("Qdisc is the wrong type")
+	}
+	if codel.Limit != qdisc.Limit || codel.Target != qdisc.Target || codel.Interval != qdisc.Interval {
+		t.Fatal("Parameters do not match")
+	}
+	if codel.ECN != qdisc.ECN {
+		t.Fatal("ECN does not match")
+	}
+	if codel.Stats == nil {
+		t.Fatal("Statistics are not reported")
+	}
+	if err := QdiscDel(qdisc); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMultiqAddDel(t *testing.T) {
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+	if err := LinkAdd(&Dummy{LinkAttrs{Name: "foo", NumTxQueues: 4}}); err != nil {
+		t.Fatal(err)
+	}
+	link, err := LinkByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	qdisc := &Multiq{
+		QdiscAttrs: QdiscAttrs{
+			LinkIndex: link.Attrs().Index,
+			Handle:    MakeHandle(1, 0),
+			Parent:    HANDLE_ROOT,
+		},
+	}
+	if err := QdiscAdd(qdisc); err != nil {
+		t.Fatal(err)
+	}
+	qdiscs, err := SafeQdiscList(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var multiq *Multiq
+	for _, q := range qdiscs {
+		if q, ok := q.(*Multiq); ok {
+			multiq = q
+		}
+	}
+	if multiq == nil {
+		t.Fatal("Failed to add qdisc")
+	}
+	if multiq.Bands != 4 {
+		t.Fatalf("Got %d bands, expected 4", multiq.Bands)
+	}
+	if err := QdiscDel(qdisc); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNetemDistribution(t *testing.T) {
+	table, err := parseNetemDistribution(strings.NewReader(
+		"# This is the distribution table for the normal distribution.\n" +
+			" -32768 -28307 -26871\n" +
+			"\n" +
+			" -25967 32767\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []int16{-32768, -28307, -26871, -25967, 32767}
+	if !reflect.DeepEqual(table, expected) {
+		t.Fatalf("%v is expected but it actually was %v", expected, table)
+	}
+	if _, err := parseNetemDistribution(strings.NewReader("40000\n")); err == nil {
+		t.Fatal("Out of range value should fail")
+	}
+	if _, err := NetemDistribution("/nonexistent/normal.dist"); err == nil {
+		t.Fatal("Missing table should fail")
+	}
+}
+
+func TestNetemFullAddChangeDel(t *testing.T) {
+	minKernelRequired(t, 4, 19)
+
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+	if err := LinkAdd(&Ifb{LinkAttrs{Name: "foo"}}); err != nil {
+		t.Fatal(err)
+	}
+	link, err := LinkByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dist := make([]int16, 64)
+	for i := range dist {
+		dist[i] = int16(i*1024 - 32768)
+	}
+	qdisc := NewNetem(QdiscAttrs{
+		LinkIndex: link.Attrs().Index,
+		Handle:    MakeHandle(1, 0),
+		Parent:    HANDLE_ROOT,
+	}, NetemQdiscAttrs{
+		Latency:        10000,
+		Jitter:         1000,
+		DelayDist:      dist,
+		LossGEModel:    &NetemLossGEModelAttrs{P: 1, H: 10},
+		Rate:           1250000,
+		PacketOverhead: 14,
+		Slot:           &NetemSlot{MinDelay: 1000000, MaxDelay: 2000000, MaxPackets: 32},
+		ECN:            true,
+		PrngSeed:       42,
+	})
+	if err := QdiscAdd(qdisc); err != nil {
+		t.Fatal(err)
+	}
+	qdisc.LossGEModel = nil
+	qdisc.LossState = &NetemLossState{P13: Percentage2u32(1), P31: Percentage2u32(99)}
+	qdisc.Rate = 1 << 33
+	if err := QdiscChange(qdisc); err != nil {
+		t.Fatal(err)
+	}
+	qdiscs, err := SafeQdiscList(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(qdiscs) != 1 {
+		t.Fatal("Failed to add qdisc")
+	}
+	netem, ok := qdiscs[0].(*Netem)
+	if !ok {
+		t.Fatal("Qdisc is the wrong type")
+	}
+	if netem.LossState == nil || *netem.LossState != *qdisc.LossState {
+		t.Fatalf("Loss model does not match: %+v", netem.LossState)
+	}
+	if netem.Rate != qdisc.Rate || netem.PacketOverhead != qdisc.PacketOverhead {
+		t.Fatal("Rate does not match")
+	}
+	if netem.Slot == nil || *netem.Slot != *qdisc.Slot {
+		t.Fatalf("Slot does not match: %+v", netem.Slot)
+	}
+	if !netem.ECN {
+		t.Fatal("ECN does not match")
+	}
+	if netem.PrngSeed != qdisc.PrngSeed {
+		t.Fatal("Seed does not match")
+	}
+	if err := QdiscDel(qdisc); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseNetemDelays(t *testing.T) {
+	if TickInUsec() == 0.0 {
+		t.Skip("psched clock not available")
+	}
+	opt := nl.TcNetemQopt{Latency: time2Tick(10000), Jitter: time2Tick(1000)}
+	options := nl.NewRtAttr(nl.TCA_OPTIONS, opt.Serialize())
+	options.AddRtAttr(nl.TCA_NETEM_LATENCY64, nl.Uint64Attr(uint64(tick2Nsec(opt.Latency))))
+	options.AddRtAttr(nl.TCA_NETEM_JITTER64, nl.Uint64Attr(uint64(tick2Nsec(opt.Jitter)+1)))
+	netem := &Netem{}
+	if err := parseNetemData(netem, options.Serialize()[unix.SizeofRtAttr:]); err != nil {
+		t.Fatal(err)
+	}
+	if netem.Latency64 != 0 {
+		t.Fatalf("Latency64 reported for a 32bit latency: %d", netem.Latency64)
+	}
+	if netem.Jitter64 != tick2Nsec(opt.Jitter)+1 {
+		t.Fatalf("Jitter64 not reported for a sub tick jitter: %d", netem.Jitter64)
+	}
+}
+
+func TestNetemChangeLatency(t *testing.T) {
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+	if err := LinkAdd(&Ifb{LinkAttrs{Name: "foo"}}); err != nil {
+		t.Fatal(err)
+	}
+	link, err := LinkByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	qdisc := NewNetem(QdiscAttrs{
+		LinkIndex: link.Attrs().Index,
+		Handle:    MakeHandle(1, 0),
+		Parent:    HANDLE_ROOT,
+	}, NetemQdiscAttrs{
+		Latency: 10000,
+		Jitter:  1000,
+	})
+	if err := QdiscAdd(qdisc); err != nil {
+		t.Fatal(err)
+	}
+	qdiscs, err := SafeQdiscList(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(qdiscs) != 1 {
+		t.Fatal("Failed to add qdisc")
+	}
+	netem, ok := qdiscs[0].(*Netem)
+	if !ok {
+		t.Fatal("Qdisc is the wrong type")
+	}
+	if netem.Latency64 != 0 || netem.Jitter64 != 0 {
+		t.Fatalf("64bit delays reported for 32bit ones: %d %d", netem.Latency64, netem.Jitter64)
+	}
+	netem.Latency = time2Tick(20000)
+	netem.Jitter = time2Tick(2000)
+	if err := QdiscChange(netem); err != nil {
+		t.Fatal(err)
+	}
+	qdiscs, err = SafeQdiscList(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(qdiscs) != 1 {
+		t.Fatal("Failed to change qdisc")
+	}
+	changed, ok := qdiscs[0].(*Netem)
+	if !ok {
+		t.Fatal("Qdisc is the wrong type")
+	}
+	if changed.Latency != netem.Latency || changed.Jitter != netem.Jitter {
+		t.Fatalf("Delays were not changed: %d %d", changed.Latency, changed.Jitter)
+	}
+	if err := QdiscDel(changed); err != nil {
+		t.Fatal(err)
+	}
+}
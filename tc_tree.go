@@ -0,0 +1,127 @@
+package netlink
+
+import (
+	"reflect"
+)
+
+// TcTree describes the whole traffic control configuration of a link: its
+// qdiscs, the classes of the classful ones and the filters, with their
+// actions, attached to them. It is applied with TcApply.
+//
+// Every qdisc must have a handle and every filter a priority, they identify
+// the objects when the tree is compared with the configuration of the link.
+// Fields left to their zero value are not compared, they are the kernel
+// defaults, and neither are statistics nor fields the kernel does not report.
+type TcTree struct {
+	Qdiscs  []Qdisc
+	Classes []Class
+	Filters []Filter
+}
+
+// tcWriteOnlyFields are the fields that are sent to the kernel but never
+// reported back, they can't be compared.
+var tcWriteOnlyFields = map[string]bool{
+	"Stab":      true,
+	"DelayDist": true,
+	"SlotDist":  true,
+	"Fd":        true,
+	"Rtab":      true,
+	"Ptab":      true,
+}
+
+// tcMatches reports whether the configuration have, as listed from the
+// kernel, satisfies the configuration want. The zero values of want are
+// ignored except for booleans, arrays and slices are compared as a whole.
+func tcMatches(want, have interface{}) bool {
+	return tcValueMatches(reflect.ValueOf(want), reflect.ValueOf(have), false)
+}
+
+func tcValueMatches(want, have reflect.Value, exact bool) bool {
+	if !exact && tcIsZero(want) && want.Kind() != reflect.Bool {
+		return true
+	}
+	switch want.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if want.IsNil() || have.IsNil() {
+			return want.IsNil() && have.IsNil()
+		}
+		want, have = want.Elem(), have.Elem()
+		if want.Type() != have.Type() {
+			return false
+		}
+		return tcValueMatches(want, have, false)
+	case reflect.Struct:
+		for i := 0; i < want.NumField(); i++ {
+			if tcWriteOnlyFields[want.Type().Field(i).Name] {
+				continue
+			}
+			if !tcValueMatches(want.Field(i), have.Field(i), false) {
+				return false
+			}
+		}
+		return true
+	case reflect.Slice, reflect.Array:
+		if want.Len() != have.Len() {
+			return false
+		}
+		for i := 0; i < want.Len(); i++ {
+			if !tcValueMatches(want.Index(i), have.Index(i), tcIsScalar(want.Index(i))) {
+				return false
+			}
+		}
+		return true
+	case reflect.Bool:
+		return want.Bool() == have.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return want.Int() == have.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return want.Uint() == have.Uint()
+	case reflect.Float32, reflect.Float64:
+		return want.Float() == have.Float()
+	case reflect.String:
+		return want.String() == have.String()
+	}
+	// maps, functions and channels are not part of the configuration
+	return true
+}
+
+func tcIsScalar(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Struct, reflect.Slice, reflect.Array:
+		return false
+	}
+	return true
+}
+
+// tcIsZero is reflect.Value.IsZero, which is not available in go 1.12.
+func tcIsZero(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map, reflect.Func, reflect.Chan:
+		return v.IsNil() || (v.Kind() == reflect.Slice && v.Len() == 0)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !tcIsZero(v.Field(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if !tcIsZero(v.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.String:
+		return v.Len() == 0
+	}
+	return true
+}
@@ -0,0 +1,444 @@
+package netlink
+
+import (
+	"fmt"
+	"sort"
+)
+
+// TcApply makes the traffic control configuration of link match tree.
+// Equivalent to a sequence of `tc qdisc|class|filter replace|change|del`.
+//
+// Only the minimal operations are made: qdiscs and classes are changed in
+// place when their type and parent are unchanged, filters are only replaced
+// for the priorities that differ, and objects of the link missing from tree
+// are deleted once the new ones are in place. Reapplying the same tree is a
+// no-op. The default qdiscs created by the kernel, which have no handle, are
+// left alone unless tree replaces them.
+//
+// The LinkIndex of the objects of tree is set to the index of link.
+func TcApply(link Link, tree *TcTree) error {
+	return pkgHandle.TcApply(link, tree)
+}
+
+// TcApply makes the traffic control configuration of link match tree.
+// Equivalent to a sequence of `tc qdisc|class|filter replace|change|del`.
+//
+// Only the minimal operations are made: qdiscs and classes are changed in
+// place when their type and parent are unchanged, filters are only replaced
+// for the priorities that differ, and objects of the link missing from tree
+// are deleted once the new ones are in place. Reapplying the same tree is a
+// no-op. The default qdiscs created by the kernel, which have no handle, are
+// left alone unless tree replaces them.
+//
+// The LinkIndex of the objects of tree is set to the index of link.
+func (h *Handle) TcApply(link Link, tree *TcTree) error {
+	base := link.Attrs()
+	h.ensureIndex(base)
+	if err := tree.prepare(base.Index); err != nil {
+		return err
+	}
+	s := &tcApplyState{h: h, link: link}
+	if err := s.refresh(); err != nil {
+		return err
+	}
+	if err := s.applyQdiscsAndClasses(tree); err != nil {
+		return err
+	}
+	if err := s.applyFilters(tree); err != nil {
+		return err
+	}
+	if err := s.deleteStaleClasses(tree); err != nil {
+		return err
+	}
+	return s.deleteStaleQdiscs(tree)
+}
+
+// prepare validates the tree and sets the link index of its objects.
+func (tree *TcTree) prepare(index int) error {
+	handles := make(map[uint32]bool)
+	for _, qdisc := range tree.Qdiscs {
+		attrs := qdisc.Attrs()
+		if attrs.Handle == HANDLE_NONE {
+			return fmt.Errorf("qdisc %s at %s has no handle", qdisc.Type(), HandleStr(attrs.Parent))
+		}
+		if handles[attrs.Handle] {
+			return fmt.Errorf("qdisc handle %s is used twice", HandleStr(attrs.Handle))
+		}
+		handles[attrs.Handle] = true
+		attrs.LinkIndex = index
+	}
+	for _, class := range tree.Classes {
+		attrs := class.Attrs()
+		if _, minor := MajorMinor(attrs.Handle); minor == 0 {
+			return fmt.Errorf("class %s has no minor handle", HandleStr(attrs.Handle))
+		}
+		if handles[attrs.Handle] {
+			return fmt.Errorf("class handle %s is used twice", HandleStr(attrs.Handle))
+		}
+		handles[attrs.Handle] = true
+		attrs.LinkIndex = index
+	}
+	protocols := make(map[tcFilterKey]uint16)
+	for _, filter := range tree.Filters {
+		attrs := filter.Attrs()
+		if attrs.Priority == 0 {
+			return fmt.Errorf("filter %s at %s has no priority", filter.Type(), HandleStr(attrs.Parent))
+		}
+		key := tcFilterKey{attrs.Parent, attrs.Priority}
+		if protocol, ok := protocols[key]; ok && protocol != attrs.Protocol {
+			return fmt.Errorf("filters at %s priority %d use different protocols", HandleStr(attrs.Parent), attrs.Priority)
+		}
+		protocols[key] = attrs.Protocol
+		attrs.LinkIndex = index
+	}
+	return nil
+}
+
+type tcFilterKey struct {
+	parent   uint32
+	priority uint16
+}
+
+type tcApplyState struct {
+	h       *Handle
+	link    Link
+	qdiscs  []Qdisc
+	classes map[uint32]Class
+}
+
+// refresh lists the qdiscs and classes of the link, needed after a qdisc was
+// created or deleted as that also creates or deletes its descendants.
+func (s *tcApplyState) refresh() error {
+	qdiscs, err := s.h.QdiscList(s.link)
+	if err != nil {
+		return err
+	}
+	classes, err := s.h.ClassList(s.link, HANDLE_NONE)
+	if err != nil {
+		return err
+	}
+	s.qdiscs = qdiscs
+	s.classes = make(map[uint32]Class)
+	for _, class := range classes {
+		s.classes[class.Attrs().Handle] = class
+	}
+	return nil
+}
+
+func (s *tcApplyState) qdiscAt(parent uint32) Qdisc {
+	for _, qdisc := range s.qdiscs {
+		if qdisc.Attrs().Parent == parent {
+			return qdisc
+		}
+	}
+	return nil
+}
+
+func (s *tcApplyState) qdiscByHandle(handle uint32) Qdisc {
+	for _, qdisc := range s.qdiscs {
+		if qdisc.Attrs().Handle == handle {
+			return qdisc
+		}
+	}
+	return nil
+}
+
+// applyQdiscsAndClasses creates or updates the qdiscs and classes of tree,
+// parents first.
+func (s *tcApplyState) applyQdiscsAndClasses(tree *TcTree) error {
+	ready := make(map[uint32]bool)
+	qdiscs, classes := tree.Qdiscs, tree.Classes
+	for len(qdiscs)+len(classes) > 0 {
+		progress := false
+		var pendingQdiscs []Qdisc
+		for _, qdisc := range qdiscs {
+			if !s.parentReady(qdisc.Attrs().Parent, ready) {
+				pendingQdiscs = append(pendingQdiscs, qdisc)
+				continue
+			}
+			if err := s.applyQdisc(qdisc); err != nil {
+				return err
+			}
+			ready[qdisc.Attrs().Handle] = true
+			progress = true
+		}
+		var pendingClasses []Class
+		for _, class := range classes {
+			if !s.parentReady(class.Attrs().Parent, ready) {
+				pendingClasses = append(pendingClasses, class)
+				continue
+			}
+			if err := s.applyClass(class); err != nil {
+				return err
+			}
+			ready[class.Attrs().Handle] = true
+			progress = true
+		}
+		if !progress {
+			if len(pendingQdiscs) > 0 {
+				attrs := pendingQdiscs[0].Attrs()
+				return fmt.Errorf("parent %s of qdisc %s does not exist", HandleStr(attrs.Parent), HandleStr(attrs.Handle))
+			}
+			attrs := pendingClasses[0].Attrs()
+			return fmt.Errorf("parent %s of class %s does not exist", HandleStr(attrs.Parent), HandleStr(attrs.Handle))
+		}
+		qdiscs, classes = pendingQdiscs, pendingClasses
+	}
+	return nil
+}
+
+// parentReady reports whether an object can be attached to parent: the root
+// of the link, a qdisc or class of the tree already applied or a class the
+// kernel created along with its qdisc, like the bands of prio.
+func (s *tcApplyState) parentReady(parent uint32, ready map[uint32]bool) bool {
+	switch parent {
+	case HANDLE_ROOT, HANDLE_INGRESS:
+		return true
+	}
+	if ready[parent] {
+		return true
+	}
+	major, _ := MajorMinor(parent)
+	_, exists := s.classes[parent]
+	return ready[MakeHandle(major, 0)] && exists
+}
+
+func (s *tcApplyState) applyQdisc(qdisc Qdisc) error {
+	attrs := qdisc.Attrs()
+	cur := s.qdiscAt(attrs.Parent)
+	if cur != nil && cur.Attrs().Handle == attrs.Handle {
+		if cur.Type() == qdisc.Type() {
+			if tcMatches(qdisc, cur) {
+				return nil
+			}
+			return s.h.QdiscChange(qdisc)
+		}
+		// the type of a qdisc can't be changed in place
+		if err := s.h.QdiscDel(cur); err != nil {
+			return err
+		}
+	} else if other := s.qdiscByHandle(attrs.Handle); other != nil {
+		// the handle is used elsewhere on the link and must be freed
+		if err := s.h.QdiscDel(other); err != nil {
+			return err
+		}
+	}
+	// replacing grafts the new qdisc in place of the one at parent, if any
+	if err := s.h.QdiscReplace(qdisc); err != nil {
+		return err
+	}
+	return s.refresh()
+}
+
+func (s *tcApplyState) applyClass(class Class) error {
+	attrs := class.Attrs()
+	cur, ok := s.classes[attrs.Handle]
+	if ok && cur.Type() == class.Type() && cur.Attrs().Parent == attrs.Parent {
+		if tcMatches(class, cur) {
+			return nil
+		}
+		return s.h.ClassChange(class)
+	}
+	if ok {
+		// classes can't be moved
+		if err := s.h.ClassDel(cur); err != nil {
+			return err
+		}
+	}
+	if err := s.h.ClassAdd(class); err != nil {
+		return err
+	}
+	s.classes[attrs.Handle] = class
+	return nil
+}
+
+// applyFilters replaces the filters of every parent and priority that differ
+// from tree and deletes the ones missing from tree.
+func (s *tcApplyState) applyFilters(tree *TcTree) error {
+	parents := make(map[uint32]bool)
+	for _, filter := range tree.Filters {
+		parents[filter.Attrs().Parent] = true
+	}
+	for _, qdisc := range s.qdiscs {
+		switch {
+		case qdisc.Type() == "clsact":
+			parents[HANDLE_MIN_INGRESS] = true
+			parents[HANDLE_MIN_EGRESS] = true
+		case qdisc.Attrs().Handle != HANDLE_NONE:
+			parents[qdisc.Attrs().Handle] = true
+		}
+	}
+	for handle := range s.classes {
+		parents[handle] = true
+	}
+
+	want := make(map[tcFilterKey][]Filter)
+	for _, filter := range tree.Filters {
+		key := tcFilterKey{filter.Attrs().Parent, filter.Attrs().Priority}
+		want[key] = append(want[key], filter)
+	}
+	have := make(map[tcFilterKey][]Filter)
+	for parent := range parents {
+		filters, err := s.h.FilterList(s.link, parent)
+		if err != nil {
+			return err
+		}
+		for _, filter := range filters {
+			key := tcFilterKey{parent, filter.Attrs().Priority}
+			have[key] = append(have[key], filter)
+		}
+	}
+
+	for key, filters := range have {
+		if _, ok := want[key]; ok {
+			continue
+		}
+		if err := s.deleteFilters(key, filters[0]); err != nil {
+			return err
+		}
+	}
+	for key, filters := range want {
+		cur := have[key]
+		if tcFiltersMatch(filters, cur) {
+			continue
+		}
+		if len(cur) > 0 {
+			if err := s.deleteFilters(key, cur[0]); err != nil {
+				return err
+			}
+		}
+		for _, filter := range filters {
+			if err := s.h.FilterAdd(filter); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// deleteFilters deletes all the filters of a parent and priority at once.
+func (s *tcApplyState) deleteFilters(key tcFilterKey, filter Filter) error {
+	return s.h.FilterDel(&GenericFilter{
+		FilterAttrs: FilterAttrs{
+			LinkIndex: filter.Attrs().LinkIndex,
+			Parent:    key.parent,
+			Priority:  key.priority,
+			Protocol:  filter.Attrs().Protocol,
+		},
+		FilterType: filter.Type(),
+	})
+}
+
+// tcFiltersMatch reports whether every wanted filter matches a distinct
+// existing one, and no other filter exists.
+func tcFiltersMatch(want, have []Filter) bool {
+	if len(want) != len(have) {
+		return false
+	}
+	used := make([]bool, len(have))
+	for _, w := range want {
+		found := false
+		for i, h := range have {
+			if used[i] || w.Type() != h.Type() || w.Attrs().Protocol != h.Attrs().Protocol {
+				continue
+			}
+			if tcMatches(w, h) {
+				used[i], found = true, true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// tcUserClasses are the qdiscs whose classes are created by the user, the
+// classes of the other qdiscs come and go with their qdisc.
+var tcUserClasses = map[string]bool{
+	"htb":  true,
+	"hfsc": true,
+	"drr":  true,
+	"qfq":  true,
+}
+
+// deleteStaleClasses deletes the classes of the qdiscs of tree that are not
+// in tree, children first.
+func (s *tcApplyState) deleteStaleClasses(tree *TcTree) error {
+	wanted := make(map[uint32]bool)
+	for _, class := range tree.Classes {
+		wanted[class.Attrs().Handle] = true
+	}
+	var stale []Class
+	for handle, class := range s.classes {
+		major, minor := MajorMinor(handle)
+		qdisc := s.qdiscByHandle(MakeHandle(major, 0))
+		if wanted[handle] || minor == 0 || qdisc == nil || !tcUserClasses[qdisc.Type()] {
+			continue
+		}
+		stale = append(stale, class)
+	}
+	depth := func(class Class) int {
+		d := 0
+		for parent := class.Attrs().Parent; ; d++ {
+			if _, minor := MajorMinor(parent); minor == 0 {
+				return d
+			}
+			p, ok := s.classes[parent]
+			if !ok {
+				return d
+			}
+			parent = p.Attrs().Parent
+		}
+	}
+	sort.Slice(stale, func(i, j int) bool {
+		return depth(stale[i]) > depth(stale[j])
+	})
+	for _, class := range stale {
+		if err := s.h.ClassDel(class); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteStaleQdiscs deletes the qdiscs that are not in tree, skipping the
+// ones going away with an ancestor.
+func (s *tcApplyState) deleteStaleQdiscs(tree *TcTree) error {
+	if err := s.refresh(); err != nil {
+		return err
+	}
+	wanted := make(map[uint32]bool)
+	for _, qdisc := range tree.Qdiscs {
+		wanted[qdisc.Attrs().Handle] = true
+	}
+	stale := make(map[uint32]Qdisc)
+	for _, qdisc := range s.qdiscs {
+		if handle := qdisc.Attrs().Handle; handle != HANDLE_NONE && !wanted[handle] {
+			stale[handle] = qdisc
+		}
+	}
+	for _, qdisc := range stale {
+		staleAncestor := false
+		for parent := qdisc.Attrs().Parent; parent != HANDLE_ROOT && parent != HANDLE_INGRESS; {
+			major, _ := MajorMinor(parent)
+			ancestor := s.qdiscByHandle(MakeHandle(major, 0))
+			if ancestor == nil {
+				break
+			}
+			if _, ok := stale[ancestor.Attrs().Handle]; ok {
+				staleAncestor = true
+				break
+			}
+			parent = ancestor.Attrs().Parent
+		}
+		if staleAncestor {
+			continue
+		}
+		if err := s.h.QdiscDel(qdisc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+// +build linux
+
+package netlink
+
+import (
+	"testing"
+
+	"github.com/ndupreez/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+func TestTcMatches(t *testing.T) {
+	want := &FqCodel{
+		QdiscAttrs: QdiscAttrs{Handle: MakeHandle(10, 0), Parent: MakeHandle(1, 10)},
+		Limit:      1000,
+	}
+	have := &FqCodel{
+		QdiscAttrs: QdiscAttrs{
+			Handle:     MakeHandle(10, 0),
+			Parent:     MakeHandle(1, 10),
+			Refcnt:     2,
+			Statistics: &QdiscStatistics{},
+		},
+		Limit:    1000,
+		Target:   4999,
+		Interval: 99999,
+		Stats:    &FqCodelQdStats{MaxPacket: 1514},
+	}
+	if !tcMatches(want, have) {
+		t.Fatal("Kernel defaults and statistics should be ignored")
+	}
+	want.Limit = 2000
+	if tcMatches(want, have) {
+		t.Fatal("Different limits should not match")
+	}
+
+	prio := &Prio{Bands: 3, PriorityMap: [16]uint8{1, 2, 2, 2, 1, 2, 0, 0, 1, 1, 1, 1, 1, 1, 1, 1}}
+	cur := &Prio{Bands: 3, PriorityMap: prio.PriorityMap}
+	if !tcMatches(prio, cur) {
+		t.Fatal("Same priority maps should match")
+	}
+	cur.PriorityMap[6] = 1
+	if tcMatches(prio, cur) {
+		t.Fatal("Zeros of arrays should be compared")
+	}
+
+	red := &Red{Limit: 1000, ECN: false, Stab: make([]byte, nl.RED_STAB_SIZE)}
+	if !tcMatches(red, &Red{Limit: 1000}) {
+		t.Fatal("Write only fields should be ignored")
+	}
+	if tcMatches(red, &Red{Limit: 1000, ECN: true}) {
+		t.Fatal("Booleans should be compared")
+	}
+
+	filter := &U32{
+		FilterAttrs: FilterAttrs{Parent: MakeHandle(1, 0), Priority: 1, Protocol: unix.ETH_P_IP},
+		ClassId:     MakeHandle(1, 10),
+		Sel: &nl.TcU32Sel{
+			Keys: []nl.TcU32Key{{Mask: 0xff, Val: 0x11, Off: 8}},
+		},
+	}
+	listed := &U32{
+		FilterAttrs: FilterAttrs{Parent: MakeHandle(1, 0), Handle: 0x80000800, Priority: 1, Protocol: unix.ETH_P_IP},
+		ClassId:     MakeHandle(1, 10),
+		Sel: &nl.TcU32Sel{
+			Nkeys: 1,
+			Flags: nl.TC_U32_TERMINAL,
+			Keys:  []nl.TcU32Key{{Mask: 0xff, Val: 0x11, Off: 8}},
+		},
+	}
+	if !tcFiltersMatch([]Filter{filter}, []Filter{listed}) {
+		t.Fatal("Listed filter should match")
+	}
+	listed.Sel.Keys[0].Val = 0x06
+	if tcFiltersMatch([]Filter{filter}, []Filter{listed}) {
+		t.Fatal("Different keys should not match")
+	}
+}
+
+func TestTcApply(t *testing.T) {
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+	if err := LinkAdd(&Ifb{LinkAttrs{Name: "foo"}}); err != nil {
+		t.Fatal(err)
+	}
+	link, err := LinkByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := LinkSetUp(link); err != nil {
+		t.Fatal(err)
+	}
+
+	newTree := func(ceil uint64, withBulk bool) *TcTree {
+		htb := NewHtb(QdiscAttrs{Handle: MakeHandle(1, 0), Parent: HANDLE_ROOT})
+		htb.Defcls = 0x20
+		tree := &TcTree{Qdiscs: []Qdisc{htb}}
+		tree.Classes = append(tree.Classes,
+			NewHtbClass(ClassAttrs{Handle: MakeHandle(1, 1), Parent: MakeHandle(1, 0)},
+				HtbClassAttrs{Rate: 100e6}),
+			NewHtbClass(ClassAttrs{Handle: MakeHandle(1, 0x10), Parent: MakeHandle(1, 1)},
+				HtbClassAttrs{Rate: 10e6, Ceil: ceil}))
+		leaf := NewFqCodel(QdiscAttrs{Handle: MakeHandle(0x10, 0), Parent: MakeHandle(1, 0x10)})
+		tree.Qdiscs = append(tree.Qdiscs, leaf)
+		tree.Filters = append(tree.Filters, &U32{
+			FilterAttrs: FilterAttrs{Parent: MakeHandle(1, 0), Priority: 1, Protocol: unix.ETH_P_IP},
+			ClassId:     MakeHandle(1, 0x10),
+		})
+		if withBulk {
+			tree.Classes = append(tree.Classes,
+				NewHtbClass(ClassAttrs{Handle: MakeHandle(1, 0x20), Parent: MakeHandle(1, 1)},
+					HtbClassAttrs{Rate: 1e6, Ceil: 100e6}))
+			tree.Filters = append(tree.Filters, &U32{
+				FilterAttrs: FilterAttrs{Parent: MakeHandle(1, 0), Priority: 2, Protocol: unix.ETH_P_IP},
+				ClassId:     MakeHandle(1, 0x20),
+			})
+		}
+		return tree
+	}
+
+	if err := TcApply(link, newTree(50e6, true)); err != nil {
+		t.Fatal(err)
+	}
+	classes, err := SafeClassList(link, MakeHandle(1, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(classes) != 3 {
+		t.Fatalf("Got %d classes, expected 3", len(classes))
+	}
+	filters, err := FilterList(link, MakeHandle(1, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(filters) != 2 {
+		t.Fatalf("Got %d filters, expected 2", len(filters))
+	}
+	handles := make(map[uint16]uint32)
+	for _, filter := range filters {
+		handles[filter.Attrs().Priority] = filter.Attrs().Handle
+	}
+
+	// Reapplying the same tree must not recreate anything, the kernel would
+	// pick a new handle for a recreated filter.
+	if err := TcApply(link, newTree(50e6, true)); err != nil {
+		t.Fatal(err)
+	}
+	filters, err = FilterList(link, MakeHandle(1, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, filter := range filters {
+		if handles[filter.Attrs().Priority] != filter.Attrs().Handle {
+			t.Fatal("Filter was recreated")
+		}
+	}
+
+	// Change a class and remove the bulk class with its filter
+	if err := TcApply(link, newTree(80e6, false)); err != nil {
+		t.Fatal(err)
+	}
+	classes, err = SafeClassList(link, MakeHandle(1, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(classes) != 2 {
+		t.Fatalf("Got %d classes, expected 2", len(classes))
+	}
+	for _, class := range classes {
+		if class.Attrs().Handle == MakeHandle(1, 0x10) && class.(*HtbClass).Ceil != 80e6/8 {
+			t.Fatalf("Ceil was not changed: %d", class.(*HtbClass).Ceil)
+		}
+	}
+	filters, err = FilterList(link, MakeHandle(1, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(filters) != 1 || filters[0].Attrs().Handle != handles[1] {
+		t.Fatal("Only the bulk filter should have been deleted")
+	}
+	qdiscs, err := SafeQdiscList(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(qdiscs) != 2 {
+		t.Fatalf("Got %d qdiscs, expected 2", len(qdiscs))
+	}
+
+	// An empty tree restores the default qdisc
+	if err := TcApply(link, &TcTree{}); err != nil {
+		t.Fatal(err)
+	}
+	qdiscs, err = SafeQdiscList(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, qdisc := range qdiscs {
+		if qdisc.Attrs().Handle != HANDLE_NONE {
+			t.Fatalf("Qdisc %v was not deleted", qdisc)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package netlink
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net"
+	"time"
+)
+
+// WireguardKeyLen is the length of the WireGuard curve25519 keys
+const WireguardKeyLen = 32
+
+// WireguardKey is a WireGuard private, public or preshared key
+type WireguardKey [WireguardKeyLen]byte
+
+// ParseWireguardKey parses a key in the base64 format of the wg tool
+func ParseWireguardKey(s string) (WireguardKey, error) {
+	var key WireguardKey
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return key, err
+	}
+	if len(b) != WireguardKeyLen {
+		return key, fmt.Errorf("invalid WireGuard key length: %d", len(b))
+	}
+	copy(key[:], b)
+	return key, nil
+}
+
+// String returns the key in the base64 format of the wg tool
+func (k WireguardKey) String() string {
+	return base64.StdEncoding.EncodeToString(k[:])
+}
+
+// WireguardDevice is the configuration and state of a WireGuard device
+type WireguardDevice struct {
+	LinkIndex    int
+	Name         string
+	PrivateKey   WireguardKey
+	PublicKey    WireguardKey
+	ListenPort   uint16
+	FirewallMark uint32
+	Peers        []WireguardPeer
+}
+
+func (d *WireguardDevice) String() string {
+	return fmt.Sprintf("{Name: %s, PublicKey: %s, ListenPort: %d, FirewallMark: %d, Peers: %d}",
+		d.Name, d.PublicKey, d.ListenPort, d.FirewallMark, len(d.Peers))
+}
+
+// WireguardPeer is the configuration and state of a peer of a WireGuard
+// device
+type WireguardPeer struct {
+	PublicKey                   WireguardKey
+	PresharedKey                WireguardKey
+	Endpoint                    *net.UDPAddr
+	PersistentKeepaliveInterval time.Duration
+	LastHandshakeTime           time.Time // zero if no handshake happened
+	ReceiveBytes                uint64
+	TransmitBytes               uint64
+	AllowedIPs                  []net.IPNet
+	ProtocolVersion             int
+}
+
+func (p *WireguardPeer) String() string {
+	return fmt.Sprintf("{PublicKey: %s, Endpoint: %v, AllowedIPs: %v, LastHandshakeTime: %v}",
+		p.PublicKey, p.Endpoint, p.AllowedIPs, p.LastHandshakeTime)
+}
+
+// WireguardDeviceConfig holds the changes WireguardDeviceSet applies to a
+// WireGuard device. The nil fields are left unchanged.
+type WireguardDeviceConfig struct {
+	PrivateKey   *WireguardKey // a zero key removes the private key
+	ListenPort   *uint16       // 0 picks a random port
+	FirewallMark *uint32       // 0 removes the mark
+	ReplacePeers bool          // remove the peers not in Peers
+	Peers        []WireguardPeerConfig
+}
+
+// WireguardPeerConfig holds the changes to apply to a peer, identified by
+// its public key. The peer is created if it does not exist unless UpdateOnly
+// is set. The nil fields are left unchanged.
+type WireguardPeerConfig struct {
+	PublicKey                   WireguardKey
+	Remove                      bool
+	UpdateOnly                  bool
+	PresharedKey                *WireguardKey // a zero key removes the preshared key
+	Endpoint                    *net.UDPAddr
+	PersistentKeepaliveInterval *time.Duration // 0 disables the keepalives
+	ReplaceAllowedIPs           bool           // remove the allowed IPs not in AllowedIPs
+	AllowedIPs                  []net.IPNet
+}
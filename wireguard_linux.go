@@ -0,0 +1,333 @@
+package netlink
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/ndupreez/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+// WireguardDeviceGet returns the configuration and state of a WireGuard
+// device, including its peers.
+// Equivalent to: `wg show $link`
+func WireguardDeviceGet(link Link) (*WireguardDevice, error) {
+	return pkgHandle.WireguardDeviceGet(link)
+}
+
+// WireguardDeviceGet returns the configuration and state of a WireGuard
+// device, including its peers.
+// Equivalent to: `wg show $link`
+func (h *Handle) WireguardDeviceGet(link Link) (*WireguardDevice, error) {
+	f, err := h.GenlFamilyGet(nl.WG_GENL_NAME)
+	if err != nil {
+		return nil, err
+	}
+	base := link.Attrs()
+	h.ensureIndex(base)
+	msg := &nl.Genlmsg{
+		Command: nl.WG_CMD_GET_DEVICE,
+		Version: nl.WG_GENL_VERSION,
+	}
+	req := h.newNetlinkRequest(int(f.ID), unix.NLM_F_DUMP)
+	req.AddData(msg)
+	req.AddData(nl.NewRtAttr(nl.WGDEVICE_A_IFINDEX, nl.Uint32Attr(uint32(base.Index))))
+	msgs, err := req.Execute(unix.NETLINK_GENERIC, 0)
+	if err != nil {
+		return nil, err
+	}
+	return parseWireguardDevice(msgs)
+}
+
+// WireguardDeviceSet applies config to a WireGuard device.
+// Equivalent to: `wg set $link ...`
+func WireguardDeviceSet(link Link, config *WireguardDeviceConfig) error {
+	return pkgHandle.WireguardDeviceSet(link, config)
+}
+
+// WireguardDeviceSet applies config to a WireGuard device.
+// Equivalent to: `wg set $link ...`
+func (h *Handle) WireguardDeviceSet(link Link, config *WireguardDeviceConfig) error {
+	f, err := h.GenlFamilyGet(nl.WG_GENL_NAME)
+	if err != nil {
+		return err
+	}
+	base := link.Attrs()
+	h.ensureIndex(base)
+	msg := &nl.Genlmsg{
+		Command: nl.WG_CMD_SET_DEVICE,
+		Version: nl.WG_GENL_VERSION,
+	}
+	req := h.newNetlinkRequest(int(f.ID), unix.NLM_F_ACK)
+	req.AddData(msg)
+	req.AddData(nl.NewRtAttr(nl.WGDEVICE_A_IFINDEX, nl.Uint32Attr(uint32(base.Index))))
+	if config.PrivateKey != nil {
+		req.AddData(nl.NewRtAttr(nl.WGDEVICE_A_PRIVATE_KEY, config.PrivateKey[:]))
+	}
+	if config.ListenPort != nil {
+		req.AddData(nl.NewRtAttr(nl.WGDEVICE_A_LISTEN_PORT, nl.Uint16Attr(*config.ListenPort)))
+	}
+	if config.FirewallMark != nil {
+		req.AddData(nl.NewRtAttr(nl.WGDEVICE_A_FWMARK, nl.Uint32Attr(*config.FirewallMark)))
+	}
+	if config.ReplacePeers {
+		req.AddData(nl.NewRtAttr(nl.WGDEVICE_A_FLAGS, nl.Uint32Attr(nl.WGDEVICE_F_REPLACE_PEERS)))
+	}
+	if len(config.Peers) > 0 {
+		peers := nl.NewRtAttr(nl.WGDEVICE_A_PEERS|unix.NLA_F_NESTED, nil)
+		for i := range config.Peers {
+			if err := encodeWireguardPeer(peers, i, &config.Peers[i]); err != nil {
+				return err
+			}
+		}
+		req.AddData(peers)
+	}
+	_, err = req.Execute(unix.NETLINK_GENERIC, 0)
+	return err
+}
+
+func encodeWireguardPeer(peers *nl.RtAttr, index int, peer *WireguardPeerConfig) error {
+	p := peers.AddRtAttr(index|unix.NLA_F_NESTED, nil)
+	p.AddRtAttr(nl.WGPEER_A_PUBLIC_KEY, peer.PublicKey[:])
+	var flags uint32
+	if peer.Remove {
+		flags |= nl.WGPEER_F_REMOVE_ME
+	}
+	if peer.UpdateOnly {
+		flags |= nl.WGPEER_F_UPDATE_ONLY
+	}
+	if peer.ReplaceAllowedIPs {
+		flags |= nl.WGPEER_F_REPLACE_ALLOWEDIPS
+	}
+	if flags != 0 {
+		p.AddRtAttr(nl.WGPEER_A_FLAGS, nl.Uint32Attr(flags))
+	}
+	if peer.Remove {
+		return nil
+	}
+	if peer.PresharedKey != nil {
+		p.AddRtAttr(nl.WGPEER_A_PRESHARED_KEY, peer.PresharedKey[:])
+	}
+	if peer.Endpoint != nil {
+		endpoint, err := encodeWireguardEndpoint(peer.Endpoint)
+		if err != nil {
+			return err
+		}
+		p.AddRtAttr(nl.WGPEER_A_ENDPOINT, endpoint)
+	}
+	if peer.PersistentKeepaliveInterval != nil {
+		interval := *peer.PersistentKeepaliveInterval / time.Second
+		if interval < 0 || interval > 0xffff {
+			return fmt.Errorf("invalid WireGuard persistent keepalive interval: %v", *peer.PersistentKeepaliveInterval)
+		}
+		p.AddRtAttr(nl.WGPEER_A_PERSISTENT_KEEPALIVE_INTERVAL, nl.Uint16Attr(uint16(interval)))
+	}
+	if len(peer.AllowedIPs) > 0 {
+		ips := p.AddRtAttr(nl.WGPEER_A_ALLOWEDIPS|unix.NLA_F_NESTED, nil)
+		for i, ipnet := range peer.AllowedIPs {
+			family := uint16(unix.AF_INET)
+			ip := ipnet.IP.To4()
+			if ip == nil {
+				family = unix.AF_INET6
+				ip = ipnet.IP.To16()
+			}
+			if ip == nil {
+				return fmt.Errorf("invalid WireGuard allowed IP: %v", ipnet)
+			}
+			ones, _ := ipnet.Mask.Size()
+			a := ips.AddRtAttr(i|unix.NLA_F_NESTED, nil)
+			a.AddRtAttr(nl.WGALLOWEDIP_A_FAMILY, nl.Uint16Attr(family))
+			a.AddRtAttr(nl.WGALLOWEDIP_A_IPADDR, ip)
+			a.AddRtAttr(nl.WGALLOWEDIP_A_CIDR_MASK, nl.Uint8Attr(uint8(ones)))
+		}
+	}
+	return nil
+}
+
+// encodeWireguardEndpoint returns the endpoint as a struct sockaddr_in or
+// sockaddr_in6.
+func encodeWireguardEndpoint(addr *net.UDPAddr) ([]byte, error) {
+	if ip := addr.IP.To4(); ip != nil {
+		b := make([]byte, unix.SizeofSockaddrInet4)
+		native.PutUint16(b[0:2], unix.AF_INET)
+		binary.BigEndian.PutUint16(b[2:4], uint16(addr.Port))
+		copy(b[4:8], ip)
+		return b, nil
+	}
+	if ip := addr.IP.To16(); ip != nil {
+		b := make([]byte, unix.SizeofSockaddrInet6)
+		native.PutUint16(b[0:2], unix.AF_INET6)
+		binary.BigEndian.PutUint16(b[2:4], uint16(addr.Port))
+		copy(b[8:24], ip)
+		if addr.Zone != "" {
+			iface, err := net.InterfaceByName(addr.Zone)
+			if err != nil {
+				return nil, err
+			}
+			native.PutUint32(b[24:28], uint32(iface.Index))
+		}
+		return b, nil
+	}
+	return nil, fmt.Errorf("invalid WireGuard endpoint: %v", addr)
+}
+
+func parseWireguardEndpoint(b []byte) (*net.UDPAddr, error) {
+	if len(b) < 4 {
+		return nil, fmt.Errorf("WireGuard endpoint too short: %d", len(b))
+	}
+	port := int(binary.BigEndian.Uint16(b[2:4]))
+	switch native.Uint16(b[0:2]) {
+	case unix.AF_INET:
+		if len(b) < 8 {
+			return nil, fmt.Errorf("WireGuard IPv4 endpoint too short: %d", len(b))
+		}
+		return &net.UDPAddr{IP: net.IP(append([]byte(nil), b[4:8]...)), Port: port}, nil
+	case unix.AF_INET6:
+		if len(b) < unix.SizeofSockaddrInet6 {
+			return nil, fmt.Errorf("WireGuard IPv6 endpoint too short: %d", len(b))
+		}
+		addr := &net.UDPAddr{IP: net.IP(append([]byte(nil), b[8:24]...)), Port: port}
+		if scope := native.Uint32(b[24:28]); scope != 0 {
+			addr.Zone = fmt.Sprint(scope)
+			if iface, err := net.InterfaceByIndex(int(scope)); err == nil {
+				addr.Zone = iface.Name
+			}
+		}
+		return addr, nil
+	}
+	return nil, fmt.Errorf("unknown WireGuard endpoint family: %d", native.Uint16(b[0:2]))
+}
+
+// wireguardAttrType strips the flags of a generic netlink attribute type
+func wireguardAttrType(attr syscall.NetlinkRouteAttr) uint16 {
+	return attr.Attr.Type &^ (unix.NLA_F_NESTED | unix.NLA_F_NET_BYTEORDER)
+}
+
+// parseWireguardDevice merges the messages of a device dump: the peers are
+// split across messages, a peer continuing in the next message when its
+// allowed IPs don't fit.
+func parseWireguardDevice(msgs [][]byte) (*WireguardDevice, error) {
+	dev := &WireguardDevice{}
+	for _, m := range msgs {
+		attrs, err := nl.ParseRouteAttr(m[nl.SizeofGenlmsg:])
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range attrs {
+			switch wireguardAttrType(a) {
+			case nl.WGDEVICE_A_IFINDEX:
+				dev.LinkIndex = int(native.Uint32(a.Value[0:4]))
+			case nl.WGDEVICE_A_IFNAME:
+				dev.Name = string(a.Value[:len(a.Value)-1])
+			case nl.WGDEVICE_A_PRIVATE_KEY:
+				copy(dev.PrivateKey[:], a.Value)
+			case nl.WGDEVICE_A_PUBLIC_KEY:
+				copy(dev.PublicKey[:], a.Value)
+			case nl.WGDEVICE_A_LISTEN_PORT:
+				dev.ListenPort = native.Uint16(a.Value[0:2])
+			case nl.WGDEVICE_A_FWMARK:
+				dev.FirewallMark = native.Uint32(a.Value[0:4])
+			case nl.WGDEVICE_A_PEERS:
+				peers, err := nl.ParseRouteAttr(a.Value)
+				if err != nil {
+					return nil, err
+				}
+				for _, p := range peers {
+					peer, err := parseWireguardPeer(p.Value)
+					if err != nil {
+						return nil, err
+					}
+					if n := len(dev.Peers); n > 0 && dev.Peers[n-1].PublicKey == peer.PublicKey {
+						dev.Peers[n-1].AllowedIPs = append(dev.Peers[n-1].AllowedIPs, peer.AllowedIPs...)
+						continue
+					}
+					dev.Peers = append(dev.Peers, *peer)
+				}
+			}
+		}
+	}
+	return dev, nil
+}
+
+func parseWireguardPeer(b []byte) (*WireguardPeer, error) {
+	attrs, err := nl.ParseRouteAttr(b)
+	if err != nil {
+		return nil, err
+	}
+	peer := &WireguardPeer{}
+	for _, a := range attrs {
+		switch wireguardAttrType(a) {
+		case nl.WGPEER_A_PUBLIC_KEY:
+			copy(peer.PublicKey[:], a.Value)
+		case nl.WGPEER_A_PRESHARED_KEY:
+			copy(peer.PresharedKey[:], a.Value)
+		case nl.WGPEER_A_ENDPOINT:
+			peer.Endpoint, err = parseWireguardEndpoint(a.Value)
+			if err != nil {
+				return nil, err
+			}
+		case nl.WGPEER_A_PERSISTENT_KEEPALIVE_INTERVAL:
+			peer.PersistentKeepaliveInterval = time.Duration(native.Uint16(a.Value[0:2])) * time.Second
+		case nl.WGPEER_A_LAST_HANDSHAKE_TIME:
+			// struct __kernel_timespec
+			if len(a.Value) < 16 {
+				return nil, fmt.Errorf("WireGuard handshake time too short: %d", len(a.Value))
+			}
+			sec := int64(native.Uint64(a.Value[0:8]))
+			nsec := int64(native.Uint64(a.Value[8:16]))
+			if sec != 0 || nsec != 0 {
+				peer.LastHandshakeTime = time.Unix(sec, nsec)
+			}
+		case nl.WGPEER_A_RX_BYTES:
+			peer.ReceiveBytes = native.Uint64(a.Value[0:8])
+		case nl.WGPEER_A_TX_BYTES:
+			peer.TransmitBytes = native.Uint64(a.Value[0:8])
+		case nl.WGPEER_A_PROTOCOL_VERSION:
+			peer.ProtocolVersion = int(native.Uint32(a.Value[0:4]))
+		case nl.WGPEER_A_ALLOWEDIPS:
+			ips, err := nl.ParseRouteAttr(a.Value)
+			if err != nil {
+				return nil, err
+			}
+			for _, ip := range ips {
+				ipnet, err := parseWireguardAllowedIP(ip.Value)
+				if err != nil {
+					return nil, err
+				}
+				peer.AllowedIPs = append(peer.AllowedIPs, *ipnet)
+			}
+		}
+	}
+	return peer, nil
+}
+
+func parseWireguardAllowedIP(b []byte) (*net.IPNet, error) {
+	attrs, err := nl.ParseRouteAttr(b)
+	if err != nil {
+		return nil, err
+	}
+	var family uint16
+	var ip net.IP
+	var ones int
+	for _, a := range attrs {
+		switch wireguardAttrType(a) {
+		case nl.WGALLOWEDIP_A_FAMILY:
+			family = native.Uint16(a.Value[0:2])
+		case nl.WGALLOWEDIP_A_IPADDR:
+			ip = net.IP(append([]byte(nil), a.Value...))
+		case nl.WGALLOWEDIP_A_CIDR_MASK:
+			ones = int(a.Value[0])
+		}
+	}
+	bits := 32
+	if family == unix.AF_INET6 {
+		bits = 128
+	}
+	if len(ip)*8 != bits {
+		return nil, fmt.Errorf("invalid WireGuard allowed IP of family %d: %v", family, ip)
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(ones, bits)}, nil
+}
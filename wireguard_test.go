@@ -0,0 +1,171 @@
+// +build linux
+
+package netlink
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ndupreez/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+func TestWireguardKey(t *testing.T) {
+	s := "YAnz5TF+lXXJte14tji3zlMNq+hd2rYUIgJBgB3fBmk="
+	key, err := ParseWireguardKey(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.String() != s {
+		t.Fatalf("expected %s, got %s", s, key)
+	}
+	if _, err := ParseWireguardKey("AAAA"); err == nil {
+		t.Fatal("short key should not parse")
+	}
+}
+
+func TestWireguardEndpoint(t *testing.T) {
+	for _, addr := range []*net.UDPAddr{
+		{IP: net.ParseIP("192.0.2.1"), Port: 51820},
+		{IP: net.ParseIP("2001:db8::1"), Port: 1234},
+	} {
+		b, err := encodeWireguardEndpoint(addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed, err := parseWireguardEndpoint(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !parsed.IP.Equal(addr.IP) || parsed.Port != addr.Port {
+			t.Fatalf("expected %v, got %v", addr, parsed)
+		}
+	}
+	b, _ := encodeWireguardEndpoint(&net.UDPAddr{IP: net.ParseIP("192.0.2.1"), Port: 0x1234})
+	if !bytes.Equal(b[2:4], []byte{0x12, 0x34}) {
+		t.Fatalf("port is not in network byte order: %v", b[2:4])
+	}
+}
+
+func TestParseWireguardDeviceSplitPeer(t *testing.T) {
+	key := WireguardKey{1}
+	dump := func(ip string) []byte {
+		_, ipnet, _ := net.ParseCIDR(ip)
+		peers := nl.NewRtAttr(nl.WGDEVICE_A_PEERS|unix.NLA_F_NESTED, nil)
+		if err := encodeWireguardPeer(peers, 0, &WireguardPeerConfig{
+			PublicKey:  key,
+			AllowedIPs: []net.IPNet{*ipnet},
+		}); err != nil {
+			t.Fatal(err)
+		}
+		msg := (&nl.Genlmsg{Command: nl.WG_CMD_GET_DEVICE}).Serialize()
+		msg = append(msg, nl.NewRtAttr(nl.WGDEVICE_A_IFNAME, nl.ZeroTerminated("wg0")).Serialize()...)
+		return append(msg, peers.Serialize()...)
+	}
+	dev, err := parseWireguardDevice([][]byte{dump("10.0.0.0/24"), dump("fd00::/64")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dev.Name != "wg0" {
+		t.Fatalf("expected name wg0, got %s", dev.Name)
+	}
+	if len(dev.Peers) != 1 {
+		t.Fatalf("expected the split peer to be merged, got %d peers", len(dev.Peers))
+	}
+	if len(dev.Peers[0].AllowedIPs) != 2 || dev.Peers[0].AllowedIPs[1].String() != "fd00::/64" {
+		t.Fatalf("unexpected allowed IPs: %v", dev.Peers[0].AllowedIPs)
+	}
+}
+
+func TestWireguardDeviceSetGet(t *testing.T) {
+	minKernelRequired(t, 5, 6)
+
+	tearDown := setUpNetlinkTest(t)
+	defer tearDown()
+
+	if err := LinkAdd(&Wireguard{LinkAttrs: LinkAttrs{Name: "wg0"}}); err != nil {
+		t.Fatal(err)
+	}
+	link, err := LinkByName("wg0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	privateKey, _ := ParseWireguardKey("YAnz5TF+lXXJte14tji3zlMNq+hd2rYUIgJBgB3fBmk=")
+	peerKey, _ := ParseWireguardKey("xTIBA5rboUvnH4htodjb6e697QjLERt1NAB4mZqp8Dg=")
+	psk, _ := ParseWireguardKey("FpCyhws9cxwWoV4xELtfJvjJN+zQVRPISllRWgeopVE=")
+	port := uint16(51820)
+	mark := uint32(0x42)
+	keepalive := 25 * time.Second
+	_, allowed, _ := net.ParseCIDR("10.0.0.0/24")
+	endpoint := &net.UDPAddr{IP: net.ParseIP("192.0.2.1"), Port: 51821}
+
+	err = WireguardDeviceSet(link, &WireguardDeviceConfig{
+		PrivateKey:   &privateKey,
+		ListenPort:   &port,
+		FirewallMark: &mark,
+		Peers: []WireguardPeerConfig{
+			{
+				PublicKey:                   peerKey,
+				PresharedKey:                &psk,
+				Endpoint:                    endpoint,
+				PersistentKeepaliveInterval: &keepalive,
+				AllowedIPs:                  []net.IPNet{*allowed},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dev, err := WireguardDeviceGet(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dev.Name != "wg0" || dev.LinkIndex != link.Attrs().Index {
+		t.Fatalf("unexpected device %v", dev)
+	}
+	if dev.PrivateKey != privateKey {
+		t.Fatal("private key not set")
+	}
+	if dev.PublicKey == (WireguardKey{}) {
+		t.Fatal("public key not derived")
+	}
+	if dev.ListenPort != port || dev.FirewallMark != mark {
+		t.Fatalf("expected port %d and mark %d, got %d and %d", port, mark, dev.ListenPort, dev.FirewallMark)
+	}
+	if len(dev.Peers) != 1 {
+		t.Fatalf("expected 1 peer, got %d", len(dev.Peers))
+	}
+	peer := dev.Peers[0]
+	if peer.PublicKey != peerKey || peer.PresharedKey != psk {
+		t.Fatal("peer keys not set")
+	}
+	if peer.Endpoint == nil || !peer.Endpoint.IP.Equal(endpoint.IP) || peer.Endpoint.Port != endpoint.Port {
+		t.Fatalf("expected endpoint %v, got %v", endpoint, peer.Endpoint)
+	}
+	if peer.PersistentKeepaliveInterval != keepalive {
+		t.Fatalf("expected keepalive %v, got %v", keepalive, peer.PersistentKeepaliveInterval)
+	}
+	if len(peer.AllowedIPs) != 1 || peer.AllowedIPs[0].String() != allowed.String() {
+		t.Fatalf("expected allowed IPs %v, got %v", allowed, peer.AllowedIPs)
+	}
+	if !peer.LastHandshakeTime.IsZero() {
+		t.Fatalf("expected no handshake, got %v", peer.LastHandshakeTime)
+	}
+
+	if err := WireguardDeviceSet(link, &WireguardDeviceConfig{
+		Peers: []WireguardPeerConfig{{PublicKey: peerKey, Remove: true}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	dev, err = WireguardDeviceGet(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dev.Peers) != 0 {
+		t.Fatalf("expected the peer to be removed, got %d peers", len(dev.Peers))
+	}
+}
@@ -2,6 +2,7 @@ package netlink
 
 import (
 	"fmt"
+	"net"
 
 	"golang.org/x/sys/unix"
 )
@@ -73,3 +74,91 @@ type XfrmMark struct {
 func (m *XfrmMark) String() string {
 	return fmt.Sprintf("(0x%x,0x%x)", m.Value, m.Mask)
 }
+
+// CustomXfrmStateFilter selects the xfrm states to list or delete.
+type CustomXfrmStateFilter interface {
+	// MatchXfrmState returns true if the state is selected
+	MatchXfrmState(state *XfrmState) bool
+}
+
+// CustomXfrmPolicyFilter selects the xfrm policies to list or delete.
+type CustomXfrmPolicyFilter interface {
+	// MatchXfrmPolicy returns true if the policy is selected
+	MatchXfrmPolicy(policy *XfrmPolicy) bool
+}
+
+// XfrmFilter selects the xfrm states and policies matching all its set
+// criteria, the zero values matching anything. Src and Dst match the
+// endpoints of a state and the selector of a policy. Proto and Reqid
+// match the templates of a policy. Mark matches the marks whose value
+// masked by its mask equals its value, a zero mask standing for all the
+// bits.
+type XfrmFilter struct {
+	Src   *net.IPNet
+	Dst   *net.IPNet
+	Proto Proto
+	Mark  *XfrmMark
+	Ifid  int
+	Reqid int
+}
+
+// MatchXfrmState returns true if the state matches the filter.
+func (f *XfrmFilter) MatchXfrmState(state *XfrmState) bool {
+	if f == nil {
+		return true
+	}
+	return (f.Src == nil || f.Src.Contains(state.Src)) &&
+		(f.Dst == nil || f.Dst.Contains(state.Dst)) &&
+		(f.Proto == 0 || f.Proto == state.Proto) &&
+		f.matchMark(state.Mark) &&
+		(f.Ifid == 0 || f.Ifid == state.Ifid) &&
+		(f.Reqid == 0 || f.Reqid == state.Reqid)
+}
+
+// MatchXfrmPolicy returns true if the policy matches the filter.
+func (f *XfrmFilter) MatchXfrmPolicy(policy *XfrmPolicy) bool {
+	if f == nil {
+		return true
+	}
+	if !ipNetWithin(policy.Src, f.Src) || !ipNetWithin(policy.Dst, f.Dst) ||
+		!f.matchMark(policy.Mark) || (f.Ifid != 0 && f.Ifid != policy.Ifid) {
+		return false
+	}
+	if f.Proto == 0 && f.Reqid == 0 {
+		return true
+	}
+	for _, tmpl := range policy.Tmpls {
+		if (f.Proto == 0 || f.Proto == tmpl.Proto) && (f.Reqid == 0 || f.Reqid == tmpl.Reqid) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *XfrmFilter) matchMark(mark *XfrmMark) bool {
+	if f.Mark == nil {
+		return true
+	}
+	if mark == nil {
+		return false
+	}
+	mask := f.Mark.Mask
+	if mask == 0 {
+		mask = ^uint32(0)
+	}
+	return mark.Value&mask == f.Mark.Value&mask
+}
+
+// ipNetWithin returns true if the prefix n is a subnet of filter, or if
+// filter is nil.
+func ipNetWithin(n, filter *net.IPNet) bool {
+	if filter == nil {
+		return true
+	}
+	if n == nil || !filter.Contains(n.IP) {
+		return false
+	}
+	nOnes, nBits := n.Mask.Size()
+	fOnes, fBits := filter.Mask.Size()
+	return nBits == fBits && nOnes >= fOnes
+}
@@ -2,6 +2,7 @@ package netlink
 
 import (
 	"fmt"
+	"net"
 
 	"github.com/ndupreez/netlink/nl"
 	"github.com/vishvananda/netns"
@@ -31,12 +32,399 @@ func parseXfrmMsgExpire(b []byte) *XfrmMsgExpire {
 	return &e
 }
 
+// XfrmMsgAcquire asks the key manager to negotiate the state described by
+// Dst, Src, Proto and Spi, for the flow of Selector matching the Policy.
+// The templates of the policy are in Policy.Tmpls.
+type XfrmMsgAcquire struct {
+	Dst        net.IP
+	Src        net.IP
+	Proto      Proto
+	Spi        int
+	Selector   XfrmSelector
+	Policy     *XfrmPolicy
+	AuthAlgos  uint32
+	CryptAlgos uint32
+	CompAlgos  uint32
+	Seq        uint32
+}
+
+func (ua *XfrmMsgAcquire) Type() nl.XfrmMsgType {
+	return nl.XFRM_MSG_ACQUIRE
+}
+
+func parseXfrmMsgAcquire(b []byte) (*XfrmMsgAcquire, error) {
+	msg := nl.DeserializeXfrmUserAcquire(b)
+	a := &XfrmMsgAcquire{
+		Dst:        msg.Id.Daddr.ToIP(),
+		Src:        msg.Saddr.ToIP(),
+		Proto:      Proto(msg.Id.Proto),
+		Spi:        int(nl.Swap32(msg.Id.Spi)),
+		Selector:   selectorFromSel(&msg.Sel),
+		Policy:     xfrmPolicyFromXfrmUserpolicyInfo(&msg.Policy),
+		AuthAlgos:  msg.Aalgos,
+		CryptAlgos: msg.Ealgos,
+		CompAlgos:  msg.Calgos,
+		Seq:        msg.Seq,
+	}
+
+	attrs, err := nl.ParseRouteAttr(b[nl.SizeofXfrmUserAcquire:])
+	if err != nil {
+		return nil, err
+	}
+	parseXfrmPolicyAttrs(a.Policy, attrs)
+
+	return a, nil
+}
+
+// XfrmMsgNewSA reports an added state.
+type XfrmMsgNewSA struct {
+	XfrmState *XfrmState
+}
+
+func (us *XfrmMsgNewSA) Type() nl.XfrmMsgType {
+	return nl.XFRM_MSG_NEWSA
+}
+
+// XfrmMsgUpdSA reports an updated state.
+type XfrmMsgUpdSA struct {
+	XfrmState *XfrmState
+}
+
+func (us *XfrmMsgUpdSA) Type() nl.XfrmMsgType {
+	return nl.XFRM_MSG_UPDSA
+}
+
+// XfrmMsgDelSA reports a deleted state.
+type XfrmMsgDelSA struct {
+	XfrmState *XfrmState
+}
+
+func (us *XfrmMsgDelSA) Type() nl.XfrmMsgType {
+	return nl.XFRM_MSG_DELSA
+}
+
+// The deleted state is in a XFRMA_SA attribute after its id
+func parseXfrmMsgDelSA(b []byte) (*XfrmMsgDelSA, error) {
+	attrs, err := nl.ParseRouteAttr(b[nl.SizeofXfrmUsersaId:])
+	if err != nil {
+		return nil, err
+	}
+
+	var state *XfrmState
+	for _, attr := range attrs {
+		if attr.Attr.Type == nl.XFRMA_SA {
+			state = xfrmStateFromXfrmUsersaInfo(nl.DeserializeXfrmUsersaInfo(attr.Value))
+		}
+	}
+	if state == nil {
+		id := nl.DeserializeXfrmUsersaId(b)
+		state = &XfrmState{
+			Dst:   id.Daddr.ToIP(),
+			Proto: Proto(id.Proto),
+			Spi:   int(nl.Swap32(id.Spi)),
+		}
+	}
+	parseXfrmStateAttrs(state, attrs)
+
+	return &XfrmMsgDelSA{XfrmState: state}, nil
+}
+
+// XfrmMsgFlushSA reports the states of Proto being flushed, 0 meaning any
+// protocol.
+type XfrmMsgFlushSA struct {
+	Proto Proto
+}
+
+func (uf *XfrmMsgFlushSA) Type() nl.XfrmMsgType {
+	return nl.XFRM_MSG_FLUSHSA
+}
+
+// XfrmMsgNewPolicy reports an added policy.
+type XfrmMsgNewPolicy struct {
+	XfrmPolicy *XfrmPolicy
+}
+
+func (up *XfrmMsgNewPolicy) Type() nl.XfrmMsgType {
+	return nl.XFRM_MSG_NEWPOLICY
+}
+
+// XfrmMsgUpdPolicy reports an updated policy.
+type XfrmMsgUpdPolicy struct {
+	XfrmPolicy *XfrmPolicy
+}
+
+func (up *XfrmMsgUpdPolicy) Type() nl.XfrmMsgType {
+	return nl.XFRM_MSG_UPDPOLICY
+}
+
+// XfrmMsgDelPolicy reports a deleted policy.
+type XfrmMsgDelPolicy struct {
+	XfrmPolicy *XfrmPolicy
+}
+
+func (up *XfrmMsgDelPolicy) Type() nl.XfrmMsgType {
+	return nl.XFRM_MSG_DELPOLICY
+}
+
+// The deleted policy is in a XFRMA_POLICY attribute after its id
+func parseXfrmMsgDelPolicy(b []byte) (*XfrmMsgDelPolicy, error) {
+	attrs, err := nl.ParseRouteAttr(b[nl.SizeofXfrmUserpolicyId:])
+	if err != nil {
+		return nil, err
+	}
+
+	var policy *XfrmPolicy
+	for _, attr := range attrs {
+		if attr.Attr.Type == nl.XFRMA_POLICY {
+			policy = xfrmPolicyFromXfrmUserpolicyInfo(nl.DeserializeXfrmUserpolicyInfo(attr.Value))
+		}
+	}
+	if policy == nil {
+		id := nl.DeserializeXfrmUserpolicyId(b)
+		policy = xfrmPolicyFromXfrmUserpolicyInfo(&nl.XfrmUserpolicyInfo{
+			Sel:   id.Sel,
+			Index: id.Index,
+			Dir:   id.Dir,
+		})
+	}
+	parseXfrmPolicyAttrs(policy, attrs)
+
+	return &XfrmMsgDelPolicy{XfrmPolicy: policy}, nil
+}
+
+// XfrmMsgPolExpire reports a policy reaching its soft or hard lifetime.
+type XfrmMsgPolExpire struct {
+	XfrmPolicy *XfrmPolicy
+	Hard       bool
+}
+
+func (up *XfrmMsgPolExpire) Type() nl.XfrmMsgType {
+	return nl.XFRM_MSG_POLEXPIRE
+}
+
+func parseXfrmMsgPolExpire(b []byte) (*XfrmMsgPolExpire, error) {
+	msg := nl.DeserializeXfrmUserPolexpire(b)
+	e := &XfrmMsgPolExpire{
+		XfrmPolicy: xfrmPolicyFromXfrmUserpolicyInfo(&msg.Pol),
+		Hard:       msg.Hard == 1,
+	}
+
+	attrs, err := nl.ParseRouteAttr(b[nl.SizeofXfrmUserPolexpire:])
+	if err != nil {
+		return nil, err
+	}
+	parseXfrmPolicyAttrs(e.XfrmPolicy, attrs)
+
+	return e, nil
+}
+
+// XfrmMsgFlushPolicy reports the policies being flushed.
+type XfrmMsgFlushPolicy struct{}
+
+func (uf *XfrmMsgFlushPolicy) Type() nl.XfrmMsgType {
+	return nl.XFRM_MSG_FLUSHPOLICY
+}
+
+// XfrmMsgNewAE reports the replay and lifetime values of a state. Only
+// the id, source, reqid, mark and ifid of the XfrmState are set. Flags
+// are the nl.XFRM_AE_* causes of the event.
+type XfrmMsgNewAE struct {
+	XfrmState *XfrmState
+	Flags     uint32
+	AE        *XfrmStateAE
+}
+
+func (ua *XfrmMsgNewAE) Type() nl.XfrmMsgType {
+	return nl.XFRM_MSG_NEWAE
+}
+
+func parseXfrmMsgNewAE(b []byte) (*XfrmMsgNewAE, error) {
+	msg := nl.DeserializeXfrmAeventId(b)
+	ae := &XfrmMsgNewAE{
+		XfrmState: &XfrmState{
+			Dst:   msg.SaId.Daddr.ToIP(),
+			Src:   msg.Saddr.ToIP(),
+			Proto: Proto(msg.SaId.Proto),
+			Spi:   int(nl.Swap32(msg.SaId.Spi)),
+			Reqid: int(msg.Reqid),
+		},
+		Flags: msg.Flags,
+	}
+
+	attrs, err := nl.ParseRouteAttr(b[nl.SizeofXfrmAeventId:])
+	if err != nil {
+		return nil, err
+	}
+	parseXfrmStateAttrs(ae.XfrmState, attrs)
+	ae.AE = parseXfrmStateAE(attrs)
+
+	return ae, nil
+}
+
+// XfrmMsgReport reports an event on the flow of Selector, like a packet
+// rejected by a mobile ipv6 route optimization state. CoAddr is the care
+// of address, if any.
+type XfrmMsgReport struct {
+	Proto    Proto
+	Selector XfrmSelector
+	CoAddr   net.IP
+}
+
+func (ur *XfrmMsgReport) Type() nl.XfrmMsgType {
+	return nl.XFRM_MSG_REPORT
+}
+
+func parseXfrmMsgReport(b []byte) (*XfrmMsgReport, error) {
+	msg := nl.DeserializeXfrmUserReport(b)
+	r := &XfrmMsgReport{
+		Proto:    Proto(msg.Proto),
+		Selector: selectorFromSel(&msg.Sel),
+	}
+
+	attrs, err := nl.ParseRouteAttr(b[nl.SizeofXfrmUserReport:])
+	if err != nil {
+		return nil, err
+	}
+	for _, attr := range attrs {
+		if attr.Attr.Type == nl.XFRMA_COADDR {
+			r.CoAddr = nl.DeserializeXfrmAddress(attr.Value).ToIP()
+		}
+	}
+
+	return r, nil
+}
+
+// XfrmMsgMapping reports the NAT-T source address or port change of the
+// encapsulated state identified by Dst, Proto and Spi.
+type XfrmMsgMapping struct {
+	Dst        net.IP
+	Proto      Proto
+	Spi        int
+	Reqid      int
+	OldSrc     net.IP
+	OldSrcPort int
+	NewSrc     net.IP
+	NewSrcPort int
+}
+
+func (um *XfrmMsgMapping) Type() nl.XfrmMsgType {
+	return nl.XFRM_MSG_MAPPING
+}
+
+func parseXfrmMsgMapping(b []byte) *XfrmMsgMapping {
+	msg := nl.DeserializeXfrmUserMapping(b)
+	return &XfrmMsgMapping{
+		Dst:        msg.Id.Daddr.ToIP(),
+		Proto:      Proto(msg.Id.Proto),
+		Spi:        int(nl.Swap32(msg.Id.Spi)),
+		Reqid:      int(msg.Reqid),
+		OldSrc:     msg.OldSaddr.ToIP(),
+		OldSrcPort: int(nl.Swap16(msg.OldSport)),
+		NewSrc:     msg.NewSaddr.ToIP(),
+		NewSrcPort: int(nl.Swap16(msg.NewSport)),
+	}
+}
+
+// XfrmMsgMigrate reports the migration of the policy of Selector and Dir,
+// and of its states, to the new endpoints of the Entries. KmAddress is nil
+// unless given by the key manager requesting the migration.
+type XfrmMsgMigrate struct {
+	Selector  XfrmSelector
+	Dir       Dir
+	Entries   []XfrmMigrateEntry
+	KmAddress *XfrmKmAddress
+}
+
+func (um *XfrmMsgMigrate) Type() nl.XfrmMsgType {
+	return nl.XFRM_MSG_MIGRATE
+}
+
+// The kernel reports each entry in its own XFRMA_MIGRATE attribute
+func parseXfrmMsgMigrate(b []byte) (*XfrmMsgMigrate, error) {
+	msg := nl.DeserializeXfrmUserpolicyId(b)
+	m := &XfrmMsgMigrate{
+		Selector: selectorFromSel(&msg.Sel),
+		Dir:      Dir(msg.Dir),
+	}
+
+	attrs, err := nl.ParseRouteAttr(b[nl.SizeofXfrmUserpolicyId:])
+	if err != nil {
+		return nil, err
+	}
+	for _, attr := range attrs {
+		switch attr.Attr.Type {
+		case nl.XFRMA_MIGRATE:
+			for v := attr.Value; len(v) >= nl.SizeofXfrmUserMigrate; v = v[nl.SizeofXfrmUserMigrate:] {
+				entry := xfrmMigrateEntryFromXfrmUserMigrate(nl.DeserializeXfrmUserMigrate(v))
+				m.Entries = append(m.Entries, entry)
+			}
+		case nl.XFRMA_KMADDRESS:
+			km := nl.DeserializeXfrmUserKmaddress(attr.Value)
+			m.KmAddress = &XfrmKmAddress{
+				Local:  km.Local.ToIP(),
+				Remote: km.Remote.ToIP(),
+			}
+		}
+	}
+
+	return m, nil
+}
+
+func parseXfrmMsg(msgType uint16, b []byte) (XfrmMsg, error) {
+	switch msgType {
+	case nl.XFRM_MSG_EXPIRE:
+		return parseXfrmMsgExpire(b), nil
+	case nl.XFRM_MSG_ACQUIRE:
+		return parseXfrmMsgAcquire(b)
+	case nl.XFRM_MSG_NEWSA, nl.XFRM_MSG_UPDSA:
+		state, err := parseXfrmState(b, FAMILY_ALL)
+		if err != nil {
+			return nil, err
+		}
+		if msgType == nl.XFRM_MSG_UPDSA {
+			return &XfrmMsgUpdSA{XfrmState: state}, nil
+		}
+		return &XfrmMsgNewSA{XfrmState: state}, nil
+	case nl.XFRM_MSG_DELSA:
+		return parseXfrmMsgDelSA(b)
+	case nl.XFRM_MSG_FLUSHSA:
+		return &XfrmMsgFlushSA{Proto: Proto(nl.DeserializeXfrmUsersaFlush(b).Proto)}, nil
+	case nl.XFRM_MSG_NEWPOLICY, nl.XFRM_MSG_UPDPOLICY:
+		policy, err := parseXfrmPolicy(b, FAMILY_ALL)
+		if err != nil {
+			return nil, err
+		}
+		if msgType == nl.XFRM_MSG_UPDPOLICY {
+			return &XfrmMsgUpdPolicy{XfrmPolicy: policy}, nil
+		}
+		return &XfrmMsgNewPolicy{XfrmPolicy: policy}, nil
+	case nl.XFRM_MSG_DELPOLICY:
+		return parseXfrmMsgDelPolicy(b)
+	case nl.XFRM_MSG_POLEXPIRE:
+		return parseXfrmMsgPolExpire(b)
+	case nl.XFRM_MSG_FLUSHPOLICY:
+		return &XfrmMsgFlushPolicy{}, nil
+	case nl.XFRM_MSG_NEWAE:
+		return parseXfrmMsgNewAE(b)
+	case nl.XFRM_MSG_REPORT:
+		return parseXfrmMsgReport(b)
+	case nl.XFRM_MSG_MAPPING:
+		return parseXfrmMsgMapping(b), nil
+	case nl.XFRM_MSG_MIGRATE:
+		return parseXfrmMsgMigrate(b)
+	}
+	return nil, fmt.Errorf("unsupported msg type: %x", msgType)
+}
+
+// XfrmMonitor sends the xfrm messages of the given types to ch, until
+// done is closed. The messages of the other types sharing their
+// multicast groups are dropped.
 func XfrmMonitor(ch chan<- XfrmMsg, done <-chan struct{}, errorChan chan<- error,
 	types ...nl.XfrmMsgType) error {
 
 	groups, err := xfrmMcastGroups(types)
 	if err != nil {
-		return nil
+		return err
 	}
 	s, err := nl.SubscribeAt(netns.None(), netns.None(), unix.NETLINK_XFRM, groups...)
 	if err != nil {
@@ -51,6 +439,11 @@ func XfrmMonitor(ch chan<- XfrmMsg, done <-chan struct{}, errorChan chan<- error
 
 	}
 
+	wanted := make(map[uint16]bool, len(types))
+	for _, t := range types {
+		wanted[uint16(t)] = true
+	}
+
 	go func() {
 		defer close(ch)
 		for {
@@ -64,12 +457,15 @@ func XfrmMonitor(ch chan<- XfrmMsg, done <-chan struct{}, errorChan chan<- error
 				return
 			}
 			for _, m := range msgs {
-				switch m.Header.Type {
-				case nl.XFRM_MSG_EXPIRE:
-					ch <- parseXfrmMsgExpire(m.Data)
-				default:
-					errorChan <- fmt.Errorf("unsupported msg type: %x", m.Header.Type)
+				if !wanted[m.Header.Type] {
+					continue
 				}
+				msg, err := parseXfrmMsg(m.Header.Type, m.Data)
+				if err != nil {
+					errorChan <- err
+					continue
+				}
+				ch <- msg
 			}
 		}
 	}()
@@ -84,17 +480,35 @@ func xfrmMcastGroups(types []nl.XfrmMsgType) ([]uint, error) {
 		return nil, fmt.Errorf("no xfrm msg type specified")
 	}
 
+	seen := make(map[uint]bool)
 	for _, t := range types {
 		var group uint
 
 		switch t {
-		case nl.XFRM_MSG_EXPIRE:
+		case nl.XFRM_MSG_ACQUIRE:
+			group = nl.XFRMNLGRP_ACQUIRE
+		case nl.XFRM_MSG_EXPIRE, nl.XFRM_MSG_POLEXPIRE:
 			group = nl.XFRMNLGRP_EXPIRE
+		case nl.XFRM_MSG_NEWSA, nl.XFRM_MSG_UPDSA, nl.XFRM_MSG_DELSA, nl.XFRM_MSG_FLUSHSA:
+			group = nl.XFRMNLGRP_SA
+		case nl.XFRM_MSG_NEWPOLICY, nl.XFRM_MSG_UPDPOLICY, nl.XFRM_MSG_DELPOLICY, nl.XFRM_MSG_FLUSHPOLICY:
+			group = nl.XFRMNLGRP_POLICY
+		case nl.XFRM_MSG_NEWAE:
+			group = nl.XFRMNLGRP_AEVENTS
+		case nl.XFRM_MSG_REPORT:
+			group = nl.XFRMNLGRP_REPORT
+		case nl.XFRM_MSG_MAPPING:
+			group = nl.XFRMNLGRP_MAPPING
+		case nl.XFRM_MSG_MIGRATE:
+			group = nl.XFRMNLGRP_MIGRATE
 		default:
 			return nil, fmt.Errorf("unsupported group: %x", t)
 		}
 
-		groups = append(groups, group)
+		if !seen[group] {
+			seen[group] = true
+			groups = append(groups, group)
+		}
 	}
 
 	return groups, nil
@@ -3,6 +3,8 @@
 package netlink
 
 import (
+	"net"
+	"reflect"
 	"testing"
 
 	"github.com/ndupreez/netlink/nl"
@@ -48,3 +50,188 @@ func TestXfrmMonitorExpire(t *testing.T) {
 		t.Fatal("Missing expire msg: hard found:", hardFound, "soft found:", softFound)
 	}
 }
+
+func TestParseXfrmMsgAcquire(t *testing.T) {
+	policy := getPolicy()
+	msg := &nl.XfrmUserAcquire{Seq: 7, Ealgos: 0xff}
+	msg.Id.Daddr.FromIP(policy.Tmpls[0].Dst)
+	msg.Id.Proto = uint8(XFRM_PROTO_ESP)
+	msg.Saddr.FromIP(policy.Tmpls[0].Src)
+	selFromPolicy(&msg.Sel, policy)
+	selFromPolicy(&msg.Policy.Sel, policy)
+	msg.Policy.Dir = uint8(XFRM_DIR_OUT)
+	msg.Policy.Priority = uint32(policy.Priority)
+
+	tmpl := nl.XfrmUserTmpl{Mode: uint8(XFRM_MODE_TUNNEL)}
+	tmpl.XfrmId.Daddr.FromIP(policy.Tmpls[0].Dst)
+	tmpl.XfrmId.Proto = uint8(XFRM_PROTO_ESP)
+	tmpl.XfrmId.Spi = nl.Swap32(uint32(policy.Tmpls[0].Spi))
+	tmpl.Saddr.FromIP(policy.Tmpls[0].Src)
+	tmpl.Family = uint16(nl.FAMILY_V4)
+	b := append(msg.Serialize(), nl.NewRtAttr(nl.XFRMA_TMPL, tmpl.Serialize()).Serialize()...)
+	b = append(b, nl.NewRtAttr(nl.XFRMA_MARK, writeMark(policy.Mark)).Serialize()...)
+
+	m, err := parseXfrmMsg(nl.XFRM_MSG_ACQUIRE, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	acquire, ok := m.(*XfrmMsgAcquire)
+	if !ok {
+		t.Fatalf("unexpected msg %T", m)
+	}
+	if !acquire.Dst.Equal(policy.Tmpls[0].Dst) || !acquire.Src.Equal(policy.Tmpls[0].Src) ||
+		acquire.Proto != XFRM_PROTO_ESP || acquire.Seq != 7 || acquire.CryptAlgos != 0xff {
+		t.Fatalf("unexpected acquire: %+v", acquire)
+	}
+	if !compareIPNet(acquire.Selector.Dst, policy.Dst) || acquire.Selector.DstPort != policy.DstPort ||
+		acquire.Selector.Proto != policy.Proto {
+		t.Fatalf("unexpected selector: %s", acquire.Selector)
+	}
+	if !comparePolicies(acquire.Policy, policy) {
+		t.Fatalf("unexpected policy: %s, expected %s", acquire.Policy, policy)
+	}
+}
+
+func TestParseXfrmMsgDelSAMapping(t *testing.T) {
+	state := getBaseState()
+	id := &nl.XfrmUsersaId{Proto: uint8(state.Proto), Spi: nl.Swap32(uint32(state.Spi))}
+	id.Daddr.FromIP(state.Dst)
+	b := append(id.Serialize(), nl.NewRtAttr(nl.XFRMA_SA, xfrmUsersaInfoFromXfrmState(state).Serialize()).Serialize()...)
+	b = append(b, nl.NewRtAttr(nl.XFRMA_MARK, writeMark(state.Mark)).Serialize()...)
+
+	m, err := parseXfrmMsg(nl.XFRM_MSG_DELSA, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	del, ok := m.(*XfrmMsgDelSA)
+	if !ok {
+		t.Fatalf("unexpected msg %T", m)
+	}
+	if !del.XfrmState.Src.Equal(state.Src) || !del.XfrmState.Dst.Equal(state.Dst) ||
+		del.XfrmState.Spi != state.Spi || del.XfrmState.Mode != state.Mode ||
+		!compareMarks(del.XfrmState.Mark, state.Mark) {
+		t.Fatalf("unexpected deleted state: %s", del.XfrmState)
+	}
+
+	mapping := &nl.XfrmUserMapping{Reqid: 3, OldSport: nl.Swap16(4500), NewSport: nl.Swap16(4501)}
+	mapping.Id.Daddr.FromIP(state.Dst)
+	mapping.Id.Proto = uint8(XFRM_PROTO_ESP)
+	mapping.Id.Spi = nl.Swap32(0x1234)
+	mapping.OldSaddr.FromIP(net.ParseIP("192.0.2.1"))
+	mapping.NewSaddr.FromIP(net.ParseIP("192.0.2.2"))
+	m, err = parseXfrmMsg(nl.XFRM_MSG_MAPPING, mapping.Serialize())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &XfrmMsgMapping{
+		Dst:        net.ParseIP(state.Dst.String()),
+		Proto:      XFRM_PROTO_ESP,
+		Spi:        0x1234,
+		Reqid:      3,
+		OldSrc:     net.ParseIP("192.0.2.1"),
+		OldSrcPort: 4500,
+		NewSrc:     net.ParseIP("192.0.2.2"),
+		NewSrcPort: 4501,
+	}
+	if !reflect.DeepEqual(m, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, m)
+	}
+}
+
+func TestParseXfrmMsgMigrate(t *testing.T) {
+	policy := getPolicy()
+	id := &nl.XfrmUserpolicyId{Dir: uint8(XFRM_DIR_OUT)}
+	selFromPolicy(&id.Sel, policy)
+	entries := []XfrmMigrateEntry{
+		{
+			OldDst: net.ParseIP("127.0.0.1"),
+			OldSrc: net.ParseIP("127.0.0.2"),
+			NewDst: net.ParseIP("127.0.0.3"),
+			NewSrc: net.ParseIP("127.0.0.4"),
+			Proto:  XFRM_PROTO_ESP,
+			Mode:   XFRM_MODE_TUNNEL,
+			Reqid:  0xff,
+		},
+		{
+			OldDst: net.ParseIP("2001:db8::1"),
+			OldSrc: net.ParseIP("2001:db8::2"),
+			NewDst: net.ParseIP("2001:db8::3"),
+			NewSrc: net.ParseIP("2001:db8::4"),
+			Proto:  XFRM_PROTO_AH,
+			Mode:   XFRM_MODE_TRANSPORT,
+		},
+	}
+	km := &nl.XfrmUserKmaddress{Family: nl.FAMILY_V4}
+	km.Local.FromIP(net.ParseIP("192.0.2.1"))
+	km.Remote.FromIP(net.ParseIP("192.0.2.2"))
+
+	b := append(id.Serialize(), nl.NewRtAttr(nl.XFRMA_KMADDRESS, km.Serialize()).Serialize()...)
+	for i := range entries {
+		b = append(b, nl.NewRtAttr(nl.XFRMA_MIGRATE, xfrmUserMigrateFromEntry(&entries[i]).Serialize()).Serialize()...)
+	}
+
+	m, err := parseXfrmMsg(nl.XFRM_MSG_MIGRATE, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	migrate, ok := m.(*XfrmMsgMigrate)
+	if !ok {
+		t.Fatalf("unexpected msg %T", m)
+	}
+	if migrate.Dir != XFRM_DIR_OUT || !compareIPNet(migrate.Selector.Dst, policy.Dst) ||
+		!compareIPNet(migrate.Selector.Src, policy.Src) {
+		t.Fatalf("unexpected selector %s dir %s", migrate.Selector, migrate.Dir)
+	}
+	if migrate.KmAddress == nil || !migrate.KmAddress.Local.Equal(net.ParseIP("192.0.2.1")) ||
+		!migrate.KmAddress.Remote.Equal(net.ParseIP("192.0.2.2")) {
+		t.Fatalf("unexpected kmaddress %v", migrate.KmAddress)
+	}
+	if len(migrate.Entries) != len(entries) {
+		t.Fatalf("expected %d entries, got %d", len(entries), len(migrate.Entries))
+	}
+	for i, e := range migrate.Entries {
+		if !e.OldDst.Equal(entries[i].OldDst) || !e.OldSrc.Equal(entries[i].OldSrc) ||
+			!e.NewDst.Equal(entries[i].NewDst) || !e.NewSrc.Equal(entries[i].NewSrc) ||
+			e.Proto != entries[i].Proto || e.Mode != entries[i].Mode || e.Reqid != entries[i].Reqid {
+			t.Fatalf("expected entry %s, got %s", entries[i], e)
+		}
+	}
+}
+
+func TestXfrmMonitorStatePolicy(t *testing.T) {
+	defer setUpNetlinkTest(t)()
+
+	ch := make(chan XfrmMsg)
+	done := make(chan struct{})
+	defer close(done)
+	errChan := make(chan error)
+	if err := XfrmMonitor(ch, done, errChan, nl.XFRM_MSG_NEWSA, nl.XFRM_MSG_DELSA, nl.XFRM_MSG_NEWPOLICY); err != nil {
+		t.Fatal(err)
+	}
+
+	state := getBaseState()
+	if err := XfrmStateAdd(state); err != nil {
+		t.Fatal(err)
+	}
+	newSA, ok := (<-ch).(*XfrmMsgNewSA)
+	if !ok || newSA.XfrmState.Spi != state.Spi {
+		t.Fatal("Received unexpected msg, expected the new state")
+	}
+
+	policy := getPolicy()
+	if err := XfrmPolicyAdd(policy); err != nil {
+		t.Fatal(err)
+	}
+	newPolicy, ok := (<-ch).(*XfrmMsgNewPolicy)
+	if !ok || !comparePolicies(newPolicy.XfrmPolicy, policy) {
+		t.Fatal("Received unexpected msg, expected the new policy")
+	}
+
+	if err := XfrmStateDel(state); err != nil {
+		t.Fatal(err)
+	}
+	delSA, ok := (<-ch).(*XfrmMsgDelSA)
+	if !ok || delSA.XfrmState.Spi != state.Spi {
+		t.Fatal("Received unexpected msg, expected the deleted state")
+	}
+}
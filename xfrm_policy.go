@@ -54,6 +54,115 @@ func (a PolicyAction) String() string {
 	}
 }
 
+// PolicyType is an enum representing the type of an ipsec policy. The sub
+// policies are looked up before the main ones.
+type PolicyType uint8
+
+const (
+	XFRM_POLICY_TYPE_MAIN PolicyType = 0
+	XFRM_POLICY_TYPE_SUB  PolicyType = 1
+)
+
+func (t PolicyType) String() string {
+	switch t {
+	case XFRM_POLICY_TYPE_MAIN:
+		return "main"
+	case XFRM_POLICY_TYPE_SUB:
+		return "sub"
+	default:
+		return fmt.Sprintf("type %d", t)
+	}
+}
+
+// PolicyFlags is a bitmask of the ipsec policy flags.
+type PolicyFlags uint8
+
+const (
+	// XFRM_POLICY_LOCALOK allows the socket policies to override the policy
+	XFRM_POLICY_LOCALOK PolicyFlags = 1
+	// XFRM_POLICY_ICMP expands the selector to the icmp errors of the flows
+	XFRM_POLICY_ICMP PolicyFlags = 2
+)
+
+// PolicyShare is an enum representing how the states of an ipsec policy
+// are shared between the flows.
+type PolicyShare uint8
+
+const (
+	XFRM_SHARE_ANY PolicyShare = iota
+	XFRM_SHARE_SESSION
+	XFRM_SHARE_USER
+	XFRM_SHARE_UNIQUE
+)
+
+func (s PolicyShare) String() string {
+	switch s {
+	case XFRM_SHARE_ANY:
+		return "any"
+	case XFRM_SHARE_SESSION:
+		return "session"
+	case XFRM_SHARE_USER:
+		return "user"
+	case XFRM_SHARE_UNIQUE:
+		return "unique"
+	default:
+		return fmt.Sprintf("share %d", s)
+	}
+}
+
+// XfrmSecCtx represents the security context of an ipsec policy, like a
+// SELinux label. The zero Doi and Alg stand for the LSM domain and the
+// SELinux algorithm.
+type XfrmSecCtx struct {
+	Doi uint8
+	Alg uint8
+	Ctx string
+}
+
+func (c *XfrmSecCtx) String() string {
+	return fmt.Sprintf("(%d,%d,%s)", c.Doi, c.Alg, c.Ctx)
+}
+
+// XfrmSelector represents the traffic selector of an ipsec policy or
+// state: the addresses, protocol, ports and interface of the flows.
+type XfrmSelector struct {
+	Dst     *net.IPNet
+	Src     *net.IPNet
+	Proto   Proto
+	DstPort int
+	SrcPort int
+	Ifindex int
+}
+
+func (s XfrmSelector) String() string {
+	return fmt.Sprintf("{Dst: %v, Src: %v, Proto: %s, DstPort: %d, SrcPort: %d, Ifindex: %d}",
+		s.Dst, s.Src, s.Proto, s.DstPort, s.SrcPort, s.Ifindex)
+}
+
+// XfrmSpdHashThresh holds the local and remote prefix lengths from which
+// the policies are hashed on their addresses, the policies with shorter
+// prefixes being kept in a list.
+type XfrmSpdHashThresh struct {
+	LocalBits  uint8
+	RemoteBits uint8
+}
+
+// XfrmSpdStats holds the number of policies per direction, for the main
+// and the socket policies, and the size and thresholds of the policy hash
+// table.
+type XfrmSpdStats struct {
+	InCount        uint32
+	OutCount       uint32
+	FwdCount       uint32
+	InSocketCount  uint32
+	OutSocketCount uint32
+	FwdSocketCount uint32
+	HashCount      uint32
+	HashMaxCount   uint32
+	IPv4Thresh     XfrmSpdHashThresh
+	IPv6Thresh     XfrmSpdHashThresh
+}
+
 // XfrmPolicyTmpl encapsulates a rule for the base addresses of an ipsec
 // policy. These rules are matched with XfrmState to determine encryption
 // and authentication algorithms.
@@ -89,9 +198,43 @@ type XfrmPolicy struct {
 	Ifid     int
 	Mark     *XfrmMark
 	Tmpls    []XfrmPolicyTmpl
+	Type     PolicyType
+	Flags    PolicyFlags
+	Share    PolicyShare
+	SecCtx   *XfrmSecCtx
+	Limits   XfrmStateLimits
+	Lifetime XfrmStateLifetime
 }
 
 func (p XfrmPolicy) String() string {
-	return fmt.Sprintf("{Dst: %v, Src: %v, Proto: %s, DstPort: %d, SrcPort: %d, Dir: %s, Priority: %d, Index: %d, Action: %s, Ifindex: %d, Ifid: %d, Mark: %s, Tmpls: %s}",
-		p.Dst, p.Src, p.Proto, p.DstPort, p.SrcPort, p.Dir, p.Priority, p.Index, p.Action, p.Ifindex, p.Ifid, p.Mark, p.Tmpls)
+	return fmt.Sprintf("{Dst: %v, Src: %v, Proto: %s, DstPort: %d, SrcPort: %d, Dir: %s, Priority: %d, Index: %d, Action: %s, Ifindex: %d, Ifid: %d, Mark: %s, Tmpls: %s, Type: %s, SecCtx: %s}",
+		p.Dst, p.Src, p.Proto, p.DstPort, p.SrcPort, p.Dir, p.Priority, p.Index, p.Action, p.Ifindex, p.Ifid, p.Mark, p.Tmpls, p.Type, p.SecCtx)
+}
+
+// XfrmMigrateEntry moves the templates and states of Proto, Mode and
+// Reqid from the OldDst and OldSrc tunnel endpoints to NewDst and NewSrc.
+type XfrmMigrateEntry struct {
+	OldDst net.IP
+	OldSrc net.IP
+	NewDst net.IP
+	NewSrc net.IP
+	Proto  Proto
+	Mode   Mode
+	Reqid  int
+}
+
+func (m XfrmMigrateEntry) String() string {
+	return fmt.Sprintf("{OldDst: %v, OldSrc: %v, NewDst: %v, NewSrc: %v, Proto: %s, Mode: %s, Reqid: 0x%x}",
+		m.OldDst, m.OldSrc, m.NewDst, m.NewSrc, m.Proto, m.Mode, m.Reqid)
+}
+
+// XfrmKmAddress holds the local and remote addresses of the key manager
+// (IKE) endpoints, reported to the other key managers with a migration.
+type XfrmKmAddress struct {
+	Local  net.IP
+	Remote net.IP
+}
+
+func (k XfrmKmAddress) String() string {
+	return fmt.Sprintf("{Local: %v, Remote: %v}", k.Local, k.Remote)
 }
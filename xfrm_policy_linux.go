@@ -1,33 +1,59 @@
 package netlink
 
 import (
+	"fmt"
+	"net"
+	"syscall"
+
 	"github.com/ndupreez/netlink/nl"
 	"golang.org/x/sys/unix"
 )
 
 func selFromPolicy(sel *nl.XfrmSelector, policy *XfrmPolicy) {
+	selFromSelector(sel, &XfrmSelector{
+		Dst:     policy.Dst,
+		Src:     policy.Src,
+		Proto:   policy.Proto,
+		DstPort: policy.DstPort,
+		SrcPort: policy.SrcPort,
+		Ifindex: policy.Ifindex,
+	})
+}
+
+func selFromSelector(sel *nl.XfrmSelector, selector *XfrmSelector) {
 	sel.Family = uint16(nl.FAMILY_V4)
-	if policy.Dst != nil {
-		sel.Family = uint16(nl.GetIPFamily(policy.Dst.IP))
-		sel.Daddr.FromIP(policy.Dst.IP)
-		prefixlenD, _ := policy.Dst.Mask.Size()
+	if selector.Dst != nil {
+		sel.Family = uint16(nl.GetIPFamily(selector.Dst.IP))
+		sel.Daddr.FromIP(selector.Dst.IP)
+		prefixlenD, _ := selector.Dst.Mask.Size()
 		sel.PrefixlenD = uint8(prefixlenD)
 	}
-	if policy.Src != nil {
-		sel.Saddr.FromIP(policy.Src.IP)
-		prefixlenS, _ := policy.Src.Mask.Size()
+	if selector.Src != nil {
+		sel.Saddr.FromIP(selector.Src.IP)
+		prefixlenS, _ := selector.Src.Mask.Size()
 		sel.PrefixlenS = uint8(prefixlenS)
 	}
-	sel.Proto = uint8(policy.Proto)
-	sel.Dport = nl.Swap16(uint16(policy.DstPort))
-	sel.Sport = nl.Swap16(uint16(policy.SrcPort))
+	sel.Proto = uint8(selector.Proto)
+	sel.Dport = nl.Swap16(uint16(selector.DstPort))
+	sel.Sport = nl.Swap16(uint16(selector.SrcPort))
 	if sel.Dport != 0 {
 		sel.DportMask = ^uint16(0)
 	}
 	if sel.Sport != 0 {
 		sel.SportMask = ^uint16(0)
 	}
-	sel.Ifindex = int32(policy.Ifindex)
+	sel.Ifindex = int32(selector.Ifindex)
+}
+
+func selectorFromSel(sel *nl.XfrmSelector) XfrmSelector {
+	return XfrmSelector{
+		Dst:     sel.Daddr.ToIPNet(sel.PrefixlenD),
+		Src:     sel.Saddr.ToIPNet(sel.PrefixlenS),
+		Proto:   Proto(sel.Proto),
+		DstPort: int(nl.Swap16(sel.Dport)),
+		SrcPort: int(nl.Swap16(sel.Sport)),
+		Ifindex: int(sel.Ifindex),
+	}
 }
 
 // XfrmPolicyAdd will add an xfrm policy to the system.
@@ -57,20 +83,43 @@ func (h *Handle) XfrmPolicyUpdate(policy *XfrmPolicy) error {
 func (h *Handle) xfrmPolicyAddOrUpdate(policy *XfrmPolicy, nlProto int) error {
 	req := h.newNetlinkRequest(nlProto, unix.NLM_F_CREATE|unix.NLM_F_EXCL|unix.NLM_F_ACK)
 
+	msg := xfrmUserpolicyInfoFromXfrmPolicy(policy)
+	req.AddData(msg)
+
+	tmplData := writeXfrmUserTmpls(policy.Tmpls)
+	if len(tmplData) > 0 {
+		tmpls := nl.NewRtAttr(nl.XFRMA_TMPL, tmplData)
+		req.AddData(tmpls)
+	}
+	if policy.Mark != nil {
+		out := nl.NewRtAttr(nl.XFRMA_MARK, writeMark(policy.Mark))
+		req.AddData(out)
+	}
+	addXfrmPolicyIdAttrs(req, policy)
+
+	ifId := nl.NewRtAttr(nl.XFRMA_IF_ID, nl.Uint32Attr(uint32(policy.Ifid)))
+	req.AddData(ifId)
+
+	_, err := req.Execute(unix.NETLINK_XFRM, 0)
+	return err
+}
+
+func xfrmUserpolicyInfoFromXfrmPolicy(policy *XfrmPolicy) *nl.XfrmUserpolicyInfo {
 	msg := &nl.XfrmUserpolicyInfo{}
 	selFromPolicy(&msg.Sel, policy)
 	msg.Priority = uint32(policy.Priority)
 	msg.Index = uint32(policy.Index)
 	msg.Dir = uint8(policy.Dir)
 	msg.Action = uint8(policy.Action)
-	msg.Lft.SoftByteLimit = nl.XFRM_INF
-	msg.Lft.HardByteLimit = nl.XFRM_INF
-	msg.Lft.SoftPacketLimit = nl.XFRM_INF
-	msg.Lft.HardPacketLimit = nl.XFRM_INF
-	req.AddData(msg)
+	msg.Flags = uint8(policy.Flags)
+	msg.Share = uint8(policy.Share)
+	limitsToLft(policy.Limits, &msg.Lft)
+	return msg
+}
 
-	tmplData := make([]byte, nl.SizeofXfrmUserTmpl*len(policy.Tmpls))
-	for i, tmpl := range policy.Tmpls {
+func writeXfrmUserTmpls(tmpls []XfrmPolicyTmpl) []byte {
+	tmplData := make([]byte, nl.SizeofXfrmUserTmpl*len(tmpls))
+	for i, tmpl := range tmpls {
 		start := i * nl.SizeofXfrmUserTmpl
 		userTmpl := nl.DeserializeXfrmUserTmpl(tmplData[start : start+nl.SizeofXfrmUserTmpl])
 		userTmpl.XfrmId.Daddr.FromIP(tmpl.Dst)
@@ -84,20 +133,38 @@ func (h *Handle) xfrmPolicyAddOrUpdate(policy *XfrmPolicy, nlProto int) error {
 		userTmpl.Ealgos = ^uint32(0)
 		userTmpl.Calgos = ^uint32(0)
 	}
-	if len(tmplData) > 0 {
-		tmpls := nl.NewRtAttr(nl.XFRMA_TMPL, tmplData)
-		req.AddData(tmpls)
+	return tmplData
+}
+
+// The security context and the type also identify a policy, so they are
+// given to the kernel with the policy to get or delete.
+func addXfrmPolicyIdAttrs(req *nl.NetlinkRequest, policy *XfrmPolicy) {
+	if policy.SecCtx != nil {
+		out := nl.NewRtAttr(nl.XFRMA_SEC_CTX, writeSecCtx(policy.SecCtx))
+		req.AddData(out)
 	}
-	if policy.Mark != nil {
-		out := nl.NewRtAttr(nl.XFRMA_MARK, writeMark(policy.Mark))
+	if policy.Type != XFRM_POLICY_TYPE_MAIN {
+		upt := &nl.XfrmUserpolicyType{Type: uint8(policy.Type)}
+		out := nl.NewRtAttr(nl.XFRMA_POLICY_TYPE, upt.Serialize())
 		req.AddData(out)
 	}
+}
 
-	ifId := nl.NewRtAttr(nl.XFRMA_IF_ID, nl.Uint32Attr(uint32(policy.Ifid)))
-	req.AddData(ifId)
-
-	_, err := req.Execute(unix.NETLINK_XFRM, 0)
-	return err
+func writeSecCtx(c *XfrmSecCtx) []byte {
+	ctx := &nl.XfrmUserSecCtx{
+		Length:  uint16(nl.SizeofXfrmUserSecCtx + len(c.Ctx)),
+		Exttype: nl.XFRMA_SEC_CTX,
+		CtxAlg:  c.Alg,
+		CtxDoi:  c.Doi,
+		CtxLen:  uint16(len(c.Ctx)),
+	}
+	if ctx.CtxAlg == nl.XFRM_SC_ALG_RESERVED {
+		ctx.CtxAlg = nl.XFRM_SC_ALG_SELINUX
+	}
+	if ctx.CtxDoi == nl.XFRM_SC_DOI_RESERVED {
+		ctx.CtxDoi = nl.XFRM_SC_DOI_LSM
+	}
+	return append(ctx.Serialize(), c.Ctx...)
 }
 
 // XfrmPolicyDel will delete an xfrm policy from the system. Note that
@@ -126,6 +193,20 @@ func XfrmPolicyList(family int) ([]XfrmPolicy, error) {
 // Equivalent to: `ip xfrm policy show`.
 // The list can be filtered by ip family.
 func (h *Handle) XfrmPolicyList(family int) ([]XfrmPolicy, error) {
+	return h.XfrmPolicyListFiltered(family, nil)
+}
+
+// XfrmPolicyListFiltered gets the xfrm policies of the ip family selected
+// by the filter.
+// Equivalent to: `ip xfrm policy show [ SELECTOR ] [ dir DIR ]`
+func XfrmPolicyListFiltered(family int, filter CustomXfrmPolicyFilter) ([]XfrmPolicy, error) {
+	return pkgHandle.XfrmPolicyListFiltered(family, filter)
+}
+
+// XfrmPolicyListFiltered gets the xfrm policies of the ip family selected
+// by the filter.
+// Equivalent to: `ip xfrm policy show [ SELECTOR ] [ dir DIR ]`
+func (h *Handle) XfrmPolicyListFiltered(family int, filter CustomXfrmPolicyFilter) ([]XfrmPolicy, error) {
 	req := h.newNetlinkRequest(nl.XFRM_MSG_GETPOLICY, unix.NLM_F_DUMP)
 
 	msg := nl.NewIfInfomsg(family)
@@ -139,6 +220,9 @@ func (h *Handle) XfrmPolicyList(family int) ([]XfrmPolicy, error) {
 	var res []XfrmPolicy
 	for _, m := range msgs {
 		if policy, err := parseXfrmPolicy(m, family); err == nil {
+			if filter != nil && !filter.MatchXfrmPolicy(policy) {
+				continue
+			}
 			res = append(res, *policy)
 		} else if err == familyError {
 			continue
@@ -149,6 +233,37 @@ func (h *Handle) XfrmPolicyList(family int) ([]XfrmPolicy, error) {
 	return res, nil
 }
 
+// XfrmPolicyDeleteFilter deletes the xfrm policies of the ip family
+// selected by the filter and returns the number of deleted policies. The
+// socket policies are never deleted.
+// Equivalent to: `ip xfrm policy deleteall [ SELECTOR ] [ dir DIR ]`
+func XfrmPolicyDeleteFilter(family int, filter CustomXfrmPolicyFilter) (uint, error) {
+	return pkgHandle.XfrmPolicyDeleteFilter(family, filter)
+}
+
+// XfrmPolicyDeleteFilter deletes the xfrm policies of the ip family
+// selected by the filter and returns the number of deleted policies. The
+// socket policies are never deleted.
+// Equivalent to: `ip xfrm policy deleteall [ SELECTOR ] [ dir DIR ]`
+func (h *Handle) XfrmPolicyDeleteFilter(family int, filter CustomXfrmPolicyFilter) (uint, error) {
+	policies, err := h.XfrmPolicyListFiltered(family, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	var deleted uint
+	for i := range policies {
+		if policies[i].Dir >= XFRM_SOCKET_IN {
+			continue
+		}
+		if err := h.XfrmPolicyDel(&policies[i]); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
 // XfrmPolicyGet gets a the policy described by the index or selector, if found.
 // Equivalent to: `ip xfrm policy get { SELECTOR | index INDEX } dir DIR [ctx CTX ] [ mark MARK [ mask MASK ] ] [ ptype PTYPE ]`.
 func XfrmPolicyGet(policy *XfrmPolicy) (*XfrmPolicy, error) {
@@ -188,6 +303,7 @@ func (h *Handle) xfrmPolicyGetOrDelete(policy *XfrmPolicy, nlProto int) (*XfrmPo
 		out := nl.NewRtAttr(nl.XFRMA_MARK, writeMark(policy.Mark))
 		req.AddData(out)
 	}
+	addXfrmPolicyIdAttrs(req, policy)
 
 	ifId := nl.NewRtAttr(nl.XFRMA_IF_ID, nl.Uint32Attr(uint32(policy.Ifid)))
 	req.AddData(ifId)
@@ -217,6 +333,18 @@ func parseXfrmPolicy(m []byte, family int) (*XfrmPolicy, error) {
 		return nil, familyError
 	}
 
+	policy := xfrmPolicyFromXfrmUserpolicyInfo(msg)
+
+	attrs, err := nl.ParseRouteAttr(m[msg.Len():])
+	if err != nil {
+		return nil, err
+	}
+	parseXfrmPolicyAttrs(policy, attrs)
+
+	return policy, nil
+}
+
+func xfrmPolicyFromXfrmUserpolicyInfo(msg *nl.XfrmUserpolicyInfo) *XfrmPolicy {
 	var policy XfrmPolicy
 
 	policy.Dst = msg.Sel.Daddr.ToIPNet(msg.Sel.PrefixlenD)
@@ -229,12 +357,20 @@ func parseXfrmPolicy(m []byte, family int) (*XfrmPolicy, error) {
 	policy.Index = int(msg.Index)
 	policy.Dir = Dir(msg.Dir)
 	policy.Action = PolicyAction(msg.Action)
-
-	attrs, err := nl.ParseRouteAttr(m[msg.Len():])
-	if err != nil {
-		return nil, err
+	policy.Flags = PolicyFlags(msg.Flags)
+	policy.Share = PolicyShare(msg.Share)
+	lftToLimits(&msg.Lft, &policy.Limits)
+	policy.Lifetime = XfrmStateLifetime{
+		Bytes:   msg.Curlft.Bytes,
+		Packets: msg.Curlft.Packets,
+		AddTime: msg.Curlft.AddTime,
+		UseTime: msg.Curlft.UseTime,
 	}
 
+	return &policy
+}
+
+func parseXfrmPolicyAttrs(policy *XfrmPolicy, attrs []syscall.NetlinkRouteAttr) {
 	for _, attr := range attrs {
 		switch attr.Attr.Type {
 		case nl.XFRMA_TMPL:
@@ -258,8 +394,199 @@ func parseXfrmPolicy(m []byte, family int) (*XfrmPolicy, error) {
 			policy.Mark.Mask = mark.Mask
 		case nl.XFRMA_IF_ID:
 			policy.Ifid = int(native.Uint32(attr.Value))
+		case nl.XFRMA_SEC_CTX:
+			ctx := nl.DeserializeXfrmUserSecCtx(attr.Value)
+			policy.SecCtx = &XfrmSecCtx{
+				Doi: ctx.CtxDoi,
+				Alg: ctx.CtxAlg,
+				Ctx: string(attr.Value[nl.SizeofXfrmUserSecCtx : nl.SizeofXfrmUserSecCtx+int(ctx.CtxLen)]),
+			}
+		case nl.XFRMA_POLICY_TYPE:
+			policy.Type = PolicyType(nl.DeserializeXfrmUserpolicyType(attr.Value).Type)
 		}
 	}
+}
+
+// XfrmSpdInfo returns the number of policies and the size and thresholds
+// of the policy hash table.
+// Equivalent to: `ip -s xfrm policy count`
+func XfrmSpdInfo() (*XfrmSpdStats, error) {
+	return pkgHandle.XfrmSpdInfo()
+}
+
+// XfrmSpdInfo returns the number of policies and the size and thresholds
+// of the policy hash table.
+// Equivalent to: `ip -s xfrm policy count`
+func (h *Handle) XfrmSpdInfo() (*XfrmSpdStats, error) {
+	req := h.newNetlinkRequest(nl.XFRM_MSG_GETSPDINFO, 0)
+	req.AddRawData(nl.Uint32Attr(^uint32(0)))
+
+	msgs, err := req.Execute(unix.NETLINK_XFRM, nl.XFRM_MSG_NEWSPDINFO)
+	if err != nil {
+		return nil, err
+	}
+
+	attrs, err := nl.ParseRouteAttr(msgs[0][4:])
+	if err != nil {
+		return nil, err
+	}
+
+	var info XfrmSpdStats
+	for _, attr := range attrs {
+		switch attr.Attr.Type {
+		case nl.XFRMA_SPD_INFO:
+			info.InCount = native.Uint32(attr.Value[0:4])
+			info.OutCount = native.Uint32(attr.Value[4:8])
+			info.FwdCount = native.Uint32(attr.Value[8:12])
+			info.InSocketCount = native.Uint32(attr.Value[12:16])
+			info.OutSocketCount = native.Uint32(attr.Value[16:20])
+			info.FwdSocketCount = native.Uint32(attr.Value[20:24])
+		case nl.XFRMA_SPD_HINFO:
+			info.HashCount = native.Uint32(attr.Value[0:4])
+			info.HashMaxCount = native.Uint32(attr.Value[4:8])
+		case nl.XFRMA_SPD_IPV4_HTHRESH:
+			thresh := nl.DeserializeXfrmuSpdHthresh(attr.Value)
+			info.IPv4Thresh = XfrmSpdHashThresh{LocalBits: thresh.Lbits, RemoteBits: thresh.Rbits}
+		case nl.XFRMA_SPD_IPV6_HTHRESH:
+			thresh := nl.DeserializeXfrmuSpdHthresh(attr.Value)
+			info.IPv6Thresh = XfrmSpdHashThresh{LocalBits: thresh.Lbits, RemoteBits: thresh.Rbits}
+		}
+	}
+	return &info, nil
+}
+
+// XfrmSpdInfoSet sets the policy hash table thresholds, a nil threshold
+// being left unchanged. The policies are rehashed with the new values.
+// Equivalent to: `ip xfrm policy set [ hthresh4 LBITS RBITS ] [ hthresh6 LBITS RBITS ]`
+func XfrmSpdInfoSet(ipv4, ipv6 *XfrmSpdHashThresh) error {
+	return pkgHandle.XfrmSpdInfoSet(ipv4, ipv6)
+}
+
+// XfrmSpdInfoSet sets the policy hash table thresholds, a nil threshold
+// being left unchanged. The policies are rehashed with the new values.
+// Equivalent to: `ip xfrm policy set [ hthresh4 LBITS RBITS ] [ hthresh6 LBITS RBITS ]`
+func (h *Handle) XfrmSpdInfoSet(ipv4, ipv6 *XfrmSpdHashThresh) error {
+	req := h.newNetlinkRequest(nl.XFRM_MSG_NEWSPDINFO, unix.NLM_F_ACK)
+	// The flags word precedes the attributes and the request serializes
+	// Data before RawData, so the attributes are appended raw as well.
+	req.AddRawData(nl.Uint32Attr(^uint32(0)))
+
+	if ipv4 != nil {
+		thresh := &nl.XfrmuSpdHthresh{Lbits: ipv4.LocalBits, Rbits: ipv4.RemoteBits}
+		req.AddRawData(nl.NewRtAttr(nl.XFRMA_SPD_IPV4_HTHRESH, thresh.Serialize()).Serialize())
+	}
+	if ipv6 != nil {
+		thresh := &nl.XfrmuSpdHthresh{Lbits: ipv6.LocalBits, Rbits: ipv6.RemoteBits}
+		req.AddRawData(nl.NewRtAttr(nl.XFRMA_SPD_IPV6_HTHRESH, thresh.Serialize()).Serialize())
+	}
+
+	_, err := req.Execute(unix.NETLINK_XFRM, 0)
+	return err
+}
+
+// XfrmMigrate moves the policy of selector and dir, and the states
+// matching its templates, to the new tunnel endpoints of the entries in a
+// single operation. The kmaddress may be nil.
+func XfrmMigrate(selector *XfrmSelector, dir Dir, entries []XfrmMigrateEntry, kmaddress *XfrmKmAddress) error {
+	return pkgHandle.XfrmMigrate(selector, dir, entries, kmaddress)
+}
+
+// XfrmMigrate moves the policy of selector and dir, and the states
+// matching its templates, to the new tunnel endpoints of the entries in a
+// single operation. The kmaddress may be nil.
+func (h *Handle) XfrmMigrate(selector *XfrmSelector, dir Dir, entries []XfrmMigrateEntry, kmaddress *XfrmKmAddress) error {
+	if len(entries) == 0 {
+		return fmt.Errorf("no xfrm migrate entry specified")
+	}
+
+	req := h.newNetlinkRequest(nl.XFRM_MSG_MIGRATE, unix.NLM_F_ACK)
+
+	msg := &nl.XfrmUserpolicyId{Dir: uint8(dir)}
+	selFromSelector(&msg.Sel, selector)
+	req.AddData(msg)
+
+	// The kernel reads all the entries from a single attribute
+	migrate := make([]byte, 0, len(entries)*nl.SizeofXfrmUserMigrate)
+	for _, entry := range entries {
+		migrate = append(migrate, xfrmUserMigrateFromEntry(&entry).Serialize()...)
+	}
+	req.AddData(nl.NewRtAttr(nl.XFRMA_MIGRATE, migrate))
+
+	if kmaddress != nil {
+		km := &nl.XfrmUserKmaddress{Family: uint16(nl.GetIPFamily(kmaddress.Local))}
+		km.Local.FromIP(kmaddress.Local)
+		km.Remote.FromIP(kmaddress.Remote)
+		req.AddData(nl.NewRtAttr(nl.XFRMA_KMADDRESS, km.Serialize()))
+	}
+
+	_, err := req.Execute(unix.NETLINK_XFRM, 0)
+	return err
+}
 
-	return &policy, nil
+func xfrmUserMigrateFromEntry(entry *XfrmMigrateEntry) *nl.XfrmUserMigrate {
+	msg := &nl.XfrmUserMigrate{
+		Proto:     uint8(entry.Proto),
+		Mode:      uint8(entry.Mode),
+		Reqid:     uint32(entry.Reqid),
+		OldFamily: uint16(nl.GetIPFamily(entry.OldDst)),
+		NewFamily: uint16(nl.GetIPFamily(entry.NewDst)),
+	}
+	msg.OldDaddr.FromIP(entry.OldDst)
+	msg.OldSaddr.FromIP(entry.OldSrc)
+	msg.NewDaddr.FromIP(entry.NewDst)
+	msg.NewSaddr.FromIP(entry.NewSrc)
+	return msg
+}
+
+func xfrmMigrateEntryFromXfrmUserMigrate(msg *nl.XfrmUserMigrate) XfrmMigrateEntry {
+	return XfrmMigrateEntry{
+		OldDst: msg.OldDaddr.ToIP(),
+		OldSrc: msg.OldSaddr.ToIP(),
+		NewDst: msg.NewDaddr.ToIP(),
+		NewSrc: msg.NewSaddr.ToIP(),
+		Proto:  Proto(msg.Proto),
+		Mode:   Mode(msg.Mode),
+		Reqid:  int(msg.Reqid),
+	}
+}
+
+// XfrmSocketPolicySet sets the ipsec policy of the socket of conn for the
+// XFRM_DIR_IN or XFRM_DIR_OUT direction of the policy. The socket policy
+// takes precedence over the global policies for the flows of the socket.
+// Only the selector, limits, action, flags, share and templates of the
+// policy are used.
+func XfrmSocketPolicySet(conn net.Conn, policy *XfrmPolicy) error {
+	if policy.Dir != XFRM_DIR_IN && policy.Dir != XFRM_DIR_OUT {
+		return fmt.Errorf("invalid socket policy direction: %s", policy.Dir)
+	}
+	sc, ok := conn.(syscall.Conn)
+	if !ok {
+		return fmt.Errorf("no socket for connection of type %T", conn)
+	}
+	rawConn, err := sc.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	// The kernel expects the policy immediately followed by its templates
+	b := xfrmUserpolicyInfoFromXfrmPolicy(policy).Serialize()
+	b = append(b, writeXfrmUserTmpls(policy.Tmpls)...)
+
+	var sockErr error
+	err = rawConn.Control(func(fd uintptr) {
+		domain, err := unix.GetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_DOMAIN)
+		if err != nil {
+			sockErr = err
+			return
+		}
+		level, opt := unix.SOL_IP, unix.IP_XFRM_POLICY
+		if domain == unix.AF_INET6 {
+			level, opt = unix.SOL_IPV6, unix.IPV6_XFRM_POLICY
+		}
+		sockErr = unix.SetsockoptString(int(fd), level, opt, string(b))
+	})
+	if err != nil {
+		return err
+	}
+	return sockErr
 }
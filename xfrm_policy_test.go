@@ -6,6 +6,9 @@ import (
 	"bytes"
 	"net"
 	"testing"
+
+	"github.com/ndupreez/netlink/nl"
+	"golang.org/x/sys/unix"
 )
 
 const zeroCIDR = "0.0.0.0/0"
@@ -215,6 +218,104 @@ func TestXfrmPolicyWithOptional(t *testing.T) {
 	}
 }
 
+func TestXfrmPolicyWithLimitsAndFlags(t *testing.T) {
+	defer setUpNetlinkTest(t)()
+
+	pol := getPolicy()
+	pol.Flags = XFRM_POLICY_ICMP
+	pol.Limits = XfrmStateLimits{
+		ByteSoft:    1000,
+		ByteHard:    2000,
+		PacketSoft:  10,
+		PacketHard:  20,
+		TimeSoft:    60,
+		TimeHard:    120,
+		TimeUseSoft: 30,
+		TimeUseHard: 90,
+	}
+
+	if err := XfrmPolicyAdd(pol); err != nil {
+		t.Fatal(err)
+	}
+	policies, err := XfrmPolicyList(FAMILY_ALL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(policies) != 1 {
+		t.Fatalf("unexpected number of policies: %d", len(policies))
+	}
+	if !comparePolicies(pol, &policies[0]) {
+		t.Fatalf("unexpected policy returned.\nExpected: %v.\nGot %v", pol, policies[0])
+	}
+	if policies[0].Limits != pol.Limits {
+		t.Fatalf("expected limits %+v, got %+v", pol.Limits, policies[0].Limits)
+	}
+	if policies[0].Lifetime.AddTime == 0 {
+		t.Fatal("policy add time not set")
+	}
+}
+
+func TestParseXfrmPolicySecCtxType(t *testing.T) {
+	pol := getPolicy()
+	pol.Type = XFRM_POLICY_TYPE_SUB
+	pol.Flags = XFRM_POLICY_LOCALOK
+	pol.SecCtx = &XfrmSecCtx{Ctx: "system_u:object_r:ipsec_spd_t:s0"}
+
+	req := nl.NewNetlinkRequest(nl.XFRM_MSG_NEWPOLICY, 0)
+	req.AddData(xfrmUserpolicyInfoFromXfrmPolicy(pol))
+	addXfrmPolicyIdAttrs(req, pol)
+	b := req.Serialize()[unix.SizeofNlMsghdr:]
+
+	parsed, err := parseXfrmPolicy(b, FAMILY_ALL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Type != XFRM_POLICY_TYPE_SUB || parsed.Flags != XFRM_POLICY_LOCALOK {
+		t.Fatalf("unexpected policy type %s flags %d", parsed.Type, parsed.Flags)
+	}
+	expected := XfrmSecCtx{Doi: nl.XFRM_SC_DOI_LSM, Alg: nl.XFRM_SC_ALG_SELINUX, Ctx: pol.SecCtx.Ctx}
+	if parsed.SecCtx == nil || *parsed.SecCtx != expected {
+		t.Fatalf("expected security context %s, got %s", &expected, parsed.SecCtx)
+	}
+}
+
+func TestXfrmSocketPolicySet(t *testing.T) {
+	defer setUpNetlinkTest(t)()
+
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	pol := &XfrmPolicy{
+		Dir: XFRM_DIR_IN,
+		Tmpls: []XfrmPolicyTmpl{{
+			Proto: XFRM_PROTO_ESP,
+			Mode:  XFRM_MODE_TRANSPORT,
+		}},
+	}
+	if err := XfrmSocketPolicySet(conn, pol); err != nil {
+		t.Fatal(err)
+	}
+	pol.Dir = XFRM_DIR_OUT
+	if err := XfrmSocketPolicySet(conn, pol); err != nil {
+		t.Fatal(err)
+	}
+	pol.Dir = XFRM_DIR_FWD
+	if err := XfrmSocketPolicySet(conn, pol); err == nil {
+		t.Fatal("expected an error for a forward socket policy")
+	}
+
+	info, err := XfrmSpdInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.InSocketCount != 1 || info.OutSocketCount != 1 {
+		t.Fatalf("expected one input and one output socket policy, got %+v", info)
+	}
+}
+
 func comparePolicies(a, b *XfrmPolicy) bool {
 	if a == b {
 		return true
@@ -227,7 +328,8 @@ func comparePolicies(a, b *XfrmPolicy) bool {
 		compareIPNet(a.Src, b.Src) && compareIPNet(a.Dst, b.Dst) &&
 		a.Action == b.Action && a.Ifindex == b.Ifindex &&
 		a.Mark.Value == b.Mark.Value && a.Mark.Mask == b.Mark.Mask &&
-		a.Ifid == b.Ifid && compareTemplates(a.Tmpls, b.Tmpls)
+		a.Ifid == b.Ifid && a.Type == b.Type && a.Flags == b.Flags &&
+		compareTemplates(a.Tmpls, b.Tmpls)
 }
 
 func compareTemplates(a, b []XfrmPolicyTmpl) bool {
@@ -285,3 +387,155 @@ func getPolicy() *XfrmPolicy {
 	policy.Tmpls = append(policy.Tmpls, tmpl)
 	return policy
 }
+
+func TestXfrmSpdInfoSet(t *testing.T) {
+	defer setUpNetlinkTest(t)()
+
+	if err := XfrmPolicyAdd(getPolicy()); err != nil {
+		t.Fatal(err)
+	}
+	ipv4 := &XfrmSpdHashThresh{LocalBits: 24, RemoteBits: 16}
+	if err := XfrmSpdInfoSet(ipv4, nil); err != nil {
+		t.Fatal(err)
+	}
+	info, err := XfrmSpdInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.OutCount != 1 || info.InCount != 0 {
+		t.Fatalf("unexpected policy counts: %+v", info)
+	}
+	if info.IPv4Thresh != *ipv4 {
+		t.Fatalf("expected ipv4 thresholds %+v, got %+v", *ipv4, info.IPv4Thresh)
+	}
+}
+
+func TestXfrmMigrate(t *testing.T) {
+	defer setUpNetlinkTest(t)()
+
+	// The kernel looks up the policy to migrate without its mark
+	policy := getPolicy()
+	policy.Mark = nil
+	if err := XfrmPolicyAdd(policy); err != nil {
+		t.Fatal(err)
+	}
+	state := getBaseState()
+	state.Src = policy.Tmpls[0].Src
+	state.Dst = policy.Tmpls[0].Dst
+	state.Mark = nil
+	if err := XfrmStateAdd(state); err != nil {
+		t.Fatal(err)
+	}
+
+	entry := XfrmMigrateEntry{
+		OldDst: policy.Tmpls[0].Dst,
+		OldSrc: policy.Tmpls[0].Src,
+		NewDst: net.ParseIP("127.0.0.4"),
+		NewSrc: net.ParseIP("127.0.0.3"),
+		Proto:  XFRM_PROTO_ESP,
+		Mode:   XFRM_MODE_TUNNEL,
+	}
+	selector := &XfrmSelector{
+		Dst:     policy.Dst,
+		Src:     policy.Src,
+		Proto:   policy.Proto,
+		DstPort: policy.DstPort,
+		SrcPort: policy.SrcPort,
+	}
+	km := &XfrmKmAddress{Local: net.ParseIP("127.0.0.3"), Remote: net.ParseIP("127.0.0.4")}
+	if err := XfrmMigrate(selector, policy.Dir, []XfrmMigrateEntry{entry}, km); err != nil {
+		t.Fatal(err)
+	}
+
+	policies, err := XfrmPolicyList(FAMILY_ALL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(policies) != 1 || len(policies[0].Tmpls) != 1 {
+		t.Fatalf("unexpected policies %v", policies)
+	}
+	if tmpl := policies[0].Tmpls[0]; !tmpl.Dst.Equal(entry.NewDst) || !tmpl.Src.Equal(entry.NewSrc) {
+		t.Fatalf("template not migrated: %s", tmpl)
+	}
+
+	states, err := XfrmStateList(FAMILY_ALL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(states) != 1 || !states[0].Dst.Equal(entry.NewDst) || !states[0].Src.Equal(entry.NewSrc) {
+		t.Fatalf("state not migrated: %v", states)
+	}
+}
+
+func TestXfrmFilterMatchXfrmPolicy(t *testing.T) {
+	policy := getPolicy()
+	policy.Ifid = 7
+	policy.Tmpls[0].Reqid = 0x20
+
+	src, _ := ParseIPNet("127.1.0.0/16")
+	other, _ := ParseIPNet("10.0.0.0/8")
+	tests := []struct {
+		filter *XfrmFilter
+		match  bool
+	}{
+		{nil, true},
+		{&XfrmFilter{}, true},
+		{&XfrmFilter{Src: src, Dst: src}, true},
+		{&XfrmFilter{Src: other}, false},
+		{&XfrmFilter{Src: policy.Src, Dst: src}, true},
+		{&XfrmFilter{Mark: &XfrmMark{Value: 0xab0000, Mask: 0xff0000}}, true},
+		{&XfrmFilter{Mark: &XfrmMark{Value: 0xab0000}}, false},
+		{&XfrmFilter{Ifid: 7, Proto: XFRM_PROTO_ESP, Reqid: 0x20}, true},
+		{&XfrmFilter{Proto: XFRM_PROTO_AH}, false},
+		{&XfrmFilter{Reqid: 0x21}, false},
+		{&XfrmFilter{Ifid: 8}, false},
+	}
+	for _, tt := range tests {
+		if match := tt.filter.MatchXfrmPolicy(policy); match != tt.match {
+			t.Errorf("filter %+v: expected match %t, got %t", tt.filter, tt.match, match)
+		}
+	}
+
+	// A policy on a wider prefix is not within the filter prefix
+	if (&XfrmFilter{Src: policy.Src}).MatchXfrmPolicy(&XfrmPolicy{Src: src}) {
+		t.Error("unexpected match of a wider prefix")
+	}
+}
+
+func TestXfrmPolicyListDeleteFilter(t *testing.T) {
+	defer setUpNetlinkTest(t)()
+
+	tenant1 := getPolicy()
+	tenant1.Mark = &XfrmMark{Value: 0x100, Mask: 0xff00}
+	tenant2 := getPolicy()
+	tenant2.Mark = &XfrmMark{Value: 0x200, Mask: 0xff00}
+	tenant2.Dir = XFRM_DIR_IN
+	for _, policy := range []*XfrmPolicy{tenant1, tenant2} {
+		if err := XfrmPolicyAdd(policy); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	filter := &XfrmFilter{Mark: &XfrmMark{Value: 0x200, Mask: 0xff00}}
+	policies, err := XfrmPolicyListFiltered(FAMILY_ALL, filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(policies) != 1 || !comparePolicies(tenant2, &policies[0]) {
+		t.Fatalf("expected the policy %v, got %v", tenant2, policies)
+	}
+
+	deleted, err := XfrmPolicyDeleteFilter(FAMILY_ALL, filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 1 {
+		t.Fatalf("expected 1 deleted policy, got %d", deleted)
+	}
+	if policies, err = XfrmPolicyList(FAMILY_ALL); err != nil {
+		t.Fatal(err)
+	}
+	if len(policies) != 1 || !comparePolicies(tenant1, &policies[0]) {
+		t.Fatalf("expected the policy %v, got %v", tenant1, policies)
+	}
+}
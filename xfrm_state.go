@@ -56,6 +56,32 @@ func (e XfrmStateEncap) String() string {
 		e.Type, e.SrcPort, e.DstPort, e.OriginalAddress)
 }
 
+// XfrmStateFlags is a bitmask of the ipsec state flags. The extended
+// sequence numbers flag is the ESN field of the XfrmState.
+type XfrmStateFlags uint8
+
+const (
+	XFRM_STATE_NOECN XfrmStateFlags = 1 << iota
+	XFRM_STATE_DECAP_DSCP
+	XFRM_STATE_NOPMTUDISC
+	XFRM_STATE_WILDRECV
+	XFRM_STATE_ICMP
+	XFRM_STATE_AF_UNSPEC
+	XFRM_STATE_ALIGN4
+)
+
+// XfrmStateExtraFlags is a bitmask of the ipsec state extra flags.
+type XfrmStateExtraFlags uint32
+
+const (
+	// XFRM_SA_XFLAG_DONT_ENCAP_DSCP does not copy the inner dscp to the
+	// outer header
+	XFRM_SA_XFLAG_DONT_ENCAP_DSCP XfrmStateExtraFlags = 1 << iota
+	// XFRM_SA_XFLAG_OSEQ_MAY_WRAP lets the output sequence number wrap
+	// around instead of expiring the state
+	XFRM_SA_XFLAG_OSEQ_MAY_WRAP
+)
+
 // XfrmStateLimits represents the configured limits for the state.
 type XfrmStateLimits struct {
 	ByteSoft    uint64
@@ -81,8 +107,58 @@ type XfrmStateStats struct {
 	UseTime      uint64
 }
 
+// XfrmReplayState is the replay state of a state without extended
+// sequence numbers.
+type XfrmReplayState struct {
+	OSeq   uint32
+	Seq    uint32
+	BitMap uint32
+}
+
+// XfrmReplayStateEsn is the replay state of a state with extended
+// sequence numbers. The BitMap words cover the ReplayWindow.
+type XfrmReplayStateEsn struct {
+	OSeq         uint32
+	Seq          uint32
+	OSeqHi       uint32
+	SeqHi        uint32
+	ReplayWindow uint32
+	BitMap       []uint32
+}
+
+// XfrmStateLifetime is the current lifetime of a state.
+type XfrmStateLifetime struct {
+	Bytes   uint64
+	Packets uint64
+	AddTime uint64
+	UseTime uint64
+}
+
+// XfrmStateAE holds the async event values of a state: its replay
+// state, its current lifetime and the thresholds triggering the replay
+// notifications. Only one of Replay and ReplayEsn is set, depending on
+// the state using extended sequence numbers.
+type XfrmStateAE struct {
+	Replay       *XfrmReplayState
+	ReplayEsn    *XfrmReplayStateEsn
+	Lifetime     *XfrmStateLifetime
+	ReplayThresh uint32 // in packets
+	ETimerThresh uint32 // in tenths of seconds
+}
+
+// XfrmSadStats holds the number of states and the size of the state hash
+// table.
+type XfrmSadStats struct {
+	Count        uint32
+	HashCount    uint32
+	HashMaxCount uint32
+}
+
 // XfrmState represents the state of an ipsec policy. It optionally
 // contains an XfrmStateAlgo for encryption and one for authentication.
+// Comp is the algorithm of the ipcomp states. Selector restricts the
+// flows of the state, the kernel defaulting it to any flow. Replay and
+// ReplayEsn are only read from the kernel.
 type XfrmState struct {
 	Dst          net.IP
 	Src          net.IP
@@ -101,6 +177,13 @@ type XfrmState struct {
 	Aead         *XfrmStateAlgo
 	Encap        *XfrmStateEncap
 	ESN          bool
+	Comp         *XfrmStateAlgo
+	TFCPad       int
+	Flags        XfrmStateFlags
+	ExtraFlags   XfrmStateExtraFlags
+	Selector     *XfrmSelector
+	Replay       *XfrmReplayState
+	ReplayEsn    *XfrmReplayStateEsn
 }
 
 func (sa XfrmState) String() string {
@@ -2,6 +2,7 @@ package netlink
 
 import (
 	"fmt"
+	"syscall"
 	"unsafe"
 
 	"github.com/ndupreez/netlink/nl"
@@ -77,6 +78,23 @@ func writeReplayEsn(replayWindow int) []byte {
 	return replayEsn.Serialize()
 }
 
+func writeReplayStateEsn(r *XfrmReplayStateEsn) []byte {
+	replayEsn := &nl.XfrmReplayStateEsn{
+		BmpLen:       uint32(len(r.BitMap)),
+		OSeq:         r.OSeq,
+		Seq:          r.Seq,
+		OSeqHi:       r.OSeqHi,
+		SeqHi:        r.SeqHi,
+		ReplayWindow: r.ReplayWindow,
+	}
+	b := make([]byte, nl.SizeofXfrmReplayStateEsn+4*len(r.BitMap))
+	copy(b, replayEsn.Serialize())
+	for i, word := range r.BitMap {
+		native.PutUint32(b[nl.SizeofXfrmReplayStateEsn+4*i:], word)
+	}
+	return b
+}
+
 // XfrmStateAdd will add an xfrm state to the system.
 // Equivalent to: `ip xfrm state add $state`
 func XfrmStateAdd(state *XfrmState) error {
@@ -140,6 +158,10 @@ func (h *Handle) xfrmStateAddOrUpdate(state *XfrmState, nlProto int) error {
 		out := nl.NewRtAttr(nl.XFRMA_ALG_AEAD, writeStateAlgoAead(state.Aead))
 		req.AddData(out)
 	}
+	if state.Comp != nil {
+		out := nl.NewRtAttr(nl.XFRMA_ALG_COMP, writeStateAlgo(state.Comp))
+		req.AddData(out)
+	}
 	if state.Encap != nil {
 		encapData := make([]byte, nl.SizeofXfrmEncapTmpl)
 		encap := nl.DeserializeXfrmEncapTmpl(encapData)
@@ -167,6 +189,15 @@ func (h *Handle) xfrmStateAddOrUpdate(state *XfrmState, nlProto int) error {
 		}
 	}
 
+	if state.TFCPad != 0 {
+		out := nl.NewRtAttr(nl.XFRMA_TFCPAD, nl.Uint32Attr(uint32(state.TFCPad)))
+		req.AddData(out)
+	}
+	if state.ExtraFlags != 0 {
+		out := nl.NewRtAttr(nl.XFRMA_SA_EXTRA_FLAGS, nl.Uint32Attr(uint32(state.ExtraFlags)))
+		req.AddData(out)
+	}
+
 	ifId := nl.NewRtAttr(nl.XFRMA_IF_ID, nl.Uint32Attr(uint32(state.Ifid)))
 	req.AddData(ifId)
 
@@ -224,7 +255,26 @@ func XfrmStateList(family int) ([]XfrmState, error) {
 // Equivalent to: `ip xfrm state show`.
 // The list can be filtered by ip family.
 func (h *Handle) XfrmStateList(family int) ([]XfrmState, error) {
+	return h.XfrmStateListFiltered(family, nil)
+}
+
+// XfrmStateListFiltered gets the xfrm states of the ip family selected by
+// the filter. The addresses and protocol of an XfrmFilter are also given
+// to the kernel, which then only dumps the matching states.
+// Equivalent to: `ip xfrm state show [ ID ] [ reqid REQID ]`
+func XfrmStateListFiltered(family int, filter CustomXfrmStateFilter) ([]XfrmState, error) {
+	return pkgHandle.XfrmStateListFiltered(family, filter)
+}
+
+// XfrmStateListFiltered gets the xfrm states of the ip family selected by
+// the filter. The addresses and protocol of an XfrmFilter are also given
+// to the kernel, which then only dumps the matching states.
+// Equivalent to: `ip xfrm state show [ ID ] [ reqid REQID ]`
+func (h *Handle) XfrmStateListFiltered(family int, filter CustomXfrmStateFilter) ([]XfrmState, error) {
 	req := h.newNetlinkRequest(nl.XFRM_MSG_GETSA, unix.NLM_F_DUMP)
+	if f, ok := filter.(*XfrmFilter); ok && f != nil {
+		addXfrmStateDumpFilter(req, f)
+	}
 
 	msgs, err := req.Execute(unix.NETLINK_XFRM, nl.XFRM_MSG_NEWSA)
 	if err != nil {
@@ -234,6 +284,9 @@ func (h *Handle) XfrmStateList(family int) ([]XfrmState, error) {
 	var res []XfrmState
 	for _, m := range msgs {
 		if state, err := parseXfrmState(m, family); err == nil {
+			if filter != nil && !filter.MatchXfrmState(state) {
+				continue
+			}
 			res = append(res, *state)
 		} else if err == familyError {
 			continue
@@ -244,6 +297,56 @@ func (h *Handle) XfrmStateList(family int) ([]XfrmState, error) {
 	return res, nil
 }
 
+// The kernel reads the dump filter attributes right after the netlink
+// header
+func addXfrmStateDumpFilter(req *nl.NetlinkRequest, f *XfrmFilter) {
+	if f.Src != nil || f.Dst != nil {
+		filter := &nl.XfrmAddressFilter{}
+		if f.Src != nil {
+			filter.Family = uint16(nl.GetIPFamily(f.Src.IP))
+			filter.Saddr.FromIP(f.Src.IP)
+			splen, _ := f.Src.Mask.Size()
+			filter.Splen = uint8(splen)
+		}
+		if f.Dst != nil {
+			filter.Family = uint16(nl.GetIPFamily(f.Dst.IP))
+			filter.Daddr.FromIP(f.Dst.IP)
+			dplen, _ := f.Dst.Mask.Size()
+			filter.Dplen = uint8(dplen)
+		}
+		req.AddData(nl.NewRtAttr(nl.XFRMA_ADDRESS_FILTER, filter.Serialize()))
+	}
+	if f.Proto != 0 {
+		req.AddData(nl.NewRtAttr(nl.XFRMA_PROTO, nl.Uint8Attr(uint8(f.Proto))))
+	}
+}
+
+// XfrmStateDeleteFilter deletes the xfrm states of the ip family selected
+// by the filter and returns the number of deleted states.
+// Equivalent to: `ip xfrm state deleteall [ ID ] [ reqid REQID ]`
+func XfrmStateDeleteFilter(family int, filter CustomXfrmStateFilter) (uint, error) {
+	return pkgHandle.XfrmStateDeleteFilter(family, filter)
+}
+
+// XfrmStateDeleteFilter deletes the xfrm states of the ip family selected
+// by the filter and returns the number of deleted states.
+// Equivalent to: `ip xfrm state deleteall [ ID ] [ reqid REQID ]`
+func (h *Handle) XfrmStateDeleteFilter(family int, filter CustomXfrmStateFilter) (uint, error) {
+	states, err := h.XfrmStateListFiltered(family, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	var deleted uint
+	for i := range states {
+		if err := h.XfrmStateDel(&states[i]); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
 // XfrmStateGet gets the xfrm state described by the ID, if found.
 // Equivalent to: `ip xfrm state get ID [ mark MARK [ mask MASK ] ]`.
 // Only the fields which constitue the SA ID must be filled in:
@@ -318,9 +421,19 @@ func xfrmStateFromXfrmUsersaInfo(msg *nl.XfrmUsersaInfo) *XfrmState {
 	state.Spi = int(nl.Swap32(msg.Id.Spi))
 	state.Reqid = int(msg.Reqid)
 	state.ReplayWindow = int(msg.ReplayWindow)
+	state.ESN = msg.Flags&nl.XFRM_STATE_ESN != 0
+	state.Flags = XfrmStateFlags(msg.Flags &^ nl.XFRM_STATE_ESN)
 	lftToLimits(&msg.Lft, &state.Limits)
 	curToStats(&msg.Curlft, &msg.Stats, &state.Statistics)
 
+	// The kernel only sets the family in the selector of the states added
+	// without one, so it is reported when it restricts the traffic
+	if msg.Sel.PrefixlenD != 0 || msg.Sel.PrefixlenS != 0 || msg.Sel.Proto != 0 ||
+		msg.Sel.Dport != 0 || msg.Sel.Sport != 0 || msg.Sel.Ifindex != 0 {
+		selector := selectorFromSel(&msg.Sel)
+		state.Selector = &selector
+	}
+
 	return &state
 }
 
@@ -338,7 +451,12 @@ func parseXfrmState(m []byte, family int) (*XfrmState, error) {
 	if err != nil {
 		return nil, err
 	}
+	parseXfrmStateAttrs(state, attrs)
+
+	return state, nil
+}
 
+func parseXfrmStateAttrs(state *XfrmState, attrs []syscall.NetlinkRouteAttr) {
 	for _, attr := range attrs {
 		switch attr.Attr.Type {
 		case nl.XFRMA_ALG_AUTH, nl.XFRMA_ALG_CRYPT:
@@ -369,6 +487,11 @@ func parseXfrmState(m []byte, family int) (*XfrmState, error) {
 			state.Aead.Name = nl.BytesToString(algo.AlgName[:])
 			state.Aead.Key = algo.AlgKey
 			state.Aead.ICVLen = int(algo.AlgICVLen)
+		case nl.XFRMA_ALG_COMP:
+			state.Comp = new(XfrmStateAlgo)
+			algo := nl.DeserializeXfrmAlgo(attr.Value[:])
+			state.Comp.Name = nl.BytesToString(algo.AlgName[:])
+			state.Comp.Key = algo.AlgKey
 		case nl.XFRMA_ENCAP:
 			encap := nl.DeserializeXfrmEncapTmpl(attr.Value[:])
 			state.Encap = new(XfrmStateEncap)
@@ -396,10 +519,65 @@ func parseXfrmState(m []byte, family int) (*XfrmState, error) {
 			}
 		case nl.XFRMA_IF_ID:
 			state.Ifid = int(native.Uint32(attr.Value))
+		case nl.XFRMA_TFCPAD:
+			state.TFCPad = int(native.Uint32(attr.Value))
+		case nl.XFRMA_SA_EXTRA_FLAGS:
+			state.ExtraFlags = XfrmStateExtraFlags(native.Uint32(attr.Value))
+		case nl.XFRMA_REPLAY_VAL:
+			state.Replay = xfrmReplayStateFromNl(nl.DeserializeXfrmReplayState(attr.Value))
+		case nl.XFRMA_REPLAY_ESN_VAL:
+			state.ReplayEsn = xfrmReplayStateEsnFromNl(nl.DeserializeXfrmReplayStateEsn(attr.Value))
+			// the replay_window of usersa_info is 0 for ESN states,
+			// the kernel keeps the window in the ESN replay state
+			if state.ReplayWindow == 0 {
+				state.ReplayWindow = int(state.ReplayEsn.ReplayWindow)
+			}
 		}
 	}
+}
 
-	return state, nil
+func xfrmReplayStateFromNl(replay *nl.XfrmReplayState) *XfrmReplayState {
+	return &XfrmReplayState{
+		OSeq:   replay.OSeq,
+		Seq:    replay.Seq,
+		BitMap: replay.BitMap,
+	}
+}
+
+func xfrmReplayStateEsnFromNl(replay *nl.XfrmReplayStateEsn) *XfrmReplayStateEsn {
+	return &XfrmReplayStateEsn{
+		OSeq:         replay.OSeq,
+		Seq:          replay.Seq,
+		OSeqHi:       replay.OSeqHi,
+		SeqHi:        replay.SeqHi,
+		ReplayWindow: replay.ReplayWindow,
+		BitMap:       replay.Bmp,
+	}
+}
+
+func parseXfrmStateAE(attrs []syscall.NetlinkRouteAttr) *XfrmStateAE {
+	var ae XfrmStateAE
+	for _, attr := range attrs {
+		switch attr.Attr.Type {
+		case nl.XFRMA_REPLAY_VAL:
+			ae.Replay = xfrmReplayStateFromNl(nl.DeserializeXfrmReplayState(attr.Value))
+		case nl.XFRMA_REPLAY_ESN_VAL:
+			ae.ReplayEsn = xfrmReplayStateEsnFromNl(nl.DeserializeXfrmReplayStateEsn(attr.Value))
+		case nl.XFRMA_LTIME_VAL:
+			cur := nl.DeserializeXfrmLifetimeCur(attr.Value)
+			ae.Lifetime = &XfrmStateLifetime{
+				Bytes:   cur.Bytes,
+				Packets: cur.Packets,
+				AddTime: cur.AddTime,
+				UseTime: cur.UseTime,
+			}
+		case nl.XFRMA_REPLAY_THRESH:
+			ae.ReplayThresh = native.Uint32(attr.Value)
+		case nl.XFRMA_ETIMER_THRESH:
+			ae.ETimerThresh = native.Uint32(attr.Value)
+		}
+	}
+	return &ae
 }
 
 // XfrmStateFlush will flush the xfrm state on the system.
@@ -472,6 +650,152 @@ func xfrmUsersaInfoFromXfrmState(state *XfrmState) *nl.XfrmUsersaInfo {
 	msg.Id.Spi = nl.Swap32(uint32(state.Spi))
 	msg.Reqid = uint32(state.Reqid)
 	msg.ReplayWindow = uint8(state.ReplayWindow)
+	msg.Flags = uint8(state.Flags)
+	if state.Selector != nil {
+		selFromSelector(&msg.Sel, state.Selector)
+	}
 
 	return msg
 }
+
+// XfrmSadInfo returns the number of states and the size of the state
+// hash table.
+// Equivalent to: `ip -s xfrm state count`
+func XfrmSadInfo() (*XfrmSadStats, error) {
+	return pkgHandle.XfrmSadInfo()
+}
+
+// XfrmSadInfo returns the number of states and the size of the state
+// hash table.
+// Equivalent to: `ip -s xfrm state count`
+func (h *Handle) XfrmSadInfo() (*XfrmSadStats, error) {
+	req := h.newNetlinkRequest(nl.XFRM_MSG_GETSADINFO, 0)
+	req.AddRawData(nl.Uint32Attr(^uint32(0)))
+
+	msgs, err := req.Execute(unix.NETLINK_XFRM, nl.XFRM_MSG_NEWSADINFO)
+	if err != nil {
+		return nil, err
+	}
+
+	attrs, err := nl.ParseRouteAttr(msgs[0][4:])
+	if err != nil {
+		return nil, err
+	}
+
+	var info XfrmSadStats
+	for _, attr := range attrs {
+		switch attr.Attr.Type {
+		case nl.XFRMA_SAD_CNT:
+			info.Count = native.Uint32(attr.Value[0:4])
+		case nl.XFRMA_SAD_HINFO:
+			info.HashCount = native.Uint32(attr.Value[0:4])
+			info.HashMaxCount = native.Uint32(attr.Value[4:8])
+		}
+	}
+	return &info, nil
+}
+
+func xfrmAeventIdFromXfrmState(state *XfrmState, flags uint32) *nl.XfrmAeventId {
+	msg := &nl.XfrmAeventId{
+		Flags: flags,
+		Reqid: uint32(state.Reqid),
+	}
+	msg.SaId.Family = uint16(nl.GetIPFamily(state.Dst))
+	msg.SaId.Daddr.FromIP(state.Dst)
+	msg.SaId.Proto = uint8(state.Proto)
+	msg.SaId.Spi = nl.Swap32(uint32(state.Spi))
+	msg.Saddr.FromIP(state.Src)
+	return msg
+}
+
+// XfrmStateGetAE returns the replay state, current lifetime and replay
+// notification thresholds of the state. Only the fields which constitute
+// the SA ID and the mark are used to find the state.
+func XfrmStateGetAE(state *XfrmState) (*XfrmStateAE, error) {
+	return pkgHandle.XfrmStateGetAE(state)
+}
+
+// XfrmStateGetAE returns the replay state, current lifetime and replay
+// notification thresholds of the state. Only the fields which constitute
+// the SA ID and the mark are used to find the state.
+func (h *Handle) XfrmStateGetAE(state *XfrmState) (*XfrmStateAE, error) {
+	req := h.newNetlinkRequest(nl.XFRM_MSG_GETAE, unix.NLM_F_ACK)
+	req.AddData(xfrmAeventIdFromXfrmState(state, nl.XFRM_AE_RTHR|nl.XFRM_AE_ETHR))
+
+	if state.Mark != nil {
+		out := nl.NewRtAttr(nl.XFRMA_MARK, writeMark(state.Mark))
+		req.AddData(out)
+	}
+
+	msgs, err := req.Execute(unix.NETLINK_XFRM, nl.XFRM_MSG_NEWAE)
+	if err != nil {
+		return nil, err
+	}
+
+	attrs, err := nl.ParseRouteAttr(msgs[0][nl.SizeofXfrmAeventId:])
+	if err != nil {
+		return nil, err
+	}
+	return parseXfrmStateAE(attrs), nil
+}
+
+// XfrmStateSetAE sets the replay state, current lifetime and replay
+// notification thresholds of the state, for example when a standby
+// gateway takes over the states of a failed one. The nil values and the
+// 0 thresholds are left unchanged. A ReplayEsn BitMap must have the
+// length of the state one.
+func XfrmStateSetAE(state *XfrmState, ae *XfrmStateAE) error {
+	return pkgHandle.XfrmStateSetAE(state, ae)
+}
+
+// XfrmStateSetAE sets the replay state, current lifetime and replay
+// notification thresholds of the state, for example when a standby
+// gateway takes over the states of a failed one. The nil values and the
+// 0 thresholds are left unchanged. A ReplayEsn BitMap must have the
+// length of the state one.
+func (h *Handle) XfrmStateSetAE(state *XfrmState, ae *XfrmStateAE) error {
+	req := h.newNetlinkRequest(nl.XFRM_MSG_NEWAE, unix.NLM_F_REPLACE|unix.NLM_F_ACK)
+	req.AddData(xfrmAeventIdFromXfrmState(state, 0))
+
+	for _, attr := range xfrmStateAEAttrs(ae) {
+		req.AddData(attr)
+	}
+	if state.Mark != nil {
+		out := nl.NewRtAttr(nl.XFRMA_MARK, writeMark(state.Mark))
+		req.AddData(out)
+	}
+
+	_, err := req.Execute(unix.NETLINK_XFRM, 0)
+	return err
+}
+
+func xfrmStateAEAttrs(ae *XfrmStateAE) []*nl.RtAttr {
+	var attrs []*nl.RtAttr
+	if ae.Replay != nil {
+		replay := &nl.XfrmReplayState{
+			OSeq:   ae.Replay.OSeq,
+			Seq:    ae.Replay.Seq,
+			BitMap: ae.Replay.BitMap,
+		}
+		attrs = append(attrs, nl.NewRtAttr(nl.XFRMA_REPLAY_VAL, replay.Serialize()))
+	}
+	if ae.ReplayEsn != nil {
+		attrs = append(attrs, nl.NewRtAttr(nl.XFRMA_REPLAY_ESN_VAL, writeReplayStateEsn(ae.ReplayEsn)))
+	}
+	if ae.Lifetime != nil {
+		cur := &nl.XfrmLifetimeCur{
+			Bytes:   ae.Lifetime.Bytes,
+			Packets: ae.Lifetime.Packets,
+			AddTime: ae.Lifetime.AddTime,
+			UseTime: ae.Lifetime.UseTime,
+		}
+		attrs = append(attrs, nl.NewRtAttr(nl.XFRMA_LTIME_VAL, cur.Serialize()))
+	}
+	if ae.ReplayThresh != 0 {
+		attrs = append(attrs, nl.NewRtAttr(nl.XFRMA_REPLAY_THRESH, nl.Uint32Attr(ae.ReplayThresh)))
+	}
+	if ae.ETimerThresh != 0 {
+		attrs = append(attrs, nl.NewRtAttr(nl.XFRMA_ETIMER_THRESH, nl.Uint32Attr(ae.ETimerThresh)))
+	}
+	return attrs
+}
@@ -6,8 +6,11 @@ import (
 	"bytes"
 	"encoding/hex"
 	"net"
+	"reflect"
 	"testing"
 	"time"
+
+	"github.com/ndupreez/netlink/nl"
 )
 
 func TestXfrmStateAddGetDel(t *testing.T) {
@@ -201,6 +204,91 @@ func TestXfrmStateStats(t *testing.T) {
 	}
 }
 
+func TestXfrmStateAEAttrs(t *testing.T) {
+	ae := &XfrmStateAE{
+		ReplayEsn: &XfrmReplayStateEsn{
+			OSeq:         100,
+			Seq:          90,
+			OSeqHi:       1,
+			SeqHi:        1,
+			ReplayWindow: 64,
+			BitMap:       []uint32{0xffffffff, 0x7},
+		},
+		Lifetime:     &XfrmStateLifetime{Bytes: 1500, Packets: 10, AddTime: 1000, UseTime: 1001},
+		ReplayThresh: 32,
+		ETimerThresh: 10,
+	}
+	var b []byte
+	for _, attr := range xfrmStateAEAttrs(ae) {
+		b = append(b, attr.Serialize()...)
+	}
+	attrs, err := nl.ParseRouteAttr(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed := parseXfrmStateAE(attrs)
+	if !reflect.DeepEqual(parsed, ae) {
+		t.Fatalf("expected %+v, got %+v", ae, parsed)
+	}
+
+	ae = &XfrmStateAE{Replay: &XfrmReplayState{OSeq: 5, Seq: 4, BitMap: 0xf}}
+	b = nil
+	for _, attr := range xfrmStateAEAttrs(ae) {
+		b = append(b, attr.Serialize()...)
+	}
+	if attrs, err = nl.ParseRouteAttr(b); err != nil {
+		t.Fatal(err)
+	}
+	parsed = parseXfrmStateAE(attrs)
+	if !reflect.DeepEqual(parsed, ae) {
+		t.Fatalf("expected %+v, got %+v", ae, parsed)
+	}
+}
+
+func TestXfrmStateGetSetAE(t *testing.T) {
+	defer setUpNetlinkTest(t)()
+
+	state := getBaseState()
+	state.ReplayWindow = 32
+	if err := XfrmStateAdd(state); err != nil {
+		t.Fatal(err)
+	}
+
+	ae, err := XfrmStateGetAE(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ae.Replay == nil || ae.Lifetime == nil {
+		t.Fatalf("missing replay state or lifetime: %+v", ae)
+	}
+
+	replay := &XfrmReplayState{OSeq: 1000, Seq: 900, BitMap: 0xff}
+	if err := XfrmStateSetAE(state, &XfrmStateAE{Replay: replay}); err != nil {
+		t.Fatal(err)
+	}
+	if ae, err = XfrmStateGetAE(state); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ae.Replay, replay) {
+		t.Fatalf("expected replay state %+v, got %+v", replay, ae.Replay)
+	}
+}
+
+func TestXfrmSadInfo(t *testing.T) {
+	defer setUpNetlinkTest(t)()
+
+	if err := XfrmStateAdd(getBaseState()); err != nil {
+		t.Fatal(err)
+	}
+	info, err := XfrmSadInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Count != 1 || info.HashCount == 0 || info.HashMaxCount < info.HashCount {
+		t.Fatalf("unexpected sad info: %+v", info)
+	}
+}
+
 func TestXfrmStateWithIfid(t *testing.T) {
 	minKernelRequired(t, 4, 19)
 	defer setUpNetlinkTest(t)()
@@ -269,6 +357,145 @@ func TestXfrmStateWithOutputMarkAndMask(t *testing.T) {
 	}
 }
 
+func TestParseXfrmStateExtras(t *testing.T) {
+	state := &XfrmState{
+		Src:   net.ParseIP("127.0.0.1").To4(),
+		Dst:   net.ParseIP("127.0.0.2").To4(),
+		Proto: XFRM_PROTO_ESP,
+		Mode:  XFRM_MODE_TUNNEL,
+		Spi:   1,
+	}
+	state.Flags = XFRM_STATE_NOECN | XFRM_STATE_WILDRECV | XFRM_STATE_ALIGN4
+	state.ExtraFlags = XFRM_SA_XFLAG_DONT_ENCAP_DSCP | XFRM_SA_XFLAG_OSEQ_MAY_WRAP
+	state.TFCPad = 1200
+	state.ESN = true
+	state.Comp = &XfrmStateAlgo{Name: "deflate", Key: []byte{}}
+	dst, _ := ParseIPNet("10.0.1.0/24")
+	src, _ := ParseIPNet("10.0.0.0/24")
+	state.Selector = &XfrmSelector{Dst: dst, Src: src, Proto: 17, DstPort: 4500}
+	state.ReplayEsn = &XfrmReplayStateEsn{
+		OSeq:         10,
+		Seq:          0xfffffff0,
+		OSeqHi:       2,
+		SeqHi:        1,
+		ReplayWindow: 64,
+		BitMap:       []uint32{0xffff0000, 0xff},
+	}
+
+	msg := xfrmUsersaInfoFromXfrmState(state)
+	msg.Flags |= nl.XFRM_STATE_ESN
+	b := msg.Serialize()
+	for _, attr := range []*nl.RtAttr{
+		nl.NewRtAttr(nl.XFRMA_ALG_COMP, writeStateAlgo(state.Comp)),
+		nl.NewRtAttr(nl.XFRMA_TFCPAD, nl.Uint32Attr(uint32(state.TFCPad))),
+		nl.NewRtAttr(nl.XFRMA_SA_EXTRA_FLAGS, nl.Uint32Attr(uint32(state.ExtraFlags))),
+		nl.NewRtAttr(nl.XFRMA_REPLAY_ESN_VAL, writeReplayStateEsn(state.ReplayEsn)),
+	} {
+		b = append(b, attr.Serialize()...)
+	}
+
+	parsed, err := parseXfrmState(b, FAMILY_ALL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !compareStates(state, parsed) {
+		t.Fatalf("unexpected state returned.\nExpected: %v.\nGot %v", state, parsed)
+	}
+	if parsed.Selector == nil || !compareIPNet(parsed.Selector.Dst, dst) || !compareIPNet(parsed.Selector.Src, src) ||
+		parsed.Selector.Proto != 17 || parsed.Selector.DstPort != 4500 {
+		t.Fatalf("expected selector %s, got %v", state.Selector, parsed.Selector)
+	}
+	if !reflect.DeepEqual(parsed.ReplayEsn, state.ReplayEsn) {
+		t.Fatalf("expected replay state %+v, got %+v", state.ReplayEsn, parsed.ReplayEsn)
+	}
+	if parsed.ReplayWindow != 64 {
+		t.Fatalf("expected the replay window of the ESN replay state, got %d", parsed.ReplayWindow)
+	}
+}
+
+func TestXfrmStateWithFlagsAndTFCPad(t *testing.T) {
+	defer setUpNetlinkTest(t)()
+
+	state := getBaseState()
+	state.Flags = XFRM_STATE_NOECN | XFRM_STATE_DECAP_DSCP | XFRM_STATE_NOPMTUDISC
+	state.ExtraFlags = XFRM_SA_XFLAG_DONT_ENCAP_DSCP
+	state.TFCPad = 1200
+	state.ESN = true
+	state.ReplayWindow = 128
+	dst, _ := ParseIPNet("10.0.1.0/24")
+	src, _ := ParseIPNet("10.0.0.0/24")
+	state.Selector = &XfrmSelector{Dst: dst, Src: src}
+	if err := XfrmStateAdd(state); err != nil {
+		t.Fatal(err)
+	}
+	s, err := XfrmStateGet(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !compareStates(state, s) {
+		t.Fatalf("unexpected state returned.\nExpected: %v.\nGot %v", state, s)
+	}
+	if s.Selector == nil || !compareIPNet(s.Selector.Dst, dst) || !compareIPNet(s.Selector.Src, src) {
+		t.Fatalf("expected selector %s, got %v", state.Selector, s.Selector)
+	}
+	if s.ReplayEsn == nil || s.ReplayEsn.ReplayWindow != 128 || len(s.ReplayEsn.BitMap) != 4 {
+		t.Fatalf("unexpected replay state %+v", s.ReplayEsn)
+	}
+}
+
+func TestXfrmStateEsnGetUpdate(t *testing.T) {
+	defer setUpNetlinkTest(t)()
+
+	state := getBaseState()
+	state.ESN = true
+	state.ReplayWindow = 128
+	if err := XfrmStateAdd(state); err != nil {
+		t.Fatal(err)
+	}
+	s, err := XfrmStateGet(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.ESN || s.ReplayWindow != 128 {
+		t.Fatalf("unexpected ESN %v or replay window %d", s.ESN, s.ReplayWindow)
+	}
+	// The state read back can be passed to update as is
+	s.Limits.TimeHard = 3600
+	if err := XfrmStateUpdate(s); err != nil {
+		t.Fatal(err)
+	}
+	s, err = XfrmStateGet(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.ESN || s.ReplayWindow != 128 || s.Limits.TimeHard != 3600 {
+		t.Fatalf("unexpected state after update: %v", s)
+	}
+}
+
+func TestXfrmStateIPComp(t *testing.T) {
+	defer setUpNetlinkTest(t)()
+
+	state := &XfrmState{
+		Src:   net.ParseIP("127.0.0.1").To4(),
+		Dst:   net.ParseIP("127.0.0.2").To4(),
+		Proto: XFRM_PROTO_COMP,
+		Mode:  XFRM_MODE_TUNNEL,
+		Spi:   0x1234,
+		Comp:  &XfrmStateAlgo{Name: "deflate"},
+	}
+	if err := XfrmStateAdd(state); err != nil {
+		t.Fatal(err)
+	}
+	s, err := XfrmStateGet(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !compareStates(state, s) {
+		t.Fatalf("unexpected state returned.\nExpected: %v.\nGot %v", state, s)
+	}
+}
+
 func getBaseState() *XfrmState {
 	return &XfrmState{
 		// Force 4 byte notation for the IPv4 addresses
@@ -323,8 +550,11 @@ func compareStates(a, b *XfrmState) bool {
 		compareAlgo(a.Auth, b.Auth) &&
 		compareAlgo(a.Crypt, b.Crypt) &&
 		compareAlgo(a.Aead, b.Aead) &&
+		compareAlgo(a.Comp, b.Comp) &&
 		compareMarks(a.Mark, b.Mark) &&
-		compareMarks(a.OutputMark, b.OutputMark)
+		compareMarks(a.OutputMark, b.OutputMark) &&
+		a.ESN == b.ESN && a.Flags == b.Flags &&
+		a.ExtraFlags == b.ExtraFlags && a.TFCPad == b.TFCPad
 }
 
 func compareLimits(a, b *XfrmState) bool {
@@ -359,3 +589,77 @@ func compareMarks(a, b *XfrmMark) bool {
 	}
 	return a.Value == b.Value && a.Mask == b.Mask
 }
+
+func TestXfrmFilterMatchXfrmState(t *testing.T) {
+	state := getBaseState()
+	state.Reqid = 0x10
+	state.Ifid = 3
+
+	dst, _ := ParseIPNet("127.0.0.0/24")
+	other, _ := ParseIPNet("192.0.2.0/24")
+	tests := []struct {
+		filter *XfrmFilter
+		match  bool
+	}{
+		{nil, true},
+		{&XfrmFilter{}, true},
+		{&XfrmFilter{Src: dst, Dst: dst, Proto: XFRM_PROTO_ESP}, true},
+		{&XfrmFilter{Dst: other}, false},
+		{&XfrmFilter{Proto: XFRM_PROTO_AH}, false},
+		{&XfrmFilter{Mark: &XfrmMark{Value: 0x12340000, Mask: 0xffff0000}}, true},
+		{&XfrmFilter{Mark: &XfrmMark{Value: 0x12340001}}, false},
+		{&XfrmFilter{Ifid: 3, Reqid: 0x10}, true},
+		{&XfrmFilter{Ifid: 4}, false},
+		{&XfrmFilter{Reqid: 0x11}, false},
+	}
+	for _, tt := range tests {
+		if match := tt.filter.MatchXfrmState(state); match != tt.match {
+			t.Errorf("filter %+v: expected match %t, got %t", tt.filter, tt.match, match)
+		}
+	}
+}
+
+func TestXfrmStateListDeleteFilter(t *testing.T) {
+	defer setUpNetlinkTest(t)()
+
+	state1 := getBaseState()
+	state2 := getBaseState()
+	state2.Spi = 2
+	state2.Dst = net.ParseIP("127.0.1.2").To4()
+	state2.Reqid = 0x20
+	for _, state := range []*XfrmState{state1, state2} {
+		if err := XfrmStateAdd(state); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dst, _ := ParseIPNet("127.0.1.0/24")
+	filter := &XfrmFilter{Dst: dst, Proto: XFRM_PROTO_ESP}
+	states, err := XfrmStateListFiltered(FAMILY_ALL, filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(states) != 1 || !compareStates(state2, &states[0]) {
+		t.Fatalf("expected the state %v, got %v", state2, states)
+	}
+	if states, err = XfrmStateListFiltered(FAMILY_ALL, &XfrmFilter{Reqid: 0x20}); err != nil {
+		t.Fatal(err)
+	}
+	if len(states) != 1 || !compareStates(state2, &states[0]) {
+		t.Fatalf("expected the state %v, got %v", state2, states)
+	}
+
+	deleted, err := XfrmStateDeleteFilter(FAMILY_ALL, filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 1 {
+		t.Fatalf("expected 1 deleted state, got %d", deleted)
+	}
+	if states, err = XfrmStateList(FAMILY_ALL); err != nil {
+		t.Fatal(err)
+	}
+	if len(states) != 1 || !compareStates(state1, &states[0]) {
+		t.Fatalf("expected the state %v, got %v", state1, states)
+	}
+}